@@ -2,9 +2,12 @@ package main
 
 import (
 	"database/sql"
+	"expvar"
+	"flag"
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
@@ -19,33 +22,117 @@ import (
 
 	"github.com/leveldorado/space-trouble/pkg/entrypoints"
 	"github.com/leveldorado/space-trouble/pkg/services"
+	"github.com/leveldorado/space-trouble/pkg/tools/cassette"
+	"github.com/leveldorado/space-trouble/pkg/tools/clock"
+	"github.com/leveldorado/space-trouble/pkg/tools/email"
 	"github.com/leveldorado/space-trouble/pkg/tools/logger"
 )
 
+const webhookTimeout = 5 * time.Second
+
+const (
+	outboxSinkLog          = "log"
+	outboxSinkWebhook      = "webhook"
+	outboxSinkNotify       = "notify"
+	outboxSinkNotification = "notification"
+	outboxSinkWaitlist     = "waitlist"
+
+	defaultOutboxSinks = outboxSinkLog + "," + outboxSinkWebhook + "," + outboxSinkNotification + "," +
+		outboxSinkWaitlist
+	defaultOutboxNotifyChannel = "order_events"
+)
+
+const (
+	notificationSenderSMTP   = "smtp"
+	notificationSenderFile   = "file"
+	notificationSenderStdout = "stdout"
+
+	defaultNotificationFrom = "bookings@space-trouble.local"
+)
+
 func main() {
+	storageKind := flag.String("storage", storagePostgres, "storage of app state: "+storagePostgres+", "+storageSQLite+" or "+storageMemory)
+	timeTravel := flag.Bool("time-travel", false, "run on virtual time which admin endpoints can set or advance, for staging only")
+	flag.Parse()
 	log := logger.New()
-	conn := mustGetPostgresDB(log)
+	st := mustGetStorage(*storageKind, log)
+	var (
+		clk timeSource = clock.Real{}
+		vc  *clock.Virtual
+	)
+	if *timeTravel {
+		vc = clock.NewVirtual()
+		clk = vc
+		log.Warn("time travel is enabled, admin endpoints can change time of app")
+	}
 	cl := &http.Client{
-		Timeout: time.Second,
+		Timeout:   time.Second,
+		Transport: mustGetCassetteTransport(log),
 	}
-	or := repositories.NewPostgreSQLOrdersRepo(conn, log)
-	lr := repositories.NewSpaceXAPILaunchpadsRepo(cl)
-	dr := repositories.NewInMemoryDestinationsRepo()
-	fr := repositories.NewInMemoryLaunchpadFirstDestinationRepo()
+	sx := repositories.NewSpaceXClient(cl, repositories.DefaultSpaceXClientConfig())
+	spacexURL := os.Getenv("SPACEX_API_URL")
+	if spacexURL == "" {
+		spacexURL = repositories.DefaultSpaceXAPIURL
+	}
+	or := st.orders
+	lr := repositories.NewSpaceXAPILaunchpadsRepo(sx, spacexURL)
+	dr := st.destinations
+	fr := st.anchors
+	wr := st.webhooks
+	nr := st.notifications
+	wlr := st.waitlist
+	lar := st.launches
+	lps := services.NewLaunchpadsSnapshot(lr, st.snapshot, log).WithClock(clk)
 
-	if err := migrations.Init(or, lr, dr, fr); err != nil {
+	if err := migrations.Init(clk.Now(), lps, dr, fr, st); err != nil {
 		log.WithField("err", err.Error()).Fatal("failed to do migration.Init")
 	}
 
+	ws := services.NewWebhooks(wr, &http.Client{Timeout: webhookTimeout}, log).WithClock(clk)
+	clr := repositories.NewCachedLaunchpadsRepo(
+		lps,
+		mustGetDurationEnv("LAUNCHPADS_CACHE_TTL", repositories.DefaultLaunchpadsCacheTTL, log),
+		mustGetDurationEnv("LAUNCHPADS_CACHE_NOT_FOUND_TTL", repositories.DefaultLaunchpadsCacheNotFoundTTL, log),
+		log,
+	)
+	smtpTimeout := mustGetDurationEnv("SMTP_TIMEOUT", email.DefaultSMTPTimeout, log)
+	ns := services.NewNotifications(nr, mustGetEmailSender(smtpTimeout, log), clr, dr, log).
+		WithClock(clk).
+		WithSendTimeout(smtpTimeout)
+
+	launches := services.NewLaunchesMirror(services.LaunchesSourceSpaceX, lar, repositories.NewSpaceXAPILaunchesRepo(sx, spacexURL), log).
+		WithClock(clk)
+	publishLaunchesStaleness(launches, log)
+
 	s := services.NewOrders(
 		or,
-		lr,
+		clr,
 		dr,
 		fr,
-		repositories.NewSpaceXAPILaunchesRepo(cl),
-	)
+		mustGetCompetitorLaunchesRepo(cl, launches, log),
+	).WithClock(clk)
+	wls := services.NewWaitlist(wlr, s, clr, log).WithClock(clk)
+	launches.WithListener(wls)
+	cw := services.NewConflictsWatcher(s, log)
+	lw := services.NewLaunchpadsWatcher(lps, dr, fr, s, log).WithClock(clk)
+	relay := services.NewOutboxRelay(or, log, mustGetOutboxSinks(st.conn, ws, ns, wls, log)...).WithClock(clk)
 
-	h := entrypoints.NewHTTPEntry(s, log).GetHandler()
+	entry := entrypoints.NewHTTPEntry(s, ws, ns, wls, log).WithLaunchpadsCache(clr).
+		WithHealthCheck("launchpads", lps)
+	if vc != nil {
+		entry = entry.WithVirtualClock(vc)
+	}
+	h := entry.GetHandler()
+
+	workersCtx, stopWorkers := context.WithCancel(context.Background())
+	go ws.Run(workersCtx)
+	go relay.Run(workersCtx)
+	go ns.Run(workersCtx)
+	go wls.Run(workersCtx)
+	go launches.Run(workersCtx)
+	go cw.Run(workersCtx)
+	go lw.Run(workersCtx)
+	go lps.Run(workersCtx)
 
 	httpS := &http.Server{
 		Addr:         ":8000",
@@ -67,6 +154,7 @@ func main() {
 
 	<-signalChan
 	log.Info("server exiting")
+	stopWorkers()
 	if err := httpS.Shutdown(context.Background()); err != nil {
 		log.WithField("err", err.Error()).Error("failed to shutdown server")
 		return
@@ -74,6 +162,13 @@ func main() {
 	log.Info("BYE!")
 }
 
+/*
+timeSource tells current time, actual one or virtual in time travel mode
+*/
+type timeSource interface {
+	Now() time.Time
+}
+
 func mustGetPostgresDB(log logrus.FieldLogger) *sql.DB {
 	url := os.Getenv("POSTGRESQL_URL")
 	db, err := repositories.GetPostgresqlConn(url)
@@ -82,3 +177,145 @@ func mustGetPostgresDB(log logrus.FieldLogger) *sql.DB {
 	}
 	return db
 }
+
+/*
+publishLaunchesStaleness exposes seconds since last launches sync in /debug/vars, -1 when launches were never synced
+*/
+func publishLaunchesStaleness(m *services.LaunchesMirror, log logrus.FieldLogger) {
+	expvar.Publish("launches_sync_staleness_seconds", expvar.Func(func() interface{} {
+		staleness, synced, err := m.Staleness(context.Background())
+		if err != nil {
+			log.WithField("err", err.Error()).Error("failed to get launches sync staleness")
+		}
+		if err != nil || !synced {
+			return -1
+		}
+		return staleness.Seconds()
+	}))
+}
+
+/*
+mustGetCassetteTransport replays (or records with SPACEX_CASSETTE_MODE=record) outgoing requests of cassette
+set by SPACEX_CASSETTE env variable, for demos without network. Nil means default transport.
+
+	launches mirror sync range depends on current time, so date range of launches query is ignored when
+	there is no interaction with the same range
+*/
+func mustGetCassetteTransport(log logrus.FieldLogger) http.RoundTripper {
+	path := os.Getenv("SPACEX_CASSETTE")
+	if path == "" {
+		return nil
+	}
+	mode := os.Getenv("SPACEX_CASSETTE_MODE")
+	if mode == "" {
+		mode = cassette.ModeReplay
+	}
+	t, err := cassette.NewTransport(path, mode, nil)
+	if err != nil {
+		log.WithField("err", err.Error()).Fatal("failed to create cassette transport")
+	}
+	t.WithIgnoredBodyFields("query.date_utc")
+	log.WithField("path", path).WithField("mode", mode).Warn("outgoing requests use cassette")
+	return t
+}
+
+/*
+mustGetDurationEnv parses env variable like 10m, default value is used when variable is empty
+*/
+func mustGetDurationEnv(name string, defaultValue time.Duration, log logrus.FieldLogger) time.Duration {
+	value := os.Getenv(name)
+	if value == "" {
+		return defaultValue
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		log.WithField("err", err.Error()).WithField("env", name).Fatal("failed to parse duration")
+	}
+	return d
+}
+
+func mustGetCompetitorLaunchesRepo(cl *http.Client, launches *services.LaunchesMirror, log logrus.FieldLogger) *repositories.CompositeLaunchesRepo {
+	c, err := repositories.ReadCompetitorLaunchesConfig(os.Getenv("COMPETITOR_LAUNCHES_CONFIG"))
+	if err != nil {
+		log.WithField("err", err.Error()).Fatal("failed to read competitor launches config")
+	}
+	r, err := repositories.NewCompositeLaunchesRepo(c, cl, launches)
+	if err != nil {
+		log.WithField("err", err.Error()).Fatal("failed to create competitor launches repo")
+	}
+	return r
+}
+
+/*
+mustGetOutboxSinks builds sinks from comma separated OUTBOX_SINKS env variable (log, webhook, notify, notification, waitlist).
+
+	notify sink sends events to OUTBOX_NOTIFY_CHANNEL postgres channel
+*/
+func mustGetOutboxSinks(
+	conn *sql.DB,
+	ws *services.Webhooks,
+	ns *services.Notifications,
+	wls *services.Waitlist,
+	log logrus.FieldLogger,
+) []services.EventSink {
+	names := os.Getenv("OUTBOX_SINKS")
+	if names == "" {
+		names = defaultOutboxSinks
+	}
+	var sinks []services.EventSink
+	for _, name := range strings.Split(names, ",") {
+		switch strings.TrimSpace(name) {
+		case outboxSinkLog:
+			sinks = append(sinks, services.NewLogEventSink(log))
+		case outboxSinkWebhook:
+			sinks = append(sinks, ws)
+		case outboxSinkNotification:
+			sinks = append(sinks, ns)
+		case outboxSinkWaitlist:
+			sinks = append(sinks, wls)
+		case outboxSinkNotify:
+			if conn == nil {
+				log.WithField("sink", name).Fatal("outbox sink requires postgres storage")
+			}
+			channel := os.Getenv("OUTBOX_NOTIFY_CHANNEL")
+			if channel == "" {
+				channel = defaultOutboxNotifyChannel
+			}
+			sinks = append(sinks, repositories.NewPostgreSQLNotifySink(conn, channel))
+		default:
+			log.WithField("sink", name).Fatal("unknown outbox sink")
+		}
+	}
+	return sinks
+}
+
+/*
+mustGetEmailSender builds notifications sender from NOTIFICATIONS_SENDER env variable (smtp, file, stdout).
+
+	smtp uses SMTP_ADDR, SMTP_USERNAME, SMTP_PASSWORD and smtpTimeout, file appends messages to NOTIFICATIONS_FILE
+*/
+func mustGetEmailSender(smtpTimeout time.Duration, log logrus.FieldLogger) services.EmailSender {
+	from := os.Getenv("NOTIFICATIONS_FROM")
+	if from == "" {
+		from = defaultNotificationFrom
+	}
+	switch name := os.Getenv("NOTIFICATIONS_SENDER"); name {
+	case notificationSenderStdout, "":
+		return email.NewWriterSender(os.Stdout, from)
+	case notificationSenderFile:
+		f, err := os.OpenFile(os.Getenv("NOTIFICATIONS_FILE"), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+		if err != nil {
+			log.WithField("err", err.Error()).Fatal("failed to open notifications file")
+		}
+		return email.NewWriterSender(f, from)
+	case notificationSenderSMTP:
+		s, err := email.NewSMTPSender(os.Getenv("SMTP_ADDR"), from, os.Getenv("SMTP_USERNAME"), os.Getenv("SMTP_PASSWORD"))
+		if err != nil {
+			log.WithField("err", err.Error()).Fatal("failed to create smtp sender")
+		}
+		return s.WithTimeout(smtpTimeout)
+	default:
+		log.WithField("sender", name).Fatal("unknown notifications sender")
+		return nil
+	}
+}
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"time"
+
+	"github.com/leveldorado/space-trouble/pkg/repositories"
+	"github.com/leveldorado/space-trouble/pkg/types"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	storagePostgres = "postgres"
+	storageMemory   = "memory"
+	storageSQLite   = "sqlite"
+	// used when SQLITE_PATH env variable is not set
+	defaultSQLitePath = "space-trouble.db"
+)
+
+type ordersStorage interface {
+	Get(ctx context.Context, id string) (types.Order, error)
+	Delete(ctx context.Context, now time.Time, id string) error
+	List(ctx context.Context, limit, offset int) ([]types.Order, error)
+	ListByLaunchpad(ctx context.Context, launchpadID string, from, to time.Time) ([]types.Order, error)
+	Insert(ctx context.Context, o types.Order) error
+	Stream(ctx context.Context, limit, offset int, fn func(types.Order) error) error
+	InsertMany(ctx context.Context, docs []types.Order) error
+	TakenSeats(ctx context.Context, launchpadID, launchLocalDate string) (int, error)
+	History(ctx context.Context, orderID string) ([]types.OrderAuditEntry, error)
+	ListByStatus(ctx context.Context, status string, from time.Time, limit, offset int) ([]types.Order, error)
+	MarkConflict(ctx context.Context, now time.Time, checked types.Order, reason string) error
+	Reschedule(ctx context.Context, now time.Time, doc types.Order) error
+	ProcessOutbox(ctx context.Context, now time.Time, limit int, fn func(e *types.OutboxEvent)) (int, error)
+}
+
+type destinationsStorage interface {
+	ListSorted(ctx context.Context) ([]types.Destination, error)
+}
+
+type anchorsStorage interface {
+	Get(ctx context.Context, launchpad string) (types.LaunchpadFirstDestination, error)
+	Set(ctx context.Context, doc types.LaunchpadFirstDestination) error
+}
+
+type webhooksStorage interface {
+	InsertSubscription(ctx context.Context, doc types.WebhookSubscription) error
+	GetSubscription(ctx context.Context, id string) (types.WebhookSubscription, error)
+	ListSubscriptions(ctx context.Context) ([]types.WebhookSubscription, error)
+	DeleteSubscription(ctx context.Context, id string) error
+	InsertDeliveries(ctx context.Context, docs []types.WebhookDelivery) error
+	ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]types.WebhookDelivery, error)
+	UpdateDelivery(ctx context.Context, doc types.WebhookDelivery) error
+	GetDelivery(ctx context.Context, id string) (types.WebhookDelivery, error)
+	ListDeliveries(ctx context.Context, status string, limit, offset int) ([]types.WebhookDelivery, error)
+}
+
+type notificationsStorage interface {
+	Insert(ctx context.Context, docs []types.Notification) error
+	CancelPending(ctx context.Context, orderID, kind string) error
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]types.Notification, error)
+	Update(ctx context.Context, doc types.Notification) error
+	ListByOrder(ctx context.Context, orderID string) ([]types.Notification, error)
+}
+
+type waitlistStorage interface {
+	Insert(ctx context.Context, doc types.WaitlistEntry) error
+	Get(ctx context.Context, id string) (types.WaitlistEntry, error)
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]types.WaitlistEntry, error)
+	Update(ctx context.Context, doc types.WaitlistEntry) error
+	Cancel(ctx context.Context, id string) error
+	RecheckLaunchpad(ctx context.Context, launchpadID string, at time.Time) error
+	HasWaitingAhead(ctx context.Context, id string) (bool, error)
+}
+
+type launchesStorage interface {
+	Replace(ctx context.Context, sync types.LaunchesSync, launches []types.Launch) error
+	GetSync(ctx context.Context, source string) (types.LaunchesSync, error)
+	ListLaunches(ctx context.Context, source, launchpad string, from, to time.Time) ([]types.Launch, error)
+	ListAllLaunches(ctx context.Context, source string, from, to time.Time) ([]types.Launch, error)
+}
+
+type launchpadsSnapshotStorage interface {
+	Save(ctx context.Context, launchpads []types.Launchpad, savedAt time.Time) error
+	Load(ctx context.Context) ([]types.Launchpad, time.Time, error)
+}
+
+type tablesCreator interface {
+	CreateTables(ctx context.Context) error
+}
+
+/*
+storage is set of repositories of one backend.
+
+	postgres keeps state between restarts, memory needs nothing to run and loses everything on restart,
+	it's meant for local demos. sqlite keeps orders, customers, destinations, anchors, webhooks, notifications
+	and waitlist in single file for instances without postgres, spacex launches and launchpads snapshot are kept
+	in memory and fetched again after restart. conn is nil when backend is not postgres
+*/
+type storage struct {
+	conn          *sql.DB
+	orders        ordersStorage
+	destinations  destinationsStorage
+	anchors       anchorsStorage
+	webhooks      webhooksStorage
+	notifications notificationsStorage
+	waitlist      waitlistStorage
+	launches      launchesStorage
+	snapshot      launchpadsSnapshotStorage
+	tables        []tablesCreator
+}
+
+func mustGetStorage(kind string, log logrus.FieldLogger) storage {
+	switch kind {
+	case storagePostgres:
+		conn := mustGetPostgresDB(log)
+		or := repositories.NewPostgreSQLOrdersRepo(conn, log)
+		fr := repositories.NewPostgreSQLLaunchpadFirstDestinationRepo(conn)
+		wr := repositories.NewPostgreSQLWebhooksRepo(conn, log)
+		nr := repositories.NewPostgreSQLNotificationsRepo(conn, log)
+		wlr := repositories.NewPostgreSQLWaitlistRepo(conn)
+		lar := repositories.NewPostgreSQLLaunchesRepo(conn, log)
+		snr := repositories.NewPostgreSQLLaunchpadsSnapshotRepo(conn, log)
+		return storage{
+			conn:          conn,
+			orders:        or,
+			destinations:  repositories.NewInMemoryDestinationsRepo(),
+			anchors:       fr,
+			webhooks:      wr,
+			notifications: nr,
+			waitlist:      wlr,
+			launches:      lar,
+			snapshot:      snr,
+			tables:        []tablesCreator{or, fr, wr, nr, wlr, lar, snr},
+		}
+	case storageMemory:
+		log.Warn("state is kept in memory and is lost on restart")
+		return storage{
+			orders:        repositories.NewInMemoryOrdersRepo(),
+			destinations:  repositories.NewInMemoryDestinationsRepo(),
+			anchors:       repositories.NewInMemoryLaunchpadFirstDestinationRepo(),
+			webhooks:      repositories.NewInMemoryWebhooksRepo(),
+			notifications: repositories.NewInMemoryNotificationsRepo(),
+			waitlist:      repositories.NewInMemoryWaitlistRepo(),
+			launches:      repositories.NewInMemoryLaunchesRepo(),
+			snapshot:      repositories.NewInMemoryLaunchpadsSnapshotRepo(),
+		}
+	case storageSQLite:
+		conn := mustGetSQLiteDB(log)
+		or := repositories.NewSQLiteOrdersRepo(conn, log)
+		dr := repositories.NewSQLiteDestinationsRepo(conn)
+		fr := repositories.NewSQLiteLaunchpadFirstDestinationRepo(conn)
+		wr := repositories.NewSQLiteWebhooksRepo(conn, log)
+		nr := repositories.NewSQLiteNotificationsRepo(conn, log)
+		wlr := repositories.NewSQLiteWaitlistRepo(conn)
+		log.Warn("spacex launches and launchpads snapshot are kept in memory and are fetched again after restart")
+		return storage{
+			orders:        or,
+			destinations:  dr,
+			anchors:       fr,
+			webhooks:      wr,
+			notifications: nr,
+			waitlist:      wlr,
+			launches:      repositories.NewInMemoryLaunchesRepo(),
+			snapshot:      repositories.NewInMemoryLaunchpadsSnapshotRepo(),
+			tables:        []tablesCreator{or, dr, fr, wr, nr, wlr},
+		}
+	}
+	log.WithField("storage", kind).Fatal("unknown storage")
+	return storage{}
+}
+
+/*
+CreateTables creates tables of all repositories, so storage is passed to migrations as one tables creator
+*/
+func (s storage) CreateTables(ctx context.Context) error {
+	for _, t := range s.tables {
+		if err := t.CreateTables(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func mustGetSQLiteDB(log logrus.FieldLogger) *sql.DB {
+	path := os.Getenv("SQLITE_PATH")
+	if path == "" {
+		path = defaultSQLitePath
+	}
+	db, err := repositories.GetSQLiteConn(path)
+	if err != nil {
+		log.WithField("err", err.Error()).Fatal("failed to obtain sqlite conn")
+	}
+	return db
+}
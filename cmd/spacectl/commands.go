@@ -0,0 +1,274 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/leveldorado/space-trouble/pkg/migrations"
+	"github.com/leveldorado/space-trouble/pkg/tools/ordersimport"
+	"github.com/leveldorado/space-trouble/pkg/types"
+	"github.com/pkg/errors"
+)
+
+const (
+	exportFlushEvery = 500
+)
+
+func ordersList(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("orders list", flag.ContinueOnError)
+	limit := fs.Int("limit", 10, "max number of orders")
+	offset := fs.Int("offset", 0, "number of orders to skip")
+	format := outputFlag(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	orders, err := a.orders.List(ctx, *limit, *offset)
+	if err != nil {
+		return err
+	}
+	return writeOrders(os.Stdout, *format, orders)
+}
+
+func ordersShow(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("orders show", flag.ContinueOnError)
+	id := fs.String("id", "", "order id")
+	format := outputFlag(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *id == "" {
+		return errors.New("-id is required")
+	}
+	order, err := a.orders.Get(ctx, *id)
+	if err != nil {
+		return err
+	}
+	return writeOrders(os.Stdout, *format, []types.Order{order})
+}
+
+func ordersHistory(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("orders history", flag.ContinueOnError)
+	id := fs.String("id", "", "order id")
+	format := outputFlag(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *id == "" {
+		return errors.New("-id is required")
+	}
+	entries, err := a.orders.History(ctx, *id)
+	if err != nil {
+		return err
+	}
+	return writeHistory(os.Stdout, *format, entries)
+}
+
+func ordersCancel(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("orders cancel", flag.ContinueOnError)
+	id := fs.String("id", "", "order id")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *id == "" {
+		return errors.New("-id is required")
+	}
+	if _, err := a.orders.Get(ctx, *id); err != nil {
+		return err
+	}
+	if err := a.orders.Delete(ctx, *id); err != nil {
+		return err
+	}
+	fmt.Fprintln(os.Stdout, "cancelled", *id)
+	return nil
+}
+
+func ordersConflicts(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("orders conflicts", flag.ContinueOnError)
+	limit := fs.Int("limit", 10, "max number of orders")
+	offset := fs.Int("offset", 0, "number of orders to skip")
+	detect := fs.Bool("detect", false, "recheck upcoming orders before listing")
+	format := outputFlag(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *detect {
+		report, err := a.orders.DetectConflicts(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "checked %d orders, %d new conflicts\n", report.Checked, report.Conflicts)
+	}
+	orders, err := a.orders.Conflicts(ctx, *limit, *offset)
+	if err != nil {
+		return err
+	}
+	return writeOrders(os.Stdout, *format, orders)
+}
+
+func ordersRebook(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("orders rebook", flag.ContinueOnError)
+	id := fs.String("id", "", "order id")
+	all := fs.Bool("all", false, "rebook all orders in conflict")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if (*id == "") == !*all {
+		return errors.New("either -id or -all is required")
+	}
+	if *id != "" {
+		o, err := a.orders.Rebook(ctx, *id)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(os.Stdout, "rebooked", o.ID, o.LaunchpadID, o.LaunchLocalDate)
+		return nil
+	}
+	results, err := a.orders.RebookConflicts(ctx)
+	if err != nil {
+		return err
+	}
+	for _, r := range results {
+		if r.Order == nil {
+			fmt.Fprintln(os.Stdout, "failed", r.OrderID, r.Error)
+			continue
+		}
+		fmt.Fprintln(os.Stdout, "rebooked", r.OrderID, r.Order.LaunchpadID, r.Order.LaunchLocalDate)
+	}
+	return nil
+}
+
+func ordersImport(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("orders import", flag.ContinueOnError)
+	file := fs.String("file", "", "path to csv or ndjson file")
+	format := fs.String("format", "", "csv or ndjson, taken from file extension by default")
+	dryRun := fs.Bool("dry-run", false, "only check rows without creating orders")
+	allOrNothing := fs.Bool("all-or-nothing", false, "create orders only if every row is feasible")
+	output := outputFlag(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *file == "" {
+		return errors.New("-file is required")
+	}
+	if *format == "" {
+		*format = strings.TrimPrefix(filepath.Ext(*file), ".")
+	}
+	f, err := os.Open(*file)
+	if err != nil {
+		return errors.Wrapf(err, `failed to open file: %s`, *file)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	rows, err := ordersimport.Decode(*format, f)
+	if err != nil {
+		return err
+	}
+	report, err := a.orders.Import(ctx, rows, types.ImportOptions{DryRun: *dryRun, AllOrNothing: *allOrNothing})
+	if err != nil {
+		return err
+	}
+	return writeImportReport(os.Stdout, *output, report)
+}
+
+func schedule(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("schedule", flag.ContinueOnError)
+	launchpad := fs.String("launchpad", "", "launchpad id")
+	from := fs.String("from", "", "first local date YYYY-MM-DD, today by default")
+	days := fs.Int("days", 7, "number of days")
+	format := outputFlag(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *launchpad == "" {
+		return errors.New("-launchpad is required")
+	}
+	fromDate := time.Now()
+	if *from != "" {
+		var err error
+		// parsed at noon UTC so local date is the same in any launchpad timezone
+		fromDate, err = time.Parse(types.LocalDateLayout+" 15", *from+" 12")
+		if err != nil {
+			return errors.Wrapf(err, `invalid -from value: %s`, *from)
+		}
+	}
+	scheduleDays, err := a.orders.LaunchpadSchedule(ctx, *launchpad, fromDate, *days)
+	if err != nil {
+		return err
+	}
+	return writeSchedule(os.Stdout, *format, scheduleDays)
+}
+
+func migrate(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := migrations.Init(time.Now(), a.lr, a.dr, a.fr, a.ordersRepo, a.fr, a.wr, a.nr, a.wlr, a.lar, a.snr); err != nil {
+		return err
+	}
+	fmt.Fprintln(os.Stdout, "migrated")
+	return nil
+}
+
+func launchesSync(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("launches sync", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := a.launches.Sync(ctx); err != nil {
+		return err
+	}
+	fmt.Fprintln(os.Stdout, "synced")
+	return nil
+}
+
+func anchorsList(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("anchors list", flag.ContinueOnError)
+	format := outputFlag(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	anchors, err := a.fr.List(ctx)
+	if err != nil {
+		return err
+	}
+	return writeAnchors(os.Stdout, *format, anchors)
+}
+
+func anchorsRebuild(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("anchors rebuild", flag.ContinueOnError)
+	format := outputFlag(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := migrations.RebuildLaunchpadFirstDestinations(ctx, a.lr, a.dr, a.fr, time.Now()); err != nil {
+		return err
+	}
+	anchors, err := a.fr.List(ctx)
+	if err != nil {
+		return err
+	}
+	return writeAnchors(os.Stdout, *format, anchors)
+}
+
+func export(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	format := outputFlag(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	stream, err := newOrdersStream(os.Stdout, *format, exportFlushEvery)
+	if err != nil {
+		return err
+	}
+	if err = a.orders.Export(ctx, 0, 0, stream.write); err != nil {
+		return err
+	}
+	return stream.close()
+}
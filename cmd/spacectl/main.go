@@ -0,0 +1,180 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/leveldorado/space-trouble/pkg/repositories"
+	"github.com/leveldorado/space-trouble/pkg/services"
+	"github.com/leveldorado/space-trouble/pkg/tools/actor"
+	"github.com/leveldorado/space-trouble/pkg/tools/logger"
+)
+
+const usage = `spacectl - admin tool for space-trouble
+
+Usage:
+  spacectl <command> [flags]
+
+Commands:
+  orders list      list orders              [-limit N] [-offset N] [-o table|json]
+  orders show      show order               -id ID [-o table|json]
+  orders history   order audit history      -id ID [-o table|json]
+  orders cancel    cancel (delete) order    -id ID
+  orders import    import orders from file  -file PATH [-format csv|ndjson] [-dry-run] [-all-or-nothing] [-o table|json]
+  orders conflicts list order conflicts     [-detect] [-limit N] [-offset N] [-o table|json]
+  orders rebook    rebook to nearest flight -id ID | -all
+  schedule         launchpad destinations   -launchpad ID [-from YYYY-MM-DD] [-days N] [-o table|json]
+  migrate          create tables and missing rotation anchors
+  anchors list     list rotation anchors    [-o table|json]
+  anchors rebuild  reset rotation anchors of all active launchpads to today
+  launches sync    mirror upcoming SpaceX launches into local table now
+  export           export all orders        [-o table|json]
+
+Environment:
+  POSTGRESQL_URL   postgres connection url
+  SPACEX_API_URL   SpaceX API base url, defaults to https://api.spacexdata.com
+  SPACECTL_ACTOR   name recorded in order audit log, defaults to spacectl:$USER
+`
+
+type app struct {
+	orders     *services.Orders
+	ordersRepo *repositories.PostgreSQLOrdersRepo
+	lr         *repositories.SpaceXAPILaunchpadsRepo
+	dr         *repositories.InMemoryDestinationsRepo
+	fr         *repositories.PostgreSQLLaunchpadFirstDestinationRepo
+	wr         *repositories.PostgreSQLWebhooksRepo
+	nr         *repositories.PostgreSQLNotificationsRepo
+	wlr        *repositories.PostgreSQLWaitlistRepo
+	lar        *repositories.PostgreSQLLaunchesRepo
+	snr        *repositories.PostgreSQLLaunchpadsSnapshotRepo
+	launches   *services.LaunchesMirror
+}
+
+func newApp() (*app, error) {
+	log := logger.New()
+	conn, err := repositories.GetPostgresqlConn(os.Getenv("POSTGRESQL_URL"))
+	if err != nil {
+		return nil, err
+	}
+	cl := &http.Client{
+		Timeout: 10 * time.Second,
+	}
+	sx := repositories.NewSpaceXClient(cl, repositories.DefaultSpaceXClientConfig())
+	spacexURL := os.Getenv("SPACEX_API_URL")
+	if spacexURL == "" {
+		spacexURL = repositories.DefaultSpaceXAPIURL
+	}
+	a := &app{
+		ordersRepo: repositories.NewPostgreSQLOrdersRepo(conn, log),
+		lr:         repositories.NewSpaceXAPILaunchpadsRepo(sx, spacexURL),
+		dr:         repositories.NewInMemoryDestinationsRepo(),
+		fr:         repositories.NewPostgreSQLLaunchpadFirstDestinationRepo(conn),
+		wr:         repositories.NewPostgreSQLWebhooksRepo(conn, log),
+		nr:         repositories.NewPostgreSQLNotificationsRepo(conn, log),
+		wlr:        repositories.NewPostgreSQLWaitlistRepo(conn),
+		lar:        repositories.NewPostgreSQLLaunchesRepo(conn, log),
+		snr:        repositories.NewPostgreSQLLaunchpadsSnapshotRepo(conn, log),
+	}
+	a.launches = services.NewLaunchesMirror(services.LaunchesSourceSpaceX, a.lar, repositories.NewSpaceXAPILaunchesRepo(sx, spacexURL), log)
+	c, err := repositories.ReadCompetitorLaunchesConfig(os.Getenv("COMPETITOR_LAUNCHES_CONFIG"))
+	if err != nil {
+		return nil, err
+	}
+	launches, err := repositories.NewCompositeLaunchesRepo(c, cl, a.launches)
+	if err != nil {
+		return nil, err
+	}
+	// conflicts rebooking looks for alternatives of every order, launchpads are fetched once per ttl for all of them
+	clr := repositories.NewCachedLaunchpadsRepo(
+		a.lr,
+		repositories.DefaultLaunchpadsCacheTTL,
+		repositories.DefaultLaunchpadsCacheNotFoundTTL,
+		log,
+	)
+	a.orders = services.NewOrders(
+		a.ordersRepo,
+		clr,
+		a.dr,
+		a.fr,
+		launches,
+	)
+	// sync from cli rechecks waitlists of launchpads which launches changed
+	a.launches.WithListener(services.NewWaitlist(a.wlr, a.orders, clr, log))
+	return a, nil
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	if err := run(context.Background(), os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, args []string) error {
+	cmd, args := args[0], args[1:]
+	if cmd == "help" || cmd == "-h" || cmd == "--help" {
+		fmt.Fprint(os.Stdout, usage)
+		return nil
+	}
+	handler, args, err := resolveCommand(cmd, args)
+	if err != nil {
+		return err
+	}
+	a, err := newApp()
+	if err != nil {
+		return err
+	}
+	return handler(actor.NewContext(ctx, cliActor()), a, args)
+}
+
+func cliActor() string {
+	if name := os.Getenv("SPACECTL_ACTOR"); name != "" {
+		return name
+	}
+	return "spacectl:" + os.Getenv("USER")
+}
+
+type commandHandler func(ctx context.Context, a *app, args []string) error
+
+func resolveCommand(cmd string, args []string) (commandHandler, []string, error) {
+	switch cmd {
+	case "orders", "anchors", "launches":
+		if len(args) == 0 {
+			return nil, nil, fmt.Errorf("%s requires subcommand, see spacectl help", cmd)
+		}
+		sub := cmd + " " + args[0]
+		h, ok := commands[sub]
+		if !ok {
+			return nil, nil, fmt.Errorf("unknown command %q, see spacectl help", sub)
+		}
+		return h, args[1:], nil
+	}
+	h, ok := commands[cmd]
+	if !ok {
+		return nil, nil, fmt.Errorf("unknown command %q, see spacectl help", cmd)
+	}
+	return h, args, nil
+}
+
+var commands = map[string]commandHandler{
+	"orders list":      ordersList,
+	"orders show":      ordersShow,
+	"orders history":   ordersHistory,
+	"orders cancel":    ordersCancel,
+	"orders import":    ordersImport,
+	"orders conflicts": ordersConflicts,
+	"orders rebook":    ordersRebook,
+	"schedule":         schedule,
+	"migrate":          migrate,
+	"anchors list":     anchorsList,
+	"anchors rebuild":  anchorsRebuild,
+	"export":           export,
+	"launches sync":    launchesSync,
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/leveldorado/space-trouble/pkg/types"
+	"github.com/pkg/errors"
+)
+
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+)
+
+func outputFlag(fs *flag.FlagSet) *string {
+	return fs.String("o", outputTable, "output format: table or json")
+}
+
+/*
+writeOutput writes docs as indented json or as table built from header and rows
+*/
+func writeOutput(w io.Writer, format string, docs interface{}, header []string, rows [][]string) error {
+	switch format {
+	case outputJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return errors.Wrap(enc.Encode(docs), `failed to encode json`)
+	case outputTable:
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		writeTableRow(tw, header)
+		for _, row := range rows {
+			writeTableRow(tw, row)
+		}
+		return errors.Wrap(tw.Flush(), `failed to flush table`)
+	default:
+		return errors.Errorf(`unknown output format: %s`, format)
+	}
+}
+
+func writeTableRow(w io.Writer, cells []string) {
+	for i, cell := range cells {
+		if i > 0 {
+			fmt.Fprint(w, "\t")
+		}
+		fmt.Fprint(w, cell)
+	}
+	fmt.Fprintln(w)
+}
+
+var orderHeader = []string{"ID", "FIRST NAME", "LAST NAME", "GENDER", "BIRTHDAY", "PASSENGERS", "LAUNCHPAD", "DESTINATION", "LAUNCH DATE",
+	"LAUNCH LOCAL DATE", "STATUS", "CREATED AT"}
+
+func orderRow(o types.Order) []string {
+	status := o.Status
+	if o.ConflictReason != "" {
+		status += " (" + o.ConflictReason + ")"
+	}
+	return []string{
+		o.ID,
+		o.FirstName,
+		o.LastName,
+		o.Gender,
+		fmt.Sprintf("%04d-%02d-%02d", o.BirthdayYear, o.BirthdayMonth, o.BirthdayDay),
+		strconv.Itoa(len(o.PassengerList())),
+		o.LaunchpadID,
+		o.DestinationID,
+		o.LaunchDate.UTC().Format(time.RFC3339),
+		o.LaunchLocalDate,
+		status,
+		o.CreatedAt.UTC().Format(time.RFC3339),
+	}
+}
+
+func writeOrders(w io.Writer, format string, orders []types.Order) error {
+	if orders == nil {
+		orders = []types.Order{}
+	}
+	rows := make([][]string, 0, len(orders))
+	for _, o := range orders {
+		rows = append(rows, orderRow(o))
+	}
+	return writeOutput(w, format, orders, orderHeader, rows)
+}
+
+/*
+ordersStream writes orders as they come, so export does not keep all orders in memory.
+
+	json is written as the same indented array writeOutput writes, table is flushed every flushEvery rows,
+	so its columns are aligned within each flushed part
+*/
+type ordersStream struct {
+	w          io.Writer
+	format     string
+	tw         *tabwriter.Writer
+	flushEvery int
+	written    int
+}
+
+func newOrdersStream(w io.Writer, format string, flushEvery int) (*ordersStream, error) {
+	s := &ordersStream{w: w, format: format, flushEvery: flushEvery}
+	switch format {
+	case outputJSON:
+		_, err := fmt.Fprint(w, "[")
+		return s, errors.Wrap(err, `failed to write json`)
+	case outputTable:
+		s.tw = tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		writeTableRow(s.tw, orderHeader)
+		return s, nil
+	default:
+		return nil, errors.Errorf(`unknown output format: %s`, format)
+	}
+}
+
+func (s *ordersStream) write(o types.OrderExport) error {
+	s.written++
+	if s.format == outputTable {
+		writeTableRow(s.tw, orderRow(o.Order))
+		if s.written%s.flushEvery == 0 {
+			return errors.Wrap(s.tw.Flush(), `failed to flush table`)
+		}
+		return nil
+	}
+	b, err := json.MarshalIndent(o, "  ", "  ")
+	if err != nil {
+		return errors.Wrapf(err, `failed to encode json: id - %s`, o.ID)
+	}
+	sep := ",\n  "
+	if s.written == 1 {
+		sep = "\n  "
+	}
+	_, err = fmt.Fprint(s.w, sep, string(b))
+	return errors.Wrap(err, `failed to write json`)
+}
+
+func (s *ordersStream) close() error {
+	if s.format == outputTable {
+		return errors.Wrap(s.tw.Flush(), `failed to flush table`)
+	}
+	end := "\n]\n"
+	if s.written == 0 {
+		end = "]\n"
+	}
+	_, err := fmt.Fprint(s.w, end)
+	return errors.Wrap(err, `failed to write json`)
+}
+
+func writeSchedule(w io.Writer, format string, days []types.LaunchpadScheduleDay) error {
+	if days == nil {
+		days = []types.LaunchpadScheduleDay{}
+	}
+	header := []string{"LOCAL DATE", "DESTINATION ID", "DESTINATION", "BLOCKED"}
+	rows := make([][]string, 0, len(days))
+	for _, d := range days {
+		rows = append(rows, []string{d.LocalDate, d.DestinationID, d.DestinationName, strconv.FormatBool(d.Blocked)})
+	}
+	return writeOutput(w, format, days, header, rows)
+}
+
+func writeAnchors(w io.Writer, format string, anchors []types.LaunchpadFirstDestination) error {
+	if anchors == nil {
+		anchors = []types.LaunchpadFirstDestination{}
+	}
+	header := []string{"LAUNCHPAD", "FIRST DESTINATION", "LOCAL DATE"}
+	rows := make([][]string, 0, len(anchors))
+	for _, a := range anchors {
+		rows = append(rows, []string{
+			a.LaunchpadID,
+			a.DestinationID,
+			fmt.Sprintf("%04d-%02d-%02d", a.LocalYear, int(a.LocalMonth), a.LocalDay),
+		})
+	}
+	return writeOutput(w, format, anchors, header, rows)
+}
+
+func writeHistory(w io.Writer, format string, entries []types.OrderAuditEntry) error {
+	header := []string{"TIME", "ACTION", "ACTOR", "REQUEST ID"}
+	rows := make([][]string, 0, len(entries))
+	for _, e := range entries {
+		rows = append(rows, []string{e.CreatedAt.UTC().Format(time.RFC3339), e.Action, e.Actor, e.RequestID})
+	}
+	return writeOutput(w, format, entries, header, rows)
+}
+
+func writeImportReport(w io.Writer, format string, report types.ImportReport) error {
+	header := []string{"LINE", "STATUS", "ID", "ERROR"}
+	rows := make([][]string, 0, len(report.Rows)+1)
+	for _, r := range report.Rows {
+		rows = append(rows, []string{strconv.Itoa(r.Line), r.Status, r.ID, r.Error})
+	}
+	if err := writeOutput(w, format, report, header, rows); err != nil {
+		return err
+	}
+	if format == outputTable {
+		fmt.Fprintf(w, "\ntotal: %d, succeeded: %d, failed: %d, committed: %t\n",
+			report.Total, report.Succeeded, report.Failed, report.Committed)
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"flag"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/middleware"
+	"github.com/leveldorado/space-trouble/pkg/tools/logger"
+	"github.com/leveldorado/space-trouble/pkg/tools/spacexstub"
+)
+
+/*
+spacex-stub serves SpaceX API endpoints used by space-trouble from JSON fixtures,
+point SPACEX_API_URL to it to develop without network
+*/
+func main() {
+	addr := flag.String("addr", ":8001", "listen address")
+	fixtures := flag.String("fixtures", "", "JSON file with launchpads and launches, bundled fixtures when empty")
+	flag.Parse()
+	log := logger.New()
+
+	f, err := spacexstub.ReadFixtures(*fixtures)
+	if err != nil {
+		log.WithField("err", err.Error()).Fatal("failed to read fixtures")
+	}
+	srv := &http.Server{
+		Addr:        *addr,
+		Handler:     middleware.Logger(spacexstub.NewHandler(f)),
+		ReadTimeout: time.Second,
+	}
+	log.WithField("addr", *addr).WithField("launchpads", len(f.Launchpads)).WithField("launches", len(f.Launches)).
+		Info("spacex stub started")
+	if err := srv.ListenAndServe(); err != nil {
+		log.WithField("err", err.Error()).Fatal("failed to listen and serve http")
+	}
+}
@@ -0,0 +1,31 @@
+package entrypoints
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi"
+)
+
+type launchpadsCache interface {
+	Purge(id string) int
+}
+
+/*
+WithLaunchpadsCache enables admin endpoints to purge cached launchpads
+*/
+func (e *HTTPEntry) WithLaunchpadsCache(c launchpadsCache) *HTTPEntry {
+	e.lc = c
+	return e
+}
+
+type purgeCacheResponse struct {
+	Purged int `json:"purged"`
+}
+
+/*
+purgeLaunchpadsCache drops cached launchpad by id or whole launchpads cache when id is not provided
+*/
+func (e *HTTPEntry) purgeLaunchpadsCache(wr http.ResponseWriter, req *http.Request) {
+	purged := e.lc.Purge(chi.URLParam(req, "id"))
+	e.respond(req.Context(), purgeCacheResponse{Purged: purged}, nil, http.StatusOK, wr)
+}
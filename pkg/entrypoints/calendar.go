@@ -0,0 +1,47 @@
+package entrypoints
+
+import (
+	"bytes"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/leveldorado/space-trouble/pkg/tools/ical"
+)
+
+func (e *HTTPEntry) orderCalendar(wr http.ResponseWriter, req *http.Request) {
+	id := chi.URLParam(req, "id")
+	c, err := e.os.OrderCalendar(req.Context(), id)
+	e.respondCalendar(wr, req, c, err, "order-"+id+".ics")
+}
+
+/*
+launchpadCalendar returns subscribable feed of launchpad destinations starting from today
+*/
+func (e *HTTPEntry) launchpadCalendar(wr http.ResponseWriter, req *http.Request) {
+	days, err := parseIntQueryParam(req.URL.Query(), "days", 0)
+	if err != nil {
+		e.respondError(req.Context(), err, wr)
+		return
+	}
+	id := chi.URLParam(req, "id")
+	c, err := e.os.LaunchpadCalendar(req.Context(), id, e.clock.Now(), days)
+	e.respondCalendar(wr, req, c, err, "launchpad-"+id+".ics")
+}
+
+func (e *HTTPEntry) respondCalendar(wr http.ResponseWriter, req *http.Request, c ical.Calendar, err error, filename string) {
+	if err != nil {
+		e.respondError(req.Context(), err, wr)
+		return
+	}
+	b := &bytes.Buffer{}
+	if err = ical.Encode(b, c, e.clock.Now()); err != nil {
+		e.respondError(req.Context(), err, wr)
+		return
+	}
+	wr.Header().Set("Content-Type", ical.ContentType)
+	wr.Header().Set("Content-Disposition", `inline; filename="`+filename+`"`)
+	wr.WriteHeader(http.StatusOK)
+	if _, err = wr.Write(b.Bytes()); err != nil {
+		e.log.WithField("err", err.Error()).WithContext(req.Context()).Warn("failed to write calendar")
+	}
+}
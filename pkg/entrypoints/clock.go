@@ -0,0 +1,96 @@
+package entrypoints
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/leveldorado/space-trouble/pkg/types"
+)
+
+type timeSource interface {
+	Now() time.Time
+}
+
+type virtualClock interface {
+	Now() time.Time
+	Offset() time.Duration
+	Set(t time.Time) time.Time
+	Advance(d time.Duration) time.Time
+	Reset() time.Time
+}
+
+/*
+WithVirtualClock makes entry use virtual time and enables admin endpoints to set and advance it.
+
+	meant for staging only, same clock has to be passed to services so schedules and cutoffs follow it.
+	moving time back doesn't reschedule stored work, leases, retries and rechecks scheduled at later time
+	wait until virtual time reaches it again
+*/
+func (e *HTTPEntry) WithVirtualClock(c virtualClock) *HTTPEntry {
+	e.clock = c
+	e.vc = c
+	return e
+}
+
+type clockResponse struct {
+	Now time.Time `json:"now"`
+	// virtual time minus actual one, like 720h0m0s
+	Offset string `json:"offset"`
+}
+
+type setClockRequest struct {
+	Now time.Time `json:"now"`
+}
+
+type advanceClockRequest struct {
+	// Go duration like 36h or -30m
+	Duration string `json:"duration"`
+}
+
+func (e *HTTPEntry) getClock(wr http.ResponseWriter, req *http.Request) {
+	e.respondClock(wr, req, e.vc.Now())
+}
+
+func (e *HTTPEntry) setClock(wr http.ResponseWriter, req *http.Request) {
+	body := setClockRequest{}
+	if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+		e.respondError(req.Context(), types.NewErrInvalidData(err.Error()), wr)
+		return
+	}
+	if body.Now.IsZero() {
+		e.respondError(req.Context(), types.NewErrInvalidData("now is required"), wr)
+		return
+	}
+	e.clockChanged(wr, req, e.vc.Set(body.Now))
+}
+
+func (e *HTTPEntry) advanceClock(wr http.ResponseWriter, req *http.Request) {
+	body := advanceClockRequest{}
+	if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+		e.respondError(req.Context(), types.NewErrInvalidData(err.Error()), wr)
+		return
+	}
+	d, err := time.ParseDuration(body.Duration)
+	if err != nil {
+		e.respondError(req.Context(), types.NewErrInvalidData("invalid duration: "+err.Error()), wr)
+		return
+	}
+	e.clockChanged(wr, req, e.vc.Advance(d))
+}
+
+/*
+resetClock returns virtual time to actual one
+*/
+func (e *HTTPEntry) resetClock(wr http.ResponseWriter, req *http.Request) {
+	e.clockChanged(wr, req, e.vc.Reset())
+}
+
+func (e *HTTPEntry) clockChanged(wr http.ResponseWriter, req *http.Request, now time.Time) {
+	e.log.WithField("now", now.UTC()).WithField("offset", e.vc.Offset().String()).Warn("virtual time changed")
+	e.respondClock(wr, req, now)
+}
+
+func (e *HTTPEntry) respondClock(wr http.ResponseWriter, req *http.Request, now time.Time) {
+	e.respond(req.Context(), clockResponse{Now: now.UTC(), Offset: e.vc.Offset().String()}, nil, http.StatusOK, wr)
+}
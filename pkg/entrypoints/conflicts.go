@@ -0,0 +1,46 @@
+package entrypoints
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/leveldorado/space-trouble/pkg/types"
+)
+
+/*
+listConflicts returns orders which flight became impossible after booking, nearest launch first
+*/
+func (e *HTTPEntry) listConflicts(wr http.ResponseWriter, req *http.Request) {
+	limit, offset, err := parseLimitOffset(req.URL.Query(), defaultOrdersLimit, maxOrdersLimit)
+	if err != nil {
+		e.respondError(req.Context(), err, wr)
+		return
+	}
+	orders, err := e.os.Conflicts(req.Context(), limit, offset)
+	if orders == nil {
+		orders = []types.Order{}
+	}
+	e.respond(req.Context(), paginationResult{
+		Docs:   orders,
+		Limit:  limit,
+		Offset: offset,
+	}, err, http.StatusOK, wr)
+}
+
+func (e *HTTPEntry) detectConflicts(wr http.ResponseWriter, req *http.Request) {
+	report, err := e.os.DetectConflicts(req.Context())
+	e.respond(req.Context(), report, err, http.StatusOK, wr)
+}
+
+func (e *HTTPEntry) rebookConflicts(wr http.ResponseWriter, req *http.Request) {
+	results, err := e.os.RebookConflicts(req.Context())
+	if results == nil {
+		results = []types.RebookResult{}
+	}
+	e.respond(req.Context(), results, err, http.StatusOK, wr)
+}
+
+func (e *HTTPEntry) rebookOrder(wr http.ResponseWriter, req *http.Request) {
+	o, err := e.os.Rebook(req.Context(), chi.URLParam(req, "id"))
+	e.respond(req.Context(), o, err, http.StatusOK, wr)
+}
@@ -0,0 +1,199 @@
+package entrypoints
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/leveldorado/space-trouble/pkg/types"
+	"github.com/pkg/errors"
+)
+
+const (
+	exportFormatCSV    = "csv"
+	exportFormatNDJSON = "ndjson"
+	exportFlushEvery   = 100
+	// export streams whole table, so it gets longer write deadline than server write timeout of other handlers
+	exportWriteTimeout = 5 * time.Minute
+)
+
+type orderExportEncoder interface {
+	contentType() string
+	writeHeader() error
+	write(o types.OrderExport) error
+	flush() error
+}
+
+func newOrderExportEncoder(format string, w io.Writer) (orderExportEncoder, error) {
+	switch format {
+	case exportFormatCSV, "":
+		return &csvOrderExportEncoder{w: csv.NewWriter(w)}, nil
+	case exportFormatNDJSON:
+		return &ndjsonOrderExportEncoder{enc: json.NewEncoder(w)}, nil
+	default:
+		return nil, types.NewErrInvalidData("unsupported export format " + format)
+	}
+}
+
+var csvOrderExportHeader = []string{
+	"id",
+	"first_name",
+	"last_name",
+	"gender",
+	"birthday_year",
+	"birthday_month",
+	"birthday_day",
+	"passengers",
+	"email",
+	"launchpad_id",
+	"launchpad_timezone",
+	"destination_id",
+	"destination_name",
+	"launch_date",
+	"launch_local_date",
+	"created_at",
+}
+
+type csvOrderExportEncoder struct {
+	w *csv.Writer
+}
+
+func (*csvOrderExportEncoder) contentType() string {
+	return "text/csv"
+}
+
+func (e *csvOrderExportEncoder) writeHeader() error {
+	return errors.Wrap(e.w.Write(csvOrderExportHeader), `failed to write csv header`)
+}
+
+func (e *csvOrderExportEncoder) write(o types.OrderExport) error {
+	return errors.Wrapf(e.w.Write([]string{
+		o.ID,
+		o.FirstName,
+		o.LastName,
+		o.Gender,
+		strconv.Itoa(o.BirthdayYear),
+		strconv.Itoa(o.BirthdayMonth),
+		strconv.Itoa(o.BirthdayDay),
+		strconv.Itoa(len(o.PassengerList())),
+		o.Email,
+		o.LaunchpadID,
+		o.LaunchpadTimezone,
+		o.DestinationID,
+		o.DestinationName,
+		o.LaunchDate.UTC().Format(time.RFC3339),
+		o.LaunchLocalDate,
+		o.CreatedAt.UTC().Format(time.RFC3339),
+	}), `failed to write csv row: id - %s`, o.ID)
+}
+
+func (e *csvOrderExportEncoder) flush() error {
+	e.w.Flush()
+	return errors.Wrap(e.w.Error(), `failed to flush csv`)
+}
+
+type ndjsonOrderExportEncoder struct {
+	enc *json.Encoder
+}
+
+func (*ndjsonOrderExportEncoder) contentType() string {
+	return "application/x-ndjson"
+}
+
+func (*ndjsonOrderExportEncoder) writeHeader() error {
+	return nil
+}
+
+func (e *ndjsonOrderExportEncoder) write(o types.OrderExport) error {
+	return errors.Wrapf(e.enc.Encode(o), `failed to encode order: id - %s`, o.ID)
+}
+
+func (*ndjsonOrderExportEncoder) flush() error {
+	return nil
+}
+
+/*
+exportOrders streams orders as csv or ndjson.
+
+	response status is sent before first row so failures after that only logged and response is cut
+*/
+func (e *HTTPEntry) exportOrders(wr http.ResponseWriter, req *http.Request) {
+	values := req.URL.Query()
+	limit, err := parseIntQueryParam(values, "limit", 0)
+	if err != nil {
+		e.respondError(req.Context(), err, wr)
+		return
+	}
+	offset, err := parseIntQueryParam(values, "offset", 0)
+	if err != nil {
+		e.respondError(req.Context(), err, wr)
+		return
+	}
+	if limit < 0 || offset < 0 {
+		e.respondError(req.Context(), types.NewErrInvalidData("limit and offset should not be negative"), wr)
+		return
+	}
+	format := values.Get("format")
+	enc, err := newOrderExportEncoder(format, wr)
+	if err != nil {
+		e.respondError(req.Context(), err, wr)
+		return
+	}
+	if format == "" {
+		format = exportFormatCSV
+	}
+	if err = http.NewResponseController(wr).SetWriteDeadline(time.Now().Add(exportWriteTimeout)); err != nil {
+		e.log.WithField("err", err.Error()).WithContext(req.Context()).Warn("failed to extend export write deadline")
+	}
+	var started bool
+	start := func() error {
+		started = true
+		wr.Header().Set("Content-Type", enc.contentType())
+		wr.Header().Set("Content-Disposition", `attachment; filename="orders.`+format+`"`)
+		wr.WriteHeader(http.StatusOK)
+		return enc.writeHeader()
+	}
+	var written int
+	err = e.os.Export(req.Context(), limit, offset, func(o types.OrderExport) error {
+		if !started {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+		if err := enc.write(o); err != nil {
+			return err
+		}
+		written++
+		if written%exportFlushEvery == 0 {
+			return flushExport(enc, wr)
+		}
+		return nil
+	})
+	if err != nil && !started {
+		e.respondError(req.Context(), err, wr)
+		return
+	}
+	if err == nil && !started {
+		err = start()
+	}
+	if err == nil {
+		err = flushExport(enc, wr)
+	}
+	if err != nil {
+		e.log.WithField("err", err.Error()).WithField("written", written).WithContext(req.Context()).
+			Error("failed to export orders")
+	}
+}
+
+func flushExport(enc orderExportEncoder, wr http.ResponseWriter) error {
+	if err := enc.flush(); err != nil {
+		return err
+	}
+	if f, ok := wr.(http.Flusher); ok {
+		f.Flush()
+	}
+	return nil
+}
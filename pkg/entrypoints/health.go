@@ -0,0 +1,40 @@
+package entrypoints
+
+import (
+	"net/http"
+
+	"github.com/leveldorado/space-trouble/pkg/types"
+)
+
+type healthChecker interface {
+	Health() types.HealthCheck
+}
+
+/*
+WithHealthCheck adds named dependency check to health endpoint
+*/
+func (e *HTTPEntry) WithHealthCheck(name string, c healthChecker) *HTTPEntry {
+	if e.checks == nil {
+		e.checks = map[string]healthChecker{}
+	}
+	e.checks[name] = c
+	return e
+}
+
+/*
+health responds 200 also in degraded mode since app keeps serving requests, status of response tells the mode
+*/
+func (e *HTTPEntry) health(wr http.ResponseWriter, req *http.Request) {
+	h := types.Health{Status: types.HealthStatusOK}
+	for name, c := range e.checks {
+		if h.Checks == nil {
+			h.Checks = map[string]types.HealthCheck{}
+		}
+		check := c.Health()
+		if check.Status != types.HealthStatusOK {
+			h.Status = types.HealthStatusDegraded
+		}
+		h.Checks[name] = check
+	}
+	e.respond(req.Context(), h, nil, http.StatusOK, wr)
+}
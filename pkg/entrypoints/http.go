@@ -3,12 +3,17 @@ package entrypoints
 import (
 	"context"
 	"encoding/json"
+	"expvar"
 	"net/http"
 	"net/url"
 	"strconv"
+	"time"
 
 	"github.com/go-chi/chi"
 	"github.com/go-chi/chi/middleware"
+	"github.com/leveldorado/space-trouble/pkg/tools/actor"
+	"github.com/leveldorado/space-trouble/pkg/tools/clock"
+	"github.com/leveldorado/space-trouble/pkg/tools/ical"
 	"github.com/leveldorado/space-trouble/pkg/types"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
@@ -20,15 +25,57 @@ type ordersService interface {
 	List(ctx context.Context, limit, offset int) ([]types.Order, error)
 	Delete(ctx context.Context, id string) error
 	Destinations(ctx context.Context) ([]types.Destination, error)
+	Export(ctx context.Context, limit, offset int, fn func(types.OrderExport) error) error
+	Import(ctx context.Context, rows []types.ImportRow, opts types.ImportOptions) (types.ImportReport, error)
+	History(ctx context.Context, id string) ([]types.OrderAuditEntry, error)
+	Alternatives(ctx context.Context, o types.Order, limit int) ([]types.FlightAlternative, error)
+	Manifest(ctx context.Context, launchpadID, localDate string) (types.Manifest, error)
+	OrderCalendar(ctx context.Context, id string) (ical.Calendar, error)
+	LaunchpadCalendar(ctx context.Context, launchpadID string, from time.Time, days int) (ical.Calendar, error)
+	Conflicts(ctx context.Context, limit, offset int) ([]types.Order, error)
+	DetectConflicts(ctx context.Context) (types.ConflictsReport, error)
+	Rebook(ctx context.Context, id string) (types.Order, error)
+	RebookConflicts(ctx context.Context) ([]types.RebookResult, error)
+}
+
+type notificationsService interface {
+	OrderNotifications(ctx context.Context, orderID string) ([]types.Notification, error)
+}
+
+type webhooksService interface {
+	Subscribe(ctx context.Context, sub types.WebhookSubscription) (types.WebhookSubscription, error)
+	Subscriptions(ctx context.Context) ([]types.WebhookSubscription, error)
+	Unsubscribe(ctx context.Context, id string) error
+	Deliveries(ctx context.Context, status string, limit, offset int) ([]types.WebhookDelivery, error)
+	Replay(ctx context.Context, id string) error
+}
+
+type waitlistService interface {
+	Join(ctx context.Context, o types.Order) (types.WaitlistEntry, error)
+	Get(ctx context.Context, id string) (types.WaitlistEntry, error)
+	Leave(ctx context.Context, id string) error
 }
 
 type HTTPEntry struct {
-	os  ordersService
-	log logrus.FieldLogger
+	os     ordersService
+	ws     webhooksService
+	ns     notificationsService
+	wls    waitlistService
+	lc     launchpadsCache
+	checks map[string]healthChecker
+	clock  timeSource
+	vc     virtualClock
+	log    logrus.FieldLogger
 }
 
-func NewHTTPEntry(os ordersService, log logrus.FieldLogger) *HTTPEntry {
-	return &HTTPEntry{os: os, log: log}
+func NewHTTPEntry(
+	os ordersService,
+	ws webhooksService,
+	ns notificationsService,
+	wls waitlistService,
+	log logrus.FieldLogger,
+) *HTTPEntry {
+	return &HTTPEntry{os: os, ws: ws, ns: ns, wls: wls, clock: clock.Real{}, log: log}
 }
 
 func (e *HTTPEntry) GetHandler() http.Handler {
@@ -36,20 +83,59 @@ func (e *HTTPEntry) GetHandler() http.Handler {
 	r.Use(middleware.RequestID)
 	r.Use(middleware.Recoverer)
 	r.Use(middleware.Logger)
-	r.Get("/health", func(wr http.ResponseWriter, _ *http.Request) {
-		wr.WriteHeader(http.StatusOK)
-	})
+	r.Use(actor.Middleware)
+	r.Get("/health", e.health)
+	r.Handle("/debug/vars", expvar.Handler())
 
 	r.Route("/api/v1", func(r chi.Router) {
 		r.Route("/orders", func(r chi.Router) {
 			r.Post("/", e.createOrder)
 			r.Get("/", e.list)
+			r.Get("/export", e.exportOrders)
+			r.Post("/import", e.importOrders)
+			r.Get("/conflicts", e.listConflicts)
+			r.Post("/conflicts/detect", e.detectConflicts)
+			r.Post("/conflicts/rebook", e.rebookConflicts)
 			r.Get("/{id}", e.getOrder)
 			r.Delete("/{id}", e.deleteOrder)
+			r.Get("/{id}/history", e.orderHistory)
+			r.Get("/{id}/notifications", e.orderNotifications)
+			r.Get("/{id}/calendar", e.orderCalendar)
+			r.Post("/{id}/rebook", e.rebookOrder)
+		})
+		r.Route("/launchpads", func(r chi.Router) {
+			r.Get("/{id}/calendar", e.launchpadCalendar)
+			r.Get("/{id}/manifest", e.launchpadManifest)
 		})
 		r.Route("/destinations", func(r chi.Router) {
 			r.Get("/", e.destinations)
 		})
+		r.Route("/waitlist", func(r chi.Router) {
+			r.Post("/", e.joinWaitlist)
+			r.Get("/{id}", e.getWaitlistEntry)
+			r.Delete("/{id}", e.leaveWaitlist)
+		})
+		r.Route("/webhooks", func(r chi.Router) {
+			r.Post("/", e.createWebhook)
+			r.Get("/", e.listWebhooks)
+			r.Delete("/{id}", e.deleteWebhook)
+			r.Get("/deliveries", e.listWebhookDeliveries)
+			r.Post("/deliveries/{id}/replay", e.replayWebhookDelivery)
+		})
+		if e.lc != nil {
+			r.Route("/admin/cache/launchpads", func(r chi.Router) {
+				r.Delete("/", e.purgeLaunchpadsCache)
+				r.Delete("/{id}", e.purgeLaunchpadsCache)
+			})
+		}
+		if e.vc != nil {
+			r.Route("/admin/clock", func(r chi.Router) {
+				r.Get("/", e.getClock)
+				r.Put("/", e.setClock)
+				r.Delete("/", e.resetClock)
+				r.Post("/advance", e.advanceClock)
+			})
+		}
 	})
 	return r
 }
@@ -69,9 +155,38 @@ func (e *HTTPEntry) createOrder(wr http.ResponseWriter, r *http.Request) {
 		return
 	}
 	id, err := e.os.Create(r.Context(), o)
+	impossible := types.ErrFlightImpossible{}
+	if errors.As(err, &impossible) {
+		err = e.withAlternatives(r, o, impossible)
+	}
 	e.respond(r.Context(), createOrderResponse{ID: id}, err, http.StatusCreated, wr)
 }
 
+const (
+	defaultFlightAlternatives = 3
+	maxFlightAlternatives     = 20
+)
+
+/*
+withAlternatives adds feasible flights to rejection, number of them is set by alternatives query param.
+
+	rejection is returned without alternatives when they can not be found
+*/
+func (e *HTTPEntry) withAlternatives(r *http.Request, o types.Order, impossible types.ErrFlightImpossible) error {
+	limit, err := parseIntQueryParam(r.URL.Query(), "alternatives", defaultFlightAlternatives)
+	if err != nil {
+		return err
+	}
+	if limit < 0 || limit > maxFlightAlternatives {
+		return types.NewErrInvalidData("alternatives param should be between 0 and " + strconv.Itoa(maxFlightAlternatives))
+	}
+	impossible.Alternatives, err = e.os.Alternatives(r.Context(), o, limit)
+	if err != nil {
+		e.log.WithField("err", err.Error()).WithContext(r.Context()).Warn("failed to find flight alternatives")
+	}
+	return impossible
+}
+
 type paginationResult struct {
 	Docs   interface{} `json:"docs"`
 	Limit  int         `json:"limit"`
@@ -136,6 +251,20 @@ func (e *HTTPEntry) deleteOrder(wr http.ResponseWriter, req *http.Request) {
 	e.respond(req.Context(), nil, err, http.StatusNoContent, wr)
 }
 
+func (e *HTTPEntry) orderHistory(wr http.ResponseWriter, req *http.Request) {
+	id := chi.URLParam(req, "id")
+	entries, err := e.os.History(req.Context(), id)
+	e.respond(req.Context(), entries, err, http.StatusOK, wr)
+}
+
+func (e *HTTPEntry) orderNotifications(wr http.ResponseWriter, req *http.Request) {
+	docs, err := e.ns.OrderNotifications(req.Context(), chi.URLParam(req, "id"))
+	if docs == nil {
+		docs = []types.Notification{}
+	}
+	e.respond(req.Context(), docs, err, http.StatusOK, wr)
+}
+
 func (e *HTTPEntry) respond(ctx context.Context, resp interface{}, err error, successCode int, wr http.ResponseWriter) {
 	if err != nil {
 		e.respondError(ctx, err, wr)
@@ -161,7 +290,9 @@ func (e *HTTPEntry) respond(ctx context.Context, resp interface{}, err error, su
 }
 
 type errorResponse struct {
-	Message string `json:"message"`
+	Message      string                    `json:"message"`
+	Reason       string                    `json:"reason,omitempty"`
+	Alternatives []types.FlightAlternative `json:"alternatives,omitempty"`
 }
 
 func (e *HTTPEntry) respondError(ctx context.Context, err error, wr http.ResponseWriter) {
@@ -171,6 +302,8 @@ func (e *HTTPEntry) respondError(ctx context.Context, err error, wr http.Respons
 	switch cause := errors.Cause(err).(type) {
 	case types.ErrFlightImpossible:
 		resp.Message = cause.Error()
+		resp.Reason = cause.Reason
+		resp.Alternatives = cause.Alternatives
 		code = http.StatusNotAcceptable
 	case types.ErrInvalidData:
 		resp.Message = cause.Error()
@@ -178,6 +311,15 @@ func (e *HTTPEntry) respondError(ctx context.Context, err error, wr http.Respons
 	case types.ErrDuplicatedOrder:
 		resp.Message = cause.Error()
 		code = http.StatusConflict
+	case types.ErrOrderChanged:
+		resp.Message = cause.Error()
+		code = http.StatusConflict
+	case types.ErrNotFound:
+		resp.Message = cause.Error()
+		code = http.StatusNotFound
+	case types.ErrUnavailable:
+		resp.Message = cause.Error()
+		code = http.StatusServiceUnavailable
 	default:
 		resp.Message = err.Error()
 	}
@@ -2,13 +2,19 @@ package entrypoints
 
 import (
 	"bytes"
+	"context"
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"testing"
 	"time"
 
+	"github.com/leveldorado/space-trouble/pkg/tools/actor"
+	"github.com/leveldorado/space-trouble/pkg/tools/ical"
 	"github.com/leveldorado/space-trouble/pkg/tools/logger"
 
 	"github.com/brianvoe/gofakeit"
@@ -37,7 +43,7 @@ func TestCreateOrderSuccess(t *testing.T) {
 	os := &mockOrdersService{}
 	os.On("Create", mock.Anything, o).Return(id, nil)
 
-	h := NewHTTPEntry(os, &logrus.Logger{}).GetHandler()
+	h := NewHTTPEntry(os, nil, nil, nil, &logrus.Logger{}).GetHandler()
 
 	b := &bytes.Buffer{}
 	require.NoError(t, json.NewEncoder(b).Encode(o))
@@ -59,7 +65,7 @@ func TestCreateOrderInvalid(t *testing.T) {
 	require.NoError(t, json.NewEncoder(b).Encode(o))
 	req := httptest.NewRequest(http.MethodPost, "/api/v1/orders", b)
 	resp := httptest.NewRecorder()
-	NewHTTPEntry(nil, &logrus.Logger{}).GetHandler().ServeHTTP(resp, req)
+	NewHTTPEntry(nil, nil, nil, nil, &logrus.Logger{}).GetHandler().ServeHTTP(resp, req)
 	require.Equal(t, http.StatusBadRequest, resp.Code)
 }
 
@@ -93,7 +99,8 @@ func TestCreateOrderFails(t *testing.T) {
 		s.On("Create", mock.Anything, order).Return("", err)
 		orders = append(orders, order)
 	}
-	h := NewHTTPEntry(s, logger.New()).GetHandler()
+	s.On("Alternatives", mock.Anything, orders[0], defaultFlightAlternatives).Return(nil, nil)
+	h := NewHTTPEntry(s, nil, nil, nil, logger.New()).GetHandler()
 	for i, order := range orders {
 		b := &bytes.Buffer{}
 		require.NoError(t, json.NewEncoder(b).Encode(order))
@@ -124,7 +131,7 @@ func TestOrdersList(t *testing.T) {
 	s := &mockOrdersService{}
 	s.On("List", mock.Anything, limit, offset).Return(orders, nil)
 
-	h := NewHTTPEntry(s, &logrus.Logger{}).GetHandler()
+	h := NewHTTPEntry(s, nil, nil, nil, &logrus.Logger{}).GetHandler()
 
 	req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/orders?limit=%d&offset=%d", limit, offset), nil)
 	resp := httptest.NewRecorder()
@@ -161,7 +168,7 @@ func TestGetOrder(t *testing.T) {
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/orders/"+order.ID, nil)
 	resp := httptest.NewRecorder()
 
-	NewHTTPEntry(s, &logrus.Logger{}).GetHandler().ServeHTTP(resp, req)
+	NewHTTPEntry(s, nil, nil, nil, &logrus.Logger{}).GetHandler().ServeHTTP(resp, req)
 
 	require.Equal(t, http.StatusOK, resp.Code)
 
@@ -172,6 +179,35 @@ func TestGetOrder(t *testing.T) {
 	s.AssertExpectations(t)
 }
 
+func TestOrderHistory(t *testing.T) {
+	id := uuid.New().String()
+	entries := []types.OrderAuditEntry{{
+		ID:        uuid.New().String(),
+		OrderID:   id,
+		Action:    types.OrderAuditActionCreate,
+		Actor:     "support",
+		After:     &types.Order{ID: id},
+		CreatedAt: time.Now().UTC(),
+	}}
+	s := &mockOrdersService{}
+	s.On("History", mock.Anything, id).
+		Return(func(ctx context.Context, _ string) []types.OrderAuditEntry {
+			require.Equal(t, "support", actor.FromContext(ctx))
+			return entries
+		}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/orders/"+id+"/history", nil)
+	req.Header.Set(actor.Header, "support")
+	resp := httptest.NewRecorder()
+	NewHTTPEntry(s, nil, nil, nil, &logrus.Logger{}).GetHandler().ServeHTTP(resp, req)
+
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.Equal(t, types.OrderAuditActionCreate, gjson.GetBytes(resp.Body.Bytes(), "0.action").String())
+	require.Equal(t, id, gjson.GetBytes(resp.Body.Bytes(), "0.after.id").String())
+	require.False(t, gjson.GetBytes(resp.Body.Bytes(), "0.before").Exists())
+	s.AssertExpectations(t)
+}
+
 func TestDeleteOrder(t *testing.T) {
 	id := uuid.New().String()
 	s := &mockOrdersService{}
@@ -180,7 +216,7 @@ func TestDeleteOrder(t *testing.T) {
 	req := httptest.NewRequest(http.MethodDelete, "/api/v1/orders/"+id, nil)
 	resp := httptest.NewRecorder()
 
-	NewHTTPEntry(s, &logrus.Logger{}).GetHandler().ServeHTTP(resp, req)
+	NewHTTPEntry(s, nil, nil, nil, &logrus.Logger{}).GetHandler().ServeHTTP(resp, req)
 
 	require.Equal(t, http.StatusNoContent, resp.Code)
 
@@ -200,7 +236,7 @@ func TestDestinations(t *testing.T) {
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/destinations", nil)
 	resp := httptest.NewRecorder()
 
-	NewHTTPEntry(s, logger.New()).GetHandler().ServeHTTP(resp, req)
+	NewHTTPEntry(s, nil, nil, nil, logger.New()).GetHandler().ServeHTTP(resp, req)
 	require.Equal(t, http.StatusOK, resp.Code)
 
 	var received []types.Destination
@@ -208,3 +244,663 @@ func TestDestinations(t *testing.T) {
 	require.Equal(t, destinations, received)
 	s.AssertExpectations(t)
 }
+
+func prepareExportService(t *testing.T, limit, offset int) ([]types.OrderExport, *mockOrdersService) {
+	docs := []types.OrderExport{
+		{
+			Order: types.Order{
+				ID:                uuid.New().String(),
+				FirstName:         gofakeit.FirstName(),
+				LastName:          gofakeit.LastName(),
+				Gender:            gofakeit.Gender(),
+				Email:             gofakeit.Email(),
+				BirthdayYear:      1990,
+				BirthdayDay:       10,
+				BirthdayMonth:     12,
+				LaunchpadID:       uuid.New().String(),
+				DestinationID:     uuid.New().String(),
+				LaunchDate:        time.Date(2053, 3, 5, 2, 0, 0, 0, time.UTC),
+				CreatedAt:         time.Date(2053, 1, 5, 2, 0, 0, 0, time.UTC),
+				LaunchLocalDate:   "2053-03-04",
+				LaunchpadTimezone: "America/New_York",
+			},
+			DestinationName: "Mars",
+		},
+	}
+	s := &mockOrdersService{}
+	s.On("Export", mock.Anything, limit, offset, mock.Anything).
+		Return(func(_ context.Context, _, _ int, fn func(types.OrderExport) error) error {
+			for _, doc := range docs {
+				require.NoError(t, fn(doc))
+			}
+			return nil
+		})
+	return docs, s
+}
+
+func TestExportOrdersCSV(t *testing.T) {
+	docs, s := prepareExportService(t, 0, 5)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/orders/export?format=csv&offset=5", nil)
+	resp := httptest.NewRecorder()
+
+	NewHTTPEntry(s, nil, nil, nil, &logrus.Logger{}).GetHandler().ServeHTTP(resp, req)
+
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.Equal(t, "text/csv", resp.Header().Get("Content-Type"))
+	records, err := csv.NewReader(resp.Body).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	require.Equal(t, csvOrderExportHeader, records[0])
+	require.Equal(t, []string{
+		docs[0].ID,
+		docs[0].FirstName,
+		docs[0].LastName,
+		docs[0].Gender,
+		"1990",
+		"12",
+		"10",
+		"1",
+		docs[0].Email,
+		docs[0].LaunchpadID,
+		"America/New_York",
+		docs[0].DestinationID,
+		"Mars",
+		"2053-03-05T02:00:00Z",
+		"2053-03-04",
+		"2053-01-05T02:00:00Z",
+	}, records[1])
+
+	s.AssertExpectations(t)
+}
+
+func TestExportOrdersNDJSON(t *testing.T) {
+	docs, s := prepareExportService(t, 10, 0)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/orders/export?format=ndjson&limit=10", nil)
+	resp := httptest.NewRecorder()
+
+	NewHTTPEntry(s, nil, nil, nil, &logrus.Logger{}).GetHandler().ServeHTTP(resp, req)
+
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.Equal(t, "application/x-ndjson", resp.Header().Get("Content-Type"))
+	var received types.OrderExport
+	dec := json.NewDecoder(resp.Body)
+	require.NoError(t, dec.Decode(&received))
+	require.Equal(t, docs[0], received)
+	require.False(t, dec.More())
+
+	s.AssertExpectations(t)
+}
+
+func TestExportOrdersLongerThanServerWriteTimeout(t *testing.T) {
+	s := &mockOrdersService{}
+	s.On("Export", mock.Anything, 0, 0, mock.Anything).
+		Return(func(_ context.Context, _, _ int, fn func(types.OrderExport) error) error {
+			for i := 0; i < exportFlushEvery*3; i++ {
+				if i%exportFlushEvery == 0 {
+					time.Sleep(100 * time.Millisecond)
+				}
+				if err := fn(types.OrderExport{Order: types.Order{ID: strconv.Itoa(i)}}); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	srv := httptest.NewUnstartedServer(NewHTTPEntry(s, nil, nil, nil, &logrus.Logger{}).GetHandler())
+	srv.Config.WriteTimeout = 50 * time.Millisecond
+	srv.Start()
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/api/v1/orders/export?format=csv")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	records, err := csv.NewReader(resp.Body).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, exportFlushEvery*3+1)
+}
+
+func TestExportOrdersInvalidFormat(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/orders/export?format=xml", nil)
+	resp := httptest.NewRecorder()
+
+	NewHTTPEntry(&mockOrdersService{}, nil, nil, nil, &logrus.Logger{}).GetHandler().ServeHTTP(resp, req)
+
+	require.Equal(t, http.StatusBadRequest, resp.Code)
+}
+
+func TestImportOrders(t *testing.T) {
+	data := "first_name,last_name,birthday_year,birthday_month,birthday_day,launchpad_id,destination_id,launch_date\n" +
+		"Vasyl,Osypchuk,2000,3,1,5e9e4501f509094ba4566f84,1,2053-09-04T00:00:00Z\n"
+	rows := []types.ImportRow{
+		{
+			Line: 2,
+			Order: types.Order{
+				FirstName:     "Vasyl",
+				LastName:      "Osypchuk",
+				BirthdayYear:  2000,
+				BirthdayMonth: 3,
+				BirthdayDay:   1,
+				LaunchpadID:   "5e9e4501f509094ba4566f84",
+				DestinationID: "1",
+				LaunchDate:    time.Date(2053, 9, 4, 0, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+	opts := types.ImportOptions{DryRun: true, AllOrNothing: true}
+	report := types.ImportReport{
+		ImportOptions: opts,
+		Total:         1,
+		Succeeded:     1,
+		Rows:          []types.ImportRowResult{{Line: 2, Status: types.ImportRowStatusOK}},
+	}
+	s := &mockOrdersService{}
+	s.On("Import", mock.Anything, rows, opts).Return(report, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/orders/import?dry_run=true&all_or_nothing=1", bytes.NewBufferString(data))
+	req.Header.Set("Content-Type", "text/csv; charset=utf-8")
+	resp := httptest.NewRecorder()
+
+	NewHTTPEntry(s, nil, nil, nil, &logrus.Logger{}).GetHandler().ServeHTTP(resp, req)
+
+	require.Equal(t, http.StatusOK, resp.Code)
+	var received types.ImportReport
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &received))
+	require.Equal(t, report, received)
+
+	s.AssertExpectations(t)
+}
+
+func TestImportOrdersLongerThanServerTimeouts(t *testing.T) {
+	header := "first_name,last_name,birthday_year,birthday_month,birthday_day,launchpad_id,destination_id,launch_date\n"
+	row := "Vasyl,Osypchuk,2000,3,1,5e9e4501f509094ba4566f84,1,2053-09-04T00:00:00Z\n"
+	s := &mockOrdersService{}
+	s.On("Import", mock.Anything, mock.Anything, types.ImportOptions{}).
+		Return(func(_ context.Context, rows []types.ImportRow, opts types.ImportOptions) types.ImportReport {
+			time.Sleep(100 * time.Millisecond)
+			return types.ImportReport{ImportOptions: opts, Total: len(rows), Succeeded: len(rows)}
+		}, nil)
+	srv := httptest.NewUnstartedServer(NewHTTPEntry(s, nil, nil, nil, &logrus.Logger{}).GetHandler())
+	srv.Config.ReadTimeout = 50 * time.Millisecond
+	srv.Config.WriteTimeout = 50 * time.Millisecond
+	srv.Start()
+	defer srv.Close()
+
+	body, w := io.Pipe()
+	go func() {
+		_, _ = io.WriteString(w, header)
+		for i := 0; i < 3; i++ {
+			time.Sleep(100 * time.Millisecond)
+			_, _ = io.WriteString(w, row)
+		}
+		_ = w.Close()
+	}()
+	resp, err := http.Post(srv.URL+"/api/v1/orders/import", "text/csv", body)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var received types.ImportReport
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&received))
+	require.Equal(t, 3, received.Total)
+}
+
+func TestImportOrdersInvalid(t *testing.T) {
+	h := NewHTTPEntry(&mockOrdersService{}, nil, nil, nil, &logrus.Logger{}).GetHandler()
+	for _, url := range []string{
+		"/api/v1/orders/import?format=xml",
+		"/api/v1/orders/import?format=csv&dry_run=maybe",
+		"/api/v1/orders/import",
+	} {
+		req := httptest.NewRequest(http.MethodPost, url, bytes.NewBufferString("first_name\n"))
+		resp := httptest.NewRecorder()
+		h.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusBadRequest, resp.Code, url)
+	}
+}
+
+func TestCreateWebhook(t *testing.T) {
+	sub := types.WebhookSubscription{URL: "https://example.com/hook", EventTypes: []string{types.OrderEventCreated}}
+	created := sub
+	created.ID = uuid.New().String()
+	created.Secret = "secret"
+	ws := &mockWebhooksService{}
+	ws.On("Subscribe", mock.Anything, sub).Return(created, nil)
+
+	b := &bytes.Buffer{}
+	require.NoError(t, json.NewEncoder(b).Encode(sub))
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/webhooks", b)
+	resp := httptest.NewRecorder()
+	NewHTTPEntry(nil, ws, nil, nil, &logrus.Logger{}).GetHandler().ServeHTTP(resp, req)
+
+	require.Equal(t, http.StatusCreated, resp.Code)
+	require.Equal(t, created.ID, gjson.GetBytes(resp.Body.Bytes(), "id").String())
+	require.Equal(t, created.Secret, gjson.GetBytes(resp.Body.Bytes(), "secret").String())
+	ws.AssertExpectations(t)
+}
+
+func TestDeleteWebhookNotFound(t *testing.T) {
+	id := uuid.New().String()
+	ws := &mockWebhooksService{}
+	ws.On("Unsubscribe", mock.Anything, id).Return(types.ErrNotFound{})
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/webhooks/"+id, nil)
+	resp := httptest.NewRecorder()
+	NewHTTPEntry(nil, ws, nil, nil, &logrus.Logger{}).GetHandler().ServeHTTP(resp, req)
+
+	require.Equal(t, http.StatusNotFound, resp.Code)
+}
+
+func TestListWebhookDeliveries(t *testing.T) {
+	deliveries := []types.WebhookDelivery{{
+		ID:             uuid.New().String(),
+		SubscriptionID: uuid.New().String(),
+		Status:         types.WebhookDeliveryStatusDead,
+		Attempts:       8,
+	}}
+	ws := &mockWebhooksService{}
+	ws.On("Deliveries", mock.Anything, types.WebhookDeliveryStatusDead, 10, 0).Return(deliveries, nil)
+	h := NewHTTPEntry(nil, ws, nil, nil, &logrus.Logger{}).GetHandler()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/webhooks/deliveries?status=dead&limit=10", nil)
+	resp := httptest.NewRecorder()
+	h.ServeHTTP(resp, req)
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.Equal(t, deliveries[0].ID, gjson.GetBytes(resp.Body.Bytes(), "docs.0.id").String())
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/webhooks/deliveries?status=lost", nil)
+	resp = httptest.NewRecorder()
+	h.ServeHTTP(resp, req)
+	require.Equal(t, http.StatusBadRequest, resp.Code)
+}
+
+func TestReplayWebhookDelivery(t *testing.T) {
+	id := uuid.New().String()
+	ws := &mockWebhooksService{}
+	ws.On("Replay", mock.Anything, id).Return(nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/webhooks/deliveries/"+id+"/replay", nil)
+	resp := httptest.NewRecorder()
+	NewHTTPEntry(nil, ws, nil, nil, &logrus.Logger{}).GetHandler().ServeHTTP(resp, req)
+
+	require.Equal(t, http.StatusAccepted, resp.Code)
+	ws.AssertExpectations(t)
+}
+
+func TestOrderNotifications(t *testing.T) {
+	id := uuid.New().String()
+	docs := []types.Notification{{
+		ID:        uuid.New().String(),
+		OrderID:   id,
+		Kind:      types.NotificationKindBookingConfirmed,
+		Recipient: gofakeit.Email(),
+		Body:      "body",
+		Status:    types.NotificationStatusSent,
+	}}
+	ns := &mockNotificationsService{}
+	ns.On("OrderNotifications", mock.Anything, id).Return(docs, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/orders/"+id+"/notifications", nil)
+	resp := httptest.NewRecorder()
+	NewHTTPEntry(nil, nil, ns, nil, &logrus.Logger{}).GetHandler().ServeHTTP(resp, req)
+
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.Equal(t, types.NotificationStatusSent, gjson.GetBytes(resp.Body.Bytes(), "0.status").String())
+	require.False(t, gjson.GetBytes(resp.Body.Bytes(), "0.body").Exists())
+	ns.AssertExpectations(t)
+}
+
+func TestOrderCalendar(t *testing.T) {
+	id := uuid.New().String()
+	start := time.Date(2053, 7, 1, 14, 0, 0, 0, time.UTC)
+	s := &mockOrdersService{}
+	s.On("OrderCalendar", mock.Anything, id).Return(ical.Calendar{Events: []ical.Event{{
+		UID:     id + "@space-trouble",
+		Summary: "Flight to Mars",
+		Start:   start,
+		End:     start.Add(time.Hour),
+	}}}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/orders/"+id+"/calendar", nil)
+	resp := httptest.NewRecorder()
+
+	NewHTTPEntry(s, nil, nil, nil, &logrus.Logger{}).GetHandler().ServeHTTP(resp, req)
+
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.Equal(t, ical.ContentType, resp.Header().Get("Content-Type"))
+	require.Contains(t, resp.Header().Get("Content-Disposition"), "order-"+id+".ics")
+	require.Contains(t, resp.Body.String(), "SUMMARY:Flight to Mars\r\n")
+	require.Contains(t, resp.Body.String(), "DTSTART:20530701T140000Z\r\n")
+
+	s.AssertExpectations(t)
+}
+
+func TestLaunchpadCalendar(t *testing.T) {
+	id := uuid.New().String()
+	s := &mockOrdersService{}
+	s.On("LaunchpadCalendar", mock.Anything, id, mock.Anything, 30).Return(ical.Calendar{Name: "Pad"}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/launchpads/"+id+"/calendar?days=30", nil)
+	resp := httptest.NewRecorder()
+
+	NewHTTPEntry(s, nil, nil, nil, &logrus.Logger{}).GetHandler().ServeHTTP(resp, req)
+
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.Contains(t, resp.Body.String(), "X-WR-CALNAME:Pad\r\n")
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/launchpads/"+id+"/calendar?days=x", nil)
+	resp = httptest.NewRecorder()
+	NewHTTPEntry(s, nil, nil, nil, &logrus.Logger{}).GetHandler().ServeHTTP(resp, req)
+	require.Equal(t, http.StatusBadRequest, resp.Code)
+
+	s.AssertExpectations(t)
+}
+
+func TestJoinWaitlist(t *testing.T) {
+	o := types.Order{
+		FirstName:     gofakeit.FirstName(),
+		LastName:      gofakeit.LastName(),
+		Gender:        gofakeit.Gender(),
+		BirthdayYear:  1990,
+		BirthdayDay:   10,
+		BirthdayMonth: 11,
+		LaunchpadID:   uuid.New().String(),
+		DestinationID: uuid.New().String(),
+		LaunchDate:    time.Now().UTC().Add(48 * time.Hour).Truncate(time.Second),
+	}
+	doc := types.WaitlistEntry{ID: uuid.New().String(), Order: o, Status: types.WaitlistStatusWaiting}
+	s := &mockWaitlistService{}
+	s.On("Join", mock.Anything, o).Return(doc, nil)
+
+	b := &bytes.Buffer{}
+	require.NoError(t, json.NewEncoder(b).Encode(o))
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/waitlist", b)
+	resp := httptest.NewRecorder()
+
+	NewHTTPEntry(nil, nil, nil, s, &logrus.Logger{}).GetHandler().ServeHTTP(resp, req)
+
+	require.Equal(t, http.StatusCreated, resp.Code)
+	var result types.WaitlistEntry
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &result))
+	require.Equal(t, doc.ID, result.ID)
+	require.Equal(t, types.WaitlistStatusWaiting, result.Status)
+
+	s.AssertExpectations(t)
+}
+
+func TestLeaveWaitlist(t *testing.T) {
+	id := uuid.New().String()
+	s := &mockWaitlistService{}
+	s.On("Leave", mock.Anything, id).Return(nil)
+	s.On("Leave", mock.Anything, mock.Anything).Return(types.ErrNotFound{})
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/waitlist/"+id, nil)
+	resp := httptest.NewRecorder()
+	NewHTTPEntry(nil, nil, nil, s, &logrus.Logger{}).GetHandler().ServeHTTP(resp, req)
+	require.Equal(t, http.StatusNoContent, resp.Code)
+
+	req = httptest.NewRequest(http.MethodDelete, "/api/v1/waitlist/"+uuid.New().String(), nil)
+	resp = httptest.NewRecorder()
+	NewHTTPEntry(nil, nil, nil, s, &logrus.Logger{}).GetHandler().ServeHTTP(resp, req)
+	require.Equal(t, http.StatusNotFound, resp.Code)
+}
+
+func TestCreateOrderImpossibleReason(t *testing.T) {
+	o := types.Order{
+		FirstName:     gofakeit.FirstName(),
+		LastName:      gofakeit.LastName(),
+		Gender:        gofakeit.Gender(),
+		BirthdayYear:  1990,
+		BirthdayDay:   10,
+		BirthdayMonth: 11,
+		LaunchpadID:   uuid.New().String(),
+		DestinationID: uuid.New().String(),
+		LaunchDate:    time.Now().UTC().Add(48 * time.Hour),
+	}
+	alternatives := []types.FlightAlternative{{
+		LaunchpadID:   o.LaunchpadID,
+		DestinationID: o.DestinationID,
+		LocalDate:     "2053-03-14",
+		LaunchDate:    time.Date(2053, 3, 14, 10, 0, 0, 0, time.UTC),
+	}}
+	s := &mockOrdersService{}
+	s.On("Create", mock.Anything, mock.Anything).
+		Return("", types.NewErrFlightImpossible(types.FlightImpossibleReasonCompetitorLaunch))
+	s.On("Alternatives", mock.Anything, mock.Anything, 5).Return(alternatives, nil)
+	s.On("Alternatives", mock.Anything, mock.Anything, 3).Return(nil, errors.New("spacex is down"))
+
+	post := func(query string) *httptest.ResponseRecorder {
+		b := &bytes.Buffer{}
+		require.NoError(t, json.NewEncoder(b).Encode(o))
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/orders"+query, b)
+		resp := httptest.NewRecorder()
+		NewHTTPEntry(s, nil, nil, nil, &logrus.Logger{}).GetHandler().ServeHTTP(resp, req)
+		return resp
+	}
+
+	resp := post("?alternatives=5")
+	require.Equal(t, http.StatusNotAcceptable, resp.Code)
+	result := errorResponse{}
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &result))
+	require.Equal(t, types.FlightImpossibleReasonCompetitorLaunch, result.Reason)
+	require.Equal(t, alternatives, result.Alternatives)
+
+	resp = post("")
+	require.Equal(t, http.StatusNotAcceptable, resp.Code)
+	result = errorResponse{}
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &result))
+	require.Empty(t, result.Alternatives)
+
+	require.Equal(t, http.StatusBadRequest, post("?alternatives=100").Code)
+}
+
+func TestLaunchpadManifest(t *testing.T) {
+	id := uuid.New().String()
+	location, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	m := types.Manifest{
+		LaunchpadID: id,
+		LocalDate:   "2053-03-05",
+		Timezone:    location.String(),
+		Seats:       2,
+		Orders: []types.ManifestOrder{{
+			OrderID:         uuid.New().String(),
+			DestinationName: "Mars",
+			LaunchDate:      time.Date(2053, 3, 5, 22, 0, 0, 0, location),
+			Seats:           2,
+			Passengers: []types.Passenger{
+				{FirstName: "Vasyl", LastName: "Osypchuk", Gender: "male", BirthdayYear: 2000, BirthdayMonth: 3, BirthdayDay: 1},
+				{FirstName: "Olena", LastName: "Osypchuk", Gender: "female", BirthdayYear: 2001, BirthdayMonth: 5, BirthdayDay: 7},
+			},
+		}},
+	}
+	s := &mockOrdersService{}
+	s.On("Manifest", mock.Anything, id, "2053-03-05").Return(m, nil)
+	h := NewHTTPEntry(s, nil, nil, nil, &logrus.Logger{}).GetHandler()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/launchpads/"+id+"/manifest?date=2053-03-05", nil)
+	resp := httptest.NewRecorder()
+	h.ServeHTTP(resp, req)
+	require.Equal(t, http.StatusOK, resp.Code)
+	var result types.Manifest
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &result))
+	require.Equal(t, 2, result.Seats)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/launchpads/"+id+"/manifest?date=2053-03-05&format=csv", nil)
+	resp = httptest.NewRecorder()
+	h.ServeHTTP(resp, req)
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.Equal(t, "text/csv", resp.Header().Get("Content-Type"))
+	rows, err := csv.NewReader(resp.Body).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+	require.Equal(t, csvManifestHeader, rows[0])
+	require.Equal(t, []string{m.Orders[0].OrderID, "2", "Olena", "Osypchuk", "female", "2001-05-07", "Mars", "22:00 EST", ""}, rows[2])
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/launchpads/"+id+"/manifest", nil)
+	resp = httptest.NewRecorder()
+	h.ServeHTTP(resp, req)
+	require.Equal(t, http.StatusBadRequest, resp.Code)
+
+	s.AssertExpectations(t)
+}
+
+func TestListConflicts(t *testing.T) {
+	o := types.Order{
+		ID:             uuid.New().String(),
+		LaunchpadID:    uuid.New().String(),
+		DestinationID:  uuid.New().String(),
+		LaunchDate:     time.Date(2053, 3, 5, 10, 0, 0, 0, time.UTC),
+		Status:         types.OrderStatusConflict,
+		ConflictReason: types.FlightImpossibleReasonCompetitorLaunch,
+	}
+	s := &mockOrdersService{}
+	s.On("Conflicts", mock.Anything, defaultOrdersLimit, 0).Return([]types.Order{o}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/orders/conflicts", nil)
+	resp := httptest.NewRecorder()
+	NewHTTPEntry(s, nil, nil, nil, &logrus.Logger{}).GetHandler().ServeHTTP(resp, req)
+
+	require.Equal(t, http.StatusOK, resp.Code)
+	var result struct {
+		Docs []types.Order `json:"docs"`
+	}
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &result))
+	require.Equal(t, []types.Order{o}, result.Docs)
+	s.AssertExpectations(t)
+}
+
+func TestRebookOrder(t *testing.T) {
+	rebooked := types.Order{ID: uuid.New().String(), Status: types.OrderStatusActive}
+	notConflict := uuid.New().String()
+	s := &mockOrdersService{}
+	s.On("Rebook", mock.Anything, rebooked.ID).Return(rebooked, nil)
+	s.On("Rebook", mock.Anything, notConflict).Return(types.Order{}, types.NewErrInvalidData("order is not in conflict"))
+	h := NewHTTPEntry(s, nil, nil, nil, &logrus.Logger{}).GetHandler()
+
+	resp := httptest.NewRecorder()
+	h.ServeHTTP(resp, httptest.NewRequest(http.MethodPost, "/api/v1/orders/"+rebooked.ID+"/rebook", nil))
+	require.Equal(t, http.StatusOK, resp.Code)
+	var result types.Order
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &result))
+	require.Equal(t, rebooked.ID, result.ID)
+
+	resp = httptest.NewRecorder()
+	h.ServeHTTP(resp, httptest.NewRequest(http.MethodPost, "/api/v1/orders/"+notConflict+"/rebook", nil))
+	require.Equal(t, http.StatusBadRequest, resp.Code)
+	s.AssertExpectations(t)
+}
+
+func TestPurgeLaunchpadsCache(t *testing.T) {
+	c := &mockLaunchpadsCache{}
+	c.On("Purge", "pad-1").Return(1)
+	c.On("Purge", "").Return(5)
+	h := NewHTTPEntry(nil, nil, nil, nil, &logrus.Logger{}).WithLaunchpadsCache(c).GetHandler()
+
+	resp := httptest.NewRecorder()
+	h.ServeHTTP(resp, httptest.NewRequest(http.MethodDelete, "/api/v1/admin/cache/launchpads/pad-1", nil))
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.Equal(t, int64(1), gjson.GetBytes(resp.Body.Bytes(), "purged").Int())
+
+	resp = httptest.NewRecorder()
+	h.ServeHTTP(resp, httptest.NewRequest(http.MethodDelete, "/api/v1/admin/cache/launchpads", nil))
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.Equal(t, int64(5), gjson.GetBytes(resp.Body.Bytes(), "purged").Int())
+	c.AssertExpectations(t)
+
+	resp = httptest.NewRecorder()
+	NewHTTPEntry(nil, nil, nil, nil, &logrus.Logger{}).GetHandler().
+		ServeHTTP(resp, httptest.NewRequest(http.MethodDelete, "/api/v1/admin/cache/launchpads", nil))
+	require.Equal(t, http.StatusNotFound, resp.Code)
+}
+
+func TestVirtualClock(t *testing.T) {
+	now := time.Date(2053, 3, 6, 5, 0, 0, 0, time.UTC)
+	c := &mockVirtualClock{}
+	c.On("Now").Return(now)
+	c.On("Offset").Return(24 * time.Hour)
+	c.On("Set", now).Return(now)
+	c.On("Advance", 36*time.Hour).Return(now.Add(36 * time.Hour))
+	c.On("Reset").Return(now)
+	s := &mockOrdersService{}
+	s.On("LaunchpadCalendar", mock.Anything, "pad-1", now, 0).Return(ical.Calendar{Name: "Pad"}, nil)
+	h := NewHTTPEntry(s, nil, nil, nil, &logrus.Logger{}).WithVirtualClock(c).GetHandler()
+
+	resp := httptest.NewRecorder()
+	h.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/api/v1/admin/clock", nil))
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.Equal(t, now.Format(time.RFC3339), gjson.GetBytes(resp.Body.Bytes(), "now").String())
+	require.Equal(t, "24h0m0s", gjson.GetBytes(resp.Body.Bytes(), "offset").String())
+
+	resp = httptest.NewRecorder()
+	h.ServeHTTP(resp, httptest.NewRequest(http.MethodPut, "/api/v1/admin/clock", bytes.NewBufferString(`{"now":"2053-03-06T05:00:00Z"}`)))
+	require.Equal(t, http.StatusOK, resp.Code)
+
+	resp = httptest.NewRecorder()
+	h.ServeHTTP(resp, httptest.NewRequest(http.MethodPost, "/api/v1/admin/clock/advance", bytes.NewBufferString(`{"duration":"36h"}`)))
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.Equal(t, now.Add(36*time.Hour).Format(time.RFC3339), gjson.GetBytes(resp.Body.Bytes(), "now").String())
+
+	resp = httptest.NewRecorder()
+	h.ServeHTTP(resp, httptest.NewRequest(http.MethodDelete, "/api/v1/admin/clock", nil))
+	require.Equal(t, http.StatusOK, resp.Code)
+
+	for _, body := range []string{`{"duration":"month"}`, `{"duration":""}`, `{`} {
+		resp = httptest.NewRecorder()
+		h.ServeHTTP(resp, httptest.NewRequest(http.MethodPost, "/api/v1/admin/clock/advance", bytes.NewBufferString(body)))
+		require.Equal(t, http.StatusBadRequest, resp.Code, body)
+	}
+	resp = httptest.NewRecorder()
+	h.ServeHTTP(resp, httptest.NewRequest(http.MethodPut, "/api/v1/admin/clock", bytes.NewBufferString(`{}`)))
+	require.Equal(t, http.StatusBadRequest, resp.Code)
+
+	// launchpad calendar starts from virtual today
+	resp = httptest.NewRecorder()
+	h.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/api/v1/launchpads/pad-1/calendar", nil))
+	require.Equal(t, http.StatusOK, resp.Code)
+	c.AssertExpectations(t)
+	s.AssertExpectations(t)
+
+	resp = httptest.NewRecorder()
+	NewHTTPEntry(nil, nil, nil, nil, &logrus.Logger{}).GetHandler().
+		ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/api/v1/admin/clock", nil))
+	require.Equal(t, http.StatusNotFound, resp.Code)
+}
+
+func TestGetOrderUnavailable(t *testing.T) {
+	id := uuid.New().String()
+	s := &mockOrdersService{}
+	s.On("Get", mock.Anything, id).Return(types.Order{}, errors.Wrap(types.ErrUnavailable{Service: "spacex api"}, "failed to get launchpad"))
+	resp := httptest.NewRecorder()
+	NewHTTPEntry(s, nil, nil, nil, &logrus.Logger{}).GetHandler().
+		ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/api/v1/orders/"+id, nil))
+	require.Equal(t, http.StatusServiceUnavailable, resp.Code)
+	require.Equal(t, "spacex api is unavailable", gjson.GetBytes(resp.Body.Bytes(), "message").String())
+}
+
+func TestHealth(t *testing.T) {
+	resp := httptest.NewRecorder()
+	NewHTTPEntry(nil, nil, nil, nil, &logrus.Logger{}).GetHandler().
+		ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/health", nil))
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.Equal(t, types.HealthStatusOK, gjson.GetBytes(resp.Body.Bytes(), "status").String())
+
+	since := time.Now().UTC()
+	ok := &mockHealthChecker{}
+	ok.On("Health").Return(types.HealthCheck{Status: types.HealthStatusOK})
+	degraded := &mockHealthChecker{}
+	degraded.On("Health").Return(types.HealthCheck{Status: types.HealthStatusDegraded, Message: "launchpads are served from snapshot", Since: &since})
+	resp = httptest.NewRecorder()
+	NewHTTPEntry(nil, nil, nil, nil, &logrus.Logger{}).
+		WithHealthCheck("db", ok).
+		WithHealthCheck("launchpads", degraded).
+		GetHandler().
+		ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/health", nil))
+	require.Equal(t, http.StatusOK, resp.Code)
+	body := resp.Body.Bytes()
+	require.Equal(t, types.HealthStatusDegraded, gjson.GetBytes(body, "status").String())
+	require.Equal(t, types.HealthStatusOK, gjson.GetBytes(body, "checks.db.status").String())
+	require.Equal(t, types.HealthStatusDegraded, gjson.GetBytes(body, "checks.launchpads.status").String())
+	require.Equal(t, "launchpads are served from snapshot", gjson.GetBytes(body, "checks.launchpads.message").String())
+	ok.AssertExpectations(t)
+	degraded.AssertExpectations(t)
+}
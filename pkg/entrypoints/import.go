@@ -0,0 +1,86 @@
+package entrypoints
+
+import (
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/leveldorado/space-trouble/pkg/tools/ordersimport"
+	"github.com/leveldorado/space-trouble/pkg/types"
+)
+
+const (
+	maxImportBodySize = 32 << 20
+	// import reads and validates up to maxImportBodySize body, so it gets longer deadlines than server timeouts of other handlers
+	importTimeout = 5 * time.Minute
+)
+
+/*
+importOrders accepts csv or ndjson body and responds with per row report.
+
+	format is taken from format query param or from content type
+*/
+func (e *HTTPEntry) importOrders(wr http.ResponseWriter, req *http.Request) {
+	values := req.URL.Query()
+	opts, err := parseImportOptions(values)
+	if err != nil {
+		e.respondError(req.Context(), err, wr)
+		return
+	}
+	format := values.Get("format")
+	if format == "" {
+		format = importFormatFromContentType(req.Header.Get("Content-Type"))
+	}
+	rc := http.NewResponseController(wr)
+	deadline := time.Now().Add(importTimeout)
+	if err = rc.SetReadDeadline(deadline); err != nil {
+		e.log.WithField("err", err.Error()).WithContext(req.Context()).Warn("failed to extend import read deadline")
+	}
+	if err = rc.SetWriteDeadline(deadline); err != nil {
+		e.log.WithField("err", err.Error()).WithContext(req.Context()).Warn("failed to extend import write deadline")
+	}
+	rows, err := ordersimport.Decode(format, http.MaxBytesReader(wr, req.Body, maxImportBodySize))
+	if err != nil {
+		e.respondError(req.Context(), err, wr)
+		return
+	}
+	report, err := e.os.Import(req.Context(), rows, opts)
+	e.respond(req.Context(), report, err, http.StatusOK, wr)
+}
+
+func parseImportOptions(values url.Values) (types.ImportOptions, error) {
+	dryRun, err := parseBoolQueryParam(values, "dry_run")
+	if err != nil {
+		return types.ImportOptions{}, err
+	}
+	allOrNothing, err := parseBoolQueryParam(values, "all_or_nothing")
+	if err != nil {
+		return types.ImportOptions{}, err
+	}
+	return types.ImportOptions{DryRun: dryRun, AllOrNothing: allOrNothing}, nil
+}
+
+func parseBoolQueryParam(values url.Values, key string) (bool, error) {
+	str := values.Get(key)
+	if str == "" {
+		return false, nil
+	}
+	value, err := strconv.ParseBool(str)
+	if err != nil {
+		return false, types.NewErrInvalidData("invalid value " + str + " for key " + key)
+	}
+	return value, nil
+}
+
+func importFormatFromContentType(contentType string) string {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch mediaType {
+	case "text/csv":
+		return ordersimport.FormatCSV
+	case "application/x-ndjson", "application/ndjson":
+		return ordersimport.FormatNDJSON
+	}
+	return mediaType
+}
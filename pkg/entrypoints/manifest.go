@@ -0,0 +1,90 @@
+package entrypoints
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+	"github.com/leveldorado/space-trouble/pkg/types"
+	"github.com/pkg/errors"
+)
+
+const (
+	manifestFormatJSON = "json"
+	manifestFormatCSV  = "csv"
+)
+
+var csvManifestHeader = []string{
+	"order_id",
+	"seat",
+	"first_name",
+	"last_name",
+	"gender",
+	"birthday",
+	"destination_name",
+	"launch_time",
+	"email",
+}
+
+/*
+launchpadManifest returns manifest for local date of launchpad as json or csv with row per passenger
+*/
+func (e *HTTPEntry) launchpadManifest(wr http.ResponseWriter, req *http.Request) {
+	values := req.URL.Query()
+	format := values.Get("format")
+	if format != "" && format != manifestFormatJSON && format != manifestFormatCSV {
+		e.respondError(req.Context(), types.NewErrInvalidData("unsupported manifest format "+format), wr)
+		return
+	}
+	date := values.Get("date")
+	if date == "" {
+		e.respondError(req.Context(), types.NewErrInvalidData("date is required"), wr)
+		return
+	}
+	id := chi.URLParam(req, "id")
+	m, err := e.os.Manifest(req.Context(), id, date)
+	if err != nil || format != manifestFormatCSV {
+		e.respond(req.Context(), m, err, http.StatusOK, wr)
+		return
+	}
+	b := &bytes.Buffer{}
+	if err = writeManifestCSV(b, m); err != nil {
+		e.respondError(req.Context(), err, wr)
+		return
+	}
+	wr.Header().Set("Content-Type", "text/csv")
+	wr.Header().Set("Content-Disposition", `attachment; filename="manifest-`+id+`-`+date+`.csv"`)
+	wr.WriteHeader(http.StatusOK)
+	if _, err = wr.Write(b.Bytes()); err != nil {
+		e.log.WithField("err", err.Error()).WithContext(req.Context()).Warn("failed to write manifest")
+	}
+}
+
+func writeManifestCSV(b *bytes.Buffer, m types.Manifest) error {
+	w := csv.NewWriter(b)
+	if err := w.Write(csvManifestHeader); err != nil {
+		return errors.Wrap(err, `failed to write csv header`)
+	}
+	for _, o := range m.Orders {
+		for i, p := range o.Passengers {
+			if err := w.Write([]string{
+				o.OrderID,
+				strconv.Itoa(i + 1),
+				p.FirstName,
+				p.LastName,
+				p.Gender,
+				fmt.Sprintf("%04d-%02d-%02d", p.BirthdayYear, p.BirthdayMonth, p.BirthdayDay),
+				o.DestinationName,
+				o.LaunchDate.Format("15:04 MST"),
+				o.Email,
+			}); err != nil {
+				return errors.Wrapf(err, `failed to write csv row: order - %s`, o.OrderID)
+			}
+		}
+	}
+	w.Flush()
+	return errors.Wrap(w.Error(), `failed to flush csv`)
+}
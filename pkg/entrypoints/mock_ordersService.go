@@ -4,7 +4,9 @@ package entrypoints
 
 import (
 	context "context"
+	time "time"
 
+	ical "github.com/leveldorado/space-trouble/pkg/tools/ical"
 	types "github.com/leveldorado/space-trouble/pkg/types"
 	mock "github.com/stretchr/testify/mock"
 )
@@ -14,6 +16,52 @@ type mockOrdersService struct {
 	mock.Mock
 }
 
+// Alternatives provides a mock function with given fields: ctx, o, limit
+func (_m *mockOrdersService) Alternatives(ctx context.Context, o types.Order, limit int) ([]types.FlightAlternative, error) {
+	ret := _m.Called(ctx, o, limit)
+
+	var r0 []types.FlightAlternative
+	if rf, ok := ret.Get(0).(func(context.Context, types.Order, int) []types.FlightAlternative); ok {
+		r0 = rf(ctx, o, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.FlightAlternative)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, types.Order, int) error); ok {
+		r1 = rf(ctx, o, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Conflicts provides a mock function with given fields: ctx, limit, offset
+func (_m *mockOrdersService) Conflicts(ctx context.Context, limit int, offset int) ([]types.Order, error) {
+	ret := _m.Called(ctx, limit, offset)
+
+	var r0 []types.Order
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) []types.Order); ok {
+		r0 = rf(ctx, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.Order)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Create provides a mock function with given fields: ctx, o
 func (_m *mockOrdersService) Create(ctx context.Context, o types.Order) (string, error) {
 	ret := _m.Called(ctx, o)
@@ -72,6 +120,41 @@ func (_m *mockOrdersService) Destinations(ctx context.Context) ([]types.Destinat
 	return r0, r1
 }
 
+// DetectConflicts provides a mock function with given fields: ctx
+func (_m *mockOrdersService) DetectConflicts(ctx context.Context) (types.ConflictsReport, error) {
+	ret := _m.Called(ctx)
+
+	var r0 types.ConflictsReport
+	if rf, ok := ret.Get(0).(func(context.Context) types.ConflictsReport); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(types.ConflictsReport)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Export provides a mock function with given fields: ctx, limit, offset, fn
+func (_m *mockOrdersService) Export(ctx context.Context, limit int, offset int, fn func(types.OrderExport) error) error {
+	ret := _m.Called(ctx, limit, offset, fn)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, func(types.OrderExport) error) error); ok {
+		r0 = rf(ctx, limit, offset, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Get provides a mock function with given fields: ctx, id
 func (_m *mockOrdersService) Get(ctx context.Context, id string) (types.Order, error) {
 	ret := _m.Called(ctx, id)
@@ -93,6 +176,71 @@ func (_m *mockOrdersService) Get(ctx context.Context, id string) (types.Order, e
 	return r0, r1
 }
 
+// History provides a mock function with given fields: ctx, id
+func (_m *mockOrdersService) History(ctx context.Context, id string) ([]types.OrderAuditEntry, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 []types.OrderAuditEntry
+	if rf, ok := ret.Get(0).(func(context.Context, string) []types.OrderAuditEntry); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.OrderAuditEntry)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Import provides a mock function with given fields: ctx, rows, opts
+func (_m *mockOrdersService) Import(ctx context.Context, rows []types.ImportRow, opts types.ImportOptions) (types.ImportReport, error) {
+	ret := _m.Called(ctx, rows, opts)
+
+	var r0 types.ImportReport
+	if rf, ok := ret.Get(0).(func(context.Context, []types.ImportRow, types.ImportOptions) types.ImportReport); ok {
+		r0 = rf(ctx, rows, opts)
+	} else {
+		r0 = ret.Get(0).(types.ImportReport)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, []types.ImportRow, types.ImportOptions) error); ok {
+		r1 = rf(ctx, rows, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// LaunchpadCalendar provides a mock function with given fields: ctx, launchpadID, from, days
+func (_m *mockOrdersService) LaunchpadCalendar(ctx context.Context, launchpadID string, from time.Time, days int) (ical.Calendar, error) {
+	ret := _m.Called(ctx, launchpadID, from, days)
+
+	var r0 ical.Calendar
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time, int) ical.Calendar); ok {
+		r0 = rf(ctx, launchpadID, from, days)
+	} else {
+		r0 = ret.Get(0).(ical.Calendar)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time, int) error); ok {
+		r1 = rf(ctx, launchpadID, from, days)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // List provides a mock function with given fields: ctx, limit, offset
 func (_m *mockOrdersService) List(ctx context.Context, limit int, offset int) ([]types.Order, error) {
 	ret := _m.Called(ctx, limit, offset)
@@ -116,6 +264,92 @@ func (_m *mockOrdersService) List(ctx context.Context, limit int, offset int) ([
 	return r0, r1
 }
 
+// Manifest provides a mock function with given fields: ctx, launchpadID, localDate
+func (_m *mockOrdersService) Manifest(ctx context.Context, launchpadID string, localDate string) (types.Manifest, error) {
+	ret := _m.Called(ctx, launchpadID, localDate)
+
+	var r0 types.Manifest
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) types.Manifest); ok {
+		r0 = rf(ctx, launchpadID, localDate)
+	} else {
+		r0 = ret.Get(0).(types.Manifest)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, launchpadID, localDate)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// OrderCalendar provides a mock function with given fields: ctx, id
+func (_m *mockOrdersService) OrderCalendar(ctx context.Context, id string) (ical.Calendar, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 ical.Calendar
+	if rf, ok := ret.Get(0).(func(context.Context, string) ical.Calendar); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(ical.Calendar)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Rebook provides a mock function with given fields: ctx, id
+func (_m *mockOrdersService) Rebook(ctx context.Context, id string) (types.Order, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 types.Order
+	if rf, ok := ret.Get(0).(func(context.Context, string) types.Order); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(types.Order)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RebookConflicts provides a mock function with given fields: ctx
+func (_m *mockOrdersService) RebookConflicts(ctx context.Context) ([]types.RebookResult, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []types.RebookResult
+	if rf, ok := ret.Get(0).(func(context.Context) []types.RebookResult); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.RebookResult)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 type mockConstructorTestingTnewMockOrdersService interface {
 	mock.TestingT
 	Cleanup(func())
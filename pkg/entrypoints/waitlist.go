@@ -0,0 +1,36 @@
+package entrypoints
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/leveldorado/space-trouble/pkg/types"
+)
+
+/*
+joinWaitlist books order or puts it on waitlist when flight is blocked by competitor launch
+*/
+func (e *HTTPEntry) joinWaitlist(wr http.ResponseWriter, req *http.Request) {
+	o := types.Order{}
+	if err := json.NewDecoder(req.Body).Decode(&o); err != nil {
+		e.respondError(req.Context(), types.NewErrInvalidData(err.Error()), wr)
+		return
+	}
+	if err := o.Validate(); err != nil {
+		e.respondError(req.Context(), types.NewErrInvalidData(err.Error()), wr)
+		return
+	}
+	doc, err := e.wls.Join(req.Context(), o)
+	e.respond(req.Context(), doc, err, http.StatusCreated, wr)
+}
+
+func (e *HTTPEntry) getWaitlistEntry(wr http.ResponseWriter, req *http.Request) {
+	doc, err := e.wls.Get(req.Context(), chi.URLParam(req, "id"))
+	e.respond(req.Context(), doc, err, http.StatusOK, wr)
+}
+
+func (e *HTTPEntry) leaveWaitlist(wr http.ResponseWriter, req *http.Request) {
+	err := e.wls.Leave(req.Context(), chi.URLParam(req, "id"))
+	e.respond(req.Context(), nil, err, http.StatusNoContent, wr)
+}
@@ -0,0 +1,61 @@
+package entrypoints
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/leveldorado/space-trouble/pkg/types"
+)
+
+func (e *HTTPEntry) createWebhook(wr http.ResponseWriter, req *http.Request) {
+	sub := types.WebhookSubscription{}
+	if err := json.NewDecoder(req.Body).Decode(&sub); err != nil {
+		e.respondError(req.Context(), types.NewErrInvalidData(err.Error()), wr)
+		return
+	}
+	created, err := e.ws.Subscribe(req.Context(), sub)
+	e.respond(req.Context(), created, err, http.StatusCreated, wr)
+}
+
+func (e *HTTPEntry) listWebhooks(wr http.ResponseWriter, req *http.Request) {
+	subs, err := e.ws.Subscriptions(req.Context())
+	if subs == nil {
+		subs = []types.WebhookSubscription{}
+	}
+	e.respond(req.Context(), subs, err, http.StatusOK, wr)
+}
+
+func (e *HTTPEntry) deleteWebhook(wr http.ResponseWriter, req *http.Request) {
+	err := e.ws.Unsubscribe(req.Context(), chi.URLParam(req, "id"))
+	e.respond(req.Context(), nil, err, http.StatusNoContent, wr)
+}
+
+/*
+listWebhookDeliveries lists deliveries, status=dead gives dead letter list
+*/
+func (e *HTTPEntry) listWebhookDeliveries(wr http.ResponseWriter, req *http.Request) {
+	limit, offset, err := parseLimitOffset(req.URL.Query(), defaultOrdersLimit, maxOrdersLimit)
+	if err != nil {
+		e.respondError(req.Context(), err, wr)
+		return
+	}
+	status := req.URL.Query().Get("status")
+	switch status {
+	case "", types.WebhookDeliveryStatusPending, types.WebhookDeliveryStatusDelivered, types.WebhookDeliveryStatusDead:
+	default:
+		e.respondError(req.Context(), types.NewErrInvalidData("unknown status "+status), wr)
+		return
+	}
+	deliveries, err := e.ws.Deliveries(req.Context(), status, limit, offset)
+	e.respond(req.Context(), paginationResult{
+		Docs:   deliveries,
+		Limit:  limit,
+		Offset: offset,
+	}, err, http.StatusOK, wr)
+}
+
+func (e *HTTPEntry) replayWebhookDelivery(wr http.ResponseWriter, req *http.Request) {
+	err := e.ws.Replay(req.Context(), chi.URLParam(req, "id"))
+	e.respond(req.Context(), nil, err, http.StatusAccepted, wr)
+}
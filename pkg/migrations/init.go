@@ -4,60 +4,157 @@ import (
 	"context"
 	"time"
 
-	"github.com/leveldorado/space-trouble/pkg/repositories"
 	"github.com/leveldorado/space-trouble/pkg/types"
 	"github.com/pkg/errors"
 )
 
+type tablesCreator interface {
+	CreateTables(ctx context.Context) error
+}
+
+type launchpadsLister interface {
+	List(ctx context.Context) ([]types.Launchpad, error)
+}
+
+type destinationRepo interface {
+	ListSorted(ctx context.Context) ([]types.Destination, error)
+}
+
+type launchpadFirstDestinationRepo interface {
+	Get(ctx context.Context, launchpad string) (types.LaunchpadFirstDestination, error)
+	Set(ctx context.Context, doc types.LaunchpadFirstDestination) error
+}
+
 /*
 Init initialize things like table creation and populating data.
 
 	in production app better approach to use dedicated tool for it
-	or code which triggered not on start but by event so only one replica of app will perform
+	or code which triggered not on start but by event so only one replica of app will perform.
+	rotation of launchpads without anchor starts on local date of now
 */
 func Init(
-	or *repositories.PostgreSQLOrdersRepo,
-	lr *repositories.SpaceXAPILaunchpadsRepo,
-	dr *repositories.InMemoryDestinationsRepo,
-	fr *repositories.InMemoryLaunchpadFirstDestinationRepo,
+	now time.Time,
+	lr launchpadsLister,
+	dr destinationRepo,
+	fr launchpadFirstDestinationRepo,
+	tables ...tablesCreator,
 ) error {
-	if err := or.CreateTables(context.TODO()); err != nil {
-		return errors.Wrap(err, `failed to create tables`)
+	if err := CreateTables(context.TODO(), tables...); err != nil {
+		return err
 	}
-	return populateLaunchpadFirstDestinations(lr, dr, fr)
+	return populateLaunchpadFirstDestinations(context.TODO(), lr, dr, fr, now, false)
+}
+
+func CreateTables(ctx context.Context, tables ...tablesCreator) error {
+	for _, t := range tables {
+		if err := t.CreateTables(ctx); err != nil {
+			return errors.Wrap(err, `failed to create tables`)
+		}
+	}
+	return nil
+}
+
+/*
+RebuildLaunchpadFirstDestinations overrides existing anchors of all launchpads with local date of now.
+
+	destinations of already booked orders may not match rotation after rebuild
+*/
+func RebuildLaunchpadFirstDestinations(
+	ctx context.Context,
+	lr launchpadsLister,
+	dr destinationRepo,
+	fr launchpadFirstDestinationRepo,
+	now time.Time,
+) error {
+	return populateLaunchpadFirstDestinations(ctx, lr, dr, fr, now, true)
 }
 
 /*
 launchpad first destination records needed as starting point of calculating destination for a date
+existing records kept unless override requested so rotation stays the same between restarts
 */
 func populateLaunchpadFirstDestinations(
-	lr *repositories.SpaceXAPILaunchpadsRepo,
-	dr *repositories.InMemoryDestinationsRepo,
-	fr *repositories.InMemoryLaunchpadFirstDestinationRepo,
+	ctx context.Context,
+	lr launchpadsLister,
+	dr destinationRepo,
+	fr launchpadFirstDestinationRepo,
+	now time.Time,
+	override bool,
 ) error {
-	launchpads, err := lr.List(context.TODO())
+	launchpads, err := lr.List(ctx)
 	if err != nil {
 		return errors.Wrap(err, `failed to list launchpads`)
 	}
-	destinations, err := dr.ListSorted(context.TODO())
+	_, err = setLaunchpadFirstDestinations(ctx, launchpads, dr, fr, now, override)
+	return err
+}
+
+/*
+AddLaunchpadFirstDestinations creates anchors for active launchpads which don't have one yet
+and returns number of created anchors.
+
+	used at runtime to pick up launchpads activated after start, anchor date is local date of now on launchpad
+*/
+func AddLaunchpadFirstDestinations(
+	ctx context.Context,
+	launchpads []types.Launchpad,
+	dr destinationRepo,
+	fr launchpadFirstDestinationRepo,
+	now time.Time,
+) (int, error) {
+	return setLaunchpadFirstDestinations(ctx, launchpads, dr, fr, now, false)
+}
+
+/*
+setLaunchpadFirstDestinations anchors rotation of active launchpads, not active launchpads are skipped
+as they can not be booked and get anchor when activated
+*/
+func setLaunchpadFirstDestinations(
+	ctx context.Context,
+	launchpads []types.Launchpad,
+	dr destinationRepo,
+	fr launchpadFirstDestinationRepo,
+	now time.Time,
+	override bool,
+) (int, error) {
+	destinations, err := dr.ListSorted(ctx)
 	if err != nil {
-		return errors.Wrap(err, `failed to list destinations`)
+		return 0, errors.Wrap(err, `failed to list destinations`)
 	}
-	var currentDestinationIndex int
+	var (
+		currentDestinationIndex int
+		created                 int
+	)
 	for _, pad := range launchpads {
-		padTime := time.Now().In(pad.Location)
+		if pad.Status != types.LaunchpadStatusActive {
+			continue
+		}
+		if !override {
+			_, err := fr.Get(ctx, pad.ID)
+			if err == nil {
+				continue
+			}
+			if !errors.As(err, &types.ErrNotFound{}) {
+				return created, errors.Wrapf(err, `failed to get launchpad first destination: launchpad - %s`, pad.ID)
+			}
+		}
+		padTime := now.In(pad.Location)
 		year, month, day := padTime.Date()
-		fr.Set(types.LaunchpadFirstDestination{
+		doc := types.LaunchpadFirstDestination{
 			LaunchpadID:   pad.ID,
 			DestinationID: destinations[0].ID,
 			LocalYear:     year,
 			LocalMonth:    month,
 			LocalDay:      day,
-		})
+		}
+		if err := fr.Set(ctx, doc); err != nil {
+			return created, errors.Wrapf(err, `failed to set launchpad first destination: doc - %+v`, doc)
+		}
+		created++
 		currentDestinationIndex++
 		if currentDestinationIndex >= len(destinations) {
 			currentDestinationIndex = 0
 		}
 	}
-	return nil
+	return created, nil
 }
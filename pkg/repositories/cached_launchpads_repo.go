@@ -0,0 +1,196 @@
+package repositories
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/leveldorado/space-trouble/pkg/types"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	DefaultLaunchpadsCacheTTL         = 10 * time.Minute
+	DefaultLaunchpadsCacheNotFoundTTL = time.Minute
+
+	launchpadsCacheListKey      = "list"
+	launchpadsCacheLaunchpadKey = "launchpad:"
+	launchpadsFetchTimeout      = 10 * time.Second
+	// ids come from clients, so not found entries are bounded to keep unknown ids from growing cache
+	launchpadsCacheMaxEntries = 1000
+)
+
+type launchpadsSource interface {
+	Get(ctx context.Context, id string) (types.Launchpad, error)
+	List(ctx context.Context) ([]types.Launchpad, error)
+}
+
+type launchpadsCacheEntry struct {
+	value   interface{}
+	err     error
+	expires time.Time
+}
+
+type launchpadsCall struct {
+	done  chan struct{}
+	value interface{}
+	err   error
+}
+
+/*
+CachedLaunchpadsRepo caches launchpads of source repo.
+
+	launchpads are kept for ttl, not found launchpads for not found ttl.
+	expired entry is returned right away while it is revalidated in background and kept when source fails,
+	so lookups don't depend on source availability once launchpad was seen.
+	concurrent misses of the same key share one source call, which is not cancelled by any of the callers.
+	cache holds up to launchpadsCacheMaxEntries entries, expired not found entries are evicted to make room
+	and results which don't fit are not cached
+*/
+type CachedLaunchpadsRepo struct {
+	source      launchpadsSource
+	ttl         time.Duration
+	notFoundTTL time.Duration
+	log         logrus.FieldLogger
+
+	mu      sync.Mutex
+	entries map[string]launchpadsCacheEntry
+	calls   map[string]*launchpadsCall
+}
+
+func NewCachedLaunchpadsRepo(source launchpadsSource, ttl, notFoundTTL time.Duration, log logrus.FieldLogger) *CachedLaunchpadsRepo {
+	return &CachedLaunchpadsRepo{
+		source:      source,
+		ttl:         ttl,
+		notFoundTTL: notFoundTTL,
+		log:         log,
+		entries:     map[string]launchpadsCacheEntry{},
+		calls:       map[string]*launchpadsCall{},
+	}
+}
+
+func (r *CachedLaunchpadsRepo) Get(ctx context.Context, id string) (types.Launchpad, error) {
+	value, err := r.load(ctx, launchpadsCacheLaunchpadKey+id, func(ctx context.Context) (interface{}, error) {
+		return r.source.Get(ctx, id)
+	})
+	if err != nil {
+		return types.Launchpad{}, err
+	}
+	return value.(types.Launchpad), nil
+}
+
+func (r *CachedLaunchpadsRepo) List(ctx context.Context) ([]types.Launchpad, error) {
+	value, err := r.load(ctx, launchpadsCacheListKey, func(ctx context.Context) (interface{}, error) {
+		return r.source.List(ctx)
+	})
+	if err != nil {
+		return nil, err
+	}
+	launchpads := value.([]types.Launchpad)
+	return append([]types.Launchpad(nil), launchpads...), nil
+}
+
+/*
+Purge drops cached launchpad, all cached launchpads and list when id is empty. Returns number of dropped entries
+*/
+func (r *CachedLaunchpadsRepo) Purge(id string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if id != "" {
+		_, ok := r.entries[launchpadsCacheLaunchpadKey+id]
+		delete(r.entries, launchpadsCacheLaunchpadKey+id)
+		if !ok {
+			return 0
+		}
+		return 1
+	}
+	purged := len(r.entries)
+	r.entries = map[string]launchpadsCacheEntry{}
+	return purged
+}
+
+func (r *CachedLaunchpadsRepo) load(ctx context.Context, key string, fetch func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	r.mu.Lock()
+	entry, ok := r.entries[key]
+	r.mu.Unlock()
+	if !ok {
+		return r.fetch(ctx, key, fetch)
+	}
+	if time.Now().After(entry.expires) {
+		go r.revalidate(key, fetch)
+	}
+	return entry.value, entry.err
+}
+
+func (r *CachedLaunchpadsRepo) revalidate(key string, fetch func(ctx context.Context) (interface{}, error)) {
+	_, err := r.fetch(context.Background(), key, fetch)
+	if err != nil && !errors.As(err, &types.ErrNotFound{}) {
+		r.log.WithField("err", err.Error()).WithField("key", key).Warn("failed to revalidate launchpads cache, serving stale")
+	}
+}
+
+/*
+fetch calls source once for concurrent callers of the same key and caches its result.
+
+	source is called with its own timeout instead of context of the caller which started the call,
+	so cancelled request doesn't fail other callers waiting for the same key.
+	source errors other than not found are not cached, existing stale entry is kept instead
+*/
+func (r *CachedLaunchpadsRepo) fetch(ctx context.Context, key string, fetch func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	r.mu.Lock()
+	c, ok := r.calls[key]
+	if !ok {
+		c = &launchpadsCall{done: make(chan struct{})}
+		r.calls[key] = c
+		go r.call(key, c, fetch)
+	}
+	r.mu.Unlock()
+	select {
+	case <-c.done:
+		return c.value, c.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (r *CachedLaunchpadsRepo) call(key string, c *launchpadsCall, fetch func(ctx context.Context) (interface{}, error)) {
+	ctx, cancel := context.WithTimeout(context.Background(), launchpadsFetchTimeout)
+	defer cancel()
+	c.value, c.err = fetch(ctx)
+
+	r.mu.Lock()
+	delete(r.calls, key)
+	switch {
+	case c.err == nil:
+		r.store(key, launchpadsCacheEntry{value: c.value, expires: time.Now().Add(r.ttl)})
+	case errors.As(c.err, &types.ErrNotFound{}):
+		r.store(key, launchpadsCacheEntry{err: types.ErrNotFound{}, expires: time.Now().Add(r.notFoundTTL)})
+	default:
+		// stale entry is served a bit longer, so failing source is not called on every lookup
+		if entry, ok := r.entries[key]; ok {
+			entry.expires = time.Now().Add(r.notFoundTTL)
+			r.entries[key] = entry
+		}
+	}
+	r.mu.Unlock()
+	close(c.done)
+}
+
+/*
+store caches entry when there is room for it, should be called under lock
+*/
+func (r *CachedLaunchpadsRepo) store(key string, entry launchpadsCacheEntry) {
+	if _, ok := r.entries[key]; !ok && len(r.entries) >= launchpadsCacheMaxEntries {
+		now := time.Now()
+		for k, e := range r.entries {
+			if e.err != nil && now.After(e.expires) {
+				delete(r.entries, k)
+			}
+		}
+		if len(r.entries) >= launchpadsCacheMaxEntries {
+			return
+		}
+	}
+	r.entries[key] = entry
+}
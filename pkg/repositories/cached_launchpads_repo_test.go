@@ -0,0 +1,141 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/leveldorado/space-trouble/pkg/tools/logger"
+	"github.com/leveldorado/space-trouble/pkg/types"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+type countingLaunchpadsSource struct {
+	calls     int32
+	release   chan struct{}
+	launchpad types.Launchpad
+	err       error
+}
+
+func (s *countingLaunchpadsSource) Get(_ context.Context, id string) (types.Launchpad, error) {
+	atomic.AddInt32(&s.calls, 1)
+	if s.release != nil {
+		<-s.release
+	}
+	if s.err != nil {
+		return types.Launchpad{}, s.err
+	}
+	if id != s.launchpad.ID {
+		return types.Launchpad{}, types.ErrNotFound{}
+	}
+	return s.launchpad, nil
+}
+
+func (s *countingLaunchpadsSource) List(context.Context) ([]types.Launchpad, error) {
+	atomic.AddInt32(&s.calls, 1)
+	return []types.Launchpad{s.launchpad}, s.err
+}
+
+func TestCachedLaunchpadsRepo_Get(t *testing.T) {
+	source := &countingLaunchpadsSource{launchpad: types.Launchpad{ID: "pad-1", Status: types.LaunchpadStatusActive}}
+	r := NewCachedLaunchpadsRepo(source, time.Hour, time.Hour, logger.New())
+
+	for i := 0; i < 3; i++ {
+		launchpad, err := r.Get(context.TODO(), "pad-1")
+		require.NoError(t, err)
+		require.Equal(t, source.launchpad, launchpad)
+		_, err = r.Get(context.TODO(), "pad-2")
+		require.True(t, errors.As(err, &types.ErrNotFound{}))
+	}
+	require.EqualValues(t, 2, atomic.LoadInt32(&source.calls))
+
+	require.Equal(t, 1, r.Purge("pad-1"))
+	_, err := r.Get(context.TODO(), "pad-1")
+	require.NoError(t, err)
+	require.EqualValues(t, 3, atomic.LoadInt32(&source.calls))
+	require.Equal(t, 2, r.Purge(""))
+}
+
+func TestCachedLaunchpadsRepo_Singleflight(t *testing.T) {
+	source := &countingLaunchpadsSource{launchpad: types.Launchpad{ID: "pad-1"}, release: make(chan struct{})}
+	r := NewCachedLaunchpadsRepo(source, time.Hour, time.Hour, logger.New())
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			launchpad, err := r.Get(context.TODO(), "pad-1")
+			require.NoError(t, err)
+			require.Equal(t, "pad-1", launchpad.ID)
+		}()
+	}
+	require.Eventually(t, func() bool { return atomic.LoadInt32(&source.calls) == 1 }, time.Second, time.Millisecond)
+	time.Sleep(10 * time.Millisecond)
+	close(source.release)
+	wg.Wait()
+	require.EqualValues(t, 1, atomic.LoadInt32(&source.calls))
+}
+
+func TestCachedLaunchpadsRepo_CancelledCaller(t *testing.T) {
+	source := &countingLaunchpadsSource{launchpad: types.Launchpad{ID: "pad-1"}, release: make(chan struct{})}
+	r := NewCachedLaunchpadsRepo(source, time.Hour, time.Hour, logger.New())
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	leaderErr := make(chan error)
+	go func() {
+		_, err := r.Get(ctx, "pad-1")
+		leaderErr <- err
+	}()
+	require.Eventually(t, func() bool { return atomic.LoadInt32(&source.calls) == 1 }, time.Second, time.Millisecond)
+	waiter := make(chan error)
+	go func() {
+		_, err := r.Get(context.TODO(), "pad-1")
+		waiter <- err
+	}()
+	cancel()
+	require.True(t, errors.Is(<-leaderErr, context.Canceled))
+	close(source.release)
+	require.NoError(t, <-waiter)
+	require.EqualValues(t, 1, atomic.LoadInt32(&source.calls))
+}
+
+func TestCachedLaunchpadsRepo_MaxEntries(t *testing.T) {
+	source := &countingLaunchpadsSource{launchpad: types.Launchpad{ID: "pad-1"}}
+	r := NewCachedLaunchpadsRepo(source, time.Hour, 0, logger.New())
+	for i := 0; i < launchpadsCacheMaxEntries+10; i++ {
+		_, err := r.Get(context.TODO(), fmt.Sprintf("unknown-%d", i))
+		require.True(t, errors.As(err, &types.ErrNotFound{}))
+	}
+	// expired not found entries are evicted to make room
+	_, err := r.Get(context.TODO(), "pad-1")
+	require.NoError(t, err)
+	r.mu.Lock()
+	require.LessOrEqual(t, len(r.entries), launchpadsCacheMaxEntries)
+	require.Contains(t, r.entries, launchpadsCacheLaunchpadKey+"pad-1")
+	r.mu.Unlock()
+}
+
+func TestCachedLaunchpadsRepo_Stale(t *testing.T) {
+	source := &countingLaunchpadsSource{launchpad: types.Launchpad{ID: "pad-1", Status: types.LaunchpadStatusActive}}
+	r := NewCachedLaunchpadsRepo(source, 0, time.Hour, logger.New())
+	_, err := r.List(context.TODO())
+	require.NoError(t, err)
+
+	// expired entry is served while source fails
+	source.err = errors.New("api is down")
+	launchpads, err := r.List(context.TODO())
+	require.NoError(t, err)
+	require.Equal(t, []types.Launchpad{source.launchpad}, launchpads)
+	require.Eventually(t, func() bool { return atomic.LoadInt32(&source.calls) == 2 }, time.Second, time.Millisecond)
+
+	// failed revalidation postpones next one
+	_, err = r.List(context.TODO())
+	require.NoError(t, err)
+	time.Sleep(10 * time.Millisecond)
+	require.EqualValues(t, 2, atomic.LoadInt32(&source.calls))
+}
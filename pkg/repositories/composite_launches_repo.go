@@ -0,0 +1,196 @@
+package repositories
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/leveldorado/space-trouble/pkg/types"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	LaunchesProviderSpaceX   = "spacex"
+	LaunchesProviderStatic   = "static"
+	LaunchesProviderHTTPJSON = "http_json"
+)
+
+/*
+CompetitorLaunchesConfig lists sources of competitor launches and which of them are used for launchpad.
+
+	launchpads without own list use default one, empty default means all providers
+*/
+type CompetitorLaunchesConfig struct {
+	Providers  []LaunchesProviderConfig `yaml:"providers"`
+	Launchpads map[string][]string      `yaml:"launchpads"`
+	Default    []string                 `yaml:"default"`
+}
+
+/*
+LaunchesProviderConfig one source of competitor launches.
+
+	path is used by static provider, feed by http_json provider
+*/
+type LaunchesProviderConfig struct {
+	Name string             `yaml:"name"`
+	Type string             `yaml:"type"`
+	Path string             `yaml:"path"`
+	Feed HTTPJSONFeedConfig `yaml:"feed"`
+}
+
+/*
+DefaultCompetitorLaunchesConfig uses SpaceX API only
+*/
+func DefaultCompetitorLaunchesConfig() CompetitorLaunchesConfig {
+	return CompetitorLaunchesConfig{
+		Providers: []LaunchesProviderConfig{{Name: LaunchesProviderSpaceX, Type: LaunchesProviderSpaceX}},
+	}
+}
+
+/*
+ReadCompetitorLaunchesConfig reads YAML config, empty path gives default config
+*/
+func ReadCompetitorLaunchesConfig(path string) (CompetitorLaunchesConfig, error) {
+	if path == "" {
+		return DefaultCompetitorLaunchesConfig(), nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return CompetitorLaunchesConfig{}, errors.Wrapf(err, `failed to read config: path - %s`, path)
+	}
+	var c CompetitorLaunchesConfig
+	if err = yaml.Unmarshal(data, &c); err != nil {
+		return CompetitorLaunchesConfig{}, errors.Wrapf(err, `failed to decode config: path - %s`, path)
+	}
+	return c, nil
+}
+
+type launchesProvider interface {
+	CheckLaunches(ctx context.Context, launchpad string, localDate time.Time) (bool, error)
+	ListLaunches(ctx context.Context, launchpad string, from, to time.Time) ([]types.Launch, error)
+}
+
+/*
+CompositeLaunchesRepo merges competitor launches of several providers.
+
+	launchpad is busy when any of its providers reports launch, failure of one provider fails whole request
+	since launchpad can not be confirmed free without it
+*/
+type CompositeLaunchesRepo struct {
+	providers  map[string]launchesProvider
+	launchpads map[string][]string
+	defaults   []string
+}
+
+/*
+NewCompositeLaunchesRepo creates providers of config.
+
+	spacex is used for providers of spacex type, when nil SpaceX API is queried directly
+*/
+func NewCompositeLaunchesRepo(c CompetitorLaunchesConfig, cl *http.Client, spacex launchesProvider) (*CompositeLaunchesRepo, error) {
+	if spacex == nil {
+		spacex = NewSpaceXAPILaunchesRepo(NewSpaceXClient(cl, DefaultSpaceXClientConfig()), DefaultSpaceXAPIURL)
+	}
+	providers := make(map[string]launchesProvider, len(c.Providers))
+	var names []string
+	for _, p := range c.Providers {
+		if p.Name == "" {
+			return nil, errors.Errorf(`provider name is required: type - %s`, p.Type)
+		}
+		if _, ok := providers[p.Name]; ok {
+			return nil, errors.Errorf(`duplicated provider: name - %s`, p.Name)
+		}
+		provider, err := newLaunchesProvider(p, cl, spacex)
+		if err != nil {
+			return nil, errors.Wrapf(err, `failed to create provider: name - %s`, p.Name)
+		}
+		providers[p.Name] = provider
+		names = append(names, p.Name)
+	}
+	defaults := c.Default
+	if len(defaults) == 0 {
+		defaults = names
+	}
+	return newCompositeLaunchesRepo(providers, c.Launchpads, defaults)
+}
+
+func newLaunchesProvider(c LaunchesProviderConfig, cl *http.Client, spacex launchesProvider) (launchesProvider, error) {
+	switch c.Type {
+	case LaunchesProviderSpaceX:
+		return spacex, nil
+	case LaunchesProviderStatic:
+		return NewStaticLaunchesRepo(c.Path)
+	case LaunchesProviderHTTPJSON:
+		return NewHTTPJSONLaunchesRepo(cl, c.Feed)
+	default:
+		return nil, errors.Errorf(`unknown provider type: type - %s`, c.Type)
+	}
+}
+
+func newCompositeLaunchesRepo(providers map[string]launchesProvider, launchpads map[string][]string, defaults []string) (*CompositeLaunchesRepo, error) {
+	check := func(names []string) error {
+		for _, name := range names {
+			if _, ok := providers[name]; !ok {
+				return errors.Errorf(`unknown provider: name - %s`, name)
+			}
+		}
+		return nil
+	}
+	if err := check(defaults); err != nil {
+		return nil, err
+	}
+	for launchpad, names := range launchpads {
+		if err := check(names); err != nil {
+			return nil, errors.Wrapf(err, `invalid providers of launchpad: id - %s`, launchpad)
+		}
+	}
+	return &CompositeLaunchesRepo{providers: providers, launchpads: launchpads, defaults: defaults}, nil
+}
+
+func (r *CompositeLaunchesRepo) providersOf(launchpad string) []string {
+	if names, ok := r.launchpads[launchpad]; ok {
+		return names
+	}
+	return r.defaults
+}
+
+func (r *CompositeLaunchesRepo) CheckLaunches(ctx context.Context, launchpad string, localDate time.Time) (bool, error) {
+	for _, name := range r.providersOf(launchpad) {
+		exists, err := r.providers[name].CheckLaunches(ctx, launchpad, localDate)
+		if err != nil {
+			return false, errors.Wrapf(err, `failed to check launches: provider - %s`, name)
+		}
+		if exists {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+/*
+ListLaunches returns launches of all launchpad providers with UTC date in [from, to) range sorted by date
+*/
+func (r *CompositeLaunchesRepo) ListLaunches(ctx context.Context, launchpad string, from, to time.Time) ([]types.Launch, error) {
+	var launches []types.Launch
+	for _, name := range r.providersOf(launchpad) {
+		list, err := r.providers[name].ListLaunches(ctx, launchpad, from, to)
+		if err != nil {
+			return nil, errors.Wrapf(err, `failed to list launches: provider - %s`, name)
+		}
+		for _, l := range list {
+			l.Provider = name
+			launches = append(launches, l)
+		}
+	}
+	sortLaunches(launches)
+	return launches, nil
+}
+
+func sortLaunches(launches []types.Launch) {
+	sort.SliceStable(launches, func(i, j int) bool {
+		return launches[i].DateUTC.Before(launches[j].DateUTC)
+	})
+}
@@ -0,0 +1,176 @@
+package repositories
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/leveldorado/space-trouble/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTempFile(t *testing.T, name, data string) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(data), 0o600))
+	return path
+}
+
+func TestStaticLaunchesRepo(t *testing.T) {
+	yamlPath := writeTempFile(t, "launches.yaml", `
+- id: blue-2
+  launchpad: pad-1
+  date_utc: 2053-03-06T03:00:00Z
+- id: blue-1
+  launchpad: pad-1
+  date_utc: 2053-03-05T10:00:00Z
+- id: blue-3
+  launchpad: pad-2
+  date_utc: 2053-03-05T10:00:00Z
+`)
+	csvPath := writeTempFile(t, "launches.csv", `id,launchpad,date_utc
+blue-2,pad-1,2053-03-06T03:00:00Z
+blue-1,pad-1,2053-03-05T10:00:00Z
+blue-3,pad-2,2053-03-05T10:00:00Z
+`)
+	ny, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	for _, path := range []string{yamlPath, csvPath} {
+		r, err := NewStaticLaunchesRepo(path)
+		require.NoError(t, err)
+
+		launches, err := r.ListLaunches(context.TODO(), "pad-1",
+			time.Date(2053, 3, 5, 0, 0, 0, 0, time.UTC), time.Date(2053, 3, 7, 0, 0, 0, 0, time.UTC))
+		require.NoError(t, err)
+		require.Len(t, launches, 2)
+		require.Equal(t, "blue-1", launches[0].ID)
+		require.Equal(t, "blue-2", launches[1].ID)
+
+		// 2053-03-06T03:00:00Z is still 5th of March in New York
+		exists, err := r.CheckLaunches(context.TODO(), "pad-1", time.Date(2053, 3, 6, 12, 0, 0, 0, ny))
+		require.NoError(t, err)
+		require.False(t, exists)
+		exists, err = r.CheckLaunches(context.TODO(), "pad-1", time.Date(2053, 3, 5, 12, 0, 0, 0, ny))
+		require.NoError(t, err)
+		require.True(t, exists)
+	}
+
+	_, err = NewStaticLaunchesRepo(writeTempFile(t, "launches.json", `[]`))
+	require.Error(t, err)
+	_, err = NewStaticLaunchesRepo(writeTempFile(t, "launches.csv", "id,launchpad\n"))
+	require.Error(t, err)
+}
+
+func TestHTTPJSONLaunchesRepo(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"data": {"launches": [
+			{"uid": "rl-1", "site": {"code": "LC-1"}, "net": "2053-03-05T10:00:00+02:00"},
+			{"uid": "rl-2", "site": {"code": "LC-1"}, "net": 2625213600},
+			{"uid": "rl-3", "site": {"code": "LC-2"}, "net": "2053-03-05T10:00:00Z"}
+		]}}`))
+	}))
+	defer srv.Close()
+
+	r, err := NewHTTPJSONLaunchesRepo(srv.Client(), HTTPJSONFeedConfig{
+		URL:        srv.URL,
+		Docs:       "data.launches",
+		Fields:     HTTPJSONFeedField{ID: "uid", Launchpad: "site.code", Date: "net"},
+		Launchpads: map[string]string{"LC-1": "pad-1"},
+	})
+	require.NoError(t, err)
+
+	launches, err := r.ListLaunches(context.TODO(), "pad-1",
+		time.Date(2053, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2054, 1, 1, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.Len(t, launches, 2)
+	require.Equal(t, "rl-1", launches[0].ID)
+	require.Equal(t, time.Date(2053, 3, 5, 8, 0, 0, 0, time.UTC), launches[0].DateUTC)
+	require.Equal(t, "rl-2", launches[1].ID)
+	require.Equal(t, "pad-1", launches[1].Launchpad)
+	require.Equal(t, time.Unix(2625213600, 0).UTC(), launches[1].DateUTC)
+
+	launches, err = r.ListLaunches(context.TODO(), "LC-2",
+		time.Date(2053, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2054, 1, 1, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.Len(t, launches, 1)
+
+	_, err = NewHTTPJSONLaunchesRepo(srv.Client(), HTTPJSONFeedConfig{URL: srv.URL})
+	require.Error(t, err)
+}
+
+func TestCompositeLaunchesRepo(t *testing.T) {
+	blue := writeTempFile(t, "blue.csv", `id,launchpad,date_utc
+blue-1,pad-1,2053-03-05T10:00:00Z
+`)
+	rocket := writeTempFile(t, "rocket.yaml", `
+- id: rocket-1
+  launchpad: pad-1
+  date_utc: 2053-03-04T10:00:00Z
+- id: rocket-2
+  launchpad: pad-2
+  date_utc: 2053-03-06T10:00:00Z
+`)
+	config := writeTempFile(t, "config.yaml", `
+providers:
+  - name: blue
+    type: static
+    path: `+blue+`
+  - name: rocket
+    type: static
+    path: `+rocket+`
+launchpads:
+  pad-2: [blue]
+`)
+	c, err := ReadCompetitorLaunchesConfig(config)
+	require.NoError(t, err)
+	r, err := NewCompositeLaunchesRepo(c, http.DefaultClient, nil)
+	require.NoError(t, err)
+
+	from, to := time.Date(2053, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2053, 3, 10, 0, 0, 0, 0, time.UTC)
+	launches, err := r.ListLaunches(context.TODO(), "pad-1", from, to)
+	require.NoError(t, err)
+	require.Equal(t, []types.Launch{
+		{
+			ID:        "rocket-1",
+			Launchpad: "pad-1",
+			DateUTC:   time.Date(2053, 3, 4, 10, 0, 0, 0, time.UTC),
+			DateLocal: time.Date(2053, 3, 4, 10, 0, 0, 0, time.UTC),
+			Provider:  "rocket",
+		},
+		{
+			ID:        "blue-1",
+			Launchpad: "pad-1",
+			DateUTC:   time.Date(2053, 3, 5, 10, 0, 0, 0, time.UTC),
+			DateLocal: time.Date(2053, 3, 5, 10, 0, 0, 0, time.UTC),
+			Provider:  "blue",
+		},
+	}, launches)
+
+	exists, err := r.CheckLaunches(context.TODO(), "pad-1", time.Date(2053, 3, 4, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.True(t, exists)
+
+	// rocket is not configured for pad-2
+	launches, err = r.ListLaunches(context.TODO(), "pad-2", from, to)
+	require.NoError(t, err)
+	require.Empty(t, launches)
+	exists, err = r.CheckLaunches(context.TODO(), "pad-2", time.Date(2053, 3, 6, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	c.Launchpads["pad-3"] = []string{"unknown"}
+	_, err = NewCompositeLaunchesRepo(c, http.DefaultClient, nil)
+	require.Error(t, err)
+}
+
+func TestReadCompetitorLaunchesConfig(t *testing.T) {
+	c, err := ReadCompetitorLaunchesConfig("")
+	require.NoError(t, err)
+	require.Equal(t, DefaultCompetitorLaunchesConfig(), c)
+	r, err := NewCompositeLaunchesRepo(c, http.DefaultClient, nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{LaunchesProviderSpaceX}, r.providersOf("any"))
+}
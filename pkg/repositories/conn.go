@@ -1,9 +1,12 @@
 package repositories
 
 import (
+	"context"
 	"database/sql"
+	"regexp"
 
 	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
 	"github.com/pkg/errors"
 )
 
@@ -14,3 +17,46 @@ func GetPostgresqlConn(url string) (*sql.DB, error) {
 	}
 	return conn, errors.Wrapf(conn.Ping(), `failed to ping: url - %s`, url)
 }
+
+/*
+GetSQLiteConn opens SQLite database file at path, it's created when missing.
+
+	single connection is kept open, so writes of the process never wait for each other on database lock
+	and transactions are serialized
+*/
+func GetSQLiteConn(path string) (*sql.DB, error) {
+	conn, err := sql.Open("sqlite3", "file:"+path+"?_busy_timeout=5000&_journal_mode=WAL")
+	if err != nil {
+		return nil, errors.Wrapf(err, `failed to open conn: path - %s`, path)
+	}
+	conn.SetMaxOpenConns(1)
+	return conn, errors.Wrapf(conn.Ping(), `failed to ping: path - %s`, path)
+}
+
+var postgresPlaceholder = regexp.MustCompile(`\$(\d+)`)
+
+/*
+sqliteRebinder runs queries written for PostgreSQL on SQLite.
+
+	SQLite reads $1 as named parameter and numbers those in order of appearance,
+	so $n placeholders are rewritten to ?n which are bound by position the same way PostgreSQL does
+*/
+type sqliteRebinder struct {
+	conn sqlExecutor
+}
+
+func (r sqliteRebinder) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return r.conn.ExecContext(ctx, rebindSQLite(query), args...)
+}
+
+func (r sqliteRebinder) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return r.conn.QueryContext(ctx, rebindSQLite(query), args...)
+}
+
+func (r sqliteRebinder) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return r.conn.QueryRowContext(ctx, rebindSQLite(query), args...)
+}
+
+func rebindSQLite(query string) string {
+	return postgresPlaceholder.ReplaceAllString(query, `?$1`)
+}
@@ -0,0 +1,123 @@
+package repositories
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/leveldorado/space-trouble/pkg/types"
+	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+)
+
+/*
+HTTPJSONFeedConfig describes generic JSON feed of launches.
+
+	fields are gjson paths inside one launch, docs is path to launches array (empty when response is array itself).
+	date is RFC3339 string or unix seconds.
+	launchpads maps launchpad ids of feed to our launchpad ids, launches of not mapped launchpads keep feed id
+*/
+type HTTPJSONFeedConfig struct {
+	URL        string            `yaml:"url"`
+	Docs       string            `yaml:"docs"`
+	Fields     HTTPJSONFeedField `yaml:"fields"`
+	Launchpads map[string]string `yaml:"launchpads"`
+}
+
+type HTTPJSONFeedField struct {
+	ID        string `yaml:"id"`
+	Launchpad string `yaml:"launchpad"`
+	Date      string `yaml:"date"`
+}
+
+/*
+HTTPJSONLaunchesRepo reads competitor launches from JSON feed of other launch operator.
+
+	whole feed is requested and filtered on our side, since query language of feed is unknown
+*/
+type HTTPJSONLaunchesRepo struct {
+	cl     *http.Client
+	config HTTPJSONFeedConfig
+}
+
+func NewHTTPJSONLaunchesRepo(cl *http.Client, config HTTPJSONFeedConfig) (*HTTPJSONLaunchesRepo, error) {
+	if config.URL == "" {
+		return nil, errors.New(`feed url is required`)
+	}
+	if config.Fields.Launchpad == "" || config.Fields.Date == "" {
+		return nil, errors.Errorf(`launchpad and date fields are required: url - %s`, config.URL)
+	}
+	return &HTTPJSONLaunchesRepo{cl: cl, config: config}, nil
+}
+
+func (r *HTTPJSONLaunchesRepo) CheckLaunches(ctx context.Context, launchpad string, localDate time.Time) (bool, error) {
+	return checkLaunchesOfDay(ctx, r, launchpad, localDate)
+}
+
+/*
+ListLaunches returns launches from launchpad with UTC date in [from, to) range sorted by date
+*/
+func (r *HTTPJSONLaunchesRepo) ListLaunches(ctx context.Context, launchpad string, from, to time.Time) ([]types.Launch, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.config.URL, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, `failed to create request: url - %s`, r.config.URL)
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := r.cl.Do(req)
+	if err != nil {
+		return nil, errors.Wrapf(err, `failed to perform request: url - %s`, r.config.URL)
+	}
+	data, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, errors.Wrapf(err, `failed to read response`)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf(`received non success code: code - %s, response - %s`, resp.Status, data)
+	}
+	docs := gjson.ParseBytes(data)
+	if r.config.Docs != "" {
+		docs = docs.Get(r.config.Docs)
+	}
+	if !docs.IsArray() {
+		return nil, errors.Errorf(`launches are not array: url - %s, docs - %s`, r.config.URL, r.config.Docs)
+	}
+	var launches []types.Launch
+	for i, doc := range docs.Array() {
+		l, err := r.decodeLaunch(doc)
+		if err != nil {
+			return nil, errors.Wrapf(err, `failed to decode launch: url - %s, index - %d`, r.config.URL, i)
+		}
+		if l.Launchpad == launchpad && !l.DateUTC.Before(from) && l.DateUTC.Before(to) {
+			launches = append(launches, l)
+		}
+	}
+	sortLaunches(launches)
+	return launches, nil
+}
+
+func (r *HTTPJSONLaunchesRepo) decodeLaunch(doc gjson.Result) (types.Launch, error) {
+	l := types.Launch{Launchpad: doc.Get(r.config.Fields.Launchpad).String()}
+	if r.config.Fields.ID != "" {
+		l.ID = doc.Get(r.config.Fields.ID).String()
+	}
+	if id, ok := r.config.Launchpads[l.Launchpad]; ok {
+		l.Launchpad = id
+	}
+	date := doc.Get(r.config.Fields.Date)
+	switch date.Type {
+	case gjson.Number:
+		l.DateLocal = time.Unix(date.Int(), 0)
+	case gjson.String:
+		parsed, err := time.Parse(time.RFC3339, date.String())
+		if err != nil {
+			return types.Launch{}, errors.Errorf(`invalid date %s, RFC3339 expected`, date.String())
+		}
+		l.DateLocal = parsed
+	default:
+		return types.Launch{}, errors.Errorf(`date is missing: field - %s`, r.config.Fields.Date)
+	}
+	l.DateUTC = l.DateLocal.UTC()
+	return l, nil
+}
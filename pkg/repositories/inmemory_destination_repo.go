@@ -16,39 +16,42 @@ type InMemoryDestinationsRepo struct {
 	destinations []types.Destination
 }
 
+/*
+defaultDestinations are destinations every storage starts with, in rotation order
+*/
+var defaultDestinations = []types.Destination{
+	{
+		ID:   "1",
+		Name: "Mars",
+	},
+	{
+		ID:   "2",
+		Name: "IO",
+	},
+	{
+		ID:   "3",
+		Name: "Venus",
+	},
+	{
+		ID:   "4",
+		Name: "Jupiter",
+	},
+	{
+		ID:   "5",
+		Name: "Moon",
+	},
+	{
+		ID:   "6",
+		Name: "Neptune",
+	},
+	{
+		ID:   "7",
+		Name: "Pluto",
+	},
+}
+
 func NewInMemoryDestinationsRepo() *InMemoryDestinationsRepo {
-	return &InMemoryDestinationsRepo{
-		destinations: []types.Destination{
-			{
-				ID:   "1",
-				Name: "Mars",
-			},
-			{
-				ID:   "2",
-				Name: "IO",
-			},
-			{
-				ID:   "3",
-				Name: "Venus",
-			},
-			{
-				ID:   "4",
-				Name: "Jupiter",
-			},
-			{
-				ID:   "5",
-				Name: "Moon",
-			},
-			{
-				ID:   "6",
-				Name: "Neptune",
-			},
-			{
-				ID:   "7",
-				Name: "Pluto",
-			},
-		},
-	}
+	return &InMemoryDestinationsRepo{destinations: defaultDestinations}
 }
 
 func (r *InMemoryDestinationsRepo) ListSorted(_ context.Context) ([]types.Destination, error) {
@@ -0,0 +1,98 @@
+package repositories
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/leveldorado/space-trouble/pkg/types"
+)
+
+/*
+InMemoryLaunchesRepo
+
+	implements the same semantics as PostgreSQLLaunchesRepo without db, state is lost on restart
+*/
+type InMemoryLaunchesRepo struct {
+	mu sync.RWMutex
+	// source to launch id to launch
+	launches map[string]map[string]types.Launch
+	syncs    map[string]types.LaunchesSync
+}
+
+func NewInMemoryLaunchesRepo() *InMemoryLaunchesRepo {
+	return &InMemoryLaunchesRepo{launches: map[string]map[string]types.Launch{}, syncs: map[string]types.LaunchesSync{}}
+}
+
+/*
+Replace stores launches of source with UTC date in [sync.From, sync.To) instead of previously stored ones and records sync
+*/
+func (r *InMemoryLaunchesRepo) Replace(_ context.Context, sync types.LaunchesSync, launches []types.Launch) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored, ok := r.launches[sync.Source]
+	if !ok {
+		stored = map[string]types.Launch{}
+		r.launches[sync.Source] = stored
+	}
+	for id, l := range stored {
+		if !l.DateUTC.Before(sync.From) && l.DateUTC.Before(sync.To) {
+			delete(stored, id)
+		}
+	}
+	for _, l := range launches {
+		l.DateUTC = l.DateUTC.UTC()
+		l.Provider = ""
+		stored[l.ID] = l
+	}
+	sync.Launches = len(launches)
+	sync.From, sync.To, sync.SyncedAt = sync.From.UTC(), sync.To.UTC(), sync.SyncedAt.UTC()
+	r.syncs[sync.Source] = sync
+	return nil
+}
+
+/*
+GetSync returns last sync of source, ErrNotFound when source was never synced
+*/
+func (r *InMemoryLaunchesRepo) GetSync(_ context.Context, source string) (types.LaunchesSync, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	doc, ok := r.syncs[source]
+	if !ok {
+		return types.LaunchesSync{}, types.ErrNotFound{}
+	}
+	return doc, nil
+}
+
+/*
+ListLaunches returns stored launches of source from launchpad with UTC date in [from, to) range sorted by date
+*/
+func (r *InMemoryLaunchesRepo) ListLaunches(_ context.Context, source, launchpad string, from, to time.Time) ([]types.Launch, error) {
+	return r.list(source, from, to, func(l types.Launch) bool { return l.Launchpad == launchpad }), nil
+}
+
+/*
+ListAllLaunches returns stored launches of source from all launchpads with UTC date in [from, to) range sorted by date
+*/
+func (r *InMemoryLaunchesRepo) ListAllLaunches(_ context.Context, source string, from, to time.Time) ([]types.Launch, error) {
+	return r.list(source, from, to, func(types.Launch) bool { return true }), nil
+}
+
+func (r *InMemoryLaunchesRepo) list(source string, from, to time.Time, match func(types.Launch) bool) []types.Launch {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var launches []types.Launch
+	for _, l := range r.launches[source] {
+		if match(l) && !l.DateUTC.Before(from) && l.DateUTC.Before(to) {
+			launches = append(launches, l)
+		}
+	}
+	sort.Slice(launches, func(i, j int) bool {
+		if !launches[i].DateUTC.Equal(launches[j].DateUTC) {
+			return launches[i].DateUTC.Before(launches[j].DateUTC)
+		}
+		return launches[i].ID < launches[j].ID
+	})
+	return launches
+}
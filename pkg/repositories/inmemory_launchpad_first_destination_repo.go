@@ -2,7 +2,7 @@ package repositories
 
 import (
 	"context"
-	"fmt"
+	"sort"
 	"sync"
 
 	"github.com/leveldorado/space-trouble/pkg/types"
@@ -23,19 +23,32 @@ func NewInMemoryLaunchpadFirstDestinationRepo() *InMemoryLaunchpadFirstDestinati
 	return &InMemoryLaunchpadFirstDestinationRepo{launchpads: map[string]types.LaunchpadFirstDestination{}}
 }
 
-func (r *InMemoryLaunchpadFirstDestinationRepo) Set(doc types.LaunchpadFirstDestination) {
+func (r *InMemoryLaunchpadFirstDestinationRepo) Set(_ context.Context, doc types.LaunchpadFirstDestination) error {
 	r.Lock()
 	r.launchpads[doc.LaunchpadID] = doc
 	r.Unlock()
+	return nil
 }
 
 func (r *InMemoryLaunchpadFirstDestinationRepo) Get(_ context.Context, launchpad string) (types.LaunchpadFirstDestination, error) {
 	r.RLock()
 	defer r.RUnlock()
 	doc, ok := r.launchpads[launchpad]
-	fmt.Println(r.launchpads)
 	if !ok {
 		return types.LaunchpadFirstDestination{}, types.ErrNotFound{}
 	}
 	return doc, nil
 }
+
+func (r *InMemoryLaunchpadFirstDestinationRepo) List(_ context.Context) ([]types.LaunchpadFirstDestination, error) {
+	r.RLock()
+	defer r.RUnlock()
+	docs := make([]types.LaunchpadFirstDestination, 0, len(r.launchpads))
+	for _, doc := range r.launchpads {
+		docs = append(docs, doc)
+	}
+	sort.Slice(docs, func(i, j int) bool {
+		return docs[i].LaunchpadID < docs[j].LaunchpadID
+	})
+	return docs, nil
+}
@@ -0,0 +1,53 @@
+package repositories
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/leveldorado/space-trouble/pkg/types"
+)
+
+/*
+InMemoryLaunchpadsSnapshotRepo keeps last launchpads list for app process only, so it helps when SpaceX API
+goes down after start but not on start
+*/
+type InMemoryLaunchpadsSnapshotRepo struct {
+	mu         sync.RWMutex
+	launchpads []types.Launchpad
+	savedAt    time.Time
+}
+
+func NewInMemoryLaunchpadsSnapshotRepo() *InMemoryLaunchpadsSnapshotRepo {
+	return &InMemoryLaunchpadsSnapshotRepo{}
+}
+
+/*
+Save replaces snapshot with launchpads
+*/
+func (r *InMemoryLaunchpadsSnapshotRepo) Save(_ context.Context, launchpads []types.Launchpad, savedAt time.Time) error {
+	sorted := append([]types.Launchpad(nil), launchpads...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].FullName != sorted[j].FullName {
+			return sorted[i].FullName < sorted[j].FullName
+		}
+		return sorted[i].ID < sorted[j].ID
+	})
+	r.mu.Lock()
+	r.launchpads, r.savedAt = sorted, savedAt.UTC()
+	r.mu.Unlock()
+	return nil
+}
+
+/*
+Load returns launchpads of snapshot sorted by full name and when they were saved, ErrNotFound when snapshot is empty
+*/
+func (r *InMemoryLaunchpadsSnapshotRepo) Load(_ context.Context) ([]types.Launchpad, time.Time, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if len(r.launchpads) == 0 {
+		return nil, time.Time{}, types.ErrNotFound{}
+	}
+	return append([]types.Launchpad(nil), r.launchpads...), r.savedAt, nil
+}
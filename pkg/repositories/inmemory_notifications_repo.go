@@ -0,0 +1,131 @@
+package repositories
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/leveldorado/space-trouble/pkg/types"
+	"github.com/pkg/errors"
+)
+
+/*
+InMemoryNotificationsRepo
+
+	implements the same semantics as PostgreSQLNotificationsRepo without db, state is lost on restart
+*/
+type InMemoryNotificationsRepo struct {
+	mu   sync.RWMutex
+	docs map[string]types.Notification
+}
+
+func NewInMemoryNotificationsRepo() *InMemoryNotificationsRepo {
+	return &InMemoryNotificationsRepo{docs: map[string]types.Notification{}}
+}
+
+/*
+Insert stores notifications, notification of the same event and kind is stored once
+*/
+func (r *InMemoryNotificationsRepo) Insert(_ context.Context, docs []types.Notification) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, doc := range docs {
+		if _, ok := r.docs[doc.ID]; ok {
+			return errors.Errorf(`notification already exists: id - %s`, doc.ID)
+		}
+	}
+	for _, doc := range docs {
+		if r.hasNotification(doc.EventID, doc.Kind) {
+			continue
+		}
+		r.docs[doc.ID] = doc
+	}
+	return nil
+}
+
+func (r *InMemoryNotificationsRepo) hasNotification(eventID, kind string) bool {
+	for _, doc := range r.docs {
+		if doc.EventID == eventID && doc.Kind == kind {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+CancelPending cancels not sent notifications of order with provided kind
+*/
+func (r *InMemoryNotificationsRepo) CancelPending(_ context.Context, orderID, kind string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id, doc := range r.docs {
+		if doc.OrderID == orderID && doc.Kind == kind && doc.Status == types.NotificationStatusPending {
+			doc.Status = types.NotificationStatusCancelled
+			r.docs[id] = doc
+		}
+	}
+	return nil
+}
+
+/*
+ClaimDue returns pending notifications which send time passed and moves their next attempt by lease
+*/
+func (r *InMemoryNotificationsRepo) ClaimDue(_ context.Context, now time.Time, lease time.Duration, limit int) ([]types.Notification, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var docs []types.Notification
+	for _, doc := range r.docs {
+		if doc.Status == types.NotificationStatusPending && !doc.NextAttemptAt.After(now) {
+			docs = append(docs, doc)
+		}
+	}
+	sort.Slice(docs, func(i, j int) bool {
+		return docs[i].NextAttemptAt.Before(docs[j].NextAttemptAt)
+	})
+	if len(docs) > limit {
+		docs = docs[:limit]
+	}
+	for i := range docs {
+		docs[i].NextAttemptAt = now.Add(lease)
+		r.docs[docs[i].ID] = docs[i]
+	}
+	return docs, nil
+}
+
+/*
+Update saves delivery state, notification cancelled meanwhile stays cancelled
+*/
+func (r *InMemoryNotificationsRepo) Update(_ context.Context, doc types.Notification) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored, ok := r.docs[doc.ID]
+	if !ok || stored.Status == types.NotificationStatusCancelled {
+		return nil
+	}
+	stored.Status = doc.Status
+	stored.Attempts = doc.Attempts
+	stored.LastError = doc.LastError
+	stored.NextAttemptAt = doc.NextAttemptAt
+	stored.SentAt = doc.SentAt
+	r.docs[doc.ID] = stored
+	return nil
+}
+
+func (r *InMemoryNotificationsRepo) ListByOrder(_ context.Context, orderID string) ([]types.Notification, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var docs []types.Notification
+	for _, doc := range r.docs {
+		if doc.OrderID == orderID {
+			docs = append(docs, doc)
+		}
+	}
+	sort.Slice(docs, func(i, j int) bool {
+		if !docs[i].CreatedAt.Equal(docs[j].CreatedAt) {
+			return docs[i].CreatedAt.Before(docs[j].CreatedAt)
+		}
+		return docs[i].NextAttemptAt.Before(docs[j].NextAttemptAt)
+	})
+	return docs, nil
+}
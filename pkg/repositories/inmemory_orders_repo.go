@@ -0,0 +1,467 @@
+package repositories
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/middleware"
+	"github.com/google/uuid"
+	"github.com/leveldorado/space-trouble/pkg/tools/actor"
+	"github.com/leveldorado/space-trouble/pkg/types"
+	"github.com/pkg/errors"
+)
+
+/*
+InMemoryOrdersRepo
+
+	implements the same semantics as PostgreSQLOrdersRepo without db: passengers are deduplicated into customers,
+	every change is recorded in audit log and outbox. state is lost on restart, meant for tests and local demos
+*/
+type InMemoryOrdersRepo struct {
+	mu        sync.RWMutex
+	customers map[types.Passenger]string
+	// customer id to passenger
+	passengers map[string]types.Passenger
+	orders     map[string]inMemoryOrder
+	audit      []types.OrderAuditEntry
+	// unpublished events, published ones are dropped
+	events []types.OutboxEvent
+	seq    int64
+	// only one relay processes outbox at a time to keep events of order in order
+	outboxMu sync.Mutex
+}
+
+type inMemoryOrder struct {
+	doc         types.Order
+	customerIDs []string
+}
+
+func NewInMemoryOrdersRepo() *InMemoryOrdersRepo {
+	return &InMemoryOrdersRepo{
+		customers:  map[types.Passenger]string{},
+		passengers: map[string]types.Passenger{},
+		orders:     map[string]inMemoryOrder{},
+	}
+}
+
+func (r *InMemoryOrdersRepo) Insert(ctx context.Context, doc types.Order) error {
+	return r.InsertMany(ctx, []types.Order{doc})
+}
+
+/*
+InsertMany inserts all orders or none of them when any is invalid or already exists
+*/
+func (r *InMemoryOrdersRepo) InsertMany(ctx context.Context, docs []types.Order) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ids := make(map[string]bool, len(docs))
+	for _, doc := range docs {
+		if _, err := uuid.Parse(doc.ID); err != nil {
+			return errors.Wrapf(err, `invalid order id: id - %s`, doc.ID)
+		}
+		if _, ok := r.orders[doc.ID]; ok || ids[doc.ID] {
+			return errors.Wrapf(types.ErrDuplicatedOrder{}, `order already exists: id - %s`, doc.ID)
+		}
+		ids[doc.ID] = true
+	}
+	for i, doc := range docs {
+		if doc.Status != "" && doc.Status != types.OrderStatusActive {
+			continue
+		}
+		if err := r.reserveSeats(doc, docs[:i]); err != nil {
+			return err
+		}
+	}
+	for _, doc := range docs {
+		r.insert(ctx, doc)
+	}
+	return nil
+}
+
+func (r *InMemoryOrdersRepo) insert(ctx context.Context, doc types.Order) {
+	passengers := doc.PassengerList()
+	customerIDs := make([]string, len(passengers))
+	for i, p := range passengers {
+		customerIDs[i] = r.obtainCustomerID(p)
+	}
+	if doc.Status == "" {
+		doc.Status = types.OrderStatusActive
+	}
+	stored := doc
+	stored.Passengers = nil
+	stored.LaunchDate = storedTime(doc.LaunchDate)
+	stored.CreatedAt = storedTime(doc.CreatedAt)
+	r.orders[doc.ID] = inMemoryOrder{doc: stored, customerIDs: customerIDs}
+	r.addAudit(ctx, doc.CreatedAt, types.OrderAuditActionCreate, nil, &doc)
+	r.addEvent(doc.CreatedAt, types.OrderEventCreated, doc)
+}
+
+/*
+reserveSeats returns ErrFlightImpossible when passengers of doc do not fit into seats left on its flight
+after stored active orders and pending ones, should be called under lock
+*/
+func (r *InMemoryOrdersRepo) reserveSeats(doc types.Order, pending []types.Order) error {
+	taken := 0
+	for id, o := range r.orders {
+		if id != doc.ID && o.doc.Status == types.OrderStatusActive && sameFlight(o.doc, doc) {
+			taken += len(o.customerIDs)
+		}
+	}
+	for _, o := range pending {
+		if (o.Status == "" || o.Status == types.OrderStatusActive) && sameFlight(o, doc) {
+			taken += len(o.PassengerList())
+		}
+	}
+	if taken+len(doc.PassengerList()) > types.FlightSeats {
+		return types.NewErrFlightImpossible(types.FlightImpossibleReasonNoSeats)
+	}
+	return nil
+}
+
+/*
+TakenSeats returns number of seats taken by active orders of flight, flight is launchpad on launch local date
+*/
+func (r *InMemoryOrdersRepo) TakenSeats(_ context.Context, launchpadID, launchLocalDate string) (int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	flight := types.Order{LaunchpadID: launchpadID, LaunchLocalDate: launchLocalDate}
+	taken := 0
+	for _, o := range r.orders {
+		if o.doc.Status == types.OrderStatusActive && sameFlight(o.doc, flight) {
+			taken += len(o.customerIDs)
+		}
+	}
+	return taken, nil
+}
+
+func sameFlight(a, b types.Order) bool {
+	return a.LaunchpadID == b.LaunchpadID && a.LaunchLocalDate == b.LaunchLocalDate
+}
+
+func (r *InMemoryOrdersRepo) obtainCustomerID(p types.Passenger) string {
+	if id, ok := r.customers[p]; ok {
+		return id
+	}
+	id := uuid.New().String()
+	r.customers[p] = id
+	r.passengers[id] = p
+	return id
+}
+
+/*
+storedTime keeps microseconds and drops location as timestamp columns do
+*/
+func storedTime(t time.Time) time.Time {
+	return t.UTC().Round(time.Microsecond)
+}
+
+/*
+load returns order with passengers read from customers, should be called under lock
+*/
+func (r *InMemoryOrdersRepo) load(o inMemoryOrder) types.Order {
+	passengers := make([]types.Passenger, len(o.customerIDs))
+	for i, id := range o.customerIDs {
+		passengers[i] = r.passengers[id]
+	}
+	return o.doc.WithPassengers(passengers)
+}
+
+func (r *InMemoryOrdersRepo) Get(_ context.Context, id string) (types.Order, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	o, ok := r.orders[id]
+	if !ok {
+		return types.Order{}, types.ErrNotFound{}
+	}
+	return r.load(o), nil
+}
+
+func (r *InMemoryOrdersRepo) List(_ context.Context, limit, offset int) ([]types.Order, error) {
+	orders := r.filter(func(types.Order) bool { return true }, byCreatedAt)
+	return limitOrders(orders, limit, offset), nil
+}
+
+/*
+ListByLaunchpad returns active orders of launchpad with launch date in [from, to) range ordered by launch date.
+
+	orders in conflict do not fly and cancelled orders are deleted, so neither of them is returned
+*/
+func (r *InMemoryOrdersRepo) ListByLaunchpad(_ context.Context, launchpadID string, from, to time.Time) ([]types.Order, error) {
+	return r.filter(func(o types.Order) bool {
+		return o.LaunchpadID == launchpadID && o.Status == types.OrderStatusActive && !o.LaunchDate.Before(from) && o.LaunchDate.Before(to)
+	}, func(a, b types.Order) bool {
+		if !a.LaunchDate.Equal(b.LaunchDate) {
+			return a.LaunchDate.Before(b.LaunchDate)
+		}
+		return byCreatedAt(a, b)
+	}), nil
+}
+
+/*
+ListByStatus returns orders in status with launch date not before from ordered by launch date
+*/
+func (r *InMemoryOrdersRepo) ListByStatus(_ context.Context, status string, from time.Time, limit, offset int) ([]types.Order, error) {
+	orders := r.filter(func(o types.Order) bool {
+		return o.Status == status && !o.LaunchDate.Before(from)
+	}, func(a, b types.Order) bool {
+		if !a.LaunchDate.Equal(b.LaunchDate) {
+			return a.LaunchDate.Before(b.LaunchDate)
+		}
+		return a.ID < b.ID
+	})
+	return limitOrders(orders, limit, offset), nil
+}
+
+/*
+Stream calls fn for each order in the same order as List, zero limit means no limit.
+
+	orders are copied before fn is called, so fn can use repo
+*/
+func (r *InMemoryOrdersRepo) Stream(_ context.Context, limit, offset int, fn func(types.Order) error) error {
+	orders := r.filter(func(types.Order) bool { return true }, byCreatedAt)
+	if limit == 0 {
+		limit = len(orders)
+	}
+	for _, o := range limitOrders(orders, limit, offset) {
+		if err := fn(o); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *InMemoryOrdersRepo) filter(match func(types.Order) bool, less func(a, b types.Order) bool) []types.Order {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var orders []types.Order
+	for _, o := range r.orders {
+		if doc := r.load(o); match(doc) {
+			orders = append(orders, doc)
+		}
+	}
+	sort.Slice(orders, func(i, j int) bool {
+		return less(orders[i], orders[j])
+	})
+	return orders
+}
+
+func byCreatedAt(a, b types.Order) bool {
+	if !a.CreatedAt.Equal(b.CreatedAt) {
+		return a.CreatedAt.Before(b.CreatedAt)
+	}
+	return a.ID < b.ID
+}
+
+func limitOrders(orders []types.Order, limit, offset int) []types.Order {
+	if offset >= len(orders) || limit <= 0 {
+		return nil
+	}
+	orders = orders[offset:]
+	if limit < len(orders) {
+		orders = orders[:limit]
+	}
+	return orders
+}
+
+/*
+Delete deletes order and records audit entry and order cancelled event at now, missing order is not an error
+*/
+func (r *InMemoryOrdersRepo) Delete(ctx context.Context, now time.Time, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	o, ok := r.orders[id]
+	if !ok {
+		return nil
+	}
+	before := r.load(o)
+	delete(r.orders, id)
+	r.addAudit(ctx, now, types.OrderAuditActionDelete, &before, nil)
+	r.addEvent(now, types.OrderEventCancelled, before)
+	return nil
+}
+
+/*
+MarkConflict moves order into conflict status with reason and emits order conflicted event.
+
+	doc is order as it was checked, ErrOrderChanged returned when stored order is not active anymore or its flight changed
+*/
+func (r *InMemoryOrdersRepo) MarkConflict(ctx context.Context, now time.Time, checked types.Order, reason string) error {
+	return r.update(ctx, now, checked.ID, types.OrderEventConflicted, sameActiveFlight(checked), func(doc *types.Order) {
+		doc.Status = types.OrderStatusConflict
+		doc.ConflictReason = reason
+	})
+}
+
+/*
+Reschedule moves order to launchpad, destination and launch date of doc, makes it active and emits order rescheduled event.
+
+	ErrOrderChanged returned when stored order is not in conflict anymore
+*/
+func (r *InMemoryOrdersRepo) Reschedule(ctx context.Context, now time.Time, doc types.Order) error {
+	return r.update(ctx, now, doc.ID, types.OrderEventRescheduled, inConflict, func(o *types.Order) {
+		o.LaunchpadID = doc.LaunchpadID
+		o.DestinationID = doc.DestinationID
+		o.LaunchDate = doc.LaunchDate
+		o.LaunchLocalDate = doc.LaunchLocalDate
+		o.LaunchpadTimezone = doc.LaunchpadTimezone
+		o.Status = types.OrderStatusActive
+		o.ConflictReason = ""
+	})
+}
+
+/*
+update changes flight fields of order by fn as PostgreSQLOrdersRepo does, ErrNotFound returned when order does not exist
+*/
+func (r *InMemoryOrdersRepo) update(ctx context.Context, now time.Time, id, eventType string, expected func(doc types.Order) bool,
+	fn func(doc *types.Order)) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	o, ok := r.orders[id]
+	if !ok {
+		return types.ErrNotFound{}
+	}
+	before := r.load(o)
+	if !expected(before) {
+		return types.ErrOrderChanged{}
+	}
+	after := r.load(o)
+	fn(&after)
+	moved := !sameFlight(after, before)
+	if after.Status == types.OrderStatusActive && (moved || before.Status != types.OrderStatusActive) {
+		if err := r.reserveSeats(after, nil); err != nil {
+			return err
+		}
+	}
+	o.doc.LaunchpadID = after.LaunchpadID
+	o.doc.DestinationID = after.DestinationID
+	o.doc.LaunchDate = storedTime(after.LaunchDate)
+	o.doc.LaunchLocalDate = after.LaunchLocalDate
+	o.doc.LaunchpadTimezone = after.LaunchpadTimezone
+	o.doc.Status = after.Status
+	o.doc.ConflictReason = after.ConflictReason
+	r.orders[id] = o
+	r.addAudit(ctx, now, types.OrderAuditActionUpdate, &before, &after)
+	r.addEvent(now, eventType, after)
+	return nil
+}
+
+/*
+addAudit records audit entry created at now with actor and request id from context, should be called under lock
+*/
+func (r *InMemoryOrdersRepo) addAudit(ctx context.Context, now time.Time, action string, before, after *types.Order) {
+	e := types.OrderAuditEntry{
+		ID:        uuid.New().String(),
+		Action:    action,
+		Actor:     actor.FromContext(ctx),
+		RequestID: middleware.GetReqID(ctx),
+		Before:    cloneOrder(before),
+		After:     cloneOrder(after),
+		CreatedAt: now.UTC(),
+	}
+	if before != nil {
+		e.OrderID = before.ID
+	}
+	if after != nil {
+		e.OrderID = after.ID
+	}
+	r.audit = append(r.audit, e)
+}
+
+/*
+History returns audit entries of order, oldest first
+*/
+func (r *InMemoryOrdersRepo) History(_ context.Context, orderID string) ([]types.OrderAuditEntry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var entries []types.OrderAuditEntry
+	for _, e := range r.audit {
+		if e.OrderID != orderID {
+			continue
+		}
+		e.Before, e.After = cloneOrder(e.Before), cloneOrder(e.After)
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+/*
+addEvent stores event occurred at now in outbox, should be called under lock
+*/
+func (r *InMemoryOrdersRepo) addEvent(now time.Time, eventType string, doc types.Order) {
+	now = now.UTC()
+	r.seq++
+	r.events = append(r.events, types.OutboxEvent{
+		OrderEvent: types.OrderEvent{
+			ID:         uuid.New().String(),
+			Type:       eventType,
+			OrderID:    doc.ID,
+			Order:      cloneOrder(&doc),
+			OccurredAt: now,
+		},
+		Seq:           r.seq,
+		NextAttemptAt: now,
+	})
+}
+
+/*
+ProcessOutbox passes due events to fn and saves state fn set on them, see PostgreSQLOrdersRepo.ProcessOutbox.
+
+	fn is called without lock, so sinks can use repo. returns number of events passed to fn
+*/
+func (r *InMemoryOrdersRepo) ProcessOutbox(_ context.Context, now time.Time, limit int, fn func(e *types.OutboxEvent)) (int, error) {
+	if !r.outboxMu.TryLock() {
+		return 0, nil
+	}
+	defer r.outboxMu.Unlock()
+	r.mu.RLock()
+	var events []types.OutboxEvent
+	pending := map[string]bool{}
+	for _, e := range r.events {
+		if len(events) == limit {
+			break
+		}
+		if e.DeadAt != nil || pending[e.OrderID] {
+			continue
+		}
+		pending[e.OrderID] = true
+		if e.Attempts > 0 && e.NextAttemptAt.After(now) {
+			continue
+		}
+		e.Order = cloneOrder(e.Order)
+		events = append(events, e)
+	}
+	r.mu.RUnlock()
+
+	processed := map[int64]types.OutboxEvent{}
+	for i := range events {
+		fn(&events[i])
+		processed[events[i].Seq] = events[i]
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	unpublished := r.events[:0]
+	for _, e := range r.events {
+		if p, ok := processed[e.Seq]; ok {
+			e.Attempts, e.LastError, e.NextAttemptAt, e.PublishedAt, e.DeadAt = p.Attempts, p.LastError, p.NextAttemptAt, p.PublishedAt, p.DeadAt
+		}
+		if e.PublishedAt == nil {
+			unpublished = append(unpublished, e)
+		}
+	}
+	r.events = unpublished
+	return len(processed), nil
+}
+
+func cloneOrder(doc *types.Order) *types.Order {
+	if doc == nil {
+		return nil
+	}
+	o := *doc
+	if doc.Passengers != nil {
+		o.Passengers = append([]types.Passenger(nil), doc.Passengers...)
+	}
+	return &o
+}
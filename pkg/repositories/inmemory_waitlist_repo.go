@@ -0,0 +1,153 @@
+package repositories
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/leveldorado/space-trouble/pkg/types"
+	"github.com/pkg/errors"
+)
+
+/*
+InMemoryWaitlistRepo
+
+	implements the same semantics as PostgreSQLWaitlistRepo without db, state is lost on restart
+*/
+type InMemoryWaitlistRepo struct {
+	mu   sync.RWMutex
+	docs map[string]types.WaitlistEntry
+}
+
+func NewInMemoryWaitlistRepo() *InMemoryWaitlistRepo {
+	return &InMemoryWaitlistRepo{docs: map[string]types.WaitlistEntry{}}
+}
+
+func (r *InMemoryWaitlistRepo) Insert(_ context.Context, doc types.WaitlistEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.docs[doc.ID]; ok {
+		return errors.Errorf(`waitlist entry already exists: id - %s`, doc.ID)
+	}
+	doc.Order = *cloneOrder(&doc.Order)
+	r.docs[doc.ID] = doc
+	return nil
+}
+
+func (r *InMemoryWaitlistRepo) Get(_ context.Context, id string) (types.WaitlistEntry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	doc, ok := r.docs[id]
+	if !ok {
+		return types.WaitlistEntry{}, types.ErrNotFound{}
+	}
+	doc.Order = *cloneOrder(&doc.Order)
+	return doc, nil
+}
+
+/*
+ClaimDue returns waiting entries which check time passed and which are first waiting entries of their flights,
+oldest entries first, and moves their next check by lease
+*/
+func (r *InMemoryWaitlistRepo) ClaimDue(_ context.Context, now time.Time, lease time.Duration, limit int) ([]types.WaitlistEntry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var docs []types.WaitlistEntry
+	for _, doc := range r.docs {
+		if doc.Status == types.WaitlistStatusWaiting && !doc.NextCheckAt.After(now) && !r.hasWaitingAhead(doc) {
+			docs = append(docs, doc)
+		}
+	}
+	sort.Slice(docs, func(i, j int) bool {
+		return joinedBefore(docs[i], docs[j])
+	})
+	if len(docs) > limit {
+		docs = docs[:limit]
+	}
+	for i := range docs {
+		docs[i].NextCheckAt = now.Add(lease)
+		r.docs[docs[i].ID] = docs[i]
+		docs[i].Order = *cloneOrder(&docs[i].Order)
+	}
+	return docs, nil
+}
+
+/*
+HasWaitingAhead reports whether entry of the same flight which joined earlier is still waiting
+*/
+func (r *InMemoryWaitlistRepo) HasWaitingAhead(_ context.Context, id string) (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	doc, ok := r.docs[id]
+	return ok && r.hasWaitingAhead(doc), nil
+}
+
+func (r *InMemoryWaitlistRepo) hasWaitingAhead(doc types.WaitlistEntry) bool {
+	for _, other := range r.docs {
+		if other.Status == types.WaitlistStatusWaiting &&
+			other.Order.LaunchpadID == doc.Order.LaunchpadID &&
+			other.Order.LaunchLocalDate == doc.Order.LaunchLocalDate &&
+			joinedBefore(other, doc) {
+			return true
+		}
+	}
+	return false
+}
+
+func joinedBefore(a, b types.WaitlistEntry) bool {
+	if !a.CreatedAt.Equal(b.CreatedAt) {
+		return a.CreatedAt.Before(b.CreatedAt)
+	}
+	return a.ID < b.ID
+}
+
+/*
+Update saves check result, entry cancelled meanwhile stays cancelled
+*/
+func (r *InMemoryWaitlistRepo) Update(_ context.Context, doc types.WaitlistEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored, ok := r.docs[doc.ID]
+	if !ok || stored.Status == types.WaitlistStatusCancelled {
+		return nil
+	}
+	stored.Status = doc.Status
+	stored.OrderID = doc.OrderID
+	stored.Checks = doc.Checks
+	stored.LastError = doc.LastError
+	stored.NextCheckAt = doc.NextCheckAt
+	stored.PromotedAt = doc.PromotedAt
+	r.docs[doc.ID] = stored
+	return nil
+}
+
+/*
+Cancel cancels waiting entry, ErrNotFound returned when there is no waiting entry with id
+*/
+func (r *InMemoryWaitlistRepo) Cancel(_ context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	doc, ok := r.docs[id]
+	if !ok || doc.Status != types.WaitlistStatusWaiting {
+		return types.ErrNotFound{}
+	}
+	doc.Status = types.WaitlistStatusCancelled
+	r.docs[id] = doc
+	return nil
+}
+
+/*
+RecheckLaunchpad makes waiting entries of launchpad due at provided time
+*/
+func (r *InMemoryWaitlistRepo) RecheckLaunchpad(_ context.Context, launchpadID string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id, doc := range r.docs {
+		if doc.Order.LaunchpadID == launchpadID && doc.Status == types.WaitlistStatusWaiting && doc.NextCheckAt.After(at) {
+			doc.NextCheckAt = at
+			r.docs[id] = doc
+		}
+	}
+	return nil
+}
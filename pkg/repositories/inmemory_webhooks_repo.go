@@ -0,0 +1,178 @@
+package repositories
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/leveldorado/space-trouble/pkg/types"
+	"github.com/pkg/errors"
+)
+
+/*
+InMemoryWebhooksRepo
+
+	implements the same semantics as PostgreSQLWebhooksRepo without db, state is lost on restart
+*/
+type InMemoryWebhooksRepo struct {
+	mu            sync.RWMutex
+	subscriptions map[string]types.WebhookSubscription
+	deliveries    map[string]types.WebhookDelivery
+}
+
+func NewInMemoryWebhooksRepo() *InMemoryWebhooksRepo {
+	return &InMemoryWebhooksRepo{
+		subscriptions: map[string]types.WebhookSubscription{},
+		deliveries:    map[string]types.WebhookDelivery{},
+	}
+}
+
+func (r *InMemoryWebhooksRepo) InsertSubscription(_ context.Context, doc types.WebhookSubscription) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.subscriptions[doc.ID]; ok {
+		return errors.Errorf(`subscription already exists: id - %s`, doc.ID)
+	}
+	doc.EventTypes = append([]string(nil), doc.EventTypes...)
+	r.subscriptions[doc.ID] = doc
+	return nil
+}
+
+func (r *InMemoryWebhooksRepo) GetSubscription(_ context.Context, id string) (types.WebhookSubscription, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	doc, ok := r.subscriptions[id]
+	if !ok {
+		return types.WebhookSubscription{}, types.ErrNotFound{}
+	}
+	return doc, nil
+}
+
+func (r *InMemoryWebhooksRepo) ListSubscriptions(_ context.Context) ([]types.WebhookSubscription, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var docs []types.WebhookSubscription
+	for _, doc := range r.subscriptions {
+		docs = append(docs, doc)
+	}
+	sort.Slice(docs, func(i, j int) bool {
+		return docs[i].CreatedAt.Before(docs[j].CreatedAt)
+	})
+	return docs, nil
+}
+
+func (r *InMemoryWebhooksRepo) DeleteSubscription(_ context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.subscriptions[id]; !ok {
+		return types.ErrNotFound{}
+	}
+	delete(r.subscriptions, id)
+	return nil
+}
+
+/*
+InsertDeliveries stores deliveries, delivery of the same event to the same subscription is stored once
+*/
+func (r *InMemoryWebhooksRepo) InsertDeliveries(_ context.Context, docs []types.WebhookDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, doc := range docs {
+		if _, ok := r.deliveries[doc.ID]; ok {
+			return errors.Errorf(`delivery already exists: id - %s`, doc.ID)
+		}
+	}
+	for _, doc := range docs {
+		if r.hasDelivery(doc.EventID, doc.SubscriptionID) {
+			continue
+		}
+		r.deliveries[doc.ID] = doc
+	}
+	return nil
+}
+
+func (r *InMemoryWebhooksRepo) hasDelivery(eventID, subscriptionID string) bool {
+	for _, doc := range r.deliveries {
+		if doc.EventID == eventID && doc.SubscriptionID == subscriptionID {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+ClaimDueDeliveries returns pending deliveries which next attempt time passed and moves their next attempt by lease
+*/
+func (r *InMemoryWebhooksRepo) ClaimDueDeliveries(_ context.Context, now time.Time, lease time.Duration, limit int) ([]types.WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var docs []types.WebhookDelivery
+	for _, doc := range r.deliveries {
+		if doc.Status == types.WebhookDeliveryStatusPending && !doc.NextAttemptAt.After(now) {
+			docs = append(docs, doc)
+		}
+	}
+	sort.Slice(docs, func(i, j int) bool {
+		return docs[i].NextAttemptAt.Before(docs[j].NextAttemptAt)
+	})
+	if len(docs) > limit {
+		docs = docs[:limit]
+	}
+	for i := range docs {
+		docs[i].NextAttemptAt = now.Add(lease)
+		r.deliveries[docs[i].ID] = docs[i]
+	}
+	return docs, nil
+}
+
+func (r *InMemoryWebhooksRepo) UpdateDelivery(_ context.Context, doc types.WebhookDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored, ok := r.deliveries[doc.ID]
+	if !ok {
+		return types.ErrNotFound{}
+	}
+	stored.Status = doc.Status
+	stored.Attempts = doc.Attempts
+	stored.LastError = doc.LastError
+	stored.NextAttemptAt = doc.NextAttemptAt
+	stored.DeliveredAt = doc.DeliveredAt
+	r.deliveries[doc.ID] = stored
+	return nil
+}
+
+func (r *InMemoryWebhooksRepo) GetDelivery(_ context.Context, id string) (types.WebhookDelivery, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	doc, ok := r.deliveries[id]
+	if !ok {
+		return types.WebhookDelivery{}, types.ErrNotFound{}
+	}
+	return doc, nil
+}
+
+/*
+ListDeliveries returns deliveries with provided status, all deliveries for empty status. newest first
+*/
+func (r *InMemoryWebhooksRepo) ListDeliveries(_ context.Context, status string, limit, offset int) ([]types.WebhookDelivery, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var docs []types.WebhookDelivery
+	for _, doc := range r.deliveries {
+		if status == "" || doc.Status == status {
+			docs = append(docs, doc)
+		}
+	}
+	sort.Slice(docs, func(i, j int) bool {
+		return docs[i].CreatedAt.After(docs[j].CreatedAt)
+	})
+	if offset >= len(docs) || limit <= 0 {
+		return nil, nil
+	}
+	docs = docs[offset:]
+	if limit < len(docs) {
+		docs = docs[:limit]
+	}
+	return docs, nil
+}
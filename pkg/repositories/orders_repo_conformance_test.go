@@ -0,0 +1,507 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/brianvoe/gofakeit"
+	"github.com/google/uuid"
+	"github.com/leveldorado/space-trouble/pkg/tools/actor"
+	"github.com/leveldorado/space-trouble/pkg/types"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+type conformanceOrdersRepo interface {
+	Insert(ctx context.Context, doc types.Order) error
+	InsertMany(ctx context.Context, docs []types.Order) error
+	TakenSeats(ctx context.Context, launchpadID, launchLocalDate string) (int, error)
+	Get(ctx context.Context, id string) (types.Order, error)
+	List(ctx context.Context, limit, offset int) ([]types.Order, error)
+	ListByLaunchpad(ctx context.Context, launchpadID string, from, to time.Time) ([]types.Order, error)
+	ListByStatus(ctx context.Context, status string, from time.Time, limit, offset int) ([]types.Order, error)
+	Stream(ctx context.Context, limit, offset int, fn func(types.Order) error) error
+	Delete(ctx context.Context, now time.Time, id string) error
+	MarkConflict(ctx context.Context, now time.Time, checked types.Order, reason string) error
+	Reschedule(ctx context.Context, now time.Time, doc types.Order) error
+	History(ctx context.Context, orderID string) ([]types.OrderAuditEntry, error)
+	ProcessOutbox(ctx context.Context, now time.Time, limit int, fn func(e *types.OutboxEvent)) (int, error)
+}
+
+/*
+ordersRepoUnderTest is empty orders repo with counter of its stored customers, which are not exposed by repo interface
+*/
+type ordersRepoUnderTest struct {
+	repo      conformanceOrdersRepo
+	customers func(t *testing.T) int
+}
+
+func TestInMemoryOrdersRepo_Conformance(t *testing.T) {
+	testOrdersRepoConformance(t, func(t *testing.T) ordersRepoUnderTest {
+		repo := NewInMemoryOrdersRepo()
+		return ordersRepoUnderTest{repo: repo, customers: func(t *testing.T) int {
+			repo.mu.RLock()
+			defer repo.mu.RUnlock()
+			return len(repo.customers)
+		}}
+	})
+}
+
+func TestPostgreSQLOrdersRepo_Conformance(t *testing.T) {
+	testOrdersRepoConformance(t, func(t *testing.T) ordersRepoUnderTest {
+		repo := prepareOrdersRepo(t)
+		q := `TRUNCATE TABLE "` + orderTableName + `", "` + orderPassengerTableName + `", "` + customerInfoTableName + `", "` +
+			orderEventsTableName + `"`
+		_, err := repo.conn.Exec(q)
+		require.NoError(t, err)
+		return ordersRepoUnderTest{repo: repo, customers: func(t *testing.T) int {
+			var n int
+			require.NoError(t, repo.conn.QueryRow(`SELECT count(*) FROM "`+customerInfoTableName+`"`).Scan(&n))
+			return n
+		}}
+	})
+}
+
+func TestSQLiteOrdersRepo_Conformance(t *testing.T) {
+	testOrdersRepoConformance(t, func(t *testing.T) ordersRepoUnderTest {
+		repo := prepareSQLiteOrdersRepo(t)
+		return ordersRepoUnderTest{repo: repo, customers: func(t *testing.T) int {
+			var n int
+			require.NoError(t, repo.conn.QueryRow(`SELECT count(*) FROM "`+customerInfoTableName+`"`).Scan(&n))
+			return n
+		}}
+	})
+}
+
+/*
+testOrdersRepoConformance checks behaviour every orders repo implementation has to share, newRepo returns empty repo
+*/
+func testOrdersRepoConformance(t *testing.T, newRepo func(t *testing.T) ordersRepoUnderTest) {
+	ctx := context.TODO()
+	t.Run("get", func(t *testing.T) {
+		r := newRepo(t)
+		doc := conformanceOrder(time.Date(2053, 3, 6, 5, 0, 0, 0, time.UTC))
+		doc.Email = gofakeit.Email()
+		require.NoError(t, r.repo.Insert(ctx, doc))
+
+		got, err := r.repo.Get(ctx, doc.ID)
+		require.NoError(t, err)
+		doc.Status = types.OrderStatusActive
+		require.Equal(t, normalizeConformanceOrder(doc.WithPassengers(nil)), normalizeConformanceOrder(got))
+
+		_, err = r.repo.Get(ctx, uuid.New().String())
+		require.True(t, errors.As(err, &types.ErrNotFound{}))
+	})
+	t.Run("customers are deduplicated", func(t *testing.T) {
+		r := newRepo(t)
+		launchDate := time.Date(2053, 3, 6, 5, 0, 0, 0, time.UTC)
+		group := conformanceOrder(launchDate).WithPassengers([]types.Passenger{conformancePassenger(), conformancePassenger()})
+		require.NoError(t, r.repo.Insert(ctx, group))
+		require.Equal(t, 2, r.customers(t))
+
+		again := conformanceOrder(launchDate).WithPassengers(group.Passengers[:1])
+		require.NoError(t, r.repo.Insert(ctx, again))
+		require.Equal(t, 2, r.customers(t))
+
+		twin := group.Passengers[0]
+		twin.BirthdayDay++
+		require.NoError(t, r.repo.Insert(ctx, conformanceOrder(launchDate).WithPassengers([]types.Passenger{twin})))
+		require.Equal(t, 3, r.customers(t))
+
+		got, err := r.repo.Get(ctx, group.ID)
+		require.NoError(t, err)
+		require.Equal(t, group.Passengers, got.Passengers)
+		require.Equal(t, group.Passengers[0], got.LeadPassenger())
+	})
+	t.Run("insert many is atomic", func(t *testing.T) {
+		r := newRepo(t)
+		launchDate := time.Date(2053, 3, 6, 5, 0, 0, 0, time.UTC)
+		existing := conformanceOrder(launchDate)
+		require.NoError(t, r.repo.Insert(ctx, existing))
+		require.True(t, errors.As(r.repo.Insert(ctx, existing), &types.ErrDuplicatedOrder{}))
+
+		valid := conformanceOrder(launchDate)
+		invalid := conformanceOrder(launchDate)
+		invalid.ID = "not uuid"
+		require.Error(t, r.repo.InsertMany(ctx, []types.Order{valid, invalid}))
+		require.Error(t, r.repo.InsertMany(ctx, []types.Order{valid, existing}))
+		_, err := r.repo.Get(ctx, valid.ID)
+		require.True(t, errors.As(err, &types.ErrNotFound{}))
+
+		require.NoError(t, r.repo.InsertMany(ctx, []types.Order{valid, conformanceOrder(launchDate)}))
+		_, err = r.repo.Get(ctx, valid.ID)
+		require.NoError(t, err)
+	})
+	t.Run("flight seats", func(t *testing.T) {
+		r := newRepo(t)
+		launchDate := time.Date(2053, 3, 6, 5, 0, 0, 0, time.UTC)
+		launchpadID := uuid.New().String()
+		flightOrder := func(passengers int) types.Order {
+			doc := conformanceOrder(launchDate)
+			doc.LaunchpadID = launchpadID
+			list := make([]types.Passenger, passengers)
+			for i := range list {
+				list[i] = conformancePassenger()
+			}
+			return doc.WithPassengers(list)
+		}
+		var group types.Order
+		booked := 0
+		for seats := 0; seats < types.FlightSeats; seats += types.MaxOrderPassengers {
+			group = flightOrder(types.MaxOrderPassengers)
+			require.NoError(t, r.repo.Insert(ctx, group))
+			booked += types.MaxOrderPassengers
+		}
+		taken, err := r.repo.TakenSeats(ctx, launchpadID, group.LaunchLocalDate)
+		require.NoError(t, err)
+		require.Equal(t, booked, taken)
+		impossible := types.ErrFlightImpossible{}
+		require.True(t, errors.As(r.repo.Insert(ctx, flightOrder(1)), &impossible))
+		require.Equal(t, types.FlightImpossibleReasonNoSeats, impossible.Reason)
+		// other day of the same launchpad is another flight
+		other := conformanceOrder(launchDate.AddDate(0, 0, 1))
+		other.LaunchpadID = launchpadID
+		require.NoError(t, r.repo.Insert(ctx, other))
+
+		// order in conflict does not take seats and can not be rebooked back to full flight
+		require.NoError(t, r.repo.MarkConflict(ctx, time.Now(), group, types.FlightImpossibleReasonCompetitorLaunch))
+		late := flightOrder(2)
+		require.NoError(t, r.repo.InsertMany(ctx, []types.Order{late}))
+		require.True(t, errors.As(r.repo.Reschedule(ctx, time.Now(), group), &impossible))
+		require.Equal(t, types.FlightImpossibleReasonNoSeats, impossible.Reason)
+		got, err := r.repo.Get(ctx, group.ID)
+		require.NoError(t, err)
+		require.Equal(t, types.OrderStatusConflict, got.Status)
+
+		// seats of orders inserted together are counted together
+		require.NoError(t, r.repo.Delete(ctx, time.Now(), late.ID))
+		require.True(t, errors.As(r.repo.InsertMany(ctx, []types.Order{flightOrder(5), flightOrder(6)}), &impossible))
+		list, err := r.repo.ListByLaunchpad(ctx, launchpadID, launchDate, launchDate.Add(time.Hour))
+		require.NoError(t, err)
+		require.Len(t, list, types.FlightSeats/types.MaxOrderPassengers-1)
+	})
+	t.Run("list and stream", func(t *testing.T) {
+		r := newRepo(t)
+		createdAt := time.Now().UTC().Truncate(time.Second)
+		var docs []types.Order
+		for i := 0; i < 4; i++ {
+			doc := conformanceOrder(time.Date(2053, 3, 6-i, 5, 0, 0, 0, time.UTC))
+			doc.CreatedAt = createdAt.Add(time.Duration(i) * time.Second)
+			docs = append(docs, doc)
+		}
+		// inserted in reverse, listed by creation time
+		for i := len(docs) - 1; i >= 0; i-- {
+			require.NoError(t, r.repo.Insert(ctx, docs[i]))
+		}
+		ids := conformanceOrderIDs(docs)
+		list, err := r.repo.List(ctx, 2, 1)
+		require.NoError(t, err)
+		require.Equal(t, ids[1:3], conformanceOrderIDs(list))
+		list, err = r.repo.List(ctx, 10, 4)
+		require.NoError(t, err)
+		require.Empty(t, list)
+
+		var streamed []string
+		require.NoError(t, r.repo.Stream(ctx, 0, 1, func(o types.Order) error {
+			streamed = append(streamed, o.ID)
+			return nil
+		}))
+		require.Equal(t, ids[1:], streamed)
+		streamed = nil
+		require.NoError(t, r.repo.Stream(ctx, 2, 0, func(o types.Order) error {
+			streamed = append(streamed, o.ID)
+			return nil
+		}))
+		require.Equal(t, ids[:2], streamed)
+		stopErr := errors.New("stop")
+		require.True(t, errors.Is(r.repo.Stream(ctx, 0, 0, func(types.Order) error { return stopErr }), stopErr))
+	})
+	t.Run("list by launchpad", func(t *testing.T) {
+		r := newRepo(t)
+		day := time.Date(2053, 3, 6, 5, 0, 0, 0, time.UTC)
+		launchpadID := uuid.New().String()
+		var ids []string
+		for _, launchDate := range []time.Time{day.Add(time.Hour), day, day.Add(-time.Second), day.Add(24 * time.Hour)} {
+			doc := conformanceOrder(launchDate)
+			doc.LaunchpadID = launchpadID
+			require.NoError(t, r.repo.Insert(ctx, doc))
+			ids = append(ids, doc.ID)
+		}
+		require.NoError(t, r.repo.Insert(ctx, conformanceOrder(day)))
+		conflict := conformanceOrder(day)
+		conflict.LaunchpadID = launchpadID
+		require.NoError(t, r.repo.Insert(ctx, conflict))
+		require.NoError(t, r.repo.MarkConflict(ctx, time.Now(), conflict, types.FlightImpossibleReasonCompetitorLaunch))
+		cancelled := conformanceOrder(day)
+		cancelled.LaunchpadID = launchpadID
+		require.NoError(t, r.repo.Insert(ctx, cancelled))
+		require.NoError(t, r.repo.Delete(ctx, time.Now(), cancelled.ID))
+
+		list, err := r.repo.ListByLaunchpad(ctx, launchpadID, day, day.Add(24*time.Hour))
+		require.NoError(t, err)
+		require.Equal(t, []string{ids[1], ids[0]}, conformanceOrderIDs(list))
+		require.Equal(t, "2053-03-06", list[0].LaunchLocalDate)
+		require.Equal(t, "UTC", list[0].LaunchpadTimezone)
+		require.Len(t, list[0].Passengers, 1)
+	})
+	t.Run("conflicts", func(t *testing.T) {
+		r := newRepo(t)
+		from := time.Date(2053, 3, 1, 0, 0, 0, 0, time.UTC)
+		var ids []string
+		for _, days := range []int{5, -1, 3, 4} {
+			doc := conformanceOrder(from.AddDate(0, 0, days))
+			require.NoError(t, r.repo.Insert(ctx, doc))
+			require.NoError(t, r.repo.MarkConflict(ctx, time.Now(), doc, types.FlightImpossibleReasonCompetitorLaunch))
+			ids = append(ids, doc.ID)
+		}
+		require.True(t, errors.As(r.repo.MarkConflict(ctx, time.Now(), conformanceOrder(from), ""), &types.ErrNotFound{}))
+		// order already in conflict or with flight changed since check is not marked
+		stale, err := r.repo.Get(ctx, ids[0])
+		require.NoError(t, err)
+		require.True(t, errors.As(r.repo.MarkConflict(ctx, time.Now(), stale, types.FlightImpossibleReasonLaunchpad), &types.ErrOrderChanged{}))
+		moved := conformanceOrder(from)
+		require.NoError(t, r.repo.Insert(ctx, moved))
+		moved.LaunchDate = moved.LaunchDate.AddDate(0, 0, 1)
+		require.True(t, errors.As(r.repo.MarkConflict(ctx, time.Now(), moved, types.FlightImpossibleReasonLaunchpad), &types.ErrOrderChanged{}))
+		require.NoError(t, r.repo.Delete(ctx, time.Now(), moved.ID))
+
+		list, err := r.repo.ListByStatus(ctx, types.OrderStatusConflict, from, 2, 0)
+		require.NoError(t, err)
+		require.Equal(t, []string{ids[2], ids[3]}, conformanceOrderIDs(list))
+		require.Equal(t, types.FlightImpossibleReasonCompetitorLaunch, list[0].ConflictReason)
+		list, err = r.repo.ListByStatus(ctx, types.OrderStatusConflict, from, 2, 2)
+		require.NoError(t, err)
+		require.Equal(t, []string{ids[0]}, conformanceOrderIDs(list))
+
+		doc := list[0]
+		doc.LaunchDate = from.AddDate(0, 0, 10)
+		doc.LaunchLocalDate = doc.LaunchDate.Format(types.LocalDateLayout)
+		doc.LaunchpadID = uuid.New().String()
+		require.NoError(t, r.repo.Reschedule(ctx, time.Now(), doc))
+		require.True(t, errors.As(r.repo.Reschedule(ctx, time.Now(), conformanceOrder(from)), &types.ErrNotFound{}))
+		// order is already rebooked
+		require.True(t, errors.As(r.repo.Reschedule(ctx, time.Now(), doc), &types.ErrOrderChanged{}))
+		got, err := r.repo.Get(ctx, doc.ID)
+		require.NoError(t, err)
+		require.Equal(t, types.OrderStatusActive, got.Status)
+		require.Empty(t, got.ConflictReason)
+		require.Equal(t, doc.LaunchpadID, got.LaunchpadID)
+		require.Equal(t, doc.LaunchLocalDate, got.LaunchLocalDate)
+		require.True(t, doc.LaunchDate.Equal(got.LaunchDate))
+		list, err = r.repo.ListByStatus(ctx, types.OrderStatusActive, from, 10, 0)
+		require.NoError(t, err)
+		require.Equal(t, []string{doc.ID}, conformanceOrderIDs(list))
+	})
+	t.Run("delete and history", func(t *testing.T) {
+		r := newRepo(t)
+		actorCtx := actor.NewContext(ctx, "conformance")
+		doc := conformanceOrder(time.Date(2053, 3, 6, 5, 0, 0, 0, time.UTC))
+		require.NoError(t, r.repo.Insert(actorCtx, doc))
+		require.NoError(t, r.repo.MarkConflict(actorCtx, time.Now(), doc, types.FlightImpossibleReasonCompetitorLaunch))
+		require.NoError(t, r.repo.Delete(actorCtx, time.Now(), doc.ID))
+		_, err := r.repo.Get(ctx, doc.ID)
+		require.True(t, errors.As(err, &types.ErrNotFound{}))
+		require.NoError(t, r.repo.Delete(ctx, time.Now(), doc.ID))
+
+		history, err := r.repo.History(ctx, doc.ID)
+		require.NoError(t, err)
+		require.Len(t, history, 3)
+		for i, action := range []string{types.OrderAuditActionCreate, types.OrderAuditActionUpdate, types.OrderAuditActionDelete} {
+			require.Equal(t, action, history[i].Action)
+			require.Equal(t, "conformance", history[i].Actor)
+			require.Equal(t, doc.ID, history[i].OrderID)
+		}
+		require.Nil(t, history[0].Before)
+		require.Equal(t, types.OrderStatusActive, history[1].Before.Status)
+		require.Equal(t, types.OrderStatusConflict, history[1].After.Status)
+		require.Nil(t, history[2].After)
+
+		history, err = r.repo.History(ctx, uuid.New().String())
+		require.NoError(t, err)
+		require.Empty(t, history)
+	})
+	t.Run("audit and events follow caller clock", func(t *testing.T) {
+		r := newRepo(t)
+		// virtual time in staging may be far from actual one
+		created := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+		conflicted, deleted := created.Add(time.Hour), created.Add(2*time.Hour)
+		doc := conformanceOrder(time.Date(2053, 3, 6, 5, 0, 0, 0, time.UTC))
+		doc.CreatedAt = created
+		require.NoError(t, r.repo.Insert(ctx, doc))
+		require.NoError(t, r.repo.MarkConflict(ctx, conflicted, doc, types.FlightImpossibleReasonCompetitorLaunch))
+		require.NoError(t, r.repo.Delete(ctx, deleted, doc.ID))
+
+		history, err := r.repo.History(ctx, doc.ID)
+		require.NoError(t, err)
+		require.Len(t, history, 3)
+		for i, at := range []time.Time{created, conflicted, deleted} {
+			require.True(t, at.Equal(history[i].CreatedAt), "%s != %s", at, history[i].CreatedAt)
+		}
+		var occurred []time.Time
+		_, err = r.repo.ProcessOutbox(ctx, deleted, 1000, func(e *types.OutboxEvent) {
+			if e.OrderID == doc.ID {
+				occurred = append(occurred, e.OccurredAt)
+			}
+			e.PublishedAt = &deleted
+		})
+		require.NoError(t, err)
+		require.Len(t, occurred, 1)
+		require.True(t, created.Equal(occurred[0]), "%s != %s", created, occurred[0])
+	})
+	t.Run("outbox", func(t *testing.T) {
+		r := newRepo(t)
+		first := conformanceOrder(time.Date(2053, 3, 6, 5, 0, 0, 0, time.UTC))
+		second := conformanceOrder(time.Date(2053, 3, 6, 5, 0, 0, 0, time.UTC))
+		require.NoError(t, r.repo.Insert(ctx, first))
+		require.NoError(t, r.repo.Insert(ctx, second))
+		require.NoError(t, r.repo.Delete(ctx, time.Now(), first.ID))
+
+		var seen []string
+		process := func(failed string) int {
+			n, err := r.repo.ProcessOutbox(ctx, time.Now(), 1000, func(e *types.OutboxEvent) {
+				require.Equal(t, e.OrderID, e.Order.ID)
+				seen = append(seen, e.OrderID+" "+e.Type)
+				e.Attempts++
+				if e.OrderID == failed {
+					e.LastError = "fail"
+					return
+				}
+				now := time.Now()
+				e.PublishedAt = &now
+			})
+			require.NoError(t, err)
+			return n
+		}
+
+		// cancelled event of first order waits until created one is published
+		require.Equal(t, 2, process(first.ID))
+		require.Equal(t, []string{first.ID + " " + types.OrderEventCreated, second.ID + " " + types.OrderEventCreated}, seen)
+
+		// only the earliest pending event of order is passed in one call
+		seen = nil
+		require.Equal(t, 1, process(""))
+		require.Equal(t, []string{first.ID + " " + types.OrderEventCreated}, seen)
+
+		seen = nil
+		require.Equal(t, 1, process(""))
+		require.Equal(t, []string{first.ID + " " + types.OrderEventCancelled}, seen)
+
+		seen = nil
+		require.Equal(t, 0, process(""))
+		require.Empty(t, seen)
+	})
+	t.Run("outbox retries follow caller clock", func(t *testing.T) {
+		r := newRepo(t)
+		doc := conformanceOrder(time.Date(2053, 3, 6, 5, 0, 0, 0, time.UTC))
+		require.NoError(t, r.repo.Insert(ctx, doc))
+		// virtual time behind actual one
+		now := time.Now().Add(-24 * time.Hour)
+
+		var attempts int
+		process := func(now time.Time) int {
+			n, err := r.repo.ProcessOutbox(ctx, now, 1000, func(e *types.OutboxEvent) {
+				attempts++
+				e.Attempts++
+				e.LastError = "fail"
+				e.NextAttemptAt = now.Add(time.Minute)
+			})
+			require.NoError(t, err)
+			return n
+		}
+
+		// never attempted event is due whatever the time is
+		require.Equal(t, 1, process(now))
+		require.Equal(t, 0, process(now))
+		require.Equal(t, 1, process(now.Add(time.Minute)))
+		require.Equal(t, 2, attempts)
+	})
+	t.Run("outbox skips backing off and dead events", func(t *testing.T) {
+		r := newRepo(t)
+		backingOff := conformanceOrder(time.Date(2053, 3, 6, 5, 0, 0, 0, time.UTC))
+		dead := conformanceOrder(time.Date(2053, 3, 6, 5, 0, 0, 0, time.UTC))
+		healthy := conformanceOrder(time.Date(2053, 3, 6, 5, 0, 0, 0, time.UTC))
+		require.NoError(t, r.repo.Insert(ctx, backingOff))
+		require.NoError(t, r.repo.Insert(ctx, dead))
+		require.NoError(t, r.repo.Insert(ctx, healthy))
+		require.NoError(t, r.repo.Delete(ctx, time.Now(), dead.ID))
+
+		var seen []string
+		process := func(limit int) int {
+			n, err := r.repo.ProcessOutbox(ctx, time.Now(), limit, func(e *types.OutboxEvent) {
+				seen = append(seen, e.OrderID+" "+e.Type)
+				e.Attempts++
+				now := time.Now()
+				switch e.OrderID {
+				case backingOff.ID:
+					e.LastError = "fail"
+					e.NextAttemptAt = now.Add(time.Hour)
+				case dead.ID:
+					if e.Type == types.OrderEventCreated {
+						e.LastError = "fail"
+						e.DeadAt = &now
+						return
+					}
+					e.PublishedAt = &now
+				default:
+					e.PublishedAt = &now
+				}
+			})
+			require.NoError(t, err)
+			return n
+		}
+
+		require.Equal(t, 3, process(10))
+		require.Equal(t, []string{
+			backingOff.ID + " " + types.OrderEventCreated,
+			dead.ID + " " + types.OrderEventCreated,
+			healthy.ID + " " + types.OrderEventCreated,
+		}, seen)
+
+		// backing off event takes no place in batch and dead event no longer holds its order
+		seen = nil
+		require.Equal(t, 1, process(1))
+		require.Equal(t, []string{dead.ID + " " + types.OrderEventCancelled}, seen)
+
+		seen = nil
+		require.Equal(t, 0, process(10))
+		require.Empty(t, seen)
+	})
+}
+
+func conformancePassenger() types.Passenger {
+	return types.Passenger{
+		FirstName:     gofakeit.FirstName(),
+		LastName:      gofakeit.LastName(),
+		Gender:        gofakeit.Gender(),
+		BirthdayYear:  gofakeit.Number(1950, 2000),
+		BirthdayMonth: gofakeit.Number(1, 12),
+		BirthdayDay:   gofakeit.Number(1, 28),
+	}
+}
+
+func conformanceOrder(launchDate time.Time) types.Order {
+	return types.Order{
+		ID:                uuid.New().String(),
+		LaunchpadID:       uuid.New().String(),
+		DestinationID:     uuid.New().String(),
+		LaunchDate:        launchDate,
+		LaunchLocalDate:   launchDate.Format(types.LocalDateLayout),
+		LaunchpadTimezone: "UTC",
+		CreatedAt:         time.Now().UTC(),
+	}.WithPassengers([]types.Passenger{conformancePassenger()})
+}
+
+func normalizeConformanceOrder(doc types.Order) types.Order {
+	doc.LaunchDate = doc.LaunchDate.UTC().Truncate(time.Millisecond)
+	doc.CreatedAt = doc.CreatedAt.UTC().Truncate(time.Millisecond)
+	return doc
+}
+
+func conformanceOrderIDs(orders []types.Order) []string {
+	ids := make([]string, 0, len(orders))
+	for _, o := range orders {
+		ids = append(ids, o.ID)
+	}
+	return ids
+}
@@ -0,0 +1,152 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/leveldorado/space-trouble/pkg/types"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	launchesTableName     = "launches"
+	launchesSyncTableName = "launches_sync"
+)
+
+/*
+PostgreSQLLaunchesRepo local mirror of launches of external source
+*/
+type PostgreSQLLaunchesRepo struct {
+	conn *sql.DB
+	log  logrus.FieldLogger
+}
+
+func NewPostgreSQLLaunchesRepo(conn *sql.DB, log logrus.FieldLogger) *PostgreSQLLaunchesRepo {
+	return &PostgreSQLLaunchesRepo{conn: conn, log: log}
+}
+
+func (r *PostgreSQLLaunchesRepo) CreateTables(ctx context.Context) error {
+	q := fmt.Sprintf(`
+CREATE TABLE IF NOT EXISTS "%s" (
+    source       text,
+    id           text,
+    launchpad_id text,
+    date_utc     timestamp with time zone,
+    date_local   text,
+    PRIMARY KEY(source, id)
+);
+CREATE INDEX IF NOT EXISTS "%[1]s_launchpad_id_date_utc" ON "%[1]s" (launchpad_id, date_utc);
+CREATE TABLE IF NOT EXISTS "%[2]s" (
+    source    text,
+    from_date timestamp with time zone,
+    to_date   timestamp with time zone,
+    synced_at timestamp with time zone,
+    launches  int,
+    PRIMARY KEY(source)
+);
+`, launchesTableName, launchesSyncTableName)
+	_, err := r.conn.ExecContext(ctx, q)
+	return errors.Wrapf(err, `failed to exec query: q - %s`, q)
+}
+
+/*
+Replace stores launches of source with UTC date in [sync.From, sync.To) instead of previously stored ones
+and records sync in the same transaction
+*/
+func (r *PostgreSQLLaunchesRepo) Replace(ctx context.Context, sync types.LaunchesSync, launches []types.Launch) error {
+	tx, err := r.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, `failed to begin transaction`)
+	}
+	if err = replaceLaunchesWithTransaction(ctx, tx, sync, launches); err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			r.log.WithField("err", rollbackErr.Error()).Error("failed to rollback")
+		}
+		return errors.Wrapf(err, `failed to replace launches: source - %s`, sync.Source)
+	}
+	return errors.Wrapf(tx.Commit(), `failed to commit: source - %s`, sync.Source)
+}
+
+func replaceLaunchesWithTransaction(ctx context.Context, tx *sql.Tx, sync types.LaunchesSync, launches []types.Launch) error {
+	q := `DELETE FROM "` + launchesTableName + `" WHERE source = $1 AND date_utc >= $2 AND date_utc < $3`
+	if _, err := tx.ExecContext(ctx, q, sync.Source, sync.From, sync.To); err != nil {
+		return errors.Wrapf(err, `failed to exec query: q - %s`, q)
+	}
+	q = `INSERT INTO "` + launchesTableName + `" (source, id, launchpad_id, date_utc, date_local) VALUES ($1, $2, $3, $4, $5) ` +
+		`ON CONFLICT (source, id) DO UPDATE SET launchpad_id = EXCLUDED.launchpad_id, date_utc = EXCLUDED.date_utc, ` +
+		`date_local = EXCLUDED.date_local`
+	for _, l := range launches {
+		if _, err := tx.ExecContext(ctx, q, sync.Source, l.ID, l.Launchpad, l.DateUTC, l.DateLocal.Format(time.RFC3339)); err != nil {
+			return errors.Wrapf(err, `failed to exec query: q - %s, id - %s`, q, l.ID)
+		}
+	}
+	q = `INSERT INTO "` + launchesSyncTableName + `" (source, from_date, to_date, synced_at, launches) VALUES ($1, $2, $3, $4, $5) ` +
+		`ON CONFLICT (source) DO UPDATE SET from_date = EXCLUDED.from_date, to_date = EXCLUDED.to_date, ` +
+		`synced_at = EXCLUDED.synced_at, launches = EXCLUDED.launches`
+	_, err := tx.ExecContext(ctx, q, sync.Source, sync.From, sync.To, sync.SyncedAt, len(launches))
+	return errors.Wrapf(err, `failed to exec query: q - %s`, q)
+}
+
+/*
+GetSync returns last sync of source, ErrNotFound when source was never synced
+*/
+func (r *PostgreSQLLaunchesRepo) GetSync(ctx context.Context, source string) (types.LaunchesSync, error) {
+	q := `SELECT source, from_date, to_date, synced_at, launches FROM "` + launchesSyncTableName + `" WHERE source = $1`
+	doc := types.LaunchesSync{}
+	err := r.conn.QueryRowContext(ctx, q, source).Scan(&doc.Source, &doc.From, &doc.To, &doc.SyncedAt, &doc.Launches)
+	if errors.Is(err, sql.ErrNoRows) {
+		return types.LaunchesSync{}, types.ErrNotFound{}
+	}
+	if err != nil {
+		return types.LaunchesSync{}, errors.Wrapf(err, `failed to query row: q - %s, source - %s`, q, source)
+	}
+	doc.From, doc.To, doc.SyncedAt = doc.From.UTC(), doc.To.UTC(), doc.SyncedAt.UTC()
+	return doc, nil
+}
+
+/*
+ListLaunches returns stored launches of source from launchpad with UTC date in [from, to) range sorted by date
+*/
+func (r *PostgreSQLLaunchesRepo) ListLaunches(ctx context.Context, source, launchpad string, from, to time.Time) ([]types.Launch, error) {
+	q := `SELECT id, launchpad_id, date_utc, date_local FROM "` + launchesTableName + `" ` +
+		`WHERE source = $1 AND launchpad_id = $2 AND date_utc >= $3 AND date_utc < $4 ORDER BY date_utc, id`
+	return r.queryLaunches(ctx, q, source, launchpad, from, to)
+}
+
+/*
+ListAllLaunches returns stored launches of source from all launchpads with UTC date in [from, to) range sorted by date
+*/
+func (r *PostgreSQLLaunchesRepo) ListAllLaunches(ctx context.Context, source string, from, to time.Time) ([]types.Launch, error) {
+	q := `SELECT id, launchpad_id, date_utc, date_local FROM "` + launchesTableName + `" ` +
+		`WHERE source = $1 AND date_utc >= $2 AND date_utc < $3 ORDER BY date_utc, id`
+	return r.queryLaunches(ctx, q, source, from, to)
+}
+
+func (r *PostgreSQLLaunchesRepo) queryLaunches(ctx context.Context, q string, args ...interface{}) ([]types.Launch, error) {
+	rows, err := r.conn.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, errors.Wrapf(err, `failed to query rows: q - %s`, q)
+	}
+	var launches []types.Launch
+	for rows.Next() {
+		l := types.Launch{}
+		var dateLocal string
+		if err = rows.Scan(&l.ID, &l.Launchpad, &l.DateUTC, &dateLocal); err != nil {
+			_ = rows.Close()
+			return nil, errors.Wrapf(err, `failed to scan rows: q - %s`, q)
+		}
+		l.DateUTC = l.DateUTC.UTC()
+		if l.DateLocal, err = time.Parse(time.RFC3339, dateLocal); err != nil {
+			l.DateLocal = l.DateUTC
+		}
+		launches = append(launches, l)
+	}
+	if err = rows.Err(); err != nil {
+		_ = rows.Close()
+		return nil, errors.Wrapf(err, `failed to iterate rows: q - %s`, q)
+	}
+	return launches, errors.Wrapf(rows.Close(), `failed to close rows: q - %s`, q)
+}
@@ -0,0 +1,81 @@
+package repositories
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/leveldorado/space-trouble/pkg/tools/logger"
+	"github.com/leveldorado/space-trouble/pkg/types"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+type launchesRepoUnderTest interface {
+	Replace(ctx context.Context, sync types.LaunchesSync, launches []types.Launch) error
+	GetSync(ctx context.Context, source string) (types.LaunchesSync, error)
+	ListLaunches(ctx context.Context, source, launchpad string, from, to time.Time) ([]types.Launch, error)
+	ListAllLaunches(ctx context.Context, source string, from, to time.Time) ([]types.Launch, error)
+}
+
+func prepareLaunchesRepo(t *testing.T) *PostgreSQLLaunchesRepo {
+	url := os.Getenv("POSTGRESQL_URL")
+	conn, err := GetPostgresqlConn(url)
+	require.NoError(t, err)
+	repo := NewPostgreSQLLaunchesRepo(conn, logger.New())
+	require.NoError(t, repo.CreateTables(context.TODO()))
+	return repo
+}
+
+func TestPostgreSQLLaunchesRepo(t *testing.T) {
+	testLaunchesRepo(t, prepareLaunchesRepo(t))
+}
+
+func TestInMemoryLaunchesRepo(t *testing.T) {
+	testLaunchesRepo(t, NewInMemoryLaunchesRepo())
+}
+
+func testLaunchesRepo(t *testing.T, repo launchesRepoUnderTest) {
+	source := uuid.New().String()
+	launchpad := uuid.New().String()
+	_, err := repo.GetSync(context.TODO(), source)
+	require.True(t, errors.As(err, &types.ErrNotFound{}))
+
+	from := time.Date(2053, 3, 1, 0, 0, 0, 0, time.UTC)
+	sync := types.LaunchesSync{Source: source, From: from, To: from.AddDate(0, 1, 0), SyncedAt: time.Now().UTC().Truncate(time.Millisecond)}
+	moved := types.Launch{ID: uuid.New().String(), Launchpad: launchpad, DateUTC: from.AddDate(0, 0, 3)}
+	moved.DateLocal = moved.DateUTC.In(time.FixedZone("", -4*60*60))
+	cancelled := types.Launch{ID: uuid.New().String(), Launchpad: launchpad, DateUTC: from.AddDate(0, 0, 5)}
+	cancelled.DateLocal = cancelled.DateUTC
+	require.NoError(t, repo.Replace(context.TODO(), sync, []types.Launch{moved, cancelled}))
+
+	launches, err := repo.ListLaunches(context.TODO(), source, launchpad, from, from.AddDate(0, 0, 10))
+	require.NoError(t, err)
+	require.Len(t, launches, 2)
+	require.Equal(t, moved.ID, launches[0].ID)
+	require.True(t, moved.DateLocal.Equal(launches[0].DateLocal))
+
+	moved.DateUTC = from.AddDate(0, 0, 7)
+	moved.DateLocal = moved.DateUTC
+	sync.SyncedAt = sync.SyncedAt.Add(time.Minute)
+	sync.Launches = 1
+	require.NoError(t, repo.Replace(context.TODO(), sync, []types.Launch{moved}))
+	launches, err = repo.ListLaunches(context.TODO(), source, launchpad, from, from.AddDate(0, 0, 10))
+	require.NoError(t, err)
+	require.Len(t, launches, 1)
+	require.Equal(t, moved.DateUTC, launches[0].DateUTC)
+
+	got, err := repo.GetSync(context.TODO(), source)
+	require.NoError(t, err)
+	require.Equal(t, sync, got)
+	other := types.Launch{ID: uuid.New().String(), Launchpad: uuid.New().String(), DateUTC: from.AddDate(0, 0, 1)}
+	other.DateLocal = other.DateUTC
+	require.NoError(t, repo.Replace(context.TODO(), sync, []types.Launch{moved, other}))
+	launches, err = repo.ListAllLaunches(context.TODO(), source, from, from.AddDate(0, 0, 10))
+	require.NoError(t, err)
+	require.Len(t, launches, 2)
+	require.Equal(t, other.ID, launches[0].ID)
+	require.Equal(t, moved.ID, launches[1].ID)
+}
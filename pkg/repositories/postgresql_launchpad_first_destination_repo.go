@@ -0,0 +1,93 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/leveldorado/space-trouble/pkg/types"
+	"github.com/pkg/errors"
+)
+
+const (
+	launchpadFirstDestinationTableName = "launchpad_first_destination"
+)
+
+/*
+PostgreSQLLaunchpadFirstDestinationRepo
+
+	stores rotation anchors so they survive restarts
+	and can be rebuilt from admin tools while server is running
+*/
+type PostgreSQLLaunchpadFirstDestinationRepo struct {
+	conn *sql.DB
+}
+
+func NewPostgreSQLLaunchpadFirstDestinationRepo(conn *sql.DB) *PostgreSQLLaunchpadFirstDestinationRepo {
+	return &PostgreSQLLaunchpadFirstDestinationRepo{conn: conn}
+}
+
+func (r *PostgreSQLLaunchpadFirstDestinationRepo) CreateTables(ctx context.Context) error {
+	q := fmt.Sprintf(`
+CREATE TABLE IF NOT EXISTS "%s" (
+    launchpad_id   text,
+    destination_id text,
+    local_year     int,
+    local_month    int,
+    local_day      int,
+    PRIMARY KEY(launchpad_id)
+);
+`, launchpadFirstDestinationTableName)
+	_, err := r.conn.ExecContext(ctx, q)
+	return errors.Wrapf(err, `failed to exec query: q - %s`, q)
+}
+
+func (r *PostgreSQLLaunchpadFirstDestinationRepo) Set(ctx context.Context, doc types.LaunchpadFirstDestination) error {
+	q := `INSERT INTO "` + launchpadFirstDestinationTableName + `" ` +
+		`(launchpad_id, destination_id, local_year, local_month, local_day) VALUES ($1, $2, $3, $4, $5) ` +
+		`ON CONFLICT (launchpad_id) DO UPDATE SET destination_id = $2, local_year = $3, local_month = $4, local_day = $5`
+	_, err := r.conn.ExecContext(ctx, q, doc.LaunchpadID, doc.DestinationID, doc.LocalYear, int(doc.LocalMonth), doc.LocalDay)
+	return errors.Wrapf(err, `failed to exec query: q - %s, doc - %+v`, q, doc)
+}
+
+func (r *PostgreSQLLaunchpadFirstDestinationRepo) Get(ctx context.Context, launchpad string) (types.LaunchpadFirstDestination, error) {
+	q := `SELECT launchpad_id, destination_id, local_year, local_month, local_day FROM "` +
+		launchpadFirstDestinationTableName + `" WHERE launchpad_id = $1`
+	doc, err := scanLaunchpadFirstDestination(r.conn.QueryRowContext(ctx, q, launchpad))
+	if errors.Is(err, sql.ErrNoRows) {
+		return types.LaunchpadFirstDestination{}, types.ErrNotFound{}
+	}
+	return doc, errors.Wrapf(err, `failed to query row: launchpad - %s, q - %s`, launchpad, q)
+}
+
+func (r *PostgreSQLLaunchpadFirstDestinationRepo) List(ctx context.Context) ([]types.LaunchpadFirstDestination, error) {
+	q := `SELECT launchpad_id, destination_id, local_year, local_month, local_day FROM "` +
+		launchpadFirstDestinationTableName + `" ORDER BY launchpad_id`
+	rows, err := r.conn.QueryContext(ctx, q)
+	if err != nil {
+		return nil, errors.Wrapf(err, `failed to query rows: q - %s`, q)
+	}
+	var docs []types.LaunchpadFirstDestination
+	for rows.Next() {
+		doc, err := scanLaunchpadFirstDestination(rows)
+		if err != nil {
+			_ = rows.Close()
+			return nil, errors.Wrapf(err, `failed to scan rows: q - %s`, q)
+		}
+		docs = append(docs, doc)
+	}
+	return docs, errors.Wrapf(rows.Close(), `failed to close rows: q - %s`, q)
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanLaunchpadFirstDestination(row rowScanner) (types.LaunchpadFirstDestination, error) {
+	doc := types.LaunchpadFirstDestination{}
+	var month int
+	err := row.Scan(&doc.LaunchpadID, &doc.DestinationID, &doc.LocalYear, &month, &doc.LocalDay)
+	doc.LocalMonth = time.Month(month)
+	return doc, err
+}
@@ -0,0 +1,66 @@
+package repositories
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/leveldorado/space-trouble/pkg/types"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func prepareLaunchpadFirstDestinationRepo(t *testing.T) *PostgreSQLLaunchpadFirstDestinationRepo {
+	url := os.Getenv("POSTGRESQL_URL")
+	conn, err := GetPostgresqlConn(url)
+	require.NoError(t, err)
+	repo := NewPostgreSQLLaunchpadFirstDestinationRepo(conn)
+	require.NoError(t, repo.CreateTables(context.TODO()))
+	return repo
+}
+
+type launchpadFirstDestinationRepoUnderTest interface {
+	Set(ctx context.Context, doc types.LaunchpadFirstDestination) error
+	Get(ctx context.Context, launchpad string) (types.LaunchpadFirstDestination, error)
+	List(ctx context.Context) ([]types.LaunchpadFirstDestination, error)
+}
+
+func TestPostgreSQLLaunchpadFirstDestinationRepo_SetGet(t *testing.T) {
+	testLaunchpadFirstDestinationRepo(t, prepareLaunchpadFirstDestinationRepo(t))
+}
+
+func TestSQLiteLaunchpadFirstDestinationRepo_SetGet(t *testing.T) {
+	repo := NewSQLiteLaunchpadFirstDestinationRepo(prepareSQLiteConn(t))
+	require.NoError(t, repo.CreateTables(context.TODO()))
+	testLaunchpadFirstDestinationRepo(t, repo)
+}
+
+func testLaunchpadFirstDestinationRepo(t *testing.T, repo launchpadFirstDestinationRepoUnderTest) {
+	doc := types.LaunchpadFirstDestination{
+		LaunchpadID:   uuid.New().String(),
+		DestinationID: "1",
+		LocalYear:     2022,
+		LocalMonth:    time.August,
+		LocalDay:      21,
+	}
+	_, err := repo.Get(context.TODO(), doc.LaunchpadID)
+	require.True(t, errors.As(err, &types.ErrNotFound{}))
+
+	require.NoError(t, repo.Set(context.TODO(), doc))
+	fromDB, err := repo.Get(context.TODO(), doc.LaunchpadID)
+	require.NoError(t, err)
+	require.Equal(t, doc, fromDB)
+
+	doc.DestinationID = "2"
+	doc.LocalMonth = time.September
+	require.NoError(t, repo.Set(context.TODO(), doc))
+	fromDB, err = repo.Get(context.TODO(), doc.LaunchpadID)
+	require.NoError(t, err)
+	require.Equal(t, doc, fromDB)
+
+	list, err := repo.List(context.TODO())
+	require.NoError(t, err)
+	require.Contains(t, list, doc)
+}
@@ -0,0 +1,113 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/leveldorado/space-trouble/pkg/types"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	launchpadsSnapshotTableName = "launchpads_snapshot"
+)
+
+/*
+PostgreSQLLaunchpadsSnapshotRepo keeps last successfully listed launchpads, so app can start when SpaceX API is down
+*/
+type PostgreSQLLaunchpadsSnapshotRepo struct {
+	conn *sql.DB
+	log  logrus.FieldLogger
+}
+
+func NewPostgreSQLLaunchpadsSnapshotRepo(conn *sql.DB, log logrus.FieldLogger) *PostgreSQLLaunchpadsSnapshotRepo {
+	return &PostgreSQLLaunchpadsSnapshotRepo{conn: conn, log: log}
+}
+
+func (r *PostgreSQLLaunchpadsSnapshotRepo) CreateTables(ctx context.Context) error {
+	q := fmt.Sprintf(`
+CREATE TABLE IF NOT EXISTS "%s" (
+    id        text,
+    full_name text,
+    timezone  text,
+    status    text,
+    saved_at  timestamp with time zone,
+    PRIMARY KEY(id)
+);
+`, launchpadsSnapshotTableName)
+	_, err := r.conn.ExecContext(ctx, q)
+	return errors.Wrapf(err, `failed to exec query: q - %s`, q)
+}
+
+/*
+Save replaces snapshot with launchpads
+*/
+func (r *PostgreSQLLaunchpadsSnapshotRepo) Save(ctx context.Context, launchpads []types.Launchpad, savedAt time.Time) error {
+	tx, err := r.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, `failed to begin transaction`)
+	}
+	if err = saveLaunchpadsSnapshotWithTransaction(ctx, tx, launchpads, savedAt); err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			r.log.WithField("err", rollbackErr.Error()).Error("failed to rollback")
+		}
+		return errors.Wrap(err, `failed to save launchpads snapshot`)
+	}
+	return errors.Wrap(tx.Commit(), `failed to commit`)
+}
+
+func saveLaunchpadsSnapshotWithTransaction(ctx context.Context, tx *sql.Tx, launchpads []types.Launchpad, savedAt time.Time) error {
+	q := `DELETE FROM "` + launchpadsSnapshotTableName + `"`
+	if _, err := tx.ExecContext(ctx, q); err != nil {
+		return errors.Wrapf(err, `failed to exec query: q - %s`, q)
+	}
+	q = `INSERT INTO "` + launchpadsSnapshotTableName + `" (id, full_name, timezone, status, saved_at) VALUES ($1, $2, $3, $4, $5)`
+	for _, l := range launchpads {
+		var timezone string
+		if l.Location != nil {
+			timezone = l.Location.String()
+		}
+		if _, err := tx.ExecContext(ctx, q, l.ID, l.FullName, timezone, l.Status, savedAt); err != nil {
+			return errors.Wrapf(err, `failed to exec query: q - %s, id - %s`, q, l.ID)
+		}
+	}
+	return nil
+}
+
+/*
+Load returns launchpads of snapshot sorted by full name and when they were saved, ErrNotFound when snapshot is empty
+*/
+func (r *PostgreSQLLaunchpadsSnapshotRepo) Load(ctx context.Context) ([]types.Launchpad, time.Time, error) {
+	q := `SELECT id, full_name, timezone, status, saved_at FROM "` + launchpadsSnapshotTableName + `" ORDER BY full_name, id`
+	rows, err := r.conn.QueryContext(ctx, q)
+	if err != nil {
+		return nil, time.Time{}, errors.Wrapf(err, `failed to query rows: q - %s`, q)
+	}
+	var (
+		launchpads []types.Launchpad
+		savedAt    time.Time
+	)
+	for rows.Next() {
+		l := types.Launchpad{}
+		var timezone string
+		if err = rows.Scan(&l.ID, &l.FullName, &timezone, &l.Status, &savedAt); err != nil {
+			_ = rows.Close()
+			return nil, time.Time{}, errors.Wrapf(err, `failed to scan rows: q - %s`, q)
+		}
+		if l.Location, err = time.LoadLocation(timezone); err != nil {
+			_ = rows.Close()
+			return nil, time.Time{}, errors.Wrapf(err, `failed to load location: timezone - %s`, timezone)
+		}
+		launchpads = append(launchpads, l)
+	}
+	if err = rows.Close(); err != nil {
+		return nil, time.Time{}, errors.Wrapf(err, `failed to close rows: q - %s`, q)
+	}
+	if len(launchpads) == 0 {
+		return nil, time.Time{}, types.ErrNotFound{}
+	}
+	return launchpads, savedAt.UTC(), nil
+}
@@ -0,0 +1,57 @@
+package repositories
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/leveldorado/space-trouble/pkg/tools/logger"
+	"github.com/leveldorado/space-trouble/pkg/types"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+type launchpadsSnapshotRepoUnderTest interface {
+	Save(ctx context.Context, launchpads []types.Launchpad, savedAt time.Time) error
+	Load(ctx context.Context) ([]types.Launchpad, time.Time, error)
+}
+
+func TestPostgreSQLLaunchpadsSnapshotRepo(t *testing.T) {
+	conn, err := GetPostgresqlConn(os.Getenv("POSTGRESQL_URL"))
+	require.NoError(t, err)
+	repo := NewPostgreSQLLaunchpadsSnapshotRepo(conn, logger.New())
+	require.NoError(t, repo.CreateTables(context.TODO()))
+	testLaunchpadsSnapshotRepo(t, repo)
+}
+
+func TestInMemoryLaunchpadsSnapshotRepo(t *testing.T) {
+	repo := NewInMemoryLaunchpadsSnapshotRepo()
+	_, _, err := repo.Load(context.TODO())
+	require.True(t, errors.As(err, &types.ErrNotFound{}))
+	testLaunchpadsSnapshotRepo(t, repo)
+}
+
+func testLaunchpadsSnapshotRepo(t *testing.T, repo launchpadsSnapshotRepoUnderTest) {
+	ny, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	la, err := time.LoadLocation("America/Los_Angeles")
+	require.NoError(t, err)
+	first := types.Launchpad{ID: uuid.New().String(), FullName: "A pad", Location: ny, Status: types.LaunchpadStatusActive}
+	second := types.Launchpad{ID: uuid.New().String(), FullName: "B pad", Location: la, Status: "retired"}
+	savedAt := time.Now().UTC().Truncate(time.Millisecond)
+	require.NoError(t, repo.Save(context.TODO(), []types.Launchpad{second, first}, savedAt))
+
+	launchpads, loadedAt, err := repo.Load(context.TODO())
+	require.NoError(t, err)
+	require.Equal(t, []types.Launchpad{first, second}, launchpads)
+	require.Equal(t, savedAt, loadedAt)
+
+	// next save replaces snapshot
+	require.NoError(t, repo.Save(context.TODO(), []types.Launchpad{first}, savedAt.Add(time.Minute)))
+	launchpads, loadedAt, err = repo.Load(context.TODO())
+	require.NoError(t, err)
+	require.Equal(t, []types.Launchpad{first}, launchpads)
+	require.Equal(t, savedAt.Add(time.Minute), loadedAt)
+}
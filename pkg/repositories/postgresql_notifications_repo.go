@@ -0,0 +1,171 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/leveldorado/space-trouble/pkg/types"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const notificationTableName = "notification"
+
+type PostgreSQLNotificationsRepo struct {
+	conn *sql.DB
+	log  logrus.FieldLogger
+}
+
+func NewPostgreSQLNotificationsRepo(conn *sql.DB, log logrus.FieldLogger) *PostgreSQLNotificationsRepo {
+	return &PostgreSQLNotificationsRepo{conn: conn, log: log}
+}
+
+func (r *PostgreSQLNotificationsRepo) CreateTables(ctx context.Context) error {
+	q := fmt.Sprintf(`
+CREATE TABLE IF NOT EXISTS "%s" (
+    id              uuid,
+    order_id        uuid,
+    event_id        uuid,
+    kind            text,
+    recipient       text,
+    subject         text,
+    body            text,
+    status          text,
+    attempts        int,
+    last_error      text,
+    next_attempt_at timestamp with time zone,
+    created_at      timestamp with time zone,
+    sent_at         timestamp with time zone,
+    PRIMARY KEY(id)
+);
+CREATE INDEX IF NOT EXISTS "%[1]s_status_next_attempt_at" ON "%[1]s" (status, next_attempt_at);
+CREATE INDEX IF NOT EXISTS "%[1]s_order_id" ON "%[1]s" (order_id);
+CREATE UNIQUE INDEX IF NOT EXISTS "%[1]s_event_id_kind" ON "%[1]s" (event_id, kind);
+`, notificationTableName)
+	_, err := r.conn.ExecContext(ctx, q)
+	return errors.Wrapf(err, `failed to exec query: q - %s`, q)
+}
+
+/*
+Insert stores notifications in single transaction, notification of the same event and kind is stored once
+*/
+func (r *PostgreSQLNotificationsRepo) Insert(ctx context.Context, docs []types.Notification) error {
+	tx, err := r.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, `failed to begin transaction`)
+	}
+	q := `INSERT INTO "` + notificationTableName + `" (` + notificationColumns + `) ` +
+		`VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13) ON CONFLICT (event_id, kind) DO NOTHING`
+	for _, doc := range docs {
+		if _, err = tx.ExecContext(ctx, q,
+			doc.ID,
+			doc.OrderID,
+			doc.EventID,
+			doc.Kind,
+			doc.Recipient,
+			doc.Subject,
+			doc.Body,
+			doc.Status,
+			doc.Attempts,
+			doc.LastError,
+			doc.NextAttemptAt,
+			doc.CreatedAt,
+			doc.SentAt,
+		); err != nil {
+			if rollbackErr := tx.Rollback(); rollbackErr != nil {
+				r.log.WithField("err", rollbackErr.Error()).Error("failed to rollback")
+			}
+			return errors.Wrapf(err, `failed to exec query: q - %s, id - %s`, q, doc.ID)
+		}
+	}
+	return errors.Wrapf(tx.Commit(), `failed to commit: count - %d`, len(docs))
+}
+
+/*
+CancelPending cancels not sent notifications of order with provided kind
+*/
+func (r *PostgreSQLNotificationsRepo) CancelPending(ctx context.Context, orderID, kind string) error {
+	q := `UPDATE "` + notificationTableName + `" SET status = $1 WHERE order_id = $2 AND kind = $3 AND status = $4`
+	_, err := r.conn.ExecContext(ctx, q, types.NotificationStatusCancelled, orderID, kind, types.NotificationStatusPending)
+	return errors.Wrapf(err, `failed to exec query: q - %s, order - %s`, q, orderID)
+}
+
+const (
+	notificationColumns = `id, order_id, event_id, kind, recipient, subject, body, status, attempts, last_error, ` +
+		`next_attempt_at, created_at, sent_at`
+)
+
+func scanNotification(row rowScanner) (types.Notification, error) {
+	doc := types.Notification{}
+	var sentAt sql.NullTime
+	err := row.Scan(
+		&doc.ID,
+		&doc.OrderID,
+		&doc.EventID,
+		&doc.Kind,
+		&doc.Recipient,
+		&doc.Subject,
+		&doc.Body,
+		&doc.Status,
+		&doc.Attempts,
+		&doc.LastError,
+		&doc.NextAttemptAt,
+		&doc.CreatedAt,
+		&sentAt,
+	)
+	if sentAt.Valid {
+		doc.SentAt = &sentAt.Time
+	}
+	return doc, err
+}
+
+func queryNotifications(ctx context.Context, conn queryer, q string, args ...interface{}) ([]types.Notification, error) {
+	rows, err := conn.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, errors.Wrapf(err, `failed to query rows: q - %s`, q)
+	}
+	var docs []types.Notification
+	for rows.Next() {
+		doc, err := scanNotification(rows)
+		if err != nil {
+			_ = rows.Close()
+			return nil, errors.Wrapf(err, `failed to scan rows: q - %s`, q)
+		}
+		docs = append(docs, doc)
+	}
+	if err = rows.Err(); err != nil {
+		_ = rows.Close()
+		return nil, errors.Wrapf(err, `failed to iterate rows: q - %s`, q)
+	}
+	return docs, errors.Wrapf(rows.Close(), `failed to close rows: q - %s`, q)
+}
+
+/*
+ClaimDue returns pending notifications which send time passed.
+
+	claimed notifications get next attempt moved by lease so other replicas do not send them at the same time
+*/
+func (r *PostgreSQLNotificationsRepo) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]types.Notification, error) {
+	q := `UPDATE "` + notificationTableName + `" SET next_attempt_at = $1 WHERE id IN (` +
+		`SELECT id FROM "` + notificationTableName + `" WHERE status = $2 AND next_attempt_at <= $3 ` +
+		`ORDER BY next_attempt_at LIMIT $4 FOR UPDATE SKIP LOCKED) RETURNING ` + notificationColumns
+	return queryNotifications(ctx, r.conn, q, now.Add(lease), types.NotificationStatusPending, now, limit)
+}
+
+/*
+Update saves delivery state, notification cancelled meanwhile stays cancelled
+*/
+func (r *PostgreSQLNotificationsRepo) Update(ctx context.Context, doc types.Notification) error {
+	q := `UPDATE "` + notificationTableName + `" SET status = $2, attempts = $3, last_error = $4, ` +
+		`next_attempt_at = $5, sent_at = $6 WHERE id = $1 AND status <> $7`
+	_, err := r.conn.ExecContext(ctx, q, doc.ID, doc.Status, doc.Attempts, doc.LastError, doc.NextAttemptAt, doc.SentAt,
+		types.NotificationStatusCancelled)
+	return errors.Wrapf(err, `failed to exec query: q - %s, id - %s`, q, doc.ID)
+}
+
+func (r *PostgreSQLNotificationsRepo) ListByOrder(ctx context.Context, orderID string) ([]types.Notification, error) {
+	q := `SELECT ` + notificationColumns + ` FROM "` + notificationTableName + `" WHERE order_id = $1 ORDER BY created_at, next_attempt_at`
+	return queryNotifications(ctx, r.conn, q, orderID)
+}
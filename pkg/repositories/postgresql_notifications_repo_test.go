@@ -0,0 +1,104 @@
+package repositories
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/leveldorado/space-trouble/pkg/tools/logger"
+	"github.com/leveldorado/space-trouble/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+type notificationsRepoUnderTest interface {
+	Insert(ctx context.Context, docs []types.Notification) error
+	CancelPending(ctx context.Context, orderID, kind string) error
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]types.Notification, error)
+	Update(ctx context.Context, doc types.Notification) error
+	ListByOrder(ctx context.Context, orderID string) ([]types.Notification, error)
+}
+
+func prepareNotificationsRepo(t *testing.T) *PostgreSQLNotificationsRepo {
+	url := os.Getenv("POSTGRESQL_URL")
+	conn, err := GetPostgresqlConn(url)
+	require.NoError(t, err)
+	repo := NewPostgreSQLNotificationsRepo(conn, logger.New())
+	require.NoError(t, repo.CreateTables(context.TODO()))
+	return repo
+}
+
+func TestPostgreSQLNotificationsRepo(t *testing.T) {
+	testNotificationsRepo(t, prepareNotificationsRepo(t))
+}
+
+func TestInMemoryNotificationsRepo(t *testing.T) {
+	testNotificationsRepo(t, NewInMemoryNotificationsRepo())
+}
+
+func TestSQLiteNotificationsRepo(t *testing.T) {
+	repo := NewSQLiteNotificationsRepo(prepareSQLiteConn(t), logger.New())
+	require.NoError(t, repo.CreateTables(context.TODO()))
+	testNotificationsRepo(t, repo)
+}
+
+func testNotificationsRepo(t *testing.T, repo notificationsRepoUnderTest) {
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	orderID := uuid.New().String()
+	eventID := uuid.New().String()
+	confirmed := types.Notification{
+		ID:            uuid.New().String(),
+		OrderID:       orderID,
+		EventID:       eventID,
+		Kind:          types.NotificationKindBookingConfirmed,
+		Recipient:     "vasyl@example.com",
+		Subject:       "subject",
+		Body:          "body",
+		Status:        types.NotificationStatusPending,
+		NextAttemptAt: now.Add(-time.Second),
+		CreatedAt:     now,
+	}
+	reminder := confirmed
+	reminder.ID = uuid.New().String()
+	reminder.Kind = types.NotificationKindLaunchReminder
+	reminder.NextAttemptAt = now.Add(time.Hour)
+	require.NoError(t, repo.Insert(context.TODO(), []types.Notification{confirmed, reminder}))
+
+	duplicate := confirmed
+	duplicate.ID = uuid.New().String()
+	require.NoError(t, repo.Insert(context.TODO(), []types.Notification{duplicate}))
+
+	docs, err := repo.ListByOrder(context.TODO(), orderID)
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+
+	claimed, err := repo.ClaimDue(context.TODO(), now, time.Minute, 1000)
+	require.NoError(t, err)
+	var found bool
+	for _, doc := range claimed {
+		require.NotEqual(t, reminder.ID, doc.ID)
+		if doc.ID == confirmed.ID {
+			found = true
+		}
+	}
+	require.True(t, found)
+
+	sentAt := now
+	confirmed.Status = types.NotificationStatusSent
+	confirmed.Attempts = 1
+	confirmed.SentAt = &sentAt
+	require.NoError(t, repo.Update(context.TODO(), confirmed))
+	require.NoError(t, repo.CancelPending(context.TODO(), orderID, types.NotificationKindLaunchReminder))
+
+	docs, err = repo.ListByOrder(context.TODO(), orderID)
+	require.NoError(t, err)
+	statuses := map[string]string{}
+	for _, doc := range docs {
+		statuses[doc.Kind] = doc.Status
+	}
+	require.Equal(t, map[string]string{
+		types.NotificationKindBookingConfirmed: types.NotificationStatusSent,
+		types.NotificationKindLaunchReminder:   types.NotificationStatusCancelled,
+	}, statuses)
+}
@@ -0,0 +1,43 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/leveldorado/space-trouble/pkg/types"
+	"github.com/pkg/errors"
+)
+
+// postgres rejects notification payload of 8000 bytes and longer
+const maxNotifyPayloadSize = 7999
+
+/*
+PostgreSQLNotifySink publishes order events with NOTIFY to channel.
+
+	order snapshot is dropped from payload when event does not fit notification size limit
+*/
+type PostgreSQLNotifySink struct {
+	conn    *sql.DB
+	channel string
+}
+
+func NewPostgreSQLNotifySink(conn *sql.DB, channel string) *PostgreSQLNotifySink {
+	return &PostgreSQLNotifySink{conn: conn, channel: channel}
+}
+
+func (s *PostgreSQLNotifySink) Publish(ctx context.Context, e types.OrderEvent) error {
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return errors.Wrapf(err, `failed to marshal event: id - %s`, e.ID)
+	}
+	if len(payload) > maxNotifyPayloadSize {
+		e.Order = nil
+		if payload, err = json.Marshal(e); err != nil {
+			return errors.Wrapf(err, `failed to marshal event: id - %s`, e.ID)
+		}
+	}
+	q := `SELECT pg_notify($1, $2)`
+	_, err = s.conn.ExecContext(ctx, q, s.channel, string(payload))
+	return errors.Wrapf(err, `failed to exec query: q - %s, channel - %s`, q, s.channel)
+}
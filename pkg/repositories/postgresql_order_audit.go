@@ -0,0 +1,130 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/go-chi/chi/middleware"
+	"github.com/google/uuid"
+	"github.com/leveldorado/space-trouble/pkg/tools/actor"
+	"github.com/leveldorado/space-trouble/pkg/types"
+	"github.com/pkg/errors"
+)
+
+const orderAuditTableName = "order_audit"
+
+// trigger rejects any change of stored entries so audit log can only grow
+var orderAuditTableCreateQuery = fmt.Sprintf(`
+CREATE TABLE IF NOT EXISTS "%s" (
+    seq        bigserial,
+    id         uuid,
+    order_id   uuid,
+    action     text,
+    actor      text,
+    request_id text,
+    before     jsonb,
+    after      jsonb,
+    created_at timestamp with time zone,
+    PRIMARY KEY(seq)
+);
+CREATE INDEX IF NOT EXISTS "%[1]s_order_id" ON "%[1]s" (order_id, seq);
+CREATE OR REPLACE FUNCTION "%[1]s_immutable"() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION '%[1]s is append only';
+END;
+$$ LANGUAGE plpgsql;
+DROP TRIGGER IF EXISTS "%[1]s_immutable" ON "%[1]s";
+CREATE TRIGGER "%[1]s_immutable" BEFORE UPDATE OR DELETE ON "%[1]s"
+    FOR EACH ROW EXECUTE PROCEDURE "%[1]s_immutable"();
+`, orderAuditTableName)
+
+/*
+insertOrderAudit stores audit entry created at now with actor and request id from context,
+should be called in the same transaction as order change
+*/
+func insertOrderAudit(ctx context.Context, tx sqlExecutor, now time.Time, action string, before, after *types.Order) error {
+	orderID := ""
+	var beforeJSON, afterJSON []byte
+	var err error
+	if before != nil {
+		orderID = before.ID
+		if beforeJSON, err = json.Marshal(before); err != nil {
+			return errors.Wrapf(err, `failed to marshal order: id - %s`, orderID)
+		}
+	}
+	if after != nil {
+		orderID = after.ID
+		if afterJSON, err = json.Marshal(after); err != nil {
+			return errors.Wrapf(err, `failed to marshal order: id - %s`, orderID)
+		}
+	}
+	q := `INSERT INTO "` + orderAuditTableName + `" (id, order_id, action, actor, request_id, before, after, created_at) ` +
+		`VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+	_, err = tx.ExecContext(ctx, q,
+		uuid.New().String(),
+		orderID,
+		action,
+		actor.FromContext(ctx),
+		middleware.GetReqID(ctx),
+		nullableJSON(beforeJSON),
+		nullableJSON(afterJSON),
+		now.UTC(),
+	)
+	return errors.Wrapf(err, `failed to exec query: q - %s, order - %s`, q, orderID)
+}
+
+func nullableJSON(b []byte) interface{} {
+	if b == nil {
+		return nil
+	}
+	return string(b)
+}
+
+/*
+History returns audit entries of order, oldest first
+*/
+func (r *PostgreSQLOrdersRepo) History(ctx context.Context, orderID string) ([]types.OrderAuditEntry, error) {
+	q := `SELECT id, order_id, action, actor, request_id, before, after, created_at FROM "` + orderAuditTableName + `" ` +
+		`WHERE order_id = $1 ORDER BY seq`
+	rows, err := r.conn.QueryContext(ctx, q, orderID)
+	if err != nil {
+		return nil, errors.Wrapf(err, `failed to query rows: q - %s, order - %s`, q, orderID)
+	}
+	var entries []types.OrderAuditEntry
+	for rows.Next() {
+		e, err := scanOrderAuditEntry(rows)
+		if err != nil {
+			_ = rows.Close()
+			return nil, errors.Wrapf(err, `failed to scan rows: q - %s`, q)
+		}
+		entries = append(entries, e)
+	}
+	if err = rows.Err(); err != nil {
+		_ = rows.Close()
+		return nil, errors.Wrapf(err, `failed to iterate rows: q - %s`, q)
+	}
+	return entries, errors.Wrapf(rows.Close(), `failed to close rows: q - %s`, q)
+}
+
+func scanOrderAuditEntry(row rowScanner) (types.OrderAuditEntry, error) {
+	e := types.OrderAuditEntry{}
+	var before, after []byte
+	if err := row.Scan(&e.ID, &e.OrderID, &e.Action, &e.Actor, &e.RequestID, &before, &after, &e.CreatedAt); err != nil {
+		return types.OrderAuditEntry{}, err
+	}
+	if before != nil {
+		e.Before = &types.Order{}
+		if err := json.Unmarshal(before, e.Before); err != nil {
+			return types.OrderAuditEntry{}, errors.Wrapf(err, `failed to unmarshal before: id - %s`, e.ID)
+		}
+	}
+	if after != nil {
+		e.After = &types.Order{}
+		if err := json.Unmarshal(after, e.After); err != nil {
+			return types.OrderAuditEntry{}, errors.Wrapf(err, `failed to unmarshal after: id - %s`, e.ID)
+		}
+	}
+	return e, nil
+}
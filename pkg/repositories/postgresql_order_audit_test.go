@@ -0,0 +1,39 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/leveldorado/space-trouble/pkg/tools/actor"
+	"github.com/leveldorado/space-trouble/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostgreSQLOrdersRepo_History(t *testing.T) {
+	repo := prepareOrdersRepo(t)
+	doc := types.Order{
+		ID:            uuid.New().String(),
+		DestinationID: uuid.New().String(),
+	}
+	require.NoError(t, repo.Insert(actor.NewContext(context.TODO(), "creator"), doc))
+	require.NoError(t, repo.Delete(actor.NewContext(context.TODO(), "support"), time.Now(), doc.ID))
+
+	entries, err := repo.History(context.TODO(), doc.ID)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+
+	require.Equal(t, types.OrderAuditActionCreate, entries[0].Action)
+	require.Equal(t, "creator", entries[0].Actor)
+	require.Nil(t, entries[0].Before)
+	require.Equal(t, doc.ID, entries[0].After.ID)
+
+	require.Equal(t, types.OrderAuditActionDelete, entries[1].Action)
+	require.Equal(t, "support", entries[1].Actor)
+	require.Equal(t, doc.ID, entries[1].Before.ID)
+	require.Nil(t, entries[1].After)
+
+	_, err = repo.conn.ExecContext(context.TODO(), `DELETE FROM "`+orderAuditTableName+`" WHERE order_id = $1`, doc.ID)
+	require.Error(t, err)
+}
@@ -0,0 +1,153 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/leveldorado/space-trouble/pkg/types"
+	"github.com/pkg/errors"
+)
+
+const (
+	orderEventsTableName = "order_events"
+	// arbitrary advisory lock key, only one relay processes outbox at a time to keep events of order in order
+	orderEventsLockKey = 4_207_311_001
+)
+
+var orderEventsTableCreateQuery = fmt.Sprintf(`
+CREATE TABLE IF NOT EXISTS "%s" (
+    seq             bigserial,
+    id              uuid,
+    order_id        uuid,
+    event_type      text,
+    payload         jsonb,
+    occurred_at     timestamp with time zone,
+    attempts        int,
+    last_error      text,
+    next_attempt_at timestamp with time zone,
+    published_at    timestamp with time zone,
+    dead_at         timestamp with time zone,
+    PRIMARY KEY(seq)
+);
+ALTER TABLE "%[1]s" ADD COLUMN IF NOT EXISTS dead_at timestamp with time zone;
+CREATE INDEX IF NOT EXISTS "%[1]s_unpublished" ON "%[1]s" (seq) WHERE published_at IS NULL;
+CREATE INDEX IF NOT EXISTS "%[1]s_pending" ON "%[1]s" (order_id, seq) WHERE published_at IS NULL AND dead_at IS NULL;
+`, orderEventsTableName)
+
+/*
+insertOrderEvent stores event occurred at now in outbox, should be called in the same transaction as order change
+*/
+func insertOrderEvent(ctx context.Context, tx sqlExecutor, now time.Time, eventType string, doc types.Order) error {
+	now = now.UTC()
+	e := types.OrderEvent{
+		ID:         uuid.New().String(),
+		Type:       eventType,
+		OrderID:    doc.ID,
+		Order:      &doc,
+		OccurredAt: now,
+	}
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return errors.Wrapf(err, `failed to marshal event: order - %s`, doc.ID)
+	}
+	q := `INSERT INTO "` + orderEventsTableName + `" ` +
+		`(id, order_id, event_type, payload, occurred_at, attempts, last_error, next_attempt_at) ` +
+		`VALUES ($1, $2, $3, $4, $5, 0, '', $5)`
+	_, err = tx.ExecContext(ctx, q, e.ID, e.OrderID, e.Type, payload, e.OccurredAt)
+	return errors.Wrapf(err, `failed to exec query: q - %s, order - %s`, q, doc.ID)
+}
+
+/*
+ProcessOutbox passes due events to fn and saves state fn set on them.
+
+	only the earliest pending event of every order is passed, so event which fn left unpublished
+	holds following events of the same order and events of every order are relayed in order.
+	dead events are skipped and stop holding the order. event is due when it was never attempted or its next attempt
+	is not after now, so retries follow the clock of caller. returns number of events passed to fn
+*/
+func (r *PostgreSQLOrdersRepo) ProcessOutbox(ctx context.Context, now time.Time, limit int, fn func(e *types.OutboxEvent)) (int, error) {
+	tx, err := r.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, errors.Wrap(err, `failed to begin transaction`)
+	}
+	n, err := processOutboxWithTransaction(ctx, tx, now, limit, fn)
+	if err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			r.log.WithField("err", rollbackErr.Error()).Error("failed to rollback")
+		}
+		return 0, err
+	}
+	return n, errors.Wrap(tx.Commit(), `failed to commit`)
+}
+
+func processOutboxWithTransaction(ctx context.Context, tx *sql.Tx, now time.Time, limit int, fn func(e *types.OutboxEvent)) (int, error) {
+	var locked bool
+	lockQuery := `SELECT pg_try_advisory_xact_lock($1)`
+	if err := tx.QueryRowContext(ctx, lockQuery, orderEventsLockKey).Scan(&locked); err != nil {
+		return 0, errors.Wrapf(err, `failed to query row: q - %s`, lockQuery)
+	}
+	if !locked {
+		return 0, nil
+	}
+	events, err := queryOutboxEvents(ctx, tx, limit, now.UTC())
+	if err != nil {
+		return 0, err
+	}
+	return relayOutboxEvents(ctx, tx, events, fn)
+}
+
+/*
+relayOutboxEvents passes events to fn and stores state fn set on them
+*/
+func relayOutboxEvents(ctx context.Context, conn sqlExecutor, events []types.OutboxEvent, fn func(e *types.OutboxEvent)) (int, error) {
+	q := `UPDATE "` + orderEventsTableName + `" SET attempts = $2, last_error = $3, next_attempt_at = $4, published_at = $5, dead_at = $6 ` +
+		`WHERE seq = $1`
+	for i := range events {
+		e := &events[i]
+		fn(e)
+		if _, err := conn.ExecContext(ctx, q, e.Seq, e.Attempts, e.LastError, e.NextAttemptAt.UTC(), e.PublishedAt, e.DeadAt); err != nil {
+			return 0, errors.Wrapf(err, `failed to exec query: q - %s, seq - %d`, q, e.Seq)
+		}
+	}
+	return len(events), nil
+}
+
+/*
+queryOutboxEvents returns earliest pending event of every order when it is due.
+
+	failing and backing off orders take at most one place in batch, so they do not stall other orders
+*/
+func queryOutboxEvents(ctx context.Context, conn queryer, limit int, now time.Time) ([]types.OutboxEvent, error) {
+	q := `SELECT e.seq, e.payload, e.attempts, e.last_error, e.next_attempt_at FROM "` + orderEventsTableName + `" e ` +
+		`WHERE e.published_at IS NULL AND e.dead_at IS NULL AND (e.attempts = 0 OR e.next_attempt_at <= $1) ` +
+		`AND NOT EXISTS (SELECT 1 FROM "` + orderEventsTableName + `" p WHERE p.order_id = e.order_id AND p.seq < e.seq ` +
+		`AND p.published_at IS NULL AND p.dead_at IS NULL) ` +
+		`ORDER BY e.seq LIMIT $2`
+	rows, err := conn.QueryContext(ctx, q, now, limit)
+	if err != nil {
+		return nil, errors.Wrapf(err, `failed to query rows: q - %s`, q)
+	}
+	var events []types.OutboxEvent
+	for rows.Next() {
+		e := types.OutboxEvent{}
+		var payload []byte
+		if err = rows.Scan(&e.Seq, &payload, &e.Attempts, &e.LastError, &e.NextAttemptAt); err != nil {
+			_ = rows.Close()
+			return nil, errors.Wrapf(err, `failed to scan rows: q - %s`, q)
+		}
+		if err = json.Unmarshal(payload, &e.OrderEvent); err != nil {
+			_ = rows.Close()
+			return nil, errors.Wrapf(err, `failed to unmarshal event: seq - %d`, e.Seq)
+		}
+		events = append(events, e)
+	}
+	if err = rows.Err(); err != nil {
+		_ = rows.Close()
+		return nil, errors.Wrapf(err, `failed to iterate rows: q - %s`, q)
+	}
+	return events, errors.Wrapf(rows.Close(), `failed to close rows: q - %s`, q)
+}
@@ -0,0 +1,59 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/leveldorado/space-trouble/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostgreSQLOrdersRepo_ProcessOutbox(t *testing.T) {
+	repo := prepareOrdersRepo(t)
+	doc := types.Order{
+		ID:            uuid.New().String(),
+		DestinationID: uuid.New().String(),
+	}
+	require.NoError(t, repo.Insert(context.TODO(), doc))
+	require.NoError(t, repo.Delete(context.TODO(), time.Now(), doc.ID))
+
+	var seen []string
+	fail := true
+	process := func() {
+		_, err := repo.ProcessOutbox(context.TODO(), time.Now(), 1000, func(e *types.OutboxEvent) {
+			if e.OrderID != doc.ID {
+				now := time.Now()
+				e.PublishedAt = &now
+				return
+			}
+			seen = append(seen, e.Type)
+			e.Attempts++
+			if fail {
+				e.LastError = "fail"
+				return
+			}
+			now := time.Now()
+			e.PublishedAt = &now
+		})
+		require.NoError(t, err)
+	}
+
+	process()
+	// cancelled event waits until created one is published
+	require.Equal(t, []string{types.OrderEventCreated}, seen)
+
+	fail = false
+	seen = nil
+	process()
+	require.Equal(t, []string{types.OrderEventCreated}, seen)
+
+	seen = nil
+	process()
+	require.Equal(t, []string{types.OrderEventCancelled}, seen)
+
+	seen = nil
+	process()
+	require.Empty(t, seen)
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/leveldorado/space-trouble/pkg/types"
@@ -12,8 +14,13 @@ import (
 )
 
 const (
-	customerInfoTableName = "customer_info"
-	orderTableName        = "order"
+	customerInfoTableName   = "customer_info"
+	orderTableName          = "order"
+	orderPassengerTableName = "order_passenger"
+	// locks selected order row until end of transaction
+	postgresOrderLock = ` FOR UPDATE OF o`
+	// serializes seat reservations of one flight until end of transaction
+	postgresFlightLock = `SELECT pg_advisory_xact_lock(hashtext($1))`
 )
 
 type PostgreSQLOrdersRepo struct {
@@ -48,8 +55,29 @@ CREATE TABLE IF NOT EXISTS "%s"  (
     created_at     timestamp,
     PRIMARY KEY(id)
 );
+ALTER TABLE "%[1]s" ADD COLUMN IF NOT EXISTS email text NOT NULL DEFAULT '';
+ALTER TABLE "%[1]s" ADD COLUMN IF NOT EXISTS launch_local_date text NOT NULL DEFAULT '';
+ALTER TABLE "%[1]s" ADD COLUMN IF NOT EXISTS launchpad_timezone text NOT NULL DEFAULT '';
+ALTER TABLE "%[1]s" ADD COLUMN IF NOT EXISTS status text NOT NULL DEFAULT 'active';
+ALTER TABLE "%[1]s" ADD COLUMN IF NOT EXISTS conflict_reason text NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS "%[1]s_status_launch_date" ON "%[1]s" (status, launch_date);
+CREATE INDEX IF NOT EXISTS "%[1]s_launchpad_id_launch_date" ON "%[1]s" (launchpad_id, launch_date);
 `, orderTableName)
-	for _, q := range []string{customerTableCreateQuery, orderTableCreateQuery} {
+	orderPassengerTableCreateQuery := fmt.Sprintf(`
+CREATE TABLE IF NOT EXISTS "%s" (
+    order_id    uuid,
+    position    int,
+    customer_id uuid,
+    PRIMARY KEY(order_id, position)
+);
+`, orderPassengerTableName)
+	for _, q := range []string{
+		customerTableCreateQuery,
+		orderTableCreateQuery,
+		orderPassengerTableCreateQuery,
+		orderEventsTableCreateQuery,
+		orderAuditTableCreateQuery,
+	} {
 		if _, err := r.conn.ExecContext(ctx, q); err != nil {
 			return errors.Wrapf(err, `failed to exec query: q - %s`, q)
 		}
@@ -62,7 +90,7 @@ func (r *PostgreSQLOrdersRepo) Insert(ctx context.Context, doc types.Order) erro
 	if err != nil {
 		return errors.Wrap(err, `failed to begin transaction`)
 	}
-	if err = insertOrderWithTransaction(ctx, tx, doc); err != nil {
+	if err = insertOrderWithTransaction(ctx, tx, doc, postgresFlightLock); err != nil {
 		if rollbackErr := tx.Rollback(); rollbackErr != nil {
 			r.log.WithField("err", err.Error()).Error("failed to rollback")
 		}
@@ -71,18 +99,124 @@ func (r *PostgreSQLOrdersRepo) Insert(ctx context.Context, doc types.Order) erro
 	return errors.Wrapf(tx.Commit(), `failed to commit: doc - %+v`, doc)
 }
 
-func insertOrderWithTransaction(ctx context.Context, tx *sql.Tx, doc types.Order) error {
-	customerID, err := obtainCustomerID(ctx, tx, doc)
+/*
+InsertMany inserts all orders in single transaction so either all or none of them stored
+*/
+func (r *PostgreSQLOrdersRepo) InsertMany(ctx context.Context, docs []types.Order) error {
+	tx, err := r.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, `failed to begin transaction`)
+	}
+	for _, doc := range docs {
+		if err = insertOrderWithTransaction(ctx, tx, doc, postgresFlightLock); err != nil {
+			if rollbackErr := tx.Rollback(); rollbackErr != nil {
+				r.log.WithField("err", rollbackErr.Error()).Error("failed to rollback")
+			}
+			return errors.Wrapf(err, `failed to insert order: doc - %+v`, doc)
+		}
+	}
+	return errors.Wrapf(tx.Commit(), `failed to commit: count - %d`, len(docs))
+}
+
+/*
+TakenSeats returns number of seats taken by active orders of flight, flight is launchpad on launch local date
+*/
+func (r *PostgreSQLOrdersRepo) TakenSeats(ctx context.Context, launchpadID, launchLocalDate string) (int, error) {
+	return countTakenSeats(ctx, r.conn, launchpadID, launchLocalDate, uuid.Nil.String())
+}
+
+/*
+insertOrderWithTransaction stores order with all passengers, audit entry and order created event in outbox.
+
+	first passenger kept in order customer_id as well, so orders created before passengers list are read the same way.
+	audit entry and event are stamped with CreatedAt of order, so they follow the clock of caller.
+	seats of active order are reserved on its flight, see reserveSeats. ErrDuplicatedOrder returned when order with id exists
+*/
+func insertOrderWithTransaction(ctx context.Context, tx sqlExecutor, doc types.Order, flightLock string) error {
+	var exists bool
+	existsQuery := `SELECT EXISTS (SELECT 1 FROM "` + orderTableName + `" WHERE id = $1)`
+	if err := tx.QueryRowContext(ctx, existsQuery, doc.ID).Scan(&exists); err != nil {
+		return errors.Wrapf(err, `failed to query row: id - %s, q - %s`, doc.ID, existsQuery)
+	}
+	if exists {
+		return types.ErrDuplicatedOrder{}
+	}
+	if doc.Status == "" || doc.Status == types.OrderStatusActive {
+		if err := reserveSeats(ctx, tx, doc, flightLock); err != nil {
+			return err
+		}
+	}
+	passengers := doc.PassengerList()
+	customerIDs := make([]string, len(passengers))
+	for i, p := range passengers {
+		id, err := obtainCustomerID(ctx, tx, p)
+		if err != nil {
+			return err
+		}
+		customerIDs[i] = id
+	}
+	if doc.Status == "" {
+		doc.Status = types.OrderStatusActive
+	}
+	q := `INSERT INTO "` + orderTableName + `" (id, customer_id, launchpad_id, destination_id, launch_date, created_at, email, ` +
+		`launch_local_date, launchpad_timezone, status, conflict_reason) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
+	_, err := tx.ExecContext(ctx, q, doc.ID, customerIDs[0], doc.LaunchpadID, doc.DestinationID, doc.LaunchDate, doc.CreatedAt, doc.Email,
+		doc.LaunchLocalDate, doc.LaunchpadTimezone, doc.Status, doc.ConflictReason)
+	if err != nil {
+		return errors.Wrapf(err, `failed to exec query: q - %s, doc - %v`, q, doc)
+	}
+	q = `INSERT INTO "` + orderPassengerTableName + `" (order_id, position, customer_id) VALUES ($1, $2, $3)`
+	for i, customerID := range customerIDs {
+		if _, err = tx.ExecContext(ctx, q, doc.ID, i, customerID); err != nil {
+			return errors.Wrapf(err, `failed to exec query: q - %s, order - %s, position - %d`, q, doc.ID, i)
+		}
+	}
+	if err = insertOrderAudit(ctx, tx, doc.CreatedAt, types.OrderAuditActionCreate, nil, &doc); err != nil {
+		return err
+	}
+	return insertOrderEvent(ctx, tx, doc.CreatedAt, types.OrderEventCreated, doc)
+}
+
+/*
+reserveSeats returns ErrFlightImpossible when passengers of doc do not fit into seats left on its flight.
+
+	flight is launchpad on launch local date, seats are taken by passengers of other active orders of the flight.
+	flightLock query is run first to serialize reservations of the flight, empty when transactions are serialized anyway
+*/
+func reserveSeats(ctx context.Context, tx sqlExecutor, doc types.Order, flightLock string) error {
+	flight := doc.LaunchpadID + "/" + doc.LaunchLocalDate
+	if flightLock != "" {
+		if _, err := tx.ExecContext(ctx, flightLock, flight); err != nil {
+			return errors.Wrapf(err, `failed to lock flight: flight - %s, q - %s`, flight, flightLock)
+		}
+	}
+	taken, err := countTakenSeats(ctx, tx, doc.LaunchpadID, doc.LaunchLocalDate, doc.ID)
 	if err != nil {
 		return err
 	}
-	q := `INSERT INTO "` + orderTableName + `" (id, customer_id, launchpad_id, destination_id, launch_date, created_at) ` +
-		`VALUES ($1, $2, $3, $4, $5, $6)`
-	_, err = tx.ExecContext(ctx, q, doc.ID, customerID, doc.LaunchpadID, doc.DestinationID, doc.LaunchDate, doc.CreatedAt)
-	return errors.Wrapf(err, `failed to exec query: q - %s, doc - %v`, q, doc)
+	if taken+len(doc.PassengerList()) > types.FlightSeats {
+		return types.NewErrFlightImpossible(types.FlightImpossibleReasonNoSeats)
+	}
+	return nil
 }
 
-func obtainCustomerID(ctx context.Context, tx *sql.Tx, doc types.Order) (string, error) {
+/*
+countTakenSeats returns number of seats taken by passengers of active orders of flight except order with excludedID
+*/
+func countTakenSeats(ctx context.Context, conn sqlExecutor, launchpadID, launchLocalDate, excludedID string) (int, error) {
+	// orders stored before passengers list have no passenger rows and take one seat
+	flightOrders := `o.launchpad_id = $1 AND o.launch_local_date = $2 AND o.status = $3 AND o.id <> $4`
+	q := `SELECT (SELECT count(*) FROM "` + orderPassengerTableName + `" p JOIN "` + orderTableName + `" o ON o.id = p.order_id ` +
+		`WHERE ` + flightOrders + `) + (SELECT count(*) FROM "` + orderTableName + `" o WHERE ` + flightOrders + ` ` +
+		`AND NOT EXISTS (SELECT 1 FROM "` + orderPassengerTableName + `" p WHERE p.order_id = o.id))`
+	var taken int
+	if err := conn.QueryRowContext(ctx, q, launchpadID, launchLocalDate, types.OrderStatusActive, excludedID).Scan(&taken); err != nil {
+		return 0, errors.Wrapf(err, `failed to query row: flight - %s/%s, q - %s`, launchpadID, launchLocalDate, q)
+	}
+	return taken, nil
+}
+
+func obtainCustomerID(ctx context.Context, tx sqlExecutor, doc types.Passenger) (string, error) {
 	q := `SELECT id FROM ` + customerInfoTableName +
 		` WHERE first_name = $1 AND last_name = $2 AND gender = $3 ` +
 		` AND birthday_year = $4 AND birthday_month = $5 AND birthday_day = $6 `
@@ -121,12 +255,17 @@ func obtainCustomerID(ctx context.Context, tx *sql.Tx, doc types.Order) (string,
 	return id, errors.Wrapf(err, `failed to insert customer info: q - %s, doc - %+v`, q, doc)
 }
 
-func (r *PostgreSQLOrdersRepo) Get(ctx context.Context, id string) (types.Order, error) {
-	q := `SELECT o.id, c.first_name, c.last_name, c.gender, c.birthday_year, c.birthday_month, c.birthday_day, ` +
-		`o.launchpad_id, o.destination_id, o.launch_date, o.created_at FROM "` + orderTableName + `" o JOIN ` +
-		customerInfoTableName + ` c ON o.customer_id = c.id WHERE o.id = $1;`
+const (
+	orderSelectQuery = `SELECT o.id, c.first_name, c.last_name, c.gender, c.birthday_year, c.birthday_month, c.birthday_day, ` +
+		`o.launchpad_id, o.destination_id, o.launch_date, o.created_at, o.email, o.launch_local_date, o.launchpad_timezone, ` +
+		`o.status, o.conflict_reason ` +
+		`FROM "` + orderTableName + `" o JOIN ` +
+		customerInfoTableName + ` c ON o.customer_id = c.id `
+)
+
+func scanOrder(row rowScanner) (types.Order, error) {
 	doc := types.Order{}
-	err := r.conn.QueryRowContext(ctx, q, id).Scan(
+	err := row.Scan(
 		&doc.ID,
 		&doc.FirstName,
 		&doc.LastName,
@@ -138,47 +277,366 @@ func (r *PostgreSQLOrdersRepo) Get(ctx context.Context, id string) (types.Order,
 		&doc.DestinationID,
 		&doc.LaunchDate,
 		&doc.CreatedAt,
+		&doc.Email,
+		&doc.LaunchLocalDate,
+		&doc.LaunchpadTimezone,
+		&doc.Status,
+		&doc.ConflictReason,
 	)
+	return doc, err
+}
+
+func (r *PostgreSQLOrdersRepo) Get(ctx context.Context, id string) (types.Order, error) {
+	q := orderSelectQuery + `WHERE o.id = $1;`
+	doc, err := scanOrder(r.conn.QueryRowContext(ctx, q, id))
 	if errors.Is(err, sql.ErrNoRows) {
 		return types.Order{}, types.ErrNotFound{}
 	}
-	return doc, errors.Wrapf(err, `failed to query row: id - %s, q - %s`, id, q)
+	if err != nil {
+		return types.Order{}, errors.Wrapf(err, `failed to query row: id - %s, q - %s`, id, q)
+	}
+	orders := []types.Order{doc}
+	if err = loadPassengers(ctx, r.conn, orders); err != nil {
+		return types.Order{}, err
+	}
+	return orders[0], nil
 }
 
 func (r *PostgreSQLOrdersRepo) List(ctx context.Context, limit, offset int) ([]types.Order, error) {
-	q := `SELECT o.id, c.first_name, c.last_name, c.gender, c.birthday_year, c.birthday_month, c.birthday_day, ` +
-		`o.launchpad_id, o.destination_id, o.launch_date, o.created_at FROM "` + orderTableName + `" o JOIN ` +
-		customerInfoTableName + ` c ON o.customer_id = c.id ORDER BY o.created_at ` +
+	q := orderSelectQuery + `ORDER BY o.created_at ` +
+		fmt.Sprintf(`LIMIT %d OFFSET %d`, limit, offset)
+	orders, err := queryOrders(ctx, r.conn, q)
+	if err != nil {
+		return nil, err
+	}
+	return orders, loadPassengers(ctx, r.conn, orders)
+}
+
+/*
+ListByLaunchpad returns active orders of launchpad with launch date in [from, to) range ordered by launch date.
+
+	orders in conflict do not fly and cancelled orders are deleted, so neither of them is returned
+*/
+func (r *PostgreSQLOrdersRepo) ListByLaunchpad(ctx context.Context, launchpadID string, from, to time.Time) ([]types.Order, error) {
+	q := orderSelectQuery + `WHERE o.launchpad_id = $1 AND o.launch_date >= $2 AND o.launch_date < $3 AND o.status = $4 ` +
+		`ORDER BY o.launch_date, o.created_at, o.id`
+	orders, err := queryOrders(ctx, r.conn, q, launchpadID, from, to, types.OrderStatusActive)
+	if err != nil {
+		return nil, err
+	}
+	return orders, loadPassengers(ctx, r.conn, orders)
+}
+
+/*
+ListByStatus returns orders in status with launch date not before from ordered by launch date
+*/
+func (r *PostgreSQLOrdersRepo) ListByStatus(ctx context.Context, status string, from time.Time, limit, offset int) ([]types.Order, error) {
+	q := orderSelectQuery + `WHERE o.status = $1 AND o.launch_date >= $2 ORDER BY o.launch_date, o.id ` +
 		fmt.Sprintf(`LIMIT %d OFFSET %d`, limit, offset)
-	rows, err := r.conn.QueryContext(ctx, q)
+	orders, err := queryOrders(ctx, r.conn, q, status, from)
+	if err != nil {
+		return nil, err
+	}
+	return orders, loadPassengers(ctx, r.conn, orders)
+}
+
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+/*
+sqlExecutor is implemented by *sql.DB, *sql.Tx and sqliteRebinder, so queries are shared between PostgreSQL and SQLite repos
+*/
+type sqlExecutor interface {
+	queryer
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+func queryOrders(ctx context.Context, conn queryer, q string, args ...interface{}) ([]types.Order, error) {
+	rows, err := conn.QueryContext(ctx, q, args...)
 	if err != nil {
 		return nil, errors.Wrapf(err, `failed to query rows: q - %s`, q)
 	}
 	var orders []types.Order
 	for rows.Next() {
-		doc := types.Order{}
-		if err = rows.Scan(
-			&doc.ID,
-			&doc.FirstName,
-			&doc.LastName,
-			&doc.Gender,
-			&doc.BirthdayYear,
-			&doc.BirthdayMonth,
-			&doc.BirthdayDay,
-			&doc.LaunchpadID,
-			&doc.DestinationID,
-			&doc.LaunchDate,
-			&doc.CreatedAt,
-		); err != nil {
+		doc, err := scanOrder(rows)
+		if err != nil {
+			_ = rows.Close()
 			return nil, errors.Wrapf(err, `failed to scan rows: q -  %s`, q)
 		}
 		orders = append(orders, doc)
 	}
+	if err = rows.Err(); err != nil {
+		_ = rows.Close()
+		return nil, errors.Wrapf(err, `failed to iterate rows: q - %s`, q)
+	}
 	return orders, errors.Wrapf(rows.Close(), `failed to close rows: q - %s`, q)
 }
 
-func (r *PostgreSQLOrdersRepo) Delete(ctx context.Context, id string) error {
-	q := `DELETE FROM "` + orderTableName + `" WHERE id = $1`
-	_, err := r.conn.ExecContext(ctx, q, id)
-	return errors.Wrapf(err, `failed to exec query: id - %s, q - %s`, id, q)
+/*
+loadPassengers fills passengers list of orders.
+
+	orders created before passengers list was introduced have only lead passenger
+*/
+func loadPassengers(ctx context.Context, conn queryer, orders []types.Order) error {
+	passengers := map[string][]types.Passenger{}
+	for start := 0; start < len(orders); start += streamBatchSize {
+		end := start + streamBatchSize
+		if end > len(orders) {
+			end = len(orders)
+		}
+		if err := queryPassengers(ctx, conn, orders[start:end], passengers); err != nil {
+			return err
+		}
+	}
+	for i, o := range orders {
+		orders[i] = o.WithPassengers(passengers[o.ID])
+	}
+	return nil
+}
+
+func queryPassengers(ctx context.Context, conn queryer, orders []types.Order, passengers map[string][]types.Passenger) error {
+	placeholders := make([]string, len(orders))
+	ids := make([]interface{}, len(orders))
+	for i, o := range orders {
+		placeholders[i] = fmt.Sprintf(`$%d`, i+1)
+		ids[i] = o.ID
+	}
+	q := `SELECT p.order_id, c.first_name, c.last_name, c.gender, c.birthday_year, c.birthday_month, c.birthday_day ` +
+		`FROM "` + orderPassengerTableName + `" p JOIN ` + customerInfoTableName + ` c ON p.customer_id = c.id ` +
+		`WHERE p.order_id IN (` + strings.Join(placeholders, `, `) + `) ORDER BY p.order_id, p.position`
+	rows, err := conn.QueryContext(ctx, q, ids...)
+	if err != nil {
+		return errors.Wrapf(err, `failed to query rows: q - %s`, q)
+	}
+	for rows.Next() {
+		var orderID string
+		p := types.Passenger{}
+		if err = rows.Scan(
+			&orderID,
+			&p.FirstName,
+			&p.LastName,
+			&p.Gender,
+			&p.BirthdayYear,
+			&p.BirthdayMonth,
+			&p.BirthdayDay,
+		); err != nil {
+			_ = rows.Close()
+			return errors.Wrapf(err, `failed to scan rows: q - %s`, q)
+		}
+		passengers[orderID] = append(passengers[orderID], p)
+	}
+	if err = rows.Err(); err != nil {
+		_ = rows.Close()
+		return errors.Wrapf(err, `failed to iterate rows: q - %s`, q)
+	}
+	return errors.Wrapf(rows.Close(), `failed to close rows: q - %s`, q)
+}
+
+const (
+	streamCursorName = "orders_stream"
+	streamBatchSize  = 500
+)
+
+/*
+Stream calls fn for each order in the same order as List.
+
+	orders are fetched by batches from server side cursor so whole result never loaded in memory.
+	zero limit means no limit
+*/
+func (r *PostgreSQLOrdersRepo) Stream(ctx context.Context, limit, offset int, fn func(types.Order) error) error {
+	tx, err := r.conn.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return errors.Wrap(err, `failed to begin transaction`)
+	}
+	if err = streamWithTransaction(ctx, tx, limit, offset, fn); err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			r.log.WithField("err", rollbackErr.Error()).Error("failed to rollback")
+		}
+		return err
+	}
+	return errors.Wrap(tx.Commit(), `failed to commit`)
+}
+
+func streamWithTransaction(ctx context.Context, tx *sql.Tx, limit, offset int, fn func(types.Order) error) error {
+	limitClause := "ALL"
+	if limit > 0 {
+		limitClause = fmt.Sprint(limit)
+	}
+	q := `DECLARE ` + streamCursorName + ` NO SCROLL CURSOR FOR ` + orderSelectQuery +
+		`ORDER BY o.created_at, o.id ` + fmt.Sprintf(`LIMIT %s OFFSET %d`, limitClause, offset)
+	if _, err := tx.ExecContext(ctx, q); err != nil {
+		return errors.Wrapf(err, `failed to declare cursor: q - %s`, q)
+	}
+	fetchQuery := fmt.Sprintf(`FETCH %d FROM %s`, streamBatchSize, streamCursorName)
+	for {
+		batch, err := queryOrders(ctx, tx, fetchQuery)
+		if err != nil {
+			return err
+		}
+		if err = loadPassengers(ctx, tx, batch); err != nil {
+			return err
+		}
+		for _, doc := range batch {
+			if err = fn(doc); err != nil {
+				return err
+			}
+		}
+		if len(batch) < streamBatchSize {
+			break
+		}
+	}
+	closeQuery := `CLOSE ` + streamCursorName
+	_, err := tx.ExecContext(ctx, closeQuery)
+	return errors.Wrapf(err, `failed to close cursor: q - %s`, closeQuery)
+}
+
+/*
+Delete deletes order and stores audit entry and order cancelled event at now in outbox, missing order is not an error
+*/
+func (r *PostgreSQLOrdersRepo) Delete(ctx context.Context, now time.Time, id string) error {
+	tx, err := r.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, `failed to begin transaction`)
+	}
+	if err = deleteOrderWithTransaction(ctx, tx, now, id, postgresOrderLock); err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			r.log.WithField("err", rollbackErr.Error()).Error("failed to rollback")
+		}
+		return err
+	}
+	return errors.Wrapf(tx.Commit(), `failed to commit: id - %s`, id)
+}
+
+/*
+deleteOrderWithTransaction deletes order and stores audit entry and order cancelled event in outbox, missing order is not an error.
+
+	lock is appended to query selecting order
+*/
+func deleteOrderWithTransaction(ctx context.Context, tx sqlExecutor, now time.Time, id, lock string) error {
+	selectQuery := orderSelectQuery + `WHERE o.id = $1` + lock
+	doc, err := scanOrder(tx.QueryRowContext(ctx, selectQuery, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, `failed to query row: id - %s, q - %s`, id, selectQuery)
+	}
+	orders := []types.Order{doc}
+	if err = loadPassengers(ctx, tx, orders); err != nil {
+		return err
+	}
+	for _, q := range []string{
+		`DELETE FROM "` + orderPassengerTableName + `" WHERE order_id = $1`,
+		`DELETE FROM "` + orderTableName + `" WHERE id = $1`,
+	} {
+		if _, err := tx.ExecContext(ctx, q, id); err != nil {
+			return errors.Wrapf(err, `failed to exec query: id - %s, q - %s`, id, q)
+		}
+	}
+	if err = insertOrderAudit(ctx, tx, now, types.OrderAuditActionDelete, &orders[0], nil); err != nil {
+		return err
+	}
+	return insertOrderEvent(ctx, tx, now, types.OrderEventCancelled, orders[0])
+}
+
+/*
+MarkConflict moves order into conflict status with reason and emits order conflicted event.
+
+	doc is order as it was checked, ErrOrderChanged returned when stored order is not active anymore or its flight changed
+*/
+func (r *PostgreSQLOrdersRepo) MarkConflict(ctx context.Context, now time.Time, checked types.Order, reason string) error {
+	return r.update(ctx, now, checked.ID, types.OrderEventConflicted, sameActiveFlight(checked), func(doc *types.Order) {
+		doc.Status = types.OrderStatusConflict
+		doc.ConflictReason = reason
+	})
+}
+
+/*
+Reschedule moves order to launchpad, destination and launch date of doc, makes it active and emits order rescheduled event.
+
+	ErrOrderChanged returned when stored order is not in conflict anymore
+*/
+func (r *PostgreSQLOrdersRepo) Reschedule(ctx context.Context, now time.Time, doc types.Order) error {
+	return r.update(ctx, now, doc.ID, types.OrderEventRescheduled, inConflict, func(o *types.Order) {
+		o.LaunchpadID = doc.LaunchpadID
+		o.DestinationID = doc.DestinationID
+		o.LaunchDate = doc.LaunchDate
+		o.LaunchLocalDate = doc.LaunchLocalDate
+		o.LaunchpadTimezone = doc.LaunchpadTimezone
+		o.Status = types.OrderStatusActive
+		o.ConflictReason = ""
+	})
+}
+
+/*
+sameActiveFlight accepts stored order which is still active and has the same flight as checked one
+*/
+func sameActiveFlight(checked types.Order) func(doc types.Order) bool {
+	return func(doc types.Order) bool {
+		return doc.Status == types.OrderStatusActive && doc.LaunchpadID == checked.LaunchpadID &&
+			doc.DestinationID == checked.DestinationID && doc.LaunchDate.Equal(checked.LaunchDate)
+	}
+}
+
+func inConflict(doc types.Order) bool {
+	return doc.Status == types.OrderStatusConflict
+}
+
+/*
+update changes flight fields of locked order by fn and stores audit entry and event of change at now in the same transaction.
+
+	ErrNotFound returned when order does not exist, ErrOrderChanged when locked order is not accepted by expected
+*/
+func (r *PostgreSQLOrdersRepo) update(ctx context.Context, now time.Time, id, eventType string, expected func(doc types.Order) bool,
+	fn func(doc *types.Order)) error {
+	tx, err := r.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, `failed to begin transaction`)
+	}
+	if err = updateOrderWithTransaction(ctx, tx, now, id, postgresOrderLock, postgresFlightLock, eventType, expected, fn); err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			r.log.WithField("err", rollbackErr.Error()).Error("failed to rollback")
+		}
+		return err
+	}
+	return errors.Wrapf(tx.Commit(), `failed to commit: id - %s`, id)
+}
+
+func updateOrderWithTransaction(ctx context.Context, tx sqlExecutor, now time.Time, id, lock, flightLock, eventType string,
+	expected func(doc types.Order) bool, fn func(doc *types.Order)) error {
+	selectQuery := orderSelectQuery + `WHERE o.id = $1` + lock
+	doc, err := scanOrder(tx.QueryRowContext(ctx, selectQuery, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return types.ErrNotFound{}
+	}
+	if err != nil {
+		return errors.Wrapf(err, `failed to query row: id - %s, q - %s`, id, selectQuery)
+	}
+	if !expected(doc) {
+		return types.ErrOrderChanged{}
+	}
+	orders := []types.Order{doc}
+	if err = loadPassengers(ctx, tx, orders); err != nil {
+		return err
+	}
+	before, after := orders[0], orders[0]
+	fn(&after)
+	moved := after.LaunchpadID != before.LaunchpadID || after.LaunchLocalDate != before.LaunchLocalDate
+	if after.Status == types.OrderStatusActive && (moved || before.Status != types.OrderStatusActive) {
+		if err = reserveSeats(ctx, tx, after, flightLock); err != nil {
+			return err
+		}
+	}
+	q := `UPDATE "` + orderTableName + `" SET launchpad_id = $2, destination_id = $3, launch_date = $4, launch_local_date = $5, ` +
+		`launchpad_timezone = $6, status = $7, conflict_reason = $8 WHERE id = $1`
+	if _, err = tx.ExecContext(ctx, q, id, after.LaunchpadID, after.DestinationID, after.LaunchDate, after.LaunchLocalDate,
+		after.LaunchpadTimezone, after.Status, after.ConflictReason); err != nil {
+		return errors.Wrapf(err, `failed to exec query: id - %s, q - %s`, id, q)
+	}
+	if err = insertOrderAudit(ctx, tx, now, types.OrderAuditActionUpdate, &before, &after); err != nil {
+		return err
+	}
+	return insertOrderEvent(ctx, tx, now, eventType, after)
 }
@@ -70,9 +70,41 @@ func TestPostgreSQLOrdersRepo_Get(t *testing.T) {
 	doc.LaunchDate = doc.LaunchDate.UTC().Truncate(time.Millisecond)
 	fromDB.CreatedAt = fromDB.CreatedAt.UTC().Truncate(time.Millisecond)
 	doc.CreatedAt = doc.CreatedAt.UTC().Truncate(time.Millisecond)
+	doc = doc.WithPassengers(nil)
 	require.Equal(t, doc, fromDB)
 }
 
+func TestPostgreSQLOrdersRepo_GetGroup(t *testing.T) {
+	repo := prepareOrdersRepo(t)
+	var passengers []types.Passenger
+	for i := 0; i < 3; i++ {
+		passengers = append(passengers, types.Passenger{
+			FirstName:     gofakeit.FirstName(),
+			LastName:      gofakeit.LastName(),
+			Gender:        gofakeit.Gender(),
+			BirthdayYear:  2000 + i,
+			BirthdayMonth: 10,
+			BirthdayDay:   2,
+		})
+	}
+	doc := types.Order{
+		ID:            uuid.New().String(),
+		LaunchpadID:   uuid.New().String(),
+		DestinationID: uuid.New().String(),
+		LaunchDate:    gofakeit.Date(),
+	}.WithPassengers(passengers)
+	require.NoError(t, repo.Insert(context.TODO(), doc))
+	fromDB, err := repo.Get(context.TODO(), doc.ID)
+	require.NoError(t, err)
+	require.Equal(t, doc.ID, fromDB.ID)
+	require.Equal(t, passengers, fromDB.Passengers)
+	require.Equal(t, passengers[0], fromDB.LeadPassenger())
+
+	require.NoError(t, repo.Delete(context.TODO(), time.Now(), doc.ID))
+	_, err = repo.Get(context.TODO(), doc.ID)
+	require.True(t, errors.As(err, &types.ErrNotFound{}))
+}
+
 func TestPostgreSQLOrdersRepo_List(t *testing.T) {
 	repo := prepareOrdersRepo(t)
 	q := `truncate table "` + orderTableName + `";`
@@ -99,7 +131,7 @@ func TestPostgreSQLOrdersRepo_List(t *testing.T) {
 	doc.LaunchDate = doc.LaunchDate.UTC().Truncate(time.Millisecond)
 	list[0].CreatedAt = list[0].CreatedAt.UTC().Truncate(time.Millisecond)
 	doc.CreatedAt = doc.CreatedAt.UTC().Truncate(time.Millisecond)
-
+	doc = doc.WithPassengers(nil)
 	require.Equal(t, []types.Order{doc}, list)
 }
 
@@ -110,9 +142,140 @@ func TestPostgreSQLOrdersRepo_Delete(t *testing.T) {
 		DestinationID: uuid.New().String(),
 	}
 	require.NoError(t, repo.Insert(context.TODO(), doc))
-	require.NoError(t, repo.Delete(context.TODO(), doc.ID))
+	require.NoError(t, repo.Delete(context.TODO(), time.Now(), doc.ID))
 	_, err := repo.Get(context.TODO(), doc.ID)
 	require.Error(t, err)
 	require.True(t, errors.As(err, &types.ErrNotFound{}))
-	require.NoError(t, repo.Delete(context.TODO(), doc.ID))
+	require.NoError(t, repo.Delete(context.TODO(), time.Now(), doc.ID))
+}
+
+func TestPostgreSQLOrdersRepo_Stream(t *testing.T) {
+	repo := prepareOrdersRepo(t)
+	q := `truncate table "` + orderTableName + `";`
+	_, err := repo.conn.Exec(q)
+	require.NoError(t, err)
+	var inserted []string
+	for i := 0; i < streamBatchSize+3; i++ {
+		doc := types.Order{
+			ID:            uuid.New().String(),
+			FirstName:     gofakeit.FirstName(),
+			LastName:      gofakeit.LastName(),
+			LaunchpadID:   uuid.New().String(),
+			DestinationID: uuid.New().String(),
+			LaunchDate:    gofakeit.Date(),
+			CreatedAt:     time.Now().UTC().Add(time.Duration(i) * time.Second),
+		}
+		require.NoError(t, repo.Insert(context.TODO(), doc))
+		inserted = append(inserted, doc.ID)
+	}
+	var streamed []string
+	require.NoError(t, repo.Stream(context.TODO(), 0, 1, func(o types.Order) error {
+		streamed = append(streamed, o.ID)
+		return nil
+	}))
+	require.Equal(t, inserted[1:], streamed)
+
+	streamed = nil
+	require.NoError(t, repo.Stream(context.TODO(), 2, 0, func(o types.Order) error {
+		streamed = append(streamed, o.ID)
+		return nil
+	}))
+	require.Equal(t, inserted[:2], streamed)
+
+	stopErr := errors.New("stop")
+	err = repo.Stream(context.TODO(), 0, 0, func(o types.Order) error {
+		return stopErr
+	})
+	require.True(t, errors.Is(err, stopErr))
+}
+
+func TestPostgreSQLOrdersRepo_InsertMany(t *testing.T) {
+	repo := prepareOrdersRepo(t)
+	var docs []types.Order
+	for i := 0; i < 2; i++ {
+		docs = append(docs, types.Order{
+			ID:            uuid.New().String(),
+			FirstName:     gofakeit.FirstName(),
+			LastName:      gofakeit.LastName(),
+			LaunchpadID:   uuid.New().String(),
+			DestinationID: uuid.New().String(),
+			LaunchDate:    gofakeit.Date(),
+		})
+	}
+	require.NoError(t, repo.InsertMany(context.TODO(), docs))
+	for _, doc := range docs {
+		_, err := repo.Get(context.TODO(), doc.ID)
+		require.NoError(t, err)
+	}
+
+	valid := types.Order{ID: uuid.New().String()}
+	invalid := types.Order{ID: "not uuid"}
+	require.Error(t, repo.InsertMany(context.TODO(), []types.Order{valid, invalid}))
+	_, err := repo.Get(context.TODO(), valid.ID)
+	require.True(t, errors.As(err, &types.ErrNotFound{}))
+}
+
+func TestPostgreSQLOrdersRepo_ListByLaunchpad(t *testing.T) {
+	repo := prepareOrdersRepo(t)
+	launchpadID := uuid.New().String()
+	day := time.Date(2053, 3, 6, 5, 0, 0, 0, time.UTC)
+	var ids []string
+	for _, launchDate := range []time.Time{day.Add(time.Hour), day, day.Add(-time.Second), day.Add(24 * time.Hour)} {
+		doc := types.Order{
+			ID:                uuid.New().String(),
+			FirstName:         gofakeit.FirstName(),
+			LastName:          gofakeit.LastName(),
+			Gender:            gofakeit.Gender(),
+			LaunchpadID:       launchpadID,
+			DestinationID:     uuid.New().String(),
+			LaunchDate:        launchDate,
+			LaunchLocalDate:   launchDate.Format(types.LocalDateLayout),
+			LaunchpadTimezone: "UTC",
+			CreatedAt:         time.Now().UTC(),
+		}
+		require.NoError(t, repo.Insert(context.TODO(), doc))
+		ids = append(ids, doc.ID)
+	}
+	list, err := repo.ListByLaunchpad(context.TODO(), launchpadID, day, day.Add(24*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	require.Equal(t, ids[1], list[0].ID)
+	require.Equal(t, ids[0], list[1].ID)
+	require.Len(t, list[0].Passengers, 1)
+	require.Equal(t, "2053-03-06", list[0].LaunchLocalDate)
+	require.Equal(t, "UTC", list[0].LaunchpadTimezone)
+}
+
+func TestPostgreSQLOrdersRepo_Conflicts(t *testing.T) {
+	repo := prepareOrdersRepo(t)
+	from := time.Date(2053, 3, 1, 0, 0, 0, 0, time.UTC)
+	doc := types.Order{
+		ID:            uuid.New().String(),
+		FirstName:     gofakeit.FirstName(),
+		LastName:      gofakeit.LastName(),
+		LaunchpadID:   uuid.New().String(),
+		DestinationID: uuid.New().String(),
+		LaunchDate:    from.AddDate(0, 0, 3),
+	}
+	require.NoError(t, repo.Insert(context.TODO(), doc))
+	require.NoError(t, repo.MarkConflict(context.TODO(), time.Now(), doc, types.FlightImpossibleReasonCompetitorLaunch))
+	require.True(t, errors.As(repo.MarkConflict(context.TODO(), time.Now(), types.Order{ID: uuid.New().String()}, ""), &types.ErrNotFound{}))
+
+	got, err := repo.Get(context.TODO(), doc.ID)
+	require.NoError(t, err)
+	require.Equal(t, types.OrderStatusConflict, got.Status)
+	require.Equal(t, types.FlightImpossibleReasonCompetitorLaunch, got.ConflictReason)
+
+	orders, err := repo.ListByStatus(context.TODO(), types.OrderStatusConflict, from, 1000, 0)
+	require.NoError(t, err)
+	require.Contains(t, orders, got)
+
+	got.LaunchDate = from.AddDate(0, 0, 5)
+	got.Status = types.OrderStatusActive
+	got.ConflictReason = ""
+	require.NoError(t, repo.Reschedule(context.TODO(), time.Now(), got))
+	rescheduled, err := repo.Get(context.TODO(), doc.ID)
+	require.NoError(t, err)
+	require.Equal(t, types.OrderStatusActive, rescheduled.Status)
+	require.True(t, got.LaunchDate.Equal(rescheduled.LaunchDate))
 }
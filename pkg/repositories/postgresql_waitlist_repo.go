@@ -0,0 +1,218 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/leveldorado/space-trouble/pkg/types"
+	"github.com/pkg/errors"
+)
+
+const waitlistTableName = "waitlist"
+
+type PostgreSQLWaitlistRepo struct {
+	conn *sql.DB
+}
+
+func NewPostgreSQLWaitlistRepo(conn *sql.DB) *PostgreSQLWaitlistRepo {
+	return &PostgreSQLWaitlistRepo{conn: conn}
+}
+
+func (r *PostgreSQLWaitlistRepo) CreateTables(ctx context.Context) error {
+	q := fmt.Sprintf(`
+CREATE TABLE IF NOT EXISTS "%s" (
+    id            uuid,
+    launchpad_id  text,
+    order_doc     jsonb,
+    status        text,
+    order_id      text,
+    checks        int,
+    last_error    text,
+    next_check_at timestamp with time zone,
+    created_at    timestamp with time zone,
+    promoted_at   timestamp with time zone,
+    PRIMARY KEY(id)
+);
+CREATE INDEX IF NOT EXISTS "%[1]s_status_created_at" ON "%[1]s" (status, created_at);
+CREATE INDEX IF NOT EXISTS "%[1]s_launchpad_id" ON "%[1]s" (launchpad_id);
+ALTER TABLE "%[1]s" ADD COLUMN IF NOT EXISTS launch_local_date text NOT NULL DEFAULT '';
+UPDATE "%[1]s" SET launch_local_date = order_doc->>'launch_local_date'
+    WHERE launch_local_date = '' AND coalesce(order_doc->>'launch_local_date', '') <> '';
+CREATE INDEX IF NOT EXISTS "%[1]s_flight" ON "%[1]s" (launchpad_id, launch_local_date, status, created_at);
+`, waitlistTableName)
+	_, err := r.conn.ExecContext(ctx, q)
+	return errors.Wrapf(err, `failed to exec query: q - %s`, q)
+}
+
+const waitlistColumns = `id, order_doc, status, order_id, checks, last_error, next_check_at, created_at, promoted_at`
+
+/*
+waitlistAheadCondition matches entries "a" of the same flight as entry "w" which joined before it and have status $2
+*/
+const waitlistAheadCondition = `a.launchpad_id = w.launchpad_id AND a.launch_local_date = w.launch_local_date AND a.status = $2 AND ` +
+	`(a.created_at < w.created_at OR (a.created_at = w.created_at AND a.id < w.id))`
+
+/*
+waitlistClaimDueQuery claims only first waiting entry of each flight, so seats of flight go to entries in order of joining
+*/
+func waitlistClaimDueQuery(lock string) string {
+	return `UPDATE "` + waitlistTableName + `" SET next_check_at = $1 WHERE id IN (` +
+		`SELECT w.id FROM "` + waitlistTableName + `" w WHERE w.status = $2 AND w.next_check_at <= $3 AND NOT EXISTS (` +
+		`SELECT 1 FROM "` + waitlistTableName + `" a WHERE ` + waitlistAheadCondition + `) ` +
+		`ORDER BY w.created_at, w.id LIMIT $4` + lock + `) RETURNING ` + waitlistColumns
+}
+
+const waitlistHasWaitingAheadQuery = `SELECT EXISTS (SELECT 1 FROM "` + waitlistTableName + `" w ` +
+	`JOIN "` + waitlistTableName + `" a ON ` + waitlistAheadCondition + ` WHERE w.id = $1)`
+
+func (r *PostgreSQLWaitlistRepo) Insert(ctx context.Context, doc types.WaitlistEntry) error {
+	orderDoc, err := json.Marshal(doc.Order)
+	if err != nil {
+		return errors.Wrapf(err, `failed to marshal order: id - %s`, doc.ID)
+	}
+	q := `INSERT INTO "` + waitlistTableName + `" (launchpad_id, launch_local_date, ` + waitlistColumns + `) ` +
+		`VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
+	_, err = r.conn.ExecContext(ctx, q,
+		doc.Order.LaunchpadID,
+		doc.Order.LaunchLocalDate,
+		doc.ID,
+		orderDoc,
+		doc.Status,
+		doc.OrderID,
+		doc.Checks,
+		doc.LastError,
+		doc.NextCheckAt,
+		doc.CreatedAt,
+		doc.PromotedAt,
+	)
+	return errors.Wrapf(err, `failed to exec query: q - %s, id - %s`, q, doc.ID)
+}
+
+func (r *PostgreSQLWaitlistRepo) Get(ctx context.Context, id string) (types.WaitlistEntry, error) {
+	q := `SELECT ` + waitlistColumns + ` FROM "` + waitlistTableName + `" WHERE id = $1`
+	docs, err := queryWaitlistEntries(ctx, r.conn, q, id)
+	if err != nil {
+		return types.WaitlistEntry{}, err
+	}
+	if len(docs) == 0 {
+		return types.WaitlistEntry{}, types.ErrNotFound{}
+	}
+	return docs[0], nil
+}
+
+/*
+ClaimDue returns waiting entries which check time passed and which are first waiting entries of their flights, oldest entries first.
+
+	claimed entries get next check moved by lease so other replicas do not check them at the same time,
+	entries behind claimed one stay unclaimed until it leaves waitlist
+*/
+func (r *PostgreSQLWaitlistRepo) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]types.WaitlistEntry, error) {
+	q := waitlistClaimDueQuery(` FOR UPDATE SKIP LOCKED`)
+	docs, err := queryWaitlistEntries(ctx, r.conn, q, now.Add(lease), types.WaitlistStatusWaiting, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	sortWaitlistEntries(docs)
+	return docs, nil
+}
+
+/*
+HasWaitingAhead reports whether entry of the same flight which joined earlier is still waiting
+*/
+func (r *PostgreSQLWaitlistRepo) HasWaitingAhead(ctx context.Context, id string) (bool, error) {
+	var exists bool
+	err := r.conn.QueryRowContext(ctx, waitlistHasWaitingAheadQuery, id, types.WaitlistStatusWaiting).Scan(&exists)
+	return exists, errors.Wrapf(err, `failed to query row: q - %s, id - %s`, waitlistHasWaitingAheadQuery, id)
+}
+
+/*
+sortWaitlistEntries sorts entries oldest first, order of RETURNING rows is not defined
+*/
+func sortWaitlistEntries(docs []types.WaitlistEntry) {
+	sort.Slice(docs, func(i, j int) bool {
+		return joinedBefore(docs[i], docs[j])
+	})
+}
+
+/*
+Update saves check result, entry cancelled meanwhile stays cancelled
+*/
+func (r *PostgreSQLWaitlistRepo) Update(ctx context.Context, doc types.WaitlistEntry) error {
+	q := `UPDATE "` + waitlistTableName + `" SET status = $2, order_id = $3, checks = $4, last_error = $5, ` +
+		`next_check_at = $6, promoted_at = $7 WHERE id = $1 AND status <> $8`
+	_, err := r.conn.ExecContext(ctx, q, doc.ID, doc.Status, doc.OrderID, doc.Checks, doc.LastError, doc.NextCheckAt,
+		doc.PromotedAt, types.WaitlistStatusCancelled)
+	return errors.Wrapf(err, `failed to exec query: q - %s, id - %s`, q, doc.ID)
+}
+
+/*
+Cancel cancels waiting entry, ErrNotFound returned when there is no waiting entry with id
+*/
+func (r *PostgreSQLWaitlistRepo) Cancel(ctx context.Context, id string) error {
+	q := `UPDATE "` + waitlistTableName + `" SET status = $2 WHERE id = $1 AND status = $3`
+	res, err := r.conn.ExecContext(ctx, q, id, types.WaitlistStatusCancelled, types.WaitlistStatusWaiting)
+	if err != nil {
+		return errors.Wrapf(err, `failed to exec query: q - %s, id - %s`, q, id)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrapf(err, `failed to get affected rows: q - %s, id - %s`, q, id)
+	}
+	if affected == 0 {
+		return types.ErrNotFound{}
+	}
+	return nil
+}
+
+/*
+RecheckLaunchpad makes waiting entries of launchpad due at provided time
+*/
+func (r *PostgreSQLWaitlistRepo) RecheckLaunchpad(ctx context.Context, launchpadID string, at time.Time) error {
+	q := `UPDATE "` + waitlistTableName + `" SET next_check_at = $3 WHERE launchpad_id = $1 AND status = $2 AND next_check_at > $3`
+	_, err := r.conn.ExecContext(ctx, q, launchpadID, types.WaitlistStatusWaiting, at)
+	return errors.Wrapf(err, `failed to exec query: q - %s, launchpad - %s`, q, launchpadID)
+}
+
+func queryWaitlistEntries(ctx context.Context, conn queryer, q string, args ...interface{}) ([]types.WaitlistEntry, error) {
+	rows, err := conn.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, errors.Wrapf(err, `failed to query rows: q - %s`, q)
+	}
+	var docs []types.WaitlistEntry
+	for rows.Next() {
+		doc := types.WaitlistEntry{}
+		var orderDoc []byte
+		var promotedAt sql.NullTime
+		if err = rows.Scan(
+			&doc.ID,
+			&orderDoc,
+			&doc.Status,
+			&doc.OrderID,
+			&doc.Checks,
+			&doc.LastError,
+			&doc.NextCheckAt,
+			&doc.CreatedAt,
+			&promotedAt,
+		); err != nil {
+			_ = rows.Close()
+			return nil, errors.Wrapf(err, `failed to scan rows: q - %s`, q)
+		}
+		if err = json.Unmarshal(orderDoc, &doc.Order); err != nil {
+			_ = rows.Close()
+			return nil, errors.Wrapf(err, `failed to unmarshal order: id - %s`, doc.ID)
+		}
+		if promotedAt.Valid {
+			doc.PromotedAt = &promotedAt.Time
+		}
+		docs = append(docs, doc)
+	}
+	if err = rows.Err(); err != nil {
+		_ = rows.Close()
+		return nil, errors.Wrapf(err, `failed to iterate rows: q - %s`, q)
+	}
+	return docs, errors.Wrapf(rows.Close(), `failed to close rows: q - %s`, q)
+}
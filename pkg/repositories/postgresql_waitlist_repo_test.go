@@ -0,0 +1,150 @@
+package repositories
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/leveldorado/space-trouble/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+type waitlistRepoUnderTest interface {
+	Insert(ctx context.Context, doc types.WaitlistEntry) error
+	Get(ctx context.Context, id string) (types.WaitlistEntry, error)
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]types.WaitlistEntry, error)
+	Update(ctx context.Context, doc types.WaitlistEntry) error
+	Cancel(ctx context.Context, id string) error
+	RecheckLaunchpad(ctx context.Context, launchpadID string, at time.Time) error
+	HasWaitingAhead(ctx context.Context, id string) (bool, error)
+}
+
+func prepareWaitlistRepo(t *testing.T) *PostgreSQLWaitlistRepo {
+	url := os.Getenv("POSTGRESQL_URL")
+	conn, err := GetPostgresqlConn(url)
+	require.NoError(t, err)
+	repo := NewPostgreSQLWaitlistRepo(conn)
+	require.NoError(t, repo.CreateTables(context.TODO()))
+	return repo
+}
+
+func TestPostgreSQLWaitlistRepo(t *testing.T) {
+	testWaitlistRepo(t, prepareWaitlistRepo(t))
+	testWaitlistRepoFlightQueue(t, prepareWaitlistRepo(t))
+}
+
+func TestInMemoryWaitlistRepo(t *testing.T) {
+	testWaitlistRepo(t, NewInMemoryWaitlistRepo())
+	testWaitlistRepoFlightQueue(t, NewInMemoryWaitlistRepo())
+}
+
+func TestSQLiteWaitlistRepo(t *testing.T) {
+	repo := NewSQLiteWaitlistRepo(prepareSQLiteConn(t))
+	require.NoError(t, repo.CreateTables(context.TODO()))
+	testWaitlistRepo(t, repo)
+	testWaitlistRepoFlightQueue(t, repo)
+}
+
+func testWaitlistRepo(t *testing.T, repo waitlistRepoUnderTest) {
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	launchpadID := uuid.New().String()
+	doc := types.WaitlistEntry{
+		ID: uuid.New().String(),
+		Order: types.Order{
+			FirstName:     "Vasyl",
+			LastName:      "Osypchuk",
+			LaunchpadID:   launchpadID,
+			DestinationID: uuid.New().String(),
+			LaunchDate:    now.Add(48 * time.Hour),
+		},
+		Status:      types.WaitlistStatusWaiting,
+		NextCheckAt: now.Add(time.Hour),
+		CreatedAt:   now,
+	}
+	require.NoError(t, repo.Insert(context.TODO(), doc))
+
+	found, err := repo.Get(context.TODO(), doc.ID)
+	require.NoError(t, err)
+	require.Equal(t, doc.Order.LaunchDate, found.Order.LaunchDate.UTC())
+	require.Equal(t, doc.Status, found.Status)
+
+	claimed, err := repo.ClaimDue(context.TODO(), now, time.Minute, 1000)
+	require.NoError(t, err)
+	for _, c := range claimed {
+		require.NotEqual(t, doc.ID, c.ID)
+	}
+
+	require.NoError(t, repo.RecheckLaunchpad(context.TODO(), launchpadID, now))
+	claimed, err = repo.ClaimDue(context.TODO(), now, time.Minute, 1000)
+	require.NoError(t, err)
+	var claimedDoc bool
+	for _, c := range claimed {
+		claimedDoc = claimedDoc || c.ID == doc.ID
+	}
+	require.True(t, claimedDoc)
+
+	require.NoError(t, repo.Cancel(context.TODO(), doc.ID))
+	require.Equal(t, types.ErrNotFound{}, repo.Cancel(context.TODO(), doc.ID))
+
+	promotedAt := now
+	doc.Status = types.WaitlistStatusPromoted
+	doc.PromotedAt = &promotedAt
+	require.NoError(t, repo.Update(context.TODO(), doc))
+	found, err = repo.Get(context.TODO(), doc.ID)
+	require.NoError(t, err)
+	require.Equal(t, types.WaitlistStatusCancelled, found.Status)
+
+	_, err = repo.Get(context.TODO(), uuid.New().String())
+	require.Equal(t, types.ErrNotFound{}, err)
+}
+
+func testWaitlistRepoFlightQueue(t *testing.T, repo waitlistRepoUnderTest) {
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	launchpadID := uuid.New().String()
+	entry := func(localDate string, createdAt time.Time) types.WaitlistEntry {
+		doc := types.WaitlistEntry{
+			ID:          uuid.New().String(),
+			Order:       types.Order{LaunchpadID: launchpadID, LaunchLocalDate: localDate, DestinationID: uuid.New().String()},
+			Status:      types.WaitlistStatusWaiting,
+			NextCheckAt: now,
+			CreatedAt:   createdAt,
+		}
+		require.NoError(t, repo.Insert(context.TODO(), doc))
+		return doc
+	}
+	// inserted out of order, queue follows time of joining
+	second := entry("2053-03-06", now.Add(-time.Minute))
+	first := entry("2053-03-06", now.Add(-2*time.Minute))
+	otherFlight := entry("2053-03-07", now)
+
+	claimedIDs := func() []string {
+		claimed, err := repo.ClaimDue(context.TODO(), now, time.Minute, 1000)
+		require.NoError(t, err)
+		var ids []string
+		for _, c := range claimed {
+			if c.Order.LaunchpadID == launchpadID {
+				ids = append(ids, c.ID)
+			}
+		}
+		return ids
+	}
+	require.Equal(t, []string{first.ID, otherFlight.ID}, claimedIDs())
+
+	ahead, err := repo.HasWaitingAhead(context.TODO(), second.ID)
+	require.NoError(t, err)
+	require.True(t, ahead)
+	ahead, err = repo.HasWaitingAhead(context.TODO(), first.ID)
+	require.NoError(t, err)
+	require.False(t, ahead)
+
+	promotedAt := now
+	first.Status = types.WaitlistStatusPromoted
+	first.PromotedAt = &promotedAt
+	require.NoError(t, repo.Update(context.TODO(), first))
+	ahead, err = repo.HasWaitingAhead(context.TODO(), second.ID)
+	require.NoError(t, err)
+	require.False(t, ahead)
+	require.Equal(t, []string{second.ID}, claimedIDs())
+}
@@ -0,0 +1,250 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/leveldorado/space-trouble/pkg/types"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	webhookSubscriptionTableName = "webhook_subscription"
+	webhookDeliveryTableName     = "webhook_delivery"
+)
+
+type PostgreSQLWebhooksRepo struct {
+	conn *sql.DB
+	log  logrus.FieldLogger
+}
+
+func NewPostgreSQLWebhooksRepo(conn *sql.DB, log logrus.FieldLogger) *PostgreSQLWebhooksRepo {
+	return &PostgreSQLWebhooksRepo{conn: conn, log: log}
+}
+
+func (r *PostgreSQLWebhooksRepo) CreateTables(ctx context.Context) error {
+	subscriptionTableCreateQuery := fmt.Sprintf(`
+CREATE TABLE IF NOT EXISTS "%s" (
+    id          uuid,
+    url         text,
+    event_types text[],
+    secret      text,
+    created_at  timestamp with time zone,
+    PRIMARY KEY(id)
+);
+`, webhookSubscriptionTableName)
+	deliveryTableCreateQuery := fmt.Sprintf(`
+CREATE TABLE IF NOT EXISTS "%s" (
+    id              uuid,
+    subscription_id uuid,
+    event_id        uuid,
+    event_type      text,
+    payload         bytea,
+    status          text,
+    attempts        int,
+    last_error      text,
+    next_attempt_at timestamp with time zone,
+    created_at      timestamp with time zone,
+    delivered_at    timestamp with time zone,
+    PRIMARY KEY(id)
+);
+CREATE INDEX IF NOT EXISTS "%[1]s_status_next_attempt_at" ON "%[1]s" (status, next_attempt_at);
+CREATE UNIQUE INDEX IF NOT EXISTS "%[1]s_event_id_subscription_id" ON "%[1]s" (event_id, subscription_id);
+`, webhookDeliveryTableName)
+	for _, q := range []string{subscriptionTableCreateQuery, deliveryTableCreateQuery} {
+		if _, err := r.conn.ExecContext(ctx, q); err != nil {
+			return errors.Wrapf(err, `failed to exec query: q - %s`, q)
+		}
+	}
+	return nil
+}
+
+func (r *PostgreSQLWebhooksRepo) InsertSubscription(ctx context.Context, doc types.WebhookSubscription) error {
+	q := `INSERT INTO "` + webhookSubscriptionTableName + `" (id, url, event_types, secret, created_at) VALUES ($1, $2, $3, $4, $5)`
+	_, err := r.conn.ExecContext(ctx, q, doc.ID, doc.URL, pq.Array(doc.EventTypes), doc.Secret, doc.CreatedAt)
+	return errors.Wrapf(err, `failed to exec query: q - %s, id - %s`, q, doc.ID)
+}
+
+const (
+	webhookSubscriptionSelectQuery = `SELECT id, url, event_types, secret, created_at FROM "` + webhookSubscriptionTableName + `" `
+)
+
+func scanWebhookSubscription(row rowScanner) (types.WebhookSubscription, error) {
+	doc := types.WebhookSubscription{}
+	err := row.Scan(&doc.ID, &doc.URL, pq.Array(&doc.EventTypes), &doc.Secret, &doc.CreatedAt)
+	return doc, err
+}
+
+func (r *PostgreSQLWebhooksRepo) GetSubscription(ctx context.Context, id string) (types.WebhookSubscription, error) {
+	q := webhookSubscriptionSelectQuery + `WHERE id = $1`
+	doc, err := scanWebhookSubscription(r.conn.QueryRowContext(ctx, q, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return types.WebhookSubscription{}, types.ErrNotFound{}
+	}
+	return doc, errors.Wrapf(err, `failed to query row: id - %s, q - %s`, id, q)
+}
+
+func (r *PostgreSQLWebhooksRepo) ListSubscriptions(ctx context.Context) ([]types.WebhookSubscription, error) {
+	q := webhookSubscriptionSelectQuery + `ORDER BY created_at`
+	rows, err := r.conn.QueryContext(ctx, q)
+	if err != nil {
+		return nil, errors.Wrapf(err, `failed to query rows: q - %s`, q)
+	}
+	var docs []types.WebhookSubscription
+	for rows.Next() {
+		doc, err := scanWebhookSubscription(rows)
+		if err != nil {
+			_ = rows.Close()
+			return nil, errors.Wrapf(err, `failed to scan rows: q - %s`, q)
+		}
+		docs = append(docs, doc)
+	}
+	if err = rows.Err(); err != nil {
+		_ = rows.Close()
+		return nil, errors.Wrapf(err, `failed to iterate rows: q - %s`, q)
+	}
+	return docs, errors.Wrapf(rows.Close(), `failed to close rows: q - %s`, q)
+}
+
+func (r *PostgreSQLWebhooksRepo) DeleteSubscription(ctx context.Context, id string) error {
+	q := `DELETE FROM "` + webhookSubscriptionTableName + `" WHERE id = $1`
+	res, err := r.conn.ExecContext(ctx, q, id)
+	if err != nil {
+		return errors.Wrapf(err, `failed to exec query: id - %s, q - %s`, id, q)
+	}
+	return notFoundIfNoRowsAffected(res)
+}
+
+func notFoundIfNoRowsAffected(res sql.Result) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, `failed to get rows affected`)
+	}
+	if n == 0 {
+		return types.ErrNotFound{}
+	}
+	return nil
+}
+
+func (r *PostgreSQLWebhooksRepo) InsertDeliveries(ctx context.Context, docs []types.WebhookDelivery) error {
+	tx, err := r.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, `failed to begin transaction`)
+	}
+	q := `INSERT INTO "` + webhookDeliveryTableName + `" ` +
+		`(id, subscription_id, event_id, event_type, payload, status, attempts, last_error, next_attempt_at, created_at, delivered_at) ` +
+		`VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) ON CONFLICT (event_id, subscription_id) DO NOTHING`
+	for _, doc := range docs {
+		if _, err = tx.ExecContext(ctx, q,
+			doc.ID,
+			doc.SubscriptionID,
+			doc.EventID,
+			doc.EventType,
+			doc.Payload,
+			doc.Status,
+			doc.Attempts,
+			doc.LastError,
+			doc.NextAttemptAt,
+			doc.CreatedAt,
+			doc.DeliveredAt,
+		); err != nil {
+			if rollbackErr := tx.Rollback(); rollbackErr != nil {
+				r.log.WithField("err", rollbackErr.Error()).Error("failed to rollback")
+			}
+			return errors.Wrapf(err, `failed to exec query: q - %s, id - %s`, q, doc.ID)
+		}
+	}
+	return errors.Wrapf(tx.Commit(), `failed to commit: count - %d`, len(docs))
+}
+
+const (
+	webhookDeliveryColumns = `id, subscription_id, event_id, event_type, payload, status, attempts, last_error, ` +
+		`next_attempt_at, created_at, delivered_at`
+)
+
+func scanWebhookDelivery(row rowScanner) (types.WebhookDelivery, error) {
+	doc := types.WebhookDelivery{}
+	var deliveredAt sql.NullTime
+	err := row.Scan(
+		&doc.ID,
+		&doc.SubscriptionID,
+		&doc.EventID,
+		&doc.EventType,
+		&doc.Payload,
+		&doc.Status,
+		&doc.Attempts,
+		&doc.LastError,
+		&doc.NextAttemptAt,
+		&doc.CreatedAt,
+		&deliveredAt,
+	)
+	if deliveredAt.Valid {
+		doc.DeliveredAt = &deliveredAt.Time
+	}
+	return doc, err
+}
+
+func queryWebhookDeliveries(ctx context.Context, conn queryer, q string, args ...interface{}) ([]types.WebhookDelivery, error) {
+	rows, err := conn.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, errors.Wrapf(err, `failed to query rows: q - %s`, q)
+	}
+	var docs []types.WebhookDelivery
+	for rows.Next() {
+		doc, err := scanWebhookDelivery(rows)
+		if err != nil {
+			_ = rows.Close()
+			return nil, errors.Wrapf(err, `failed to scan rows: q - %s`, q)
+		}
+		docs = append(docs, doc)
+	}
+	if err = rows.Err(); err != nil {
+		_ = rows.Close()
+		return nil, errors.Wrapf(err, `failed to iterate rows: q - %s`, q)
+	}
+	return docs, errors.Wrapf(rows.Close(), `failed to close rows: q - %s`, q)
+}
+
+/*
+ClaimDueDeliveries returns pending deliveries which next attempt time passed.
+
+	claimed deliveries get next attempt moved by lease so other replicas do not send them at the same time
+*/
+func (r *PostgreSQLWebhooksRepo) ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]types.WebhookDelivery, error) {
+	q := `UPDATE "` + webhookDeliveryTableName + `" SET next_attempt_at = $1 WHERE id IN (` +
+		`SELECT id FROM "` + webhookDeliveryTableName + `" WHERE status = $2 AND next_attempt_at <= $3 ` +
+		`ORDER BY next_attempt_at LIMIT $4 FOR UPDATE SKIP LOCKED) RETURNING ` + webhookDeliveryColumns
+	return queryWebhookDeliveries(ctx, r.conn, q, now.Add(lease), types.WebhookDeliveryStatusPending, now, limit)
+}
+
+func (r *PostgreSQLWebhooksRepo) UpdateDelivery(ctx context.Context, doc types.WebhookDelivery) error {
+	q := `UPDATE "` + webhookDeliveryTableName + `" SET status = $2, attempts = $3, last_error = $4, ` +
+		`next_attempt_at = $5, delivered_at = $6 WHERE id = $1`
+	res, err := r.conn.ExecContext(ctx, q, doc.ID, doc.Status, doc.Attempts, doc.LastError, doc.NextAttemptAt, doc.DeliveredAt)
+	if err != nil {
+		return errors.Wrapf(err, `failed to exec query: q - %s, id - %s`, q, doc.ID)
+	}
+	return notFoundIfNoRowsAffected(res)
+}
+
+func (r *PostgreSQLWebhooksRepo) GetDelivery(ctx context.Context, id string) (types.WebhookDelivery, error) {
+	q := `SELECT ` + webhookDeliveryColumns + ` FROM "` + webhookDeliveryTableName + `" WHERE id = $1`
+	doc, err := scanWebhookDelivery(r.conn.QueryRowContext(ctx, q, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return types.WebhookDelivery{}, types.ErrNotFound{}
+	}
+	return doc, errors.Wrapf(err, `failed to query row: id - %s, q - %s`, id, q)
+}
+
+/*
+ListDeliveries returns deliveries with provided status, all deliveries for empty status. newest first
+*/
+func (r *PostgreSQLWebhooksRepo) ListDeliveries(ctx context.Context, status string, limit, offset int) ([]types.WebhookDelivery, error) {
+	q := `SELECT ` + webhookDeliveryColumns + ` FROM "` + webhookDeliveryTableName + `" ` +
+		`WHERE ($1 = '' OR status = $1) ORDER BY created_at DESC ` + fmt.Sprintf(`LIMIT %d OFFSET %d`, limit, offset)
+	return queryWebhookDeliveries(ctx, r.conn, q, status)
+}
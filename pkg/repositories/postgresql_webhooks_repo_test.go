@@ -0,0 +1,137 @@
+package repositories
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/leveldorado/space-trouble/pkg/tools/logger"
+	"github.com/leveldorado/space-trouble/pkg/types"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+type webhooksRepoUnderTest interface {
+	InsertSubscription(ctx context.Context, doc types.WebhookSubscription) error
+	GetSubscription(ctx context.Context, id string) (types.WebhookSubscription, error)
+	ListSubscriptions(ctx context.Context) ([]types.WebhookSubscription, error)
+	DeleteSubscription(ctx context.Context, id string) error
+	InsertDeliveries(ctx context.Context, docs []types.WebhookDelivery) error
+	ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]types.WebhookDelivery, error)
+	UpdateDelivery(ctx context.Context, doc types.WebhookDelivery) error
+	GetDelivery(ctx context.Context, id string) (types.WebhookDelivery, error)
+	ListDeliveries(ctx context.Context, status string, limit, offset int) ([]types.WebhookDelivery, error)
+}
+
+func prepareWebhooksRepo(t *testing.T) *PostgreSQLWebhooksRepo {
+	url := os.Getenv("POSTGRESQL_URL")
+	conn, err := GetPostgresqlConn(url)
+	require.NoError(t, err)
+	repo := NewPostgreSQLWebhooksRepo(conn, logger.New())
+	require.NoError(t, repo.CreateTables(context.TODO()))
+	return repo
+}
+
+func TestPostgreSQLWebhooksRepo_Subscriptions(t *testing.T) {
+	testWebhooksRepoSubscriptions(t, prepareWebhooksRepo(t))
+}
+
+func TestInMemoryWebhooksRepo_Subscriptions(t *testing.T) {
+	testWebhooksRepoSubscriptions(t, NewInMemoryWebhooksRepo())
+}
+
+func TestSQLiteWebhooksRepo_Subscriptions(t *testing.T) {
+	testWebhooksRepoSubscriptions(t, prepareSQLiteWebhooksRepo(t))
+}
+
+func prepareSQLiteWebhooksRepo(t *testing.T) *SQLiteWebhooksRepo {
+	repo := NewSQLiteWebhooksRepo(prepareSQLiteConn(t), logger.New())
+	require.NoError(t, repo.CreateTables(context.TODO()))
+	return repo
+}
+
+func testWebhooksRepoSubscriptions(t *testing.T, repo webhooksRepoUnderTest) {
+	sub := types.WebhookSubscription{
+		ID:         uuid.New().String(),
+		URL:        "https://example.com/hook",
+		EventTypes: []string{types.OrderEventCreated, types.OrderEventCancelled},
+		Secret:     "secret",
+		CreatedAt:  time.Now().UTC().Truncate(time.Millisecond),
+	}
+	require.NoError(t, repo.InsertSubscription(context.TODO(), sub))
+	fromDB, err := repo.GetSubscription(context.TODO(), sub.ID)
+	require.NoError(t, err)
+	fromDB.CreatedAt = fromDB.CreatedAt.UTC()
+	require.Equal(t, sub, fromDB)
+
+	list, err := repo.ListSubscriptions(context.TODO())
+	require.NoError(t, err)
+	require.NotEmpty(t, list)
+
+	require.NoError(t, repo.DeleteSubscription(context.TODO(), sub.ID))
+	_, err = repo.GetSubscription(context.TODO(), sub.ID)
+	require.True(t, errors.As(err, &types.ErrNotFound{}))
+	require.True(t, errors.As(repo.DeleteSubscription(context.TODO(), sub.ID), &types.ErrNotFound{}))
+}
+
+func TestPostgreSQLWebhooksRepo_Deliveries(t *testing.T) {
+	testWebhooksRepoDeliveries(t, prepareWebhooksRepo(t))
+}
+
+func TestInMemoryWebhooksRepo_Deliveries(t *testing.T) {
+	testWebhooksRepoDeliveries(t, NewInMemoryWebhooksRepo())
+}
+
+func TestSQLiteWebhooksRepo_Deliveries(t *testing.T) {
+	testWebhooksRepoDeliveries(t, prepareSQLiteWebhooksRepo(t))
+}
+
+func testWebhooksRepoDeliveries(t *testing.T, repo webhooksRepoUnderTest) {
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	d := types.WebhookDelivery{
+		ID:             uuid.New().String(),
+		SubscriptionID: uuid.New().String(),
+		EventID:        uuid.New().String(),
+		EventType:      types.OrderEventCreated,
+		Payload:        []byte(`{}`),
+		Status:         types.WebhookDeliveryStatusPending,
+		NextAttemptAt:  now.Add(-time.Second),
+		CreatedAt:      now,
+	}
+	require.NoError(t, repo.InsertDeliveries(context.TODO(), []types.WebhookDelivery{d}))
+
+	claimed, err := repo.ClaimDueDeliveries(context.TODO(), now, time.Minute, 1000)
+	require.NoError(t, err)
+	var found bool
+	for _, c := range claimed {
+		if c.ID == d.ID {
+			found = true
+			require.Equal(t, now.Add(time.Minute), c.NextAttemptAt.UTC())
+		}
+	}
+	require.True(t, found)
+	claimed, err = repo.ClaimDueDeliveries(context.TODO(), now, time.Minute, 1000)
+	require.NoError(t, err)
+	for _, c := range claimed {
+		require.NotEqual(t, d.ID, c.ID)
+	}
+
+	d.Status = types.WebhookDeliveryStatusDead
+	d.Attempts = 8
+	d.LastError = "fail"
+	require.NoError(t, repo.UpdateDelivery(context.TODO(), d))
+	fromDB, err := repo.GetDelivery(context.TODO(), d.ID)
+	require.NoError(t, err)
+	require.Equal(t, types.WebhookDeliveryStatusDead, fromDB.Status)
+	require.Equal(t, 8, fromDB.Attempts)
+	require.Nil(t, fromDB.DeliveredAt)
+
+	dead, err := repo.ListDeliveries(context.TODO(), types.WebhookDeliveryStatusDead, 1000, 0)
+	require.NoError(t, err)
+	require.NotEmpty(t, dead)
+	for _, doc := range dead {
+		require.Equal(t, types.WebhookDeliveryStatusDead, doc.Status)
+	}
+}
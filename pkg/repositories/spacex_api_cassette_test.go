@@ -0,0 +1,87 @@
+package repositories
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/leveldorado/space-trouble/pkg/tools/cassette"
+	"github.com/leveldorado/space-trouble/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+/*
+prepareSpaceXCassette replays SpaceX API interactions of test from testdata/cassettes.
+
+	SPACEX_CASSETTE_MODE=record records them again from SPACEX_API_URL (live API by default).
+	tests use fixed dates, so recording again gives the same requests. every recorded interaction has to be
+	replayed, so cassette can't have requests which test doesn't do
+*/
+func prepareSpaceXCassette(t *testing.T) (*SpaceXClient, string) {
+	mode := os.Getenv("SPACEX_CASSETTE_MODE")
+	if mode == "" {
+		mode = cassette.ModeReplay
+	}
+	baseURL := os.Getenv("SPACEX_API_URL")
+	if baseURL == "" {
+		baseURL = DefaultSpaceXAPIURL
+	}
+	tr, err := cassette.NewTransport(filepath.Join("testdata", "cassettes", t.Name()+".json"), mode, nil)
+	require.NoError(t, err)
+	if mode == cassette.ModeReplay {
+		t.Cleanup(func() {
+			require.Empty(t, tr.Unused(), "cassette has interactions test didn't do")
+		})
+	}
+	return NewSpaceXClient(&http.Client{Transport: tr, Timeout: 10 * time.Second}, DefaultSpaceXClientConfig()), baseURL
+}
+
+func TestSpaceXAPICassette_Launchpads(t *testing.T) {
+	r := NewSpaceXAPILaunchpadsRepo(prepareSpaceXCassette(t))
+	losAngelesTimezone, err := time.LoadLocation("America/Los_Angeles")
+	require.NoError(t, err)
+
+	pad, err := r.Get(context.TODO(), "5e9e4501f5090910d4566f83")
+	require.NoError(t, err)
+	require.Equal(t, types.Launchpad{
+		ID:       "5e9e4501f5090910d4566f83",
+		FullName: "Vandenberg Space Force Base Space Launch Complex 3W",
+		Location: losAngelesTimezone,
+		Status:   "retired",
+	}, pad)
+
+	launchpads, err := r.List(context.TODO())
+	require.NoError(t, err)
+	require.NotEmpty(t, launchpads)
+	for i, l := range launchpads {
+		require.NotNil(t, l.Location)
+		require.NotEmpty(t, l.Status)
+		if i > 0 {
+			require.LessOrEqual(t, launchpads[i-1].FullName, l.FullName)
+		}
+	}
+}
+
+func TestSpaceXAPICassette_Launches(t *testing.T) {
+	r := NewSpaceXAPILaunchesRepo(prepareSpaceXCassette(t))
+	launchpad := "5e9e4502f509092b78566f87"
+
+	exists, err := r.CheckLaunches(context.TODO(), launchpad, time.Date(2022, 8, 26, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.True(t, exists)
+	exists, err = r.CheckLaunches(context.TODO(), launchpad, time.Date(2022, 8, 28, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	from := time.Date(2022, 8, 20, 0, 0, 0, 0, time.UTC)
+	launches, err := r.ListLaunches(context.TODO(), launchpad, from, from.AddDate(0, 0, 14))
+	require.NoError(t, err)
+	require.NotEmpty(t, launches)
+	for _, l := range launches {
+		require.Equal(t, launchpad, l.Launchpad)
+		require.False(t, l.DateUTC.Before(from))
+	}
+}
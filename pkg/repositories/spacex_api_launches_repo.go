@@ -4,25 +4,30 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
-	"io"
 	"net/http"
+	"strings"
 	"time"
 
+	"github.com/leveldorado/space-trouble/pkg/types"
 	"github.com/tidwall/gjson"
 
 	"github.com/pkg/errors"
 )
 
 const (
-	launchesURL = "https://api.spacexdata.com/v5/launches/query"
+	launchesPath = "/v5/launches/query"
 )
 
 type SpaceXAPILaunchesRepo struct {
-	cl *http.Client
+	cl          *SpaceXClient
+	launchesURL string
 }
 
-func NewSpaceXAPILaunchesRepo(cl *http.Client) *SpaceXAPILaunchesRepo {
-	return &SpaceXAPILaunchesRepo{cl: cl}
+/*
+NewSpaceXAPILaunchesRepo creates repo querying SpaceX API at base url like DefaultSpaceXAPIURL
+*/
+func NewSpaceXAPILaunchesRepo(cl *SpaceXClient, baseURL string) *SpaceXAPILaunchesRepo {
+	return &SpaceXAPILaunchesRepo{cl: cl, launchesURL: strings.TrimSuffix(baseURL, "/") + launchesPath}
 }
 
 type queryRequestPayload struct {
@@ -41,26 +46,72 @@ func (r *SpaceXAPILaunchesRepo) CheckLaunches(ctx context.Context, launchpad str
 	if err != nil {
 		return false, errors.Wrapf(err, `failed to prepare payload: launchpad - %s, date - %s`, launchpad, localDate)
 	}
-	req, err := http.NewRequest(http.MethodPost, launchesURL, b)
+	code, data, err := r.cl.Query(ctx, r.launchesURL, b.Bytes())
 	if err != nil {
-		return false, errors.Wrapf(err, `failed to create request: url - %s, payload - %s`, launchesURL, b)
+		return false, errors.Wrapf(err, `failed to perform request: url - %s, payload - %s`, r.launchesURL, b)
 	}
-	req.Header.Set("Content-Type", "application/json")
-	req = req.WithContext(ctx)
-	resp, err := r.cl.Do(req)
-	if err != nil {
-		return false, errors.Wrapf(err, `failed to perform request: url - %s, payload - %s`, launchesURL, b)
+	if code != http.StatusOK {
+		return false, errors.Errorf(`received non success code: code - %d, response - %s`, code, data)
 	}
-	data, err := io.ReadAll(resp.Body)
-	_ = resp.Body.Close()
+	docs := gjson.GetBytes(data, "docs").Array()
+	return len(docs) > 0, nil
+}
+
+/*
+ListLaunches returns launches from launchpad with UTC date in [from, to) range
+*/
+func (r *SpaceXAPILaunchesRepo) ListLaunches(ctx context.Context, launchpad string, from, to time.Time) ([]types.Launch, error) {
+	return r.queryLaunches(ctx, map[string]interface{}{
+		"date_utc": map[string]interface{}{
+			"$gte": from.UTC(),
+			"$lt":  to.UTC(),
+		},
+		"launchpad": launchpad,
+	})
+}
+
+/*
+ListAllLaunches returns launches from all launchpads with UTC date in [from, to) range
+*/
+func (r *SpaceXAPILaunchesRepo) ListAllLaunches(ctx context.Context, from, to time.Time) ([]types.Launch, error) {
+	return r.queryLaunches(ctx, map[string]interface{}{
+		"date_utc": map[string]interface{}{
+			"$gte": from.UTC(),
+			"$lt":  to.UTC(),
+		},
+	})
+}
+
+func (r *SpaceXAPILaunchesRepo) queryLaunches(ctx context.Context, query map[string]interface{}) ([]types.Launch, error) {
+	p := queryRequestPayload{
+		Query: query,
+		Options: map[string]interface{}{
+			"select": map[string]int{
+				"id":         1,
+				"date_utc":   1,
+				"date_local": 1,
+				"launchpad":  1,
+			},
+			"sort":       map[string]string{"date_utc": "asc"},
+			"pagination": false,
+		},
+	}
+	b := &bytes.Buffer{}
+	if err := json.NewEncoder(b).Encode(p); err != nil {
+		return nil, errors.Wrapf(err, `failed to marshal payload: p - %+v`, p)
+	}
+	code, data, err := r.cl.Query(ctx, r.launchesURL, b.Bytes())
 	if err != nil {
-		return false, errors.Wrapf(err, `failed to read response`)
+		return nil, errors.Wrapf(err, `failed to perform request: url - %s`, r.launchesURL)
 	}
-	if resp.StatusCode != http.StatusOK {
-		return false, errors.Errorf(`received non success code: code - %s, response - %s`, resp.Status, data)
+	if code != http.StatusOK {
+		return nil, errors.Errorf(`received non success code: code - %d, response - %s`, code, data)
 	}
-	docs := gjson.GetBytes(data, "docs").Array()
-	return len(docs) > 0, nil
+	var launches []types.Launch
+	if err = json.Unmarshal([]byte(gjson.GetBytes(data, "docs").Raw), &launches); err != nil {
+		return nil, errors.Wrapf(err, `failed to unmarshal launches: response - %s`, data)
+	}
+	return launches, nil
 }
 
 func preparePayload(localDate time.Time, launchpad string) (*bytes.Buffer, error) {
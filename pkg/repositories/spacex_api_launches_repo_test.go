@@ -3,19 +3,27 @@ package repositories
 import (
 	"context"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
+	"github.com/leveldorado/space-trouble/pkg/tools/spacexstub"
 	"github.com/stretchr/testify/require"
 )
 
 /*
-Normally for testing external API in unit tests - should be used mock of http.Transport.
-
-	so tests do not exceed requests quota
+prepareSpaceXStub starts SpaceX API stand-in serving bundled fixtures, so tests don't depend on live data and quota
 */
+func prepareSpaceXStub(t *testing.T) (*SpaceXClient, string) {
+	f, err := spacexstub.ReadFixtures("")
+	require.NoError(t, err)
+	srv := httptest.NewServer(spacexstub.NewHandler(f))
+	t.Cleanup(srv.Close)
+	return NewSpaceXClient(http.DefaultClient, DefaultSpaceXClientConfig()), srv.URL
+}
+
 func TestSpaceXAPILaunchesRepo_CheckLaunches(t *testing.T) {
-	r := NewSpaceXAPILaunchesRepo(http.DefaultClient)
+	r := NewSpaceXAPILaunchesRepo(prepareSpaceXStub(t))
 	launchpad := "5e9e4502f509092b78566f87"
 	busyDay := time.Date(2022, 8, 26, 0, 0, 0, 0, time.UTC)
 	notBusyDay := time.Date(2022, 8, 28, 0, 0, 0, 0, time.UTC)
@@ -28,3 +36,22 @@ func TestSpaceXAPILaunchesRepo_CheckLaunches(t *testing.T) {
 	require.NoError(t, err)
 	require.False(t, exists)
 }
+
+func TestSpaceXAPILaunchesRepo_ListLaunches(t *testing.T) {
+	r := NewSpaceXAPILaunchesRepo(prepareSpaceXStub(t))
+	launchpad := "5e9e4502f509092b78566f87"
+	from := time.Date(2022, 8, 20, 0, 0, 0, 0, time.UTC)
+
+	launches, err := r.ListLaunches(context.TODO(), launchpad, from, from.AddDate(0, 0, 14))
+	require.NoError(t, err)
+	require.Len(t, launches, 2)
+	for _, l := range launches {
+		require.Equal(t, launchpad, l.Launchpad)
+		require.False(t, l.DateUTC.Before(from))
+	}
+	require.True(t, launches[0].DateUTC.Before(launches[1].DateUTC))
+
+	launches, err = r.ListAllLaunches(context.TODO(), from, from.AddDate(0, 0, 14))
+	require.NoError(t, err)
+	require.Len(t, launches, 3)
+}
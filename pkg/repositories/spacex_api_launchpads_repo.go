@@ -4,9 +4,9 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
-	"io"
 	"net/http"
 	"net/url"
+	"strings"
 	"time"
 
 	"github.com/leveldorado/space-trouble/pkg/types"
@@ -15,16 +15,22 @@ import (
 )
 
 const (
-	launchpadURLPrefix = "https://api.spacexdata.com/v4/launchpads/"
-	launchpadsURL      = "https://api.spacexdata.com/v4/launchpads/query"
+	DefaultSpaceXAPIURL = "https://api.spacexdata.com"
+
+	launchpadPathPrefix = "/v4/launchpads/"
+	launchpadsPath      = "/v4/launchpads/query"
 )
 
 type SpaceXAPILaunchpadsRepo struct {
-	cl *http.Client
+	cl      *SpaceXClient
+	baseURL string
 }
 
-func NewSpaceXAPILaunchpadsRepo(cl *http.Client) *SpaceXAPILaunchpadsRepo {
-	return &SpaceXAPILaunchpadsRepo{cl: cl}
+/*
+NewSpaceXAPILaunchpadsRepo creates repo querying SpaceX API at base url like DefaultSpaceXAPIURL
+*/
+func NewSpaceXAPILaunchpadsRepo(cl *SpaceXClient, baseURL string) *SpaceXAPILaunchpadsRepo {
+	return &SpaceXAPILaunchpadsRepo{cl: cl, baseURL: strings.TrimSuffix(baseURL, "/")}
 }
 
 /*
@@ -34,29 +40,19 @@ Get returns launchpad for provided id.
 	             in given implementation it's skipped
 */
 func (r *SpaceXAPILaunchpadsRepo) Get(ctx context.Context, id string) (types.Launchpad, error) {
-	u, err := url.Parse(launchpadURLPrefix + id)
+	u, err := url.Parse(r.baseURL + launchpadPathPrefix + url.PathEscape(id))
 	if err != nil {
 		return types.Launchpad{}, err
 	}
-	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
-	if err != nil {
-		return types.Launchpad{}, errors.Wrapf(err, `failed to create request: url - %s`, u)
-	}
-	req = req.WithContext(ctx)
-	resp, err := r.cl.Do(req)
+	code, data, err := r.cl.Get(ctx, u.String())
 	if err != nil {
 		return types.Launchpad{}, errors.Wrapf(err, `failed to do request: url - %s`, u)
 	}
-	data, err := io.ReadAll(resp.Body)
-	_ = resp.Body.Close()
-	if err != nil {
-		return types.Launchpad{}, errors.Wrap(err, `failed to read body`)
-	}
-	if resp.StatusCode == http.StatusNotFound {
+	if code == http.StatusNotFound {
 		return types.Launchpad{}, types.ErrNotFound{}
 	}
-	if resp.StatusCode != http.StatusOK {
-		return types.Launchpad{}, errors.Errorf(`non success response code: code - %s, boody - %s`, resp.Status, data)
+	if code != http.StatusOK {
+		return types.Launchpad{}, errors.Errorf(`non success response code: code - %d, boody - %s`, code, data)
 	}
 	return parseLaunchpad(string(data))
 }
@@ -73,6 +69,9 @@ func parseLaunchpad(data string) (types.Launchpad, error) {
 	return pad, errors.Wrapf(err, `failed to load location: timezone - %s`, timezone)
 }
 
+/*
+List returns all launchpads including retired and under construction ones, callers filter by status
+*/
 func (r *SpaceXAPILaunchpadsRepo) List(ctx context.Context) ([]types.Launchpad, error) {
 	var launchpads []types.Launchpad
 	const limit = 10
@@ -95,24 +94,13 @@ func (r *SpaceXAPILaunchpadsRepo) queryList(ctx context.Context, limit, offset i
 	if err != nil {
 		return nil, 0, errors.Wrapf(err, `failed to prepare paylaod: limit - %d, offset - %d`, limit, offset)
 	}
-	req, err := http.NewRequest(http.MethodPost, launchpadsURL, b)
+	u := r.baseURL + launchpadsPath
+	code, data, err := r.cl.Query(ctx, u, b.Bytes())
 	if err != nil {
-		return nil, 0, errors.Wrapf(err, `failed to create request: url - %s, payload - %s`, launchpadsURL, b)
+		return nil, 0, errors.Wrapf(err, `failed to do request: url - %s, payload - %s`, u, b)
 	}
-	req.Header.Set("Content-Type", "application/json")
-	req = req.WithContext(ctx)
-
-	resp, err := r.cl.Do(req)
-	if err != nil {
-		return nil, 0, errors.Wrapf(err, `failed to do request: url - %s, payload - %s`, launchpadsURL, b)
-	}
-	data, err := io.ReadAll(resp.Body)
-	_ = resp.Body.Close()
-	if err != nil {
-		return nil, 0, errors.Wrapf(err, `failed to read response`)
-	}
-	if resp.StatusCode != http.StatusOK {
-		return nil, 0, errors.Errorf(`received non success code: code - %s, response - %s`, resp.Status, data)
+	if code != http.StatusOK {
+		return nil, 0, errors.Errorf(`received non success code: code - %d, response - %s`, code, data)
 	}
 	docs := gjson.GetBytes(data, "docs").Array()
 	totalDocs := int(gjson.GetBytes(data, "totalDocs").Int())
@@ -129,13 +117,12 @@ func (r *SpaceXAPILaunchpadsRepo) queryList(ctx context.Context, limit, offset i
 
 func prepareLaunchpadsPayload(limit, offset int) (*bytes.Buffer, error) {
 	req := queryRequestPayload{
-		Query: map[string]interface{}{
-			"status": "active",
-		},
+		Query: map[string]interface{}{},
 		Options: map[string]interface{}{
 			"select": map[string]int{
 				"timezone":  1,
 				"full_name": 1,
+				"status":    1,
 			},
 			"limit":  limit,
 			"offset": offset,
@@ -2,21 +2,16 @@ package repositories
 
 import (
 	"context"
-	"net/http"
 	"testing"
 	"time"
 
 	"github.com/leveldorado/space-trouble/pkg/types"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
 )
 
-/*
-Normally for testing external API in unit tests - should be used mock of http.Transport.
-
-	so tests do not exceed requests quota
-*/
 func TestSpaceXAPILaunchpadsRepo_Get(t *testing.T) {
-	r := NewSpaceXAPILaunchpadsRepo(http.DefaultClient)
+	r := NewSpaceXAPILaunchpadsRepo(prepareSpaceXStub(t))
 	padID := "5e9e4501f5090910d4566f83"
 	losAngelesTimezone, err := time.LoadLocation("America/Los_Angeles")
 	require.NoError(t, err)
@@ -30,6 +25,9 @@ func TestSpaceXAPILaunchpadsRepo_Get(t *testing.T) {
 		Location: losAngelesTimezone,
 		Status:   "retired",
 	}, pad)
+
+	_, err = r.Get(context.TODO(), "unknown")
+	require.True(t, errors.As(err, &types.ErrNotFound{}))
 }
 
 func TestSpaceXAPILaunchpadsRepo_List(t *testing.T) {
@@ -37,24 +35,49 @@ func TestSpaceXAPILaunchpadsRepo_List(t *testing.T) {
 	require.NoError(t, err)
 	losAndgelestime, err := time.LoadLocation("America/Los_Angeles")
 	require.NoError(t, err)
+	kwajaleinTime, err := time.LoadLocation("Pacific/Kwajalein")
+	require.NoError(t, err)
+	chicagoTime, err := time.LoadLocation("America/Chicago")
+	require.NoError(t, err)
 	expected := []types.Launchpad{
 		{
 			ID:       "5e9e4501f509094ba4566f84",
 			FullName: "Cape Canaveral Space Force Station Space Launch Complex 40",
 			Location: newYorkTime,
+			Status:   "active",
 		},
 		{
 			ID:       "5e9e4502f509094188566f88",
 			FullName: "Kennedy Space Center Historic Launch Complex 39A",
 			Location: newYorkTime,
+			Status:   "active",
+		},
+		{
+			ID:       "5e9e4502f5090995de566f86",
+			FullName: "Kwajalein Atoll Omelek Island",
+			Location: kwajaleinTime,
+			Status:   "retired",
+		},
+		{
+			ID:       "5e9e4502f5090927f8566f85",
+			FullName: "SpaceX South Texas Launch Site",
+			Location: chicagoTime,
+			Status:   "under construction",
+		},
+		{
+			ID:       "5e9e4501f5090910d4566f83",
+			FullName: "Vandenberg Space Force Base Space Launch Complex 3W",
+			Location: losAndgelestime,
+			Status:   "retired",
 		},
 		{
 			ID:       "5e9e4502f509092b78566f87",
 			FullName: "Vandenberg Space Force Base Space Launch Complex 4E",
 			Location: losAndgelestime,
+			Status:   "active",
 		},
 	}
-	r := NewSpaceXAPILaunchpadsRepo(http.DefaultClient)
+	r := NewSpaceXAPILaunchpadsRepo(prepareSpaceXStub(t))
 	resp, err := r.List(context.TODO())
 	require.NoError(t, err)
 	require.Equal(t, expected, resp)
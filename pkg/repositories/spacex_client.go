@@ -0,0 +1,242 @@
+package repositories
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/leveldorado/space-trouble/pkg/types"
+	"github.com/pkg/errors"
+)
+
+const spacexServiceName = "spacex api"
+
+type SpaceXClientConfig struct {
+	// Retries is number of repeated attempts of idempotent request after the first one
+	Retries int
+	// Backoff before first retry, doubled for every next one and jittered
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+	// FailureThreshold consecutive failed attempts open circuit breaker
+	FailureThreshold int
+	// OpenTimeout is how long open circuit breaker rejects requests before probing
+	OpenTimeout time.Duration
+}
+
+func DefaultSpaceXClientConfig() SpaceXClientConfig {
+	return SpaceXClientConfig{
+		Retries:          2,
+		Backoff:          100 * time.Millisecond,
+		MaxBackoff:       2 * time.Second,
+		FailureThreshold: 5,
+		OpenTimeout:      30 * time.Second,
+	}
+}
+
+/*
+SpaceXClient is http client shared by SpaceX API repos.
+
+	network errors, 429, 502, 503 and 504 responses are failures. Idempotent requests are retried on failure
+	with jittered exponential backoff, Retry-After of response is honored when it fits into max backoff.
+	consecutive failures open circuit breaker, while it's open requests fail right away,
+	after open timeout one probe request is let through and its result closes or opens breaker again.
+	failure left after retries is returned as types.ErrUnavailable
+*/
+type SpaceXClient struct {
+	cl      *http.Client
+	c       SpaceXClientConfig
+	breaker *circuitBreaker
+}
+
+func NewSpaceXClient(cl *http.Client, c SpaceXClientConfig) *SpaceXClient {
+	return &SpaceXClient{cl: cl, c: c, breaker: newCircuitBreaker(c.FailureThreshold, c.OpenTimeout)}
+}
+
+/*
+Get returns response code and body of GET request
+*/
+func (c *SpaceXClient) Get(ctx context.Context, url string) (int, []byte, error) {
+	return c.do(ctx, http.MethodGet, url, nil, true)
+}
+
+/*
+Query posts JSON query, queries only read data so they are retried as idempotent
+*/
+func (c *SpaceXClient) Query(ctx context.Context, url string, payload []byte) (int, []byte, error) {
+	return c.do(ctx, http.MethodPost, url, payload, true)
+}
+
+func (c *SpaceXClient) do(ctx context.Context, method, url string, payload []byte, idempotent bool) (int, []byte, error) {
+	for attempt := 0; ; attempt++ {
+		if !c.breaker.allow() {
+			return 0, nil, errors.Wrapf(types.ErrUnavailable{Service: spacexServiceName}, `circuit breaker is open: url - %s`, url)
+		}
+		code, data, retryAfter, err := c.attempt(ctx, method, url, payload)
+		if ctx.Err() != nil {
+			c.breaker.release()
+			return 0, nil, errors.Wrapf(ctx.Err(), `request cancelled: url - %s`, url)
+		}
+		failed := err != nil || isUnavailableCode(code)
+		c.breaker.record(!failed)
+		if !failed {
+			return code, data, nil
+		}
+		if err == nil {
+			err = errors.Errorf(`received unavailable code: code - %d, response - %s`, code, data)
+		}
+		wait := c.backoff(attempt)
+		if retryAfter > 0 {
+			wait = retryAfter
+		}
+		if !idempotent || attempt >= c.c.Retries || wait > c.c.MaxBackoff {
+			return 0, nil, errors.Wrapf(types.ErrUnavailable{Service: spacexServiceName},
+				`failed after %d attempts: url - %s, err - %s`, attempt+1, url, err)
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return 0, nil, errors.Wrapf(ctx.Err(), `request cancelled: url - %s`, url)
+		case <-timer.C:
+		}
+	}
+}
+
+func (c *SpaceXClient) attempt(ctx context.Context, method, url string, payload []byte) (int, []byte, time.Duration, error) {
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return 0, nil, 0, errors.Wrapf(err, `failed to create request: url - %s`, url)
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := c.cl.Do(req)
+	if err != nil {
+		return 0, nil, 0, errors.Wrapf(err, `failed to do request: url - %s, payload - %s`, url, payload)
+	}
+	data, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return 0, nil, 0, errors.Wrap(err, `failed to read body`)
+	}
+	return resp.StatusCode, data, parseRetryAfter(resp.Header.Get("Retry-After")), nil
+}
+
+/*
+backoff doubles base backoff for every attempt up to max backoff, half of it is random to spread retries of concurrent requests
+*/
+func (c *SpaceXClient) backoff(attempt int) time.Duration {
+	d := c.c.Backoff << attempt
+	if d > c.c.MaxBackoff || d <= 0 {
+		d = c.c.MaxBackoff
+	}
+	half := int64(d / 2)
+	if half <= 0 {
+		return d
+	}
+	return time.Duration(half + rand.Int63n(half))
+}
+
+func isUnavailableCode(code int) bool {
+	switch code {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+/*
+parseRetryAfter supports both delay seconds and http date forms, zero when header is missing or invalid
+*/
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	date, err := http.ParseTime(value)
+	if err != nil {
+		return 0
+	}
+	if d := time.Until(date); d > 0 {
+		return d
+	}
+	return 0
+}
+
+const (
+	breakerClosed = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+type circuitBreaker struct {
+	mu          sync.Mutex
+	threshold   int
+	openTimeout time.Duration
+	state       int
+	failures    int
+	openedAt    time.Time
+	probing     bool
+}
+
+func newCircuitBreaker(threshold int, openTimeout time.Duration) *circuitBreaker {
+	return &circuitBreaker{threshold: threshold, openTimeout: openTimeout}
+}
+
+/*
+allow reports whether request can be done, in half open state only one probe request is allowed at a time
+*/
+func (b *circuitBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == breakerOpen {
+		if time.Since(b.openedAt) < b.openTimeout {
+			return false
+		}
+		b.state = breakerHalfOpen
+	}
+	if b.state == breakerHalfOpen {
+		if b.probing {
+			return false
+		}
+		b.probing = true
+	}
+	return true
+}
+
+func (b *circuitBreaker) record(success bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if success {
+		b.state = breakerClosed
+		b.failures = 0
+		b.probing = false
+		return
+	}
+	b.failures++
+	if b.state == breakerHalfOpen || b.failures >= b.threshold {
+		b.state = breakerOpen
+		b.openedAt = time.Now()
+		b.probing = false
+	}
+}
+
+/*
+release frees probe of cancelled request without changing state
+*/
+func (b *circuitBreaker) release() {
+	b.mu.Lock()
+	b.probing = false
+	b.mu.Unlock()
+}
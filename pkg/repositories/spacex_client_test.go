@@ -0,0 +1,124 @@
+package repositories
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/leveldorado/space-trouble/pkg/types"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func testSpaceXClientConfig() SpaceXClientConfig {
+	return SpaceXClientConfig{
+		Retries:          2,
+		Backoff:          time.Millisecond,
+		MaxBackoff:       2 * time.Second,
+		FailureThreshold: 10,
+		OpenTimeout:      time.Minute,
+	}
+}
+
+func respondCodes(codes ...int) (*httptest.Server, *int32) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		i := int(atomic.AddInt32(&calls, 1)) - 1
+		code := codes[len(codes)-1]
+		if i < len(codes) {
+			code = codes[i]
+		}
+		if code == http.StatusTooManyRequests {
+			w.Header().Set("Retry-After", "1")
+		}
+		w.WriteHeader(code)
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	return srv, &calls
+}
+
+func TestSpaceXClient_Retries(t *testing.T) {
+	srv, calls := respondCodes(http.StatusBadGateway, http.StatusGatewayTimeout, http.StatusOK)
+	defer srv.Close()
+	c := NewSpaceXClient(srv.Client(), testSpaceXClientConfig())
+	code, data, err := c.Query(context.TODO(), srv.URL, []byte(`{}`))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, `{}`, string(data))
+	require.EqualValues(t, 3, atomic.LoadInt32(calls))
+
+	// not found is returned as is
+	srv, calls = respondCodes(http.StatusNotFound)
+	defer srv.Close()
+	c = NewSpaceXClient(srv.Client(), testSpaceXClientConfig())
+	code, _, err = c.Get(context.TODO(), srv.URL)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNotFound, code)
+	require.EqualValues(t, 1, atomic.LoadInt32(calls))
+
+	srv, calls = respondCodes(http.StatusServiceUnavailable)
+	defer srv.Close()
+	c = NewSpaceXClient(srv.Client(), testSpaceXClientConfig())
+	_, _, err = c.Get(context.TODO(), srv.URL)
+	require.True(t, errors.As(err, &types.ErrUnavailable{}))
+	require.EqualValues(t, 3, atomic.LoadInt32(calls))
+}
+
+func TestSpaceXClient_RetryAfter(t *testing.T) {
+	srv, calls := respondCodes(http.StatusTooManyRequests, http.StatusOK)
+	defer srv.Close()
+	c := NewSpaceXClient(srv.Client(), testSpaceXClientConfig())
+	started := time.Now()
+	code, _, err := c.Get(context.TODO(), srv.URL)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, code)
+	require.GreaterOrEqual(t, time.Since(started), time.Second)
+	require.EqualValues(t, 2, atomic.LoadInt32(calls))
+
+	// retry after longer than max backoff is not waited
+	srv, calls = respondCodes(http.StatusTooManyRequests, http.StatusOK)
+	defer srv.Close()
+	config := testSpaceXClientConfig()
+	config.MaxBackoff = 100 * time.Millisecond
+	c = NewSpaceXClient(srv.Client(), config)
+	_, _, err = c.Get(context.TODO(), srv.URL)
+	require.True(t, errors.As(err, &types.ErrUnavailable{}))
+	require.EqualValues(t, 1, atomic.LoadInt32(calls))
+}
+
+func TestSpaceXClient_CircuitBreaker(t *testing.T) {
+	srv, calls := respondCodes(http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway, http.StatusOK)
+	defer srv.Close()
+	config := testSpaceXClientConfig()
+	config.Retries = 0
+	config.FailureThreshold = 2
+	config.OpenTimeout = 50 * time.Millisecond
+	c := NewSpaceXClient(srv.Client(), config)
+
+	for i := 0; i < 3; i++ {
+		_, _, err := c.Get(context.TODO(), srv.URL)
+		require.True(t, errors.As(err, &types.ErrUnavailable{}))
+	}
+	// breaker opened after second failure
+	require.EqualValues(t, 2, atomic.LoadInt32(calls))
+
+	// failed probe opens breaker again
+	time.Sleep(config.OpenTimeout)
+	_, _, err := c.Get(context.TODO(), srv.URL)
+	require.True(t, errors.As(err, &types.ErrUnavailable{}))
+	_, _, err = c.Get(context.TODO(), srv.URL)
+	require.True(t, errors.As(err, &types.ErrUnavailable{}))
+	require.EqualValues(t, 3, atomic.LoadInt32(calls))
+
+	// successful probe closes breaker
+	time.Sleep(config.OpenTimeout)
+	for i := 0; i < 2; i++ {
+		code, _, err := c.Get(context.TODO(), srv.URL)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, code)
+	}
+	require.EqualValues(t, 5, atomic.LoadInt32(calls))
+}
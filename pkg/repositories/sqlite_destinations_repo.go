@@ -0,0 +1,67 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/leveldorado/space-trouble/pkg/types"
+	"github.com/pkg/errors"
+)
+
+const (
+	destinationTableName = "destination"
+)
+
+/*
+SQLiteDestinationsRepo
+
+	keeps destinations in SQLite, table is filled with default destinations when created.
+	position keeps rotation order of destinations
+*/
+type SQLiteDestinationsRepo struct {
+	conn *sql.DB
+}
+
+func NewSQLiteDestinationsRepo(conn *sql.DB) *SQLiteDestinationsRepo {
+	return &SQLiteDestinationsRepo{conn: conn}
+}
+
+func (r *SQLiteDestinationsRepo) CreateTables(ctx context.Context) error {
+	q := fmt.Sprintf(`
+CREATE TABLE IF NOT EXISTS "%s" (
+    id       text,
+    name     text,
+    position int,
+    PRIMARY KEY(id)
+);
+`, destinationTableName)
+	if _, err := r.conn.ExecContext(ctx, q); err != nil {
+		return errors.Wrapf(err, `failed to exec query: q - %s`, q)
+	}
+	q = `INSERT OR IGNORE INTO "` + destinationTableName + `" (id, name, position) VALUES (?, ?, ?)`
+	for i, d := range defaultDestinations {
+		if _, err := r.conn.ExecContext(ctx, q, d.ID, d.Name, i); err != nil {
+			return errors.Wrapf(err, `failed to exec query: q - %s, id - %s`, q, d.ID)
+		}
+	}
+	return nil
+}
+
+func (r *SQLiteDestinationsRepo) ListSorted(ctx context.Context) ([]types.Destination, error) {
+	q := `SELECT id, name FROM "` + destinationTableName + `" ORDER BY position, id`
+	rows, err := r.conn.QueryContext(ctx, q)
+	if err != nil {
+		return nil, errors.Wrapf(err, `failed to query rows: q - %s`, q)
+	}
+	var destinations []types.Destination
+	for rows.Next() {
+		d := types.Destination{}
+		if err = rows.Scan(&d.ID, &d.Name); err != nil {
+			_ = rows.Close()
+			return nil, errors.Wrapf(err, `failed to scan rows: q - %s`, q)
+		}
+		destinations = append(destinations, d)
+	}
+	return destinations, errors.Wrapf(rows.Close(), `failed to close rows: q - %s`, q)
+}
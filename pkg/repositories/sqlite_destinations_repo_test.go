@@ -0,0 +1,21 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLiteDestinationsRepo_ListSorted(t *testing.T) {
+	repo := NewSQLiteDestinationsRepo(prepareSQLiteConn(t))
+	require.NoError(t, repo.CreateTables(context.TODO()))
+	// tables are created on every start, destinations are not duplicated
+	require.NoError(t, repo.CreateTables(context.TODO()))
+
+	destinations, err := repo.ListSorted(context.TODO())
+	require.NoError(t, err)
+	expected, err := NewInMemoryDestinationsRepo().ListSorted(context.TODO())
+	require.NoError(t, err)
+	require.Equal(t, expected, destinations)
+}
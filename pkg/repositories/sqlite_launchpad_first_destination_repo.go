@@ -0,0 +1,76 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/leveldorado/space-trouble/pkg/types"
+	"github.com/pkg/errors"
+)
+
+/*
+SQLiteLaunchpadFirstDestinationRepo
+
+	stores rotation anchors in SQLite in the same table as PostgreSQLLaunchpadFirstDestinationRepo
+*/
+type SQLiteLaunchpadFirstDestinationRepo struct {
+	conn *sql.DB
+}
+
+func NewSQLiteLaunchpadFirstDestinationRepo(conn *sql.DB) *SQLiteLaunchpadFirstDestinationRepo {
+	return &SQLiteLaunchpadFirstDestinationRepo{conn: conn}
+}
+
+func (r *SQLiteLaunchpadFirstDestinationRepo) CreateTables(ctx context.Context) error {
+	q := fmt.Sprintf(`
+CREATE TABLE IF NOT EXISTS "%s" (
+    launchpad_id   text,
+    destination_id text,
+    local_year     int,
+    local_month    int,
+    local_day      int,
+    PRIMARY KEY(launchpad_id)
+);
+`, launchpadFirstDestinationTableName)
+	_, err := r.conn.ExecContext(ctx, q)
+	return errors.Wrapf(err, `failed to exec query: q - %s`, q)
+}
+
+func (r *SQLiteLaunchpadFirstDestinationRepo) Set(ctx context.Context, doc types.LaunchpadFirstDestination) error {
+	q := `INSERT INTO "` + launchpadFirstDestinationTableName + `" ` +
+		`(launchpad_id, destination_id, local_year, local_month, local_day) VALUES (?, ?, ?, ?, ?) ` +
+		`ON CONFLICT (launchpad_id) DO UPDATE SET destination_id = excluded.destination_id, ` +
+		`local_year = excluded.local_year, local_month = excluded.local_month, local_day = excluded.local_day`
+	_, err := r.conn.ExecContext(ctx, q, doc.LaunchpadID, doc.DestinationID, doc.LocalYear, int(doc.LocalMonth), doc.LocalDay)
+	return errors.Wrapf(err, `failed to exec query: q - %s, doc - %+v`, q, doc)
+}
+
+func (r *SQLiteLaunchpadFirstDestinationRepo) Get(ctx context.Context, launchpad string) (types.LaunchpadFirstDestination, error) {
+	q := `SELECT launchpad_id, destination_id, local_year, local_month, local_day FROM "` +
+		launchpadFirstDestinationTableName + `" WHERE launchpad_id = ?`
+	doc, err := scanLaunchpadFirstDestination(r.conn.QueryRowContext(ctx, q, launchpad))
+	if errors.Is(err, sql.ErrNoRows) {
+		return types.LaunchpadFirstDestination{}, types.ErrNotFound{}
+	}
+	return doc, errors.Wrapf(err, `failed to query row: launchpad - %s, q - %s`, launchpad, q)
+}
+
+func (r *SQLiteLaunchpadFirstDestinationRepo) List(ctx context.Context) ([]types.LaunchpadFirstDestination, error) {
+	q := `SELECT launchpad_id, destination_id, local_year, local_month, local_day FROM "` +
+		launchpadFirstDestinationTableName + `" ORDER BY launchpad_id`
+	rows, err := r.conn.QueryContext(ctx, q)
+	if err != nil {
+		return nil, errors.Wrapf(err, `failed to query rows: q - %s`, q)
+	}
+	var docs []types.LaunchpadFirstDestination
+	for rows.Next() {
+		doc, err := scanLaunchpadFirstDestination(rows)
+		if err != nil {
+			_ = rows.Close()
+			return nil, errors.Wrapf(err, `failed to scan rows: q - %s`, q)
+		}
+		docs = append(docs, doc)
+	}
+	return docs, errors.Wrapf(rows.Close(), `failed to close rows: q - %s`, q)
+}
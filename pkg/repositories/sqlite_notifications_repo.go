@@ -0,0 +1,127 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/leveldorado/space-trouble/pkg/types"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+/*
+SQLiteNotificationsRepo
+
+	stores notifications in the same table as PostgreSQLNotificationsRepo and shares its queries.
+	timestamps are stored as text in UTC, so they are compared in queries as strings
+*/
+type SQLiteNotificationsRepo struct {
+	conn *sql.DB
+	log  logrus.FieldLogger
+}
+
+func NewSQLiteNotificationsRepo(conn *sql.DB, log logrus.FieldLogger) *SQLiteNotificationsRepo {
+	return &SQLiteNotificationsRepo{conn: conn, log: log}
+}
+
+func (r *SQLiteNotificationsRepo) CreateTables(ctx context.Context) error {
+	q := fmt.Sprintf(`
+CREATE TABLE IF NOT EXISTS "%s" (
+    id              text,
+    order_id        text,
+    event_id        text,
+    kind            text,
+    recipient       text,
+    subject         text,
+    body            text,
+    status          text,
+    attempts        int,
+    last_error      text,
+    next_attempt_at timestamp,
+    created_at      timestamp,
+    sent_at         timestamp,
+    PRIMARY KEY(id)
+);
+CREATE INDEX IF NOT EXISTS "%[1]s_status_next_attempt_at" ON "%[1]s" (status, next_attempt_at);
+CREATE INDEX IF NOT EXISTS "%[1]s_order_id" ON "%[1]s" (order_id);
+CREATE UNIQUE INDEX IF NOT EXISTS "%[1]s_event_id_kind" ON "%[1]s" (event_id, kind);
+`, notificationTableName)
+	_, err := r.conn.ExecContext(ctx, q)
+	return errors.Wrapf(err, `failed to exec query: q - %s`, q)
+}
+
+/*
+Insert stores notifications in single transaction, notification of the same event and kind is stored once
+*/
+func (r *SQLiteNotificationsRepo) Insert(ctx context.Context, docs []types.Notification) error {
+	tx, err := r.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, `failed to begin transaction`)
+	}
+	q := `INSERT INTO "` + notificationTableName + `" (` + notificationColumns + `) ` +
+		`VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13) ON CONFLICT (event_id, kind) DO NOTHING`
+	conn := sqliteRebinder{conn: tx}
+	for _, doc := range docs {
+		if _, err = conn.ExecContext(ctx, q,
+			doc.ID,
+			doc.OrderID,
+			doc.EventID,
+			doc.Kind,
+			doc.Recipient,
+			doc.Subject,
+			doc.Body,
+			doc.Status,
+			doc.Attempts,
+			doc.LastError,
+			doc.NextAttemptAt.UTC(),
+			doc.CreatedAt.UTC(),
+			utcTime(doc.SentAt),
+		); err != nil {
+			if rollbackErr := tx.Rollback(); rollbackErr != nil {
+				r.log.WithField("err", rollbackErr.Error()).Error("failed to rollback")
+			}
+			return errors.Wrapf(err, `failed to exec query: q - %s, id - %s`, q, doc.ID)
+		}
+	}
+	return errors.Wrapf(tx.Commit(), `failed to commit: count - %d`, len(docs))
+}
+
+/*
+CancelPending cancels not sent notifications of order with provided kind
+*/
+func (r *SQLiteNotificationsRepo) CancelPending(ctx context.Context, orderID, kind string) error {
+	q := `UPDATE "` + notificationTableName + `" SET status = $1 WHERE order_id = $2 AND kind = $3 AND status = $4`
+	_, err := sqliteRebinder{conn: r.conn}.ExecContext(ctx, q, types.NotificationStatusCancelled, orderID, kind, types.NotificationStatusPending)
+	return errors.Wrapf(err, `failed to exec query: q - %s, order - %s`, q, orderID)
+}
+
+/*
+ClaimDue returns pending notifications which send time passed.
+
+	claimed notifications get next attempt moved by lease, transactions of single connection are serialized so no row lock is needed
+*/
+func (r *SQLiteNotificationsRepo) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]types.Notification, error) {
+	now = now.UTC()
+	q := `UPDATE "` + notificationTableName + `" SET next_attempt_at = $1 WHERE id IN (` +
+		`SELECT id FROM "` + notificationTableName + `" WHERE status = $2 AND next_attempt_at <= $3 ` +
+		`ORDER BY next_attempt_at LIMIT $4) RETURNING ` + notificationColumns
+	return queryNotifications(ctx, sqliteRebinder{conn: r.conn}, q, now.Add(lease), types.NotificationStatusPending, now, limit)
+}
+
+/*
+Update saves delivery state, notification cancelled meanwhile stays cancelled
+*/
+func (r *SQLiteNotificationsRepo) Update(ctx context.Context, doc types.Notification) error {
+	q := `UPDATE "` + notificationTableName + `" SET status = $2, attempts = $3, last_error = $4, ` +
+		`next_attempt_at = $5, sent_at = $6 WHERE id = $1 AND status <> $7`
+	_, err := sqliteRebinder{conn: r.conn}.ExecContext(ctx, q, doc.ID, doc.Status, doc.Attempts, doc.LastError,
+		doc.NextAttemptAt.UTC(), utcTime(doc.SentAt), types.NotificationStatusCancelled)
+	return errors.Wrapf(err, `failed to exec query: q - %s, id - %s`, q, doc.ID)
+}
+
+func (r *SQLiteNotificationsRepo) ListByOrder(ctx context.Context, orderID string) ([]types.Notification, error) {
+	q := `SELECT ` + notificationColumns + ` FROM "` + notificationTableName + `" WHERE order_id = $1 ORDER BY created_at, next_attempt_at`
+	return queryNotifications(ctx, sqliteRebinder{conn: r.conn}, q, orderID)
+}
@@ -0,0 +1,344 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/leveldorado/space-trouble/pkg/types"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+/*
+SQLiteOrdersRepo
+
+	stores orders, customers, audit entries and outbox events in the same tables as PostgreSQLOrdersRepo
+	and shares its queries. timestamps are stored as text in UTC, so they are compared in queries as strings
+*/
+type SQLiteOrdersRepo struct {
+	conn *sql.DB
+	log  logrus.FieldLogger
+	// SQLite has no advisory locks, only one relay of process handles outbox at a time
+	outboxMu sync.Mutex
+}
+
+func NewSQLiteOrdersRepo(conn *sql.DB, log logrus.FieldLogger) *SQLiteOrdersRepo {
+	return &SQLiteOrdersRepo{conn: conn, log: log}
+}
+
+func (r *SQLiteOrdersRepo) CreateTables(ctx context.Context) error {
+	customerTableCreateQuery := fmt.Sprintf(`
+CREATE TABLE IF NOT EXISTS "%s" (
+    id             text,
+    first_name     text,
+    last_name      text,
+    birthday_year  int,
+    birthday_month int,
+    birthday_day   int,
+    gender         text,
+    PRIMARY KEY(id)
+);
+`, customerInfoTableName)
+	orderTableCreateQuery := fmt.Sprintf(`
+CREATE TABLE IF NOT EXISTS "%s"  (
+    id                 text,
+    customer_id        text,
+    launchpad_id       text,
+    destination_id     text,
+    launch_date        timestamp,
+    created_at         timestamp,
+    email              text NOT NULL DEFAULT '',
+    launch_local_date  text NOT NULL DEFAULT '',
+    launchpad_timezone text NOT NULL DEFAULT '',
+    status             text NOT NULL DEFAULT 'active',
+    conflict_reason    text NOT NULL DEFAULT '',
+    PRIMARY KEY(id)
+);
+CREATE INDEX IF NOT EXISTS "%[1]s_status_launch_date" ON "%[1]s" (status, launch_date);
+CREATE INDEX IF NOT EXISTS "%[1]s_launchpad_id_launch_date" ON "%[1]s" (launchpad_id, launch_date);
+`, orderTableName)
+	orderPassengerTableCreateQuery := fmt.Sprintf(`
+CREATE TABLE IF NOT EXISTS "%s" (
+    order_id    text,
+    position    int,
+    customer_id text,
+    PRIMARY KEY(order_id, position)
+);
+`, orderPassengerTableName)
+	orderEventsTableCreateQuery := fmt.Sprintf(`
+CREATE TABLE IF NOT EXISTS "%s" (
+    seq             integer PRIMARY KEY AUTOINCREMENT,
+    id              text,
+    order_id        text,
+    event_type      text,
+    payload         text,
+    occurred_at     timestamp,
+    attempts        int,
+    last_error      text,
+    next_attempt_at timestamp,
+    published_at    timestamp,
+    dead_at         timestamp
+);
+CREATE INDEX IF NOT EXISTS "%[1]s_unpublished" ON "%[1]s" (seq) WHERE published_at IS NULL;
+CREATE INDEX IF NOT EXISTS "%[1]s_pending" ON "%[1]s" (order_id, seq) WHERE published_at IS NULL AND dead_at IS NULL;
+`, orderEventsTableName)
+	// triggers reject any change of stored entries so audit log can only grow
+	orderAuditTableCreateQuery := fmt.Sprintf(`
+CREATE TABLE IF NOT EXISTS "%s" (
+    seq        integer PRIMARY KEY AUTOINCREMENT,
+    id         text,
+    order_id   text,
+    action     text,
+    actor      text,
+    request_id text,
+    before     text,
+    after      text,
+    created_at timestamp
+);
+CREATE INDEX IF NOT EXISTS "%[1]s_order_id" ON "%[1]s" (order_id, seq);
+CREATE TRIGGER IF NOT EXISTS "%[1]s_immutable_update" BEFORE UPDATE ON "%[1]s"
+BEGIN
+    SELECT RAISE(ABORT, '%[1]s is append only');
+END;
+CREATE TRIGGER IF NOT EXISTS "%[1]s_immutable_delete" BEFORE DELETE ON "%[1]s"
+BEGIN
+    SELECT RAISE(ABORT, '%[1]s is append only');
+END;
+`, orderAuditTableName)
+	for _, q := range []string{
+		customerTableCreateQuery,
+		orderTableCreateQuery,
+		orderPassengerTableCreateQuery,
+		orderEventsTableCreateQuery,
+		orderAuditTableCreateQuery,
+	} {
+		if _, err := r.conn.ExecContext(ctx, q); err != nil {
+			return errors.Wrapf(err, `failed to exec query: q - %s`, q)
+		}
+	}
+	return nil
+}
+
+func (r *SQLiteOrdersRepo) Insert(ctx context.Context, doc types.Order) error {
+	return r.InsertMany(ctx, []types.Order{doc})
+}
+
+/*
+InsertMany inserts all orders in single transaction so either all or none of them stored
+*/
+func (r *SQLiteOrdersRepo) InsertMany(ctx context.Context, docs []types.Order) error {
+	for _, doc := range docs {
+		// SQLite doesn't check type of stored values
+		if _, err := uuid.Parse(doc.ID); err != nil {
+			return errors.Wrapf(err, `invalid order id: id - %s`, doc.ID)
+		}
+	}
+	return r.withTransaction(ctx, func(tx sqlExecutor) error {
+		for _, doc := range docs {
+			doc.LaunchDate = doc.LaunchDate.UTC()
+			doc.CreatedAt = doc.CreatedAt.UTC()
+			// SQLite transactions of single connection are serialized, flight needs no lock
+			if err := insertOrderWithTransaction(ctx, tx, doc, ""); err != nil {
+				return errors.Wrapf(err, `failed to insert order: doc - %+v`, doc)
+			}
+		}
+		return nil
+	})
+}
+
+/*
+TakenSeats returns number of seats taken by active orders of flight, flight is launchpad on launch local date
+*/
+func (r *SQLiteOrdersRepo) TakenSeats(ctx context.Context, launchpadID, launchLocalDate string) (int, error) {
+	return countTakenSeats(ctx, sqliteRebinder{conn: r.conn}, launchpadID, launchLocalDate, uuid.Nil.String())
+}
+
+/*
+withTransaction runs fn in transaction which is committed when fn succeeds
+*/
+func (r *SQLiteOrdersRepo) withTransaction(ctx context.Context, fn func(tx sqlExecutor) error) error {
+	tx, err := r.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, `failed to begin transaction`)
+	}
+	if err = fn(sqliteRebinder{conn: tx}); err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			r.log.WithField("err", rollbackErr.Error()).Error("failed to rollback")
+		}
+		return err
+	}
+	return errors.Wrap(tx.Commit(), `failed to commit`)
+}
+
+func (r *SQLiteOrdersRepo) Get(ctx context.Context, id string) (types.Order, error) {
+	conn := sqliteRebinder{conn: r.conn}
+	q := orderSelectQuery + `WHERE o.id = $1`
+	doc, err := scanOrder(conn.QueryRowContext(ctx, q, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return types.Order{}, types.ErrNotFound{}
+	}
+	if err != nil {
+		return types.Order{}, errors.Wrapf(err, `failed to query row: id - %s, q - %s`, id, q)
+	}
+	orders := []types.Order{doc}
+	if err = loadPassengers(ctx, conn, orders); err != nil {
+		return types.Order{}, err
+	}
+	return orders[0], nil
+}
+
+func (r *SQLiteOrdersRepo) List(ctx context.Context, limit, offset int) ([]types.Order, error) {
+	q := orderSelectQuery + `ORDER BY o.created_at, o.id ` +
+		fmt.Sprintf(`LIMIT %d OFFSET %d`, limit, offset)
+	return r.queryOrders(ctx, q)
+}
+
+/*
+ListByLaunchpad returns active orders of launchpad with launch date in [from, to) range ordered by launch date.
+
+	orders in conflict do not fly and cancelled orders are deleted, so neither of them is returned
+*/
+func (r *SQLiteOrdersRepo) ListByLaunchpad(ctx context.Context, launchpadID string, from, to time.Time) ([]types.Order, error) {
+	q := orderSelectQuery + `WHERE o.launchpad_id = $1 AND o.launch_date >= $2 AND o.launch_date < $3 AND o.status = $4 ` +
+		`ORDER BY o.launch_date, o.created_at, o.id`
+	return r.queryOrders(ctx, q, launchpadID, from.UTC(), to.UTC(), types.OrderStatusActive)
+}
+
+/*
+ListByStatus returns orders in status with launch date not before from ordered by launch date
+*/
+func (r *SQLiteOrdersRepo) ListByStatus(ctx context.Context, status string, from time.Time, limit, offset int) ([]types.Order, error) {
+	q := orderSelectQuery + `WHERE o.status = $1 AND o.launch_date >= $2 ORDER BY o.launch_date, o.id ` +
+		fmt.Sprintf(`LIMIT %d OFFSET %d`, limit, offset)
+	return r.queryOrders(ctx, q, status, from.UTC())
+}
+
+func (r *SQLiteOrdersRepo) queryOrders(ctx context.Context, q string, args ...interface{}) ([]types.Order, error) {
+	conn := sqliteRebinder{conn: r.conn}
+	orders, err := queryOrders(ctx, conn, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	return orders, loadPassengers(ctx, conn, orders)
+}
+
+/*
+Stream calls fn for each order in the same order as List.
+
+	orders are fetched by batches so whole result never loaded in memory, unlike PostgreSQL cursor
+	batches are not read from one snapshot. zero limit means no limit
+*/
+func (r *SQLiteOrdersRepo) Stream(ctx context.Context, limit, offset int, fn func(types.Order) error) error {
+	for fetched := 0; limit == 0 || fetched < limit; {
+		size := streamBatchSize
+		if limit > 0 && limit-fetched < size {
+			size = limit - fetched
+		}
+		batch, err := r.List(ctx, size, offset+fetched)
+		if err != nil {
+			return err
+		}
+		for _, doc := range batch {
+			if err = fn(doc); err != nil {
+				return err
+			}
+		}
+		if len(batch) < size {
+			break
+		}
+		fetched += len(batch)
+	}
+	return nil
+}
+
+/*
+Delete deletes order and stores audit entry and order cancelled event at now in outbox, missing order is not an error
+*/
+func (r *SQLiteOrdersRepo) Delete(ctx context.Context, now time.Time, id string) error {
+	return r.withTransaction(ctx, func(tx sqlExecutor) error {
+		// SQLite has no row locks, transactions of single connection are serialized
+		return deleteOrderWithTransaction(ctx, tx, now, id, "")
+	})
+}
+
+/*
+MarkConflict moves order into conflict status with reason and emits order conflicted event.
+
+	doc is order as it was checked, ErrOrderChanged returned when stored order is not active anymore or its flight changed
+*/
+func (r *SQLiteOrdersRepo) MarkConflict(ctx context.Context, now time.Time, checked types.Order, reason string) error {
+	return r.withTransaction(ctx, func(tx sqlExecutor) error {
+		return updateOrderWithTransaction(ctx, tx, now, checked.ID, "", "", types.OrderEventConflicted, sameActiveFlight(checked), func(doc *types.Order) {
+			doc.Status = types.OrderStatusConflict
+			doc.ConflictReason = reason
+		})
+	})
+}
+
+/*
+Reschedule moves order to launchpad, destination and launch date of doc, makes it active and emits order rescheduled event.
+
+	ErrOrderChanged returned when stored order is not in conflict anymore
+*/
+func (r *SQLiteOrdersRepo) Reschedule(ctx context.Context, now time.Time, doc types.Order) error {
+	return r.withTransaction(ctx, func(tx sqlExecutor) error {
+		return updateOrderWithTransaction(ctx, tx, now, doc.ID, "", "", types.OrderEventRescheduled, inConflict, func(o *types.Order) {
+			o.LaunchpadID = doc.LaunchpadID
+			o.DestinationID = doc.DestinationID
+			o.LaunchDate = doc.LaunchDate.UTC()
+			o.LaunchLocalDate = doc.LaunchLocalDate
+			o.LaunchpadTimezone = doc.LaunchpadTimezone
+			o.Status = types.OrderStatusActive
+			o.ConflictReason = ""
+		})
+	})
+}
+
+/*
+History returns audit entries of order, oldest first
+*/
+func (r *SQLiteOrdersRepo) History(ctx context.Context, orderID string) ([]types.OrderAuditEntry, error) {
+	q := `SELECT id, order_id, action, actor, request_id, before, after, created_at FROM "` + orderAuditTableName + `" ` +
+		`WHERE order_id = $1 ORDER BY seq`
+	rows, err := sqliteRebinder{conn: r.conn}.QueryContext(ctx, q, orderID)
+	if err != nil {
+		return nil, errors.Wrapf(err, `failed to query rows: q - %s, order - %s`, q, orderID)
+	}
+	var entries []types.OrderAuditEntry
+	for rows.Next() {
+		e, err := scanOrderAuditEntry(rows)
+		if err != nil {
+			_ = rows.Close()
+			return nil, errors.Wrapf(err, `failed to scan rows: q - %s`, q)
+		}
+		entries = append(entries, e)
+	}
+	if err = rows.Err(); err != nil {
+		_ = rows.Close()
+		return nil, errors.Wrapf(err, `failed to iterate rows: q - %s`, q)
+	}
+	return entries, errors.Wrapf(rows.Close(), `failed to close rows: q - %s`, q)
+}
+
+/*
+ProcessOutbox passes due events to fn and saves state fn set on them, see PostgreSQLOrdersRepo.ProcessOutbox.
+
+	fn is called outside of transaction, as it may change orders through the same single connection.
+	returns number of events passed to fn, 0 when outbox is processed by other call
+*/
+func (r *SQLiteOrdersRepo) ProcessOutbox(ctx context.Context, now time.Time, limit int, fn func(e *types.OutboxEvent)) (int, error) {
+	if !r.outboxMu.TryLock() {
+		return 0, nil
+	}
+	defer r.outboxMu.Unlock()
+	conn := sqliteRebinder{conn: r.conn}
+	events, err := queryOutboxEvents(ctx, conn, limit, now.UTC())
+	if err != nil {
+		return 0, err
+	}
+	return relayOutboxEvents(ctx, conn, events, fn)
+}
@@ -0,0 +1,52 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/leveldorado/space-trouble/pkg/tools/logger"
+	"github.com/leveldorado/space-trouble/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+func prepareSQLiteConn(t *testing.T) *sql.DB {
+	conn, err := GetSQLiteConn(filepath.Join(t.TempDir(), "space-trouble.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+func prepareSQLiteOrdersRepo(t *testing.T) *SQLiteOrdersRepo {
+	repo := NewSQLiteOrdersRepo(prepareSQLiteConn(t), logger.New())
+	require.NoError(t, repo.CreateTables(context.TODO()))
+	return repo
+}
+
+func TestSQLiteOrdersRepo_CreateTables(t *testing.T) {
+	repo := prepareSQLiteOrdersRepo(t)
+	require.NoError(t, repo.CreateTables(context.TODO()))
+
+	doc := types.Order{
+		ID:            uuid.New().String(),
+		DestinationID: uuid.New().String(),
+		LaunchDate:    time.Date(2053, 3, 6, 5, 0, 0, 0, time.FixedZone("UTC+3", 3*60*60)),
+	}
+	require.NoError(t, repo.Insert(context.TODO(), doc))
+	var launchDate string
+	require.NoError(t, repo.conn.QueryRow(`SELECT CAST(launch_date AS text) FROM "`+orderTableName+`"`).Scan(&launchDate))
+	require.Equal(t, "2053-03-06 02:00:00+00:00", launchDate)
+
+	_, err := repo.conn.ExecContext(context.TODO(), `DELETE FROM "`+orderAuditTableName+`" WHERE order_id = ?`, doc.ID)
+	require.Error(t, err)
+	_, err = repo.conn.ExecContext(context.TODO(), `UPDATE "`+orderAuditTableName+`" SET actor = 'other'`)
+	require.Error(t, err)
+}
+
+func TestRebindSQLite(t *testing.T) {
+	require.Equal(t, `UPDATE t SET a = ?2, b = ?10 WHERE id = ?1 AND c = '$'`,
+		rebindSQLite(`UPDATE t SET a = $2, b = $10 WHERE id = $1 AND c = '$'`))
+}
@@ -0,0 +1,153 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/leveldorado/space-trouble/pkg/types"
+	"github.com/pkg/errors"
+)
+
+/*
+SQLiteWaitlistRepo
+
+	stores waitlist entries in the same table as PostgreSQLWaitlistRepo and shares its queries.
+	timestamps are stored as text in UTC, so they are compared in queries as strings
+*/
+type SQLiteWaitlistRepo struct {
+	conn *sql.DB
+}
+
+func NewSQLiteWaitlistRepo(conn *sql.DB) *SQLiteWaitlistRepo {
+	return &SQLiteWaitlistRepo{conn: conn}
+}
+
+func (r *SQLiteWaitlistRepo) CreateTables(ctx context.Context) error {
+	q := fmt.Sprintf(`
+CREATE TABLE IF NOT EXISTS "%s" (
+    id                text,
+    launchpad_id      text,
+    launch_local_date text,
+    order_doc         text,
+    status            text,
+    order_id          text,
+    checks            int,
+    last_error        text,
+    next_check_at     timestamp,
+    created_at        timestamp,
+    promoted_at       timestamp,
+    PRIMARY KEY(id)
+);
+CREATE INDEX IF NOT EXISTS "%[1]s_status_created_at" ON "%[1]s" (status, created_at);
+CREATE INDEX IF NOT EXISTS "%[1]s_launchpad_id" ON "%[1]s" (launchpad_id);
+CREATE INDEX IF NOT EXISTS "%[1]s_flight" ON "%[1]s" (launchpad_id, launch_local_date, status, created_at);
+`, waitlistTableName)
+	_, err := r.conn.ExecContext(ctx, q)
+	return errors.Wrapf(err, `failed to exec query: q - %s`, q)
+}
+
+func (r *SQLiteWaitlistRepo) Insert(ctx context.Context, doc types.WaitlistEntry) error {
+	orderDoc, err := json.Marshal(doc.Order)
+	if err != nil {
+		return errors.Wrapf(err, `failed to marshal order: id - %s`, doc.ID)
+	}
+	q := `INSERT INTO "` + waitlistTableName + `" (launchpad_id, launch_local_date, ` + waitlistColumns + `) ` +
+		`VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
+	_, err = sqliteRebinder{conn: r.conn}.ExecContext(ctx, q,
+		doc.Order.LaunchpadID,
+		doc.Order.LaunchLocalDate,
+		doc.ID,
+		string(orderDoc),
+		doc.Status,
+		doc.OrderID,
+		doc.Checks,
+		doc.LastError,
+		doc.NextCheckAt.UTC(),
+		doc.CreatedAt.UTC(),
+		utcTime(doc.PromotedAt),
+	)
+	return errors.Wrapf(err, `failed to exec query: q - %s, id - %s`, q, doc.ID)
+}
+
+func (r *SQLiteWaitlistRepo) Get(ctx context.Context, id string) (types.WaitlistEntry, error) {
+	q := `SELECT ` + waitlistColumns + ` FROM "` + waitlistTableName + `" WHERE id = $1`
+	docs, err := queryWaitlistEntries(ctx, sqliteRebinder{conn: r.conn}, q, id)
+	if err != nil {
+		return types.WaitlistEntry{}, err
+	}
+	if len(docs) == 0 {
+		return types.WaitlistEntry{}, types.ErrNotFound{}
+	}
+	return docs[0], nil
+}
+
+/*
+ClaimDue returns waiting entries which check time passed and which are first waiting entries of their flights, oldest entries first.
+
+	claimed entries get next check moved by lease, transactions of single connection are serialized so no row lock is needed
+*/
+func (r *SQLiteWaitlistRepo) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]types.WaitlistEntry, error) {
+	now = now.UTC()
+	q := waitlistClaimDueQuery("")
+	docs, err := queryWaitlistEntries(ctx, sqliteRebinder{conn: r.conn}, q, now.Add(lease), types.WaitlistStatusWaiting, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	sortWaitlistEntries(docs)
+	return docs, nil
+}
+
+/*
+HasWaitingAhead reports whether entry of the same flight which joined earlier is still waiting
+*/
+func (r *SQLiteWaitlistRepo) HasWaitingAhead(ctx context.Context, id string) (bool, error) {
+	var exists bool
+	err := sqliteRebinder{conn: r.conn}.QueryRowContext(ctx, waitlistHasWaitingAheadQuery, id, types.WaitlistStatusWaiting).Scan(&exists)
+	return exists, errors.Wrapf(err, `failed to query row: q - %s, id - %s`, waitlistHasWaitingAheadQuery, id)
+}
+
+/*
+Update saves check result, entry cancelled meanwhile stays cancelled
+*/
+func (r *SQLiteWaitlistRepo) Update(ctx context.Context, doc types.WaitlistEntry) error {
+	q := `UPDATE "` + waitlistTableName + `" SET status = $2, order_id = $3, checks = $4, last_error = $5, ` +
+		`next_check_at = $6, promoted_at = $7 WHERE id = $1 AND status <> $8`
+	_, err := sqliteRebinder{conn: r.conn}.ExecContext(ctx, q, doc.ID, doc.Status, doc.OrderID, doc.Checks, doc.LastError,
+		doc.NextCheckAt.UTC(), utcTime(doc.PromotedAt), types.WaitlistStatusCancelled)
+	return errors.Wrapf(err, `failed to exec query: q - %s, id - %s`, q, doc.ID)
+}
+
+/*
+Cancel cancels waiting entry, ErrNotFound returned when there is no waiting entry with id
+*/
+func (r *SQLiteWaitlistRepo) Cancel(ctx context.Context, id string) error {
+	q := `UPDATE "` + waitlistTableName + `" SET status = $2 WHERE id = $1 AND status = $3`
+	res, err := sqliteRebinder{conn: r.conn}.ExecContext(ctx, q, id, types.WaitlistStatusCancelled, types.WaitlistStatusWaiting)
+	if err != nil {
+		return errors.Wrapf(err, `failed to exec query: q - %s, id - %s`, q, id)
+	}
+	return notFoundIfNoRowsAffected(res)
+}
+
+/*
+RecheckLaunchpad makes waiting entries of launchpad due at provided time
+*/
+func (r *SQLiteWaitlistRepo) RecheckLaunchpad(ctx context.Context, launchpadID string, at time.Time) error {
+	q := `UPDATE "` + waitlistTableName + `" SET next_check_at = $3 WHERE launchpad_id = $1 AND status = $2 AND next_check_at > $3`
+	_, err := sqliteRebinder{conn: r.conn}.ExecContext(ctx, q, launchpadID, types.WaitlistStatusWaiting, at.UTC())
+	return errors.Wrapf(err, `failed to exec query: q - %s, launchpad - %s`, q, launchpadID)
+}
+
+/*
+utcTime returns optional time in UTC, SQLite compares stored timestamps as strings
+*/
+func utcTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	utc := t.UTC()
+	return &utc
+}
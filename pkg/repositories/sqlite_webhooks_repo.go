@@ -0,0 +1,197 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/leveldorado/space-trouble/pkg/types"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+/*
+SQLiteWebhooksRepo
+
+	stores subscriptions and deliveries in the same tables as PostgreSQLWebhooksRepo and shares delivery queries.
+	subscription event types are stored as json array, timestamps are stored as text in UTC
+*/
+type SQLiteWebhooksRepo struct {
+	conn *sql.DB
+	log  logrus.FieldLogger
+}
+
+func NewSQLiteWebhooksRepo(conn *sql.DB, log logrus.FieldLogger) *SQLiteWebhooksRepo {
+	return &SQLiteWebhooksRepo{conn: conn, log: log}
+}
+
+func (r *SQLiteWebhooksRepo) CreateTables(ctx context.Context) error {
+	subscriptionTableCreateQuery := fmt.Sprintf(`
+CREATE TABLE IF NOT EXISTS "%s" (
+    id          text,
+    url         text,
+    event_types text,
+    secret      text,
+    created_at  timestamp,
+    PRIMARY KEY(id)
+);
+`, webhookSubscriptionTableName)
+	deliveryTableCreateQuery := fmt.Sprintf(`
+CREATE TABLE IF NOT EXISTS "%s" (
+    id              text,
+    subscription_id text,
+    event_id        text,
+    event_type      text,
+    payload         blob,
+    status          text,
+    attempts        int,
+    last_error      text,
+    next_attempt_at timestamp,
+    created_at      timestamp,
+    delivered_at    timestamp,
+    PRIMARY KEY(id)
+);
+CREATE INDEX IF NOT EXISTS "%[1]s_status_next_attempt_at" ON "%[1]s" (status, next_attempt_at);
+CREATE UNIQUE INDEX IF NOT EXISTS "%[1]s_event_id_subscription_id" ON "%[1]s" (event_id, subscription_id);
+`, webhookDeliveryTableName)
+	for _, q := range []string{subscriptionTableCreateQuery, deliveryTableCreateQuery} {
+		if _, err := r.conn.ExecContext(ctx, q); err != nil {
+			return errors.Wrapf(err, `failed to exec query: q - %s`, q)
+		}
+	}
+	return nil
+}
+
+func (r *SQLiteWebhooksRepo) InsertSubscription(ctx context.Context, doc types.WebhookSubscription) error {
+	eventTypes, err := json.Marshal(doc.EventTypes)
+	if err != nil {
+		return errors.Wrapf(err, `failed to marshal event types: id - %s`, doc.ID)
+	}
+	q := `INSERT INTO "` + webhookSubscriptionTableName + `" (id, url, event_types, secret, created_at) VALUES ($1, $2, $3, $4, $5)`
+	_, err = sqliteRebinder{conn: r.conn}.ExecContext(ctx, q, doc.ID, doc.URL, string(eventTypes), doc.Secret, doc.CreatedAt.UTC())
+	return errors.Wrapf(err, `failed to exec query: q - %s, id - %s`, q, doc.ID)
+}
+
+func scanSQLiteWebhookSubscription(row rowScanner) (types.WebhookSubscription, error) {
+	doc := types.WebhookSubscription{}
+	var eventTypes []byte
+	if err := row.Scan(&doc.ID, &doc.URL, &eventTypes, &doc.Secret, &doc.CreatedAt); err != nil {
+		return doc, err
+	}
+	return doc, errors.Wrapf(json.Unmarshal(eventTypes, &doc.EventTypes), `failed to unmarshal event types: id - %s`, doc.ID)
+}
+
+func (r *SQLiteWebhooksRepo) GetSubscription(ctx context.Context, id string) (types.WebhookSubscription, error) {
+	q := webhookSubscriptionSelectQuery + `WHERE id = $1`
+	doc, err := scanSQLiteWebhookSubscription(sqliteRebinder{conn: r.conn}.QueryRowContext(ctx, q, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return types.WebhookSubscription{}, types.ErrNotFound{}
+	}
+	return doc, errors.Wrapf(err, `failed to query row: id - %s, q - %s`, id, q)
+}
+
+func (r *SQLiteWebhooksRepo) ListSubscriptions(ctx context.Context) ([]types.WebhookSubscription, error) {
+	q := webhookSubscriptionSelectQuery + `ORDER BY created_at`
+	rows, err := r.conn.QueryContext(ctx, q)
+	if err != nil {
+		return nil, errors.Wrapf(err, `failed to query rows: q - %s`, q)
+	}
+	var docs []types.WebhookSubscription
+	for rows.Next() {
+		doc, err := scanSQLiteWebhookSubscription(rows)
+		if err != nil {
+			_ = rows.Close()
+			return nil, errors.Wrapf(err, `failed to scan rows: q - %s`, q)
+		}
+		docs = append(docs, doc)
+	}
+	if err = rows.Err(); err != nil {
+		_ = rows.Close()
+		return nil, errors.Wrapf(err, `failed to iterate rows: q - %s`, q)
+	}
+	return docs, errors.Wrapf(rows.Close(), `failed to close rows: q - %s`, q)
+}
+
+func (r *SQLiteWebhooksRepo) DeleteSubscription(ctx context.Context, id string) error {
+	q := `DELETE FROM "` + webhookSubscriptionTableName + `" WHERE id = $1`
+	res, err := sqliteRebinder{conn: r.conn}.ExecContext(ctx, q, id)
+	if err != nil {
+		return errors.Wrapf(err, `failed to exec query: id - %s, q - %s`, id, q)
+	}
+	return notFoundIfNoRowsAffected(res)
+}
+
+func (r *SQLiteWebhooksRepo) InsertDeliveries(ctx context.Context, docs []types.WebhookDelivery) error {
+	tx, err := r.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, `failed to begin transaction`)
+	}
+	q := `INSERT INTO "` + webhookDeliveryTableName + `" (` + webhookDeliveryColumns + `) ` +
+		`VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) ON CONFLICT (event_id, subscription_id) DO NOTHING`
+	conn := sqliteRebinder{conn: tx}
+	for _, doc := range docs {
+		if _, err = conn.ExecContext(ctx, q,
+			doc.ID,
+			doc.SubscriptionID,
+			doc.EventID,
+			doc.EventType,
+			doc.Payload,
+			doc.Status,
+			doc.Attempts,
+			doc.LastError,
+			doc.NextAttemptAt.UTC(),
+			doc.CreatedAt.UTC(),
+			utcTime(doc.DeliveredAt),
+		); err != nil {
+			if rollbackErr := tx.Rollback(); rollbackErr != nil {
+				r.log.WithField("err", rollbackErr.Error()).Error("failed to rollback")
+			}
+			return errors.Wrapf(err, `failed to exec query: q - %s, id - %s`, q, doc.ID)
+		}
+	}
+	return errors.Wrapf(tx.Commit(), `failed to commit: count - %d`, len(docs))
+}
+
+/*
+ClaimDueDeliveries returns pending deliveries which next attempt time passed.
+
+	claimed deliveries get next attempt moved by lease, transactions of single connection are serialized so no row lock is needed
+*/
+func (r *SQLiteWebhooksRepo) ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]types.WebhookDelivery, error) {
+	now = now.UTC()
+	q := `UPDATE "` + webhookDeliveryTableName + `" SET next_attempt_at = $1 WHERE id IN (` +
+		`SELECT id FROM "` + webhookDeliveryTableName + `" WHERE status = $2 AND next_attempt_at <= $3 ` +
+		`ORDER BY next_attempt_at LIMIT $4) RETURNING ` + webhookDeliveryColumns
+	return queryWebhookDeliveries(ctx, sqliteRebinder{conn: r.conn}, q, now.Add(lease), types.WebhookDeliveryStatusPending, now, limit)
+}
+
+func (r *SQLiteWebhooksRepo) UpdateDelivery(ctx context.Context, doc types.WebhookDelivery) error {
+	q := `UPDATE "` + webhookDeliveryTableName + `" SET status = $2, attempts = $3, last_error = $4, ` +
+		`next_attempt_at = $5, delivered_at = $6 WHERE id = $1`
+	res, err := sqliteRebinder{conn: r.conn}.ExecContext(ctx, q, doc.ID, doc.Status, doc.Attempts, doc.LastError,
+		doc.NextAttemptAt.UTC(), utcTime(doc.DeliveredAt))
+	if err != nil {
+		return errors.Wrapf(err, `failed to exec query: q - %s, id - %s`, q, doc.ID)
+	}
+	return notFoundIfNoRowsAffected(res)
+}
+
+func (r *SQLiteWebhooksRepo) GetDelivery(ctx context.Context, id string) (types.WebhookDelivery, error) {
+	q := `SELECT ` + webhookDeliveryColumns + ` FROM "` + webhookDeliveryTableName + `" WHERE id = $1`
+	doc, err := scanWebhookDelivery(sqliteRebinder{conn: r.conn}.QueryRowContext(ctx, q, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return types.WebhookDelivery{}, types.ErrNotFound{}
+	}
+	return doc, errors.Wrapf(err, `failed to query row: id - %s, q - %s`, id, q)
+}
+
+/*
+ListDeliveries returns deliveries with provided status, all deliveries for empty status. newest first
+*/
+func (r *SQLiteWebhooksRepo) ListDeliveries(ctx context.Context, status string, limit, offset int) ([]types.WebhookDelivery, error) {
+	q := `SELECT ` + webhookDeliveryColumns + ` FROM "` + webhookDeliveryTableName + `" ` +
+		`WHERE ($1 = '' OR status = $1) ORDER BY created_at DESC ` + fmt.Sprintf(`LIMIT %d OFFSET %d`, limit, offset)
+	return queryWebhookDeliveries(ctx, sqliteRebinder{conn: r.conn}, q, status)
+}
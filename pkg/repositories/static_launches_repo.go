@@ -0,0 +1,132 @@
+package repositories
+
+import (
+	"context"
+	"encoding/csv"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/leveldorado/space-trouble/pkg/types"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+type staticLaunch struct {
+	ID        string    `yaml:"id"`
+	Launchpad string    `yaml:"launchpad"`
+	DateUTC   time.Time `yaml:"date_utc"`
+}
+
+/*
+StaticLaunchesRepo serves competitor launches from schedule file.
+
+	file is YAML list of launches or CSV with id,launchpad,date_utc header, format is chosen by extension.
+	schedule is read once on creation
+*/
+type StaticLaunchesRepo struct {
+	launches []types.Launch
+}
+
+func NewStaticLaunchesRepo(path string) (*StaticLaunchesRepo, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, `failed to open schedule: path - %s`, path)
+	}
+	defer func() { _ = f.Close() }()
+	var docs []staticLaunch
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		if err = yaml.NewDecoder(f).Decode(&docs); err != nil && !errors.Is(err, io.EOF) {
+			return nil, errors.Wrapf(err, `failed to decode yaml schedule: path - %s`, path)
+		}
+	case ".csv":
+		if docs, err = decodeStaticLaunchesCSV(f); err != nil {
+			return nil, errors.Wrapf(err, `failed to decode csv schedule: path - %s`, path)
+		}
+	default:
+		return nil, errors.Errorf(`unsupported schedule format: path - %s`, path)
+	}
+	launches := make([]types.Launch, 0, len(docs))
+	for i, doc := range docs {
+		if doc.Launchpad == "" || doc.DateUTC.IsZero() {
+			return nil, errors.Errorf(`launchpad and date_utc are required: path - %s, launch - %d`, path, i+1)
+		}
+		launches = append(launches, types.Launch{
+			ID:        doc.ID,
+			Launchpad: doc.Launchpad,
+			DateUTC:   doc.DateUTC.UTC(),
+			DateLocal: doc.DateUTC,
+		})
+	}
+	sortLaunches(launches)
+	return &StaticLaunchesRepo{launches: launches}, nil
+}
+
+func decodeStaticLaunchesCSV(r io.Reader) ([]staticLaunch, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+	columns := map[string]int{}
+	for i, name := range records[0] {
+		columns[strings.TrimSpace(name)] = i
+	}
+	for _, name := range []string{"id", "launchpad", "date_utc"} {
+		if _, ok := columns[name]; !ok {
+			return nil, errors.Errorf(`column %s is missing`, name)
+		}
+	}
+	docs := make([]staticLaunch, 0, len(records)-1)
+	for i, record := range records[1:] {
+		date, err := time.Parse(time.RFC3339, record[columns["date_utc"]])
+		if err != nil {
+			return nil, errors.Errorf(`invalid date_utc %s on line %d, RFC3339 expected`, record[columns["date_utc"]], i+2)
+		}
+		docs = append(docs, staticLaunch{
+			ID:        record[columns["id"]],
+			Launchpad: record[columns["launchpad"]],
+			DateUTC:   date,
+		})
+	}
+	return docs, nil
+}
+
+func (r *StaticLaunchesRepo) CheckLaunches(ctx context.Context, launchpad string, localDate time.Time) (bool, error) {
+	return checkLaunchesOfDay(ctx, r, launchpad, localDate)
+}
+
+/*
+ListLaunches returns launches from launchpad with UTC date in [from, to) range
+*/
+func (r *StaticLaunchesRepo) ListLaunches(_ context.Context, launchpad string, from, to time.Time) ([]types.Launch, error) {
+	var launches []types.Launch
+	for _, l := range r.launches {
+		if l.Launchpad == launchpad && !l.DateUTC.Before(from) && l.DateUTC.Before(to) {
+			launches = append(launches, l)
+		}
+	}
+	return launches, nil
+}
+
+type launchesLister interface {
+	ListLaunches(ctx context.Context, launchpad string, from, to time.Time) ([]types.Launch, error)
+}
+
+/*
+checkLaunchesOfDay reports launches of local day for sources which can only list launches
+*/
+func checkLaunchesOfDay(ctx context.Context, r launchesLister, launchpad string, localDate time.Time) (bool, error) {
+	year, month, day := localDate.Date()
+	start := time.Date(year, month, day, 0, 0, 0, 0, localDate.Location())
+	launches, err := r.ListLaunches(ctx, launchpad, start, start.AddDate(0, 0, 1))
+	if err != nil {
+		return false, err
+	}
+	return len(launches) > 0, nil
+}
@@ -0,0 +1,199 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/leveldorado/space-trouble/pkg/types"
+	"github.com/pkg/errors"
+)
+
+// how many days before and after requested date are searched for flights from the same launchpad
+const alternativesSearchDays = 30
+
+type flightCandidate struct {
+	alternative types.FlightAlternative
+	// days between requested and alternative local dates
+	distance int
+}
+
+/*
+Alternatives returns up to limit nearest feasible flights to destination of order.
+
+	flights from the same launchpad on other dates and from other active launchpads on the same local date are considered,
+	same launchpad is skipped when it is not active anymore. Launch keeps requested local time of day. Nearest dates go first, flights of the same date are ordered by launchpad
+*/
+func (s *Orders) Alternatives(ctx context.Context, o types.Order, limit int) ([]types.FlightAlternative, error) {
+	if limit <= 0 {
+		return nil, nil
+	}
+	launchpad, err := s.launchpadRepo.Get(ctx, o.LaunchpadID)
+	if errors.As(err, &types.ErrNotFound{}) {
+		return nil, types.NewErrInvalidData("invalid launchpad id")
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, `failed to get launchpad: id - %s`, o.LaunchpadID)
+	}
+	if o, err = withLaunchLocalDate(o, launchpad.Location); err != nil {
+		return nil, err
+	}
+	destinations, err := s.destinationRepo.ListSorted(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, `failed to get destinations`)
+	}
+	if len(destinations) == 0 {
+		return nil, nil
+	}
+	var sameLaunchpad []flightCandidate
+	if launchpad.Status == types.LaunchpadStatusActive {
+		if sameLaunchpad, err = s.launchpadAlternatives(ctx, launchpad, o, destinations); err != nil {
+			return nil, err
+		}
+	}
+	otherLaunchpads, err := s.otherLaunchpadsAlternatives(ctx, launchpad, o, destinations, limit)
+	if err != nil {
+		return nil, err
+	}
+	candidates := append(otherLaunchpads, sameLaunchpad...)
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].distance != candidates[j].distance {
+			return abs(candidates[i].distance) < abs(candidates[j].distance)
+		}
+		if candidates[i].distance != 0 {
+			return candidates[i].distance < candidates[j].distance
+		}
+		return candidates[i].alternative.LaunchpadID < candidates[j].alternative.LaunchpadID
+	})
+	if len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+	alternatives := make([]types.FlightAlternative, 0, len(candidates))
+	for _, c := range candidates {
+		alternatives = append(alternatives, c.alternative)
+	}
+	return alternatives, nil
+}
+
+/*
+launchpadAlternatives finds dates around requested one when launchpad flies to destination and is not used by competitors
+*/
+func (s *Orders) launchpadAlternatives(ctx context.Context, launchpad types.Launchpad, o types.Order, destinations []types.Destination) ([]flightCandidate, error) {
+	firstDestination, err := s.launchpadFirstDestinationRepo.Get(ctx, launchpad.ID)
+	if err != nil {
+		return nil, errors.Wrapf(err, `failed to get first destination for launchpad: id - %s`, launchpad.ID)
+	}
+	requested := o.LaunchDate.In(launchpad.Location)
+	year, month, day := requested.Date()
+	hour, minute, second := requested.Clock()
+	from := time.Date(year, month, day-alternativesSearchDays, 0, 0, 0, 0, launchpad.Location)
+	to := time.Date(year, month, day+alternativesSearchDays+1, 0, 0, 0, 0, launchpad.Location)
+	launches, err := s.competitorLaunchesRepo.ListLaunches(ctx, launchpad.ID, from, to)
+	if err != nil {
+		return nil, errors.Wrapf(err, `failed to list competitor launches: launchpad - %s`, launchpad.ID)
+	}
+	blocked := make(map[string]bool, len(launches))
+	for _, l := range launches {
+		blocked[l.DateUTC.In(launchpad.Location).Format(types.LocalDateLayout)] = true
+	}
+	var candidates []flightCandidate
+	for shift := -alternativesSearchDays; shift <= alternativesSearchDays; shift++ {
+		date := time.Date(year, month, day+shift, hour, minute, second, 0, launchpad.Location)
+		localDate := date.Format(types.LocalDateLayout)
+		if shift == 0 || blocked[localDate] || hasDatePassed(s.clock.Now(), date, launchpad.Location) {
+			continue
+		}
+		destinationID, err := calculateDestinationForDate(date, launchpad.Location, firstDestination, destinations)
+		if err != nil {
+			return nil, err
+		}
+		if destinationID != o.DestinationID {
+			continue
+		}
+		candidates = append(candidates, flightCandidate{
+			alternative: newFlightAlternative(launchpad, destinationID, date),
+			distance:    shift,
+		})
+	}
+	return candidates, nil
+}
+
+/*
+otherLaunchpadsAlternatives finds up to limit active launchpads flying to destination on requested local date,
+launchpads without rotation anchor are skipped.
+
+	launchpads are checked in the order alternatives of the same date are returned, so competitor launches
+	are checked only until limit is reached and not for every launchpad
+*/
+func (s *Orders) otherLaunchpadsAlternatives(
+	ctx context.Context,
+	requested types.Launchpad,
+	o types.Order,
+	destinations []types.Destination,
+	limit int,
+) ([]flightCandidate, error) {
+	listed, err := s.launchpadRepo.List(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, `failed to list launchpads`)
+	}
+	launchpads := append([]types.Launchpad(nil), listed...)
+	sort.Slice(launchpads, func(i, j int) bool {
+		return launchpads[i].ID < launchpads[j].ID
+	})
+	local := o.LaunchDate.In(requested.Location)
+	year, month, day := local.Date()
+	hour, minute, second := local.Clock()
+	var candidates []flightCandidate
+	for _, launchpad := range launchpads {
+		if len(candidates) == limit {
+			break
+		}
+		if launchpad.ID == requested.ID || launchpad.Status != types.LaunchpadStatusActive || launchpad.Location == nil {
+			continue
+		}
+		date := time.Date(year, month, day, hour, minute, second, 0, launchpad.Location)
+		if hasDatePassed(s.clock.Now(), date, launchpad.Location) {
+			continue
+		}
+		firstDestination, err := s.launchpadFirstDestinationRepo.Get(ctx, launchpad.ID)
+		if errors.As(err, &types.ErrNotFound{}) {
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, `failed to get first destination for launchpad: id - %s`, launchpad.ID)
+		}
+		destinationID, err := calculateDestinationForDate(date, launchpad.Location, firstDestination, destinations)
+		if err != nil {
+			return nil, err
+		}
+		if destinationID != o.DestinationID {
+			continue
+		}
+		busy, err := s.competitorLaunchesRepo.CheckLaunches(ctx, launchpad.ID, date)
+		if err != nil {
+			return nil, errors.Wrapf(err, `failed to check competitor launches: launchpad - %s`, launchpad.ID)
+		}
+		if busy {
+			continue
+		}
+		candidates = append(candidates, flightCandidate{alternative: newFlightAlternative(launchpad, destinationID, date)})
+	}
+	return candidates, nil
+}
+
+func newFlightAlternative(launchpad types.Launchpad, destinationID string, date time.Time) types.FlightAlternative {
+	return types.FlightAlternative{
+		LaunchpadID:   launchpad.ID,
+		LaunchpadName: launchpad.FullName,
+		DestinationID: destinationID,
+		LocalDate:     date.Format(types.LocalDateLayout),
+		LaunchDate:    date.UTC(),
+	}
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
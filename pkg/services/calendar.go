@@ -0,0 +1,105 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/leveldorado/space-trouble/pkg/tools/ical"
+	"github.com/leveldorado/space-trouble/pkg/types"
+	"github.com/pkg/errors"
+)
+
+const (
+	calendarUIDDomain   = "space-trouble"
+	orderEventDuration  = time.Hour
+	defaultCalendarDays = 90
+)
+
+/*
+OrderCalendar returns calendar with launch of order in launchpad timezone.
+
+	order of unknown launchpad is placed in UTC
+*/
+func (s *Orders) OrderCalendar(ctx context.Context, id string) (ical.Calendar, error) {
+	o, err := s.orderRepo.Get(ctx, id)
+	if err != nil {
+		return ical.Calendar{}, err
+	}
+	destinations, err := s.destinationRepo.ListSorted(ctx)
+	if err != nil {
+		return ical.Calendar{}, errors.Wrap(err, `failed to get destinations`)
+	}
+	destinationName := o.DestinationID
+	for _, d := range destinations {
+		if d.ID == o.DestinationID {
+			destinationName = d.Name
+		}
+	}
+	location, launchpadName := time.UTC, o.LaunchpadID
+	launchpad, err := s.launchpadRepo.Get(ctx, o.LaunchpadID)
+	switch {
+	case errors.As(err, &types.ErrNotFound{}):
+	case err != nil:
+		return ical.Calendar{}, errors.Wrapf(err, `failed to get launchpad: id - %s`, o.LaunchpadID)
+	default:
+		location, launchpadName = launchpad.Location, launchpad.FullName
+	}
+	passengers := o.PassengerList()
+	names := make([]string, 0, len(passengers))
+	for _, p := range passengers {
+		names = append(names, p.FirstName+" "+p.LastName)
+	}
+	start := o.LaunchDate.In(location)
+	return ical.Calendar{
+		Name: "Flight to " + destinationName,
+		Events: []ical.Event{{
+			UID:         o.ID + "@" + calendarUIDDomain,
+			Summary:     "Flight to " + destinationName,
+			Description: fmt.Sprintf("Booking: %s\nPassengers: %s", o.ID, strings.Join(names, ", ")),
+			Location:    launchpadName,
+			Start:       start,
+			End:         start.Add(orderEventDuration),
+		}},
+	}, nil
+}
+
+/*
+LaunchpadCalendar returns all day events with destination of launchpad for each local day starting from provided date.
+
+	days blocked by competitor launches are listed as blocked, zero days means default period
+*/
+func (s *Orders) LaunchpadCalendar(ctx context.Context, launchpadID string, from time.Time, days int) (ical.Calendar, error) {
+	if days == 0 {
+		days = defaultCalendarDays
+	}
+	schedule, err := s.LaunchpadSchedule(ctx, launchpadID, from, days)
+	if err != nil {
+		return ical.Calendar{}, err
+	}
+	launchpad, err := s.launchpadRepo.Get(ctx, launchpadID)
+	if err != nil {
+		return ical.Calendar{}, errors.Wrapf(err, `failed to get launchpad: id - %s`, launchpadID)
+	}
+	c := ical.Calendar{Name: launchpad.FullName + " destinations"}
+	for _, day := range schedule {
+		date, err := time.ParseInLocation(types.LocalDateLayout, day.LocalDate, launchpad.Location)
+		if err != nil {
+			return ical.Calendar{}, errors.Wrapf(err, `failed to parse schedule date: date - %s`, day.LocalDate)
+		}
+		summary := "Flight to " + day.DestinationName
+		if day.Blocked {
+			summary = "No flights: launchpad is used by other launch"
+		}
+		c.Events = append(c.Events, ical.Event{
+			UID:      launchpadID + "-" + day.LocalDate + "@" + calendarUIDDomain,
+			Summary:  summary,
+			Location: launchpad.FullName,
+			Start:    date,
+			End:      date.AddDate(0, 0, 1),
+			AllDay:   true,
+		})
+	}
+	return c, nil
+}
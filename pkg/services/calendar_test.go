@@ -0,0 +1,63 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/leveldorado/space-trouble/pkg/types"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrders_OrderCalendar(t *testing.T) {
+	launchpad, lr := prepareLaunchpad(t)
+	launchpad.FullName = "Kennedy Space Center"
+	lr.ExpectedCalls = nil
+	lr.On("Get", mock.Anything, launchpad.ID).Return(launchpad, nil)
+	destinations, dr := prepareDestinations()
+	destinations[1].Name = "Mars"
+	launchDate := time.Date(2053, 7, 1, 14, 0, 0, 0, time.UTC)
+	o, or := prepareOrder(t, launchpad.ID, destinations[1].ID, launchDate)
+	o.ID = uuid.New().String()
+	or.On("Get", mock.Anything, o.ID).Return(o, nil)
+
+	s := NewOrders(or, lr, dr, nil, nil)
+	c, err := s.OrderCalendar(context.TODO(), o.ID)
+	require.NoError(t, err)
+	require.Len(t, c.Events, 1)
+	e := c.Events[0]
+	require.Equal(t, o.ID+"@space-trouble", e.UID)
+	require.Equal(t, "Flight to Mars", e.Summary)
+	require.Equal(t, "Kennedy Space Center", e.Location)
+	require.Contains(t, e.Description, o.FirstName+" "+o.LastName)
+	require.Equal(t, launchpad.Location, e.Start.Location())
+	require.True(t, launchDate.Equal(e.Start))
+	require.Equal(t, time.Hour, e.End.Sub(e.Start))
+	require.False(t, e.AllDay)
+}
+
+func TestOrders_LaunchpadCalendar(t *testing.T) {
+	launchpad, lr := prepareLaunchpad(t)
+	destinations, dr := prepareDestinations()
+	destinations[0].Name = "Moon"
+	lfr := prepareFirstDestinationRepo(launchpad.ID, destinations[0].ID, 2053, 3, 1)
+	clr := &mockCompetitorLaunchesRepo{}
+	clr.On("ListLaunches", mock.Anything, launchpad.ID, mock.Anything, mock.Anything).
+		Return([]types.Launch{{DateUTC: time.Date(2053, 3, 2, 15, 0, 0, 0, time.UTC)}}, nil)
+
+	s := NewOrders(nil, lr, dr, lfr, clr)
+	c, err := s.LaunchpadCalendar(context.TODO(), launchpad.ID, time.Date(2053, 3, 1, 12, 0, 0, 0, time.UTC), 3)
+	require.NoError(t, err)
+	require.Len(t, c.Events, 3)
+	require.Equal(t, "Flight to Moon", c.Events[0].Summary)
+	require.True(t, c.Events[0].AllDay)
+	require.Equal(t, time.Date(2053, 3, 1, 0, 0, 0, 0, launchpad.Location), c.Events[0].Start)
+	require.Equal(t, time.Date(2053, 3, 2, 0, 0, 0, 0, launchpad.Location), c.Events[0].End)
+	require.Contains(t, c.Events[1].Summary, "No flights")
+
+	c, err = s.LaunchpadCalendar(context.TODO(), launchpad.ID, time.Date(2053, 3, 1, 12, 0, 0, 0, time.UTC), 0)
+	require.NoError(t, err)
+	require.Len(t, c.Events, defaultCalendarDays)
+}
@@ -0,0 +1,216 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/leveldorado/space-trouble/pkg/types"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	conflictsCheckInterval = time.Hour
+	conflictsBatchSize     = 100
+)
+
+type conflict struct {
+	order  types.Order
+	reason string
+}
+
+/*
+DetectConflicts rechecks rotation and competitor launches of all upcoming active orders
+and moves orders which flight became impossible into conflict status.
+
+	orders are marked after whole scan, so paging over active orders is not shifted by status changes.
+	order is marked only if it is still active with the checked flight, orders changed meanwhile are skipped.
+	orders of launchpad which is not active anymore are in conflict, orders of unknown launchpads are skipped
+*/
+func (s *Orders) DetectConflicts(ctx context.Context) (types.ConflictsReport, error) {
+	report := types.ConflictsReport{}
+	launchpads := map[string]*types.Launchpad{}
+	var conflicts []conflict
+	from := s.clock.Now().UTC()
+	for offset := 0; ; offset += conflictsBatchSize {
+		orders, err := s.orderRepo.ListByStatus(ctx, types.OrderStatusActive, from, conflictsBatchSize, offset)
+		if err != nil {
+			return types.ConflictsReport{}, errors.Wrapf(err, `failed to list active orders: offset - %d`, offset)
+		}
+		for _, o := range orders {
+			launchpad, err := s.cachedLaunchpad(ctx, o.LaunchpadID, launchpads)
+			if err != nil {
+				return types.ConflictsReport{}, err
+			}
+			if launchpad == nil {
+				continue
+			}
+			report.Checked++
+			if launchpad.Status != types.LaunchpadStatusActive {
+				conflicts = append(conflicts, conflict{order: o, reason: types.FlightImpossibleReasonLaunchpad})
+				continue
+			}
+			err = s.checkFlight(ctx, *launchpad, o)
+			impossible := types.ErrFlightImpossible{}
+			switch {
+			case errors.As(err, &impossible):
+				conflicts = append(conflicts, conflict{order: o, reason: impossible.Reason})
+			case err != nil && !isRejection(err):
+				return types.ConflictsReport{}, errors.Wrapf(err, `failed to check order: id - %s`, o.ID)
+			}
+		}
+		if len(orders) < conflictsBatchSize {
+			break
+		}
+	}
+	for _, c := range conflicts {
+		err := s.orderRepo.MarkConflict(ctx, s.clock.Now().UTC(), c.order, c.reason)
+		if errors.As(err, &types.ErrNotFound{}) || errors.As(err, &types.ErrOrderChanged{}) {
+			continue
+		}
+		if err != nil {
+			return types.ConflictsReport{}, errors.Wrapf(err, `failed to mark conflict: id - %s`, c.order.ID)
+		}
+		report.Conflicts++
+	}
+	return report, nil
+}
+
+/*
+cachedLaunchpad returns launchpad by id resolving each launchpad once, nil for unknown launchpad
+*/
+func (s *Orders) cachedLaunchpad(ctx context.Context, id string, cache map[string]*types.Launchpad) (*types.Launchpad, error) {
+	if launchpad, ok := cache[id]; ok {
+		return launchpad, nil
+	}
+	launchpad, err := s.launchpadRepo.Get(ctx, id)
+	if errors.As(err, &types.ErrNotFound{}) {
+		cache[id] = nil
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, `failed to get launchpad: id - %s`, id)
+	}
+	cache[id] = &launchpad
+	return &launchpad, nil
+}
+
+/*
+Conflicts returns orders in conflict status nearest launch first
+*/
+func (s *Orders) Conflicts(ctx context.Context, limit, offset int) ([]types.Order, error) {
+	orders, err := s.orderRepo.ListByStatus(ctx, types.OrderStatusConflict, time.Time{}, limit, offset)
+	if err != nil {
+		return nil, errors.Wrapf(err, `failed to list conflicts: limit - %d, offset - %d`, limit, offset)
+	}
+	return orders, nil
+}
+
+/*
+Rebook moves order in conflict to nearest feasible flight to the same destination.
+
+	flight is chosen the same way as alternatives of rejected order, so it can be another launchpad on the same day.
+	ErrOrderChanged returned when order was rebooked or changed concurrently
+*/
+func (s *Orders) Rebook(ctx context.Context, id string) (types.Order, error) {
+	o, err := s.orderRepo.Get(ctx, id)
+	if err != nil {
+		return types.Order{}, err
+	}
+	if o.Status != types.OrderStatusConflict {
+		return types.Order{}, types.NewErrInvalidData("order is not in conflict")
+	}
+	alternatives, err := s.Alternatives(ctx, o, 1)
+	if err != nil {
+		return types.Order{}, errors.Wrapf(err, `failed to find alternatives: id - %s`, id)
+	}
+	if len(alternatives) == 0 {
+		return types.Order{}, types.NewErrFlightImpossible(o.ConflictReason)
+	}
+	location, err := s.launchpadLocation(ctx, alternatives[0].LaunchpadID)
+	if err != nil {
+		return types.Order{}, err
+	}
+	o.LaunchpadID = alternatives[0].LaunchpadID
+	o.DestinationID = alternatives[0].DestinationID
+	o.LaunchDate = alternatives[0].LaunchDate
+	o.LaunchLocalDate = alternatives[0].LocalDate
+	o.LaunchpadTimezone = location.String()
+	o.Status = types.OrderStatusActive
+	o.ConflictReason = ""
+	if err = s.orderRepo.Reschedule(ctx, s.clock.Now().UTC(), o); err != nil {
+		return types.Order{}, errors.Wrapf(err, `failed to reschedule order: id - %s`, id)
+	}
+	return o, nil
+}
+
+/*
+RebookConflicts rebooks all orders in conflict, orders without feasible flight stay in conflict and are reported with error
+*/
+func (s *Orders) RebookConflicts(ctx context.Context) ([]types.RebookResult, error) {
+	var conflicts []types.Order
+	for offset := 0; ; offset += conflictsBatchSize {
+		orders, err := s.Conflicts(ctx, conflictsBatchSize, offset)
+		if err != nil {
+			return nil, err
+		}
+		conflicts = append(conflicts, orders...)
+		if len(orders) < conflictsBatchSize {
+			break
+		}
+	}
+	results := make([]types.RebookResult, 0, len(conflicts))
+	for _, c := range conflicts {
+		result := types.RebookResult{OrderID: c.ID}
+		o, err := s.Rebook(ctx, c.ID)
+		switch {
+		case err == nil:
+			result.Order = &o
+		case isRejection(err) || errors.As(err, &types.ErrNotFound{}) || errors.As(err, &types.ErrOrderChanged{}):
+			result.Error = err.Error()
+		default:
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+type conflictsDetector interface {
+	DetectConflicts(ctx context.Context) (types.ConflictsReport, error)
+}
+
+/*
+ConflictsWatcher periodically looks for booked orders which flight became impossible
+*/
+type ConflictsWatcher struct {
+	orders conflictsDetector
+	log    logrus.FieldLogger
+}
+
+func NewConflictsWatcher(orders conflictsDetector, log logrus.FieldLogger) *ConflictsWatcher {
+	return &ConflictsWatcher{orders: orders, log: log}
+}
+
+/*
+Run detects conflicts until context is cancelled
+*/
+func (w *ConflictsWatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(conflictsCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		report, err := w.orders.DetectConflicts(ctx)
+		if err != nil {
+			w.log.WithField("err", err.Error()).Error("failed to detect conflicts")
+			continue
+		}
+		if report.Conflicts > 0 {
+			w.log.WithField("checked", report.Checked).WithField("conflicts", report.Conflicts).Warn("orders in conflict found")
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/leveldorado/space-trouble/pkg/types"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func localDay(day int) interface{} {
+	return mock.MatchedBy(func(d time.Time) bool { return d.Day() == day })
+}
+
+func TestOrders_DetectConflicts(t *testing.T) {
+	launchpad, lr := prepareLaunchpad(t)
+	destinations, dr := prepareDestinations()
+	lfr := prepareFirstDestinationRepo(launchpad.ID, destinations[0].ID, 2053, 3, 3)
+	unknownLaunchpad := uuid.New().String()
+	lr.On("Get", mock.Anything, unknownLaunchpad).Return(types.Launchpad{}, types.ErrNotFound{})
+	retiredLaunchpad := types.Launchpad{ID: uuid.New().String(), Location: launchpad.Location, Status: "retired"}
+	lr.On("Get", mock.Anything, retiredLaunchpad.ID).Return(retiredLaunchpad, nil)
+
+	// destinations[0] is reached on 2053-03-03 and 2053-03-12
+	feasible := types.Order{ID: uuid.New().String(), LaunchpadID: launchpad.ID, DestinationID: destinations[0].ID,
+		LaunchDate: time.Date(2053, 3, 3, 10, 0, 0, 0, launchpad.Location)}
+	busy := types.Order{ID: uuid.New().String(), LaunchpadID: launchpad.ID, DestinationID: destinations[0].ID,
+		LaunchDate: time.Date(2053, 3, 12, 10, 0, 0, 0, launchpad.Location)}
+	rotated := types.Order{ID: uuid.New().String(), LaunchpadID: launchpad.ID, DestinationID: destinations[0].ID,
+		LaunchDate: time.Date(2053, 3, 4, 10, 0, 0, 0, launchpad.Location)}
+	unknown := types.Order{ID: uuid.New().String(), LaunchpadID: unknownLaunchpad, DestinationID: destinations[0].ID,
+		LaunchDate: time.Date(2053, 3, 4, 10, 0, 0, 0, launchpad.Location)}
+	retired := types.Order{ID: uuid.New().String(), LaunchpadID: retiredLaunchpad.ID, DestinationID: destinations[0].ID,
+		LaunchDate: time.Date(2053, 3, 3, 10, 0, 0, 0, launchpad.Location)}
+
+	clr := &mockCompetitorLaunchesRepo{}
+	clr.On("CheckLaunches", mock.Anything, launchpad.ID, localDay(3)).Return(false, nil)
+	clr.On("CheckLaunches", mock.Anything, launchpad.ID, localDay(12)).Return(true, nil)
+	or := &mockOrderRepo{}
+	or.On("ListByStatus", mock.Anything, types.OrderStatusActive, mock.Anything, conflictsBatchSize, 0).
+		Return([]types.Order{feasible, rotated, unknown, busy, retired}, nil)
+	or.On("MarkConflict", mock.Anything, mock.Anything, rotated, types.FlightImpossibleReasonDestination).Return(nil)
+	or.On("MarkConflict", mock.Anything, mock.Anything, busy, types.FlightImpossibleReasonCompetitorLaunch).Return(nil)
+	or.On("MarkConflict", mock.Anything, mock.Anything, retired, types.FlightImpossibleReasonLaunchpad).Return(nil)
+
+	s := NewOrders(or, lr, dr, lfr, clr)
+	report, err := s.DetectConflicts(context.TODO())
+	require.NoError(t, err)
+	require.Equal(t, types.ConflictsReport{Checked: 4, Conflicts: 3}, report)
+	or.AssertExpectations(t)
+	clr.AssertExpectations(t)
+	lr.AssertNumberOfCalls(t, "Get", 3)
+}
+
+func TestOrders_Rebook(t *testing.T) {
+	launchpad, lr := prepareLaunchpad(t)
+	lr.On("List", mock.Anything).Return([]types.Launchpad{launchpad}, nil)
+	destinations, dr := prepareDestinations()
+	lfr := prepareFirstDestinationRepo(launchpad.ID, destinations[0].ID, 2053, 3, 3)
+	o := types.Order{
+		ID:                uuid.New().String(),
+		LaunchpadID:       launchpad.ID,
+		DestinationID:     destinations[0].ID,
+		LaunchDate:        time.Date(2053, 3, 12, 10, 0, 0, 0, launchpad.Location).UTC(),
+		LaunchLocalDate:   "2053-03-12",
+		LaunchpadTimezone: launchpad.Location.String(),
+		Status:            types.OrderStatusConflict,
+		ConflictReason:    types.FlightImpossibleReasonCompetitorLaunch,
+	}
+	active := o
+	active.ID = uuid.New().String()
+	active.Status = types.OrderStatusActive
+	clr := &mockCompetitorLaunchesRepo{}
+	clr.On("ListLaunches", mock.Anything, launchpad.ID, mock.Anything, mock.Anything).
+		Return([]types.Launch{{DateUTC: o.LaunchDate}}, nil)
+
+	// 2053-03-03 and 2053-03-21 are equally near, earlier one wins
+	rebooked := o
+	rebooked.LaunchDate = time.Date(2053, 3, 3, 10, 0, 0, 0, launchpad.Location).UTC()
+	rebooked.LaunchLocalDate = "2053-03-03"
+	rebooked.Status = types.OrderStatusActive
+	rebooked.ConflictReason = ""
+	or := &mockOrderRepo{}
+	or.On("Get", mock.Anything, o.ID).Return(o, nil)
+	or.On("Get", mock.Anything, active.ID).Return(active, nil)
+	or.On("Reschedule", mock.Anything, mock.Anything, rebooked).Return(nil)
+
+	s := NewOrders(or, lr, dr, lfr, clr)
+	got, err := s.Rebook(context.TODO(), o.ID)
+	require.NoError(t, err)
+	require.Equal(t, rebooked, got)
+
+	_, err = s.Rebook(context.TODO(), active.ID)
+	require.True(t, errors.As(err, &types.ErrInvalidData{}))
+	or.AssertExpectations(t)
+}
+
+func TestOrders_RebookConflicts(t *testing.T) {
+	launchpad, lr := prepareLaunchpad(t)
+	lr.On("List", mock.Anything).Return([]types.Launchpad{launchpad}, nil)
+	destinations, dr := prepareDestinations()
+	lfr := prepareFirstDestinationRepo(launchpad.ID, destinations[0].ID, 2053, 3, 3)
+	o := types.Order{
+		ID:             uuid.New().String(),
+		LaunchpadID:    launchpad.ID,
+		DestinationID:  destinations[0].ID,
+		LaunchDate:     time.Date(2053, 3, 12, 10, 0, 0, 0, launchpad.Location).UTC(),
+		Status:         types.OrderStatusConflict,
+		ConflictReason: types.FlightImpossibleReasonCompetitorLaunch,
+	}
+	clr := &mockCompetitorLaunchesRepo{}
+	// every day is busy, nothing to rebook to
+	var launches []types.Launch
+	for day := -31; day <= 31; day++ {
+		launches = append(launches, types.Launch{DateUTC: o.LaunchDate.AddDate(0, 0, day)})
+	}
+	clr.On("ListLaunches", mock.Anything, launchpad.ID, mock.Anything, mock.Anything).Return(launches, nil)
+	or := &mockOrderRepo{}
+	or.On("ListByStatus", mock.Anything, types.OrderStatusConflict, time.Time{}, conflictsBatchSize, 0).
+		Return([]types.Order{o}, nil)
+	or.On("Get", mock.Anything, o.ID).Return(o, nil)
+
+	s := NewOrders(or, lr, dr, lfr, clr)
+	results, err := s.RebookConflicts(context.TODO())
+	require.NoError(t, err)
+	require.Equal(t, []types.RebookResult{
+		{OrderID: o.ID, Error: types.NewErrFlightImpossible(types.FlightImpossibleReasonCompetitorLaunch).Error()},
+	}, results)
+	or.AssertExpectations(t)
+}
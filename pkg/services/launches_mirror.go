@@ -0,0 +1,211 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/leveldorado/space-trouble/pkg/tools/clock"
+	"github.com/leveldorado/space-trouble/pkg/types"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	LaunchesSourceSpaceX = "spacex"
+
+	launchesSyncInterval = 15 * time.Minute
+	// mirror older than that is not trusted and launches are checked live
+	launchesMaxStaleness = time.Hour
+	// launches of past day are kept so checks of today keep working
+	launchesSyncBehind  = 24 * time.Hour
+	launchesSyncHorizon = 365 * 24 * time.Hour
+)
+
+type launchesMirrorRepo interface {
+	Replace(ctx context.Context, sync types.LaunchesSync, launches []types.Launch) error
+	GetSync(ctx context.Context, source string) (types.LaunchesSync, error)
+	ListLaunches(ctx context.Context, source, launchpad string, from, to time.Time) ([]types.Launch, error)
+	ListAllLaunches(ctx context.Context, source string, from, to time.Time) ([]types.Launch, error)
+}
+
+type launchesSource interface {
+	competitorLaunchesRepo
+	ListAllLaunches(ctx context.Context, from, to time.Time) ([]types.Launch, error)
+}
+
+/*
+launchesListener is told about launchpads which launches were added, moved or removed by sync
+*/
+type launchesListener interface {
+	LaunchesChanged(ctx context.Context, launchpadIDs []string) error
+}
+
+/*
+LaunchesMirror keeps upcoming launches of source in local storage and serves competitor launches checks from it.
+
+	launches are synced periodically, checks outside of synced range or with too old mirror go to source directly
+*/
+type LaunchesMirror struct {
+	name     string
+	repo     launchesMirrorRepo
+	source   launchesSource
+	listener launchesListener
+	clock    timeSource
+	log      logrus.FieldLogger
+}
+
+func NewLaunchesMirror(name string, repo launchesMirrorRepo, source launchesSource, log logrus.FieldLogger) *LaunchesMirror {
+	return &LaunchesMirror{name: name, repo: repo, source: source, clock: clock.Real{}, log: log}
+}
+
+/*
+WithClock replaces actual time used for sync range and staleness of mirror
+*/
+func (m *LaunchesMirror) WithClock(c timeSource) *LaunchesMirror {
+	m.clock = c
+	return m
+}
+
+/*
+WithListener sets listener told about launchpads which launches changed on sync
+*/
+func (m *LaunchesMirror) WithListener(l launchesListener) *LaunchesMirror {
+	m.listener = l
+	return m
+}
+
+/*
+Run syncs launches right away and then periodically until context is cancelled
+*/
+func (m *LaunchesMirror) Run(ctx context.Context) {
+	ticker := time.NewTicker(launchesSyncInterval)
+	defer ticker.Stop()
+	for {
+		if err := m.Sync(ctx); err != nil {
+			m.log.WithField("err", err.Error()).Error("failed to sync launches")
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+/*
+Sync replaces mirrored launches of sync range with current launches of source.
+
+	listener is told about launchpads which launches differ from mirrored ones after launches are stored
+*/
+func (m *LaunchesMirror) Sync(ctx context.Context) error {
+	now := m.clock.Now().UTC()
+	sync := types.LaunchesSync{
+		Source:   m.name,
+		From:     now.Add(-launchesSyncBehind),
+		To:       now.Add(launchesSyncHorizon),
+		SyncedAt: now,
+	}
+	launches, err := m.source.ListAllLaunches(ctx, sync.From, sync.To)
+	if err != nil {
+		return errors.Wrapf(err, `failed to list launches: source - %s`, m.name)
+	}
+	sync.Launches = len(launches)
+	if m.listener == nil {
+		return errors.Wrapf(m.repo.Replace(ctx, sync, launches), `failed to store launches: source - %s`, m.name)
+	}
+	mirrored, err := m.repo.ListAllLaunches(ctx, m.name, sync.From, sync.To)
+	if err != nil {
+		return errors.Wrapf(err, `failed to list mirrored launches: source - %s`, m.name)
+	}
+	if err = m.repo.Replace(ctx, sync, launches); err != nil {
+		return errors.Wrapf(err, `failed to store launches: source - %s`, m.name)
+	}
+	changed := changedLaunchpads(mirrored, launches)
+	if len(changed) == 0 {
+		return nil
+	}
+	return errors.Wrapf(m.listener.LaunchesChanged(ctx, changed), `failed to notify about changed launches: launchpads - %v`, changed)
+}
+
+/*
+changedLaunchpads returns sorted ids of launchpads which have launch present only in one of lists or with other date
+*/
+func changedLaunchpads(before, after []types.Launch) []string {
+	type launchKey struct {
+		launchpad string
+		id        string
+		date      int64
+	}
+	keys := map[launchKey]int{}
+	for _, l := range before {
+		keys[launchKey{launchpad: l.Launchpad, id: l.ID, date: l.DateUTC.UnixNano()}]++
+	}
+	for _, l := range after {
+		keys[launchKey{launchpad: l.Launchpad, id: l.ID, date: l.DateUTC.UnixNano()}]--
+	}
+	changed := map[string]bool{}
+	for k, n := range keys {
+		if n != 0 {
+			changed[k.launchpad] = true
+		}
+	}
+	ids := make([]string, 0, len(changed))
+	for id := range changed {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+/*
+Staleness returns time passed since last sync, false when launches were never synced
+*/
+func (m *LaunchesMirror) Staleness(ctx context.Context) (time.Duration, bool, error) {
+	sync, err := m.repo.GetSync(ctx, m.name)
+	if errors.As(err, &types.ErrNotFound{}) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, errors.Wrapf(err, `failed to get sync: source - %s`, m.name)
+	}
+	return m.clock.Now().Sub(sync.SyncedAt), true, nil
+}
+
+func (m *LaunchesMirror) CheckLaunches(ctx context.Context, launchpad string, localDate time.Time) (bool, error) {
+	year, month, day := localDate.Date()
+	from := time.Date(year, month, day, 0, 0, 0, 0, localDate.Location())
+	if !m.covers(ctx, from, from.AddDate(0, 0, 1)) {
+		return m.source.CheckLaunches(ctx, launchpad, localDate)
+	}
+	launches, err := m.repo.ListLaunches(ctx, m.name, launchpad, from, from.AddDate(0, 0, 1))
+	if err != nil {
+		return false, errors.Wrapf(err, `failed to list mirrored launches: launchpad - %s`, launchpad)
+	}
+	return len(launches) > 0, nil
+}
+
+func (m *LaunchesMirror) ListLaunches(ctx context.Context, launchpad string, from, to time.Time) ([]types.Launch, error) {
+	if !m.covers(ctx, from, to) {
+		return m.source.ListLaunches(ctx, launchpad, from, to)
+	}
+	launches, err := m.repo.ListLaunches(ctx, m.name, launchpad, from, to)
+	return launches, errors.Wrapf(err, `failed to list mirrored launches: launchpad - %s`, launchpad)
+}
+
+/*
+covers reports whether fresh mirror contains all launches of [from, to) range.
+
+	failure to read sync state is not fatal, source is asked directly then
+*/
+func (m *LaunchesMirror) covers(ctx context.Context, from, to time.Time) bool {
+	sync, err := m.repo.GetSync(ctx, m.name)
+	if errors.As(err, &types.ErrNotFound{}) {
+		return false
+	}
+	if err != nil {
+		m.log.WithField("err", err.Error()).Warn("failed to get launches sync, checking source")
+		return false
+	}
+	return m.clock.Now().Sub(sync.SyncedAt) <= launchesMaxStaleness && !from.Before(sync.From) && !to.After(sync.To)
+}
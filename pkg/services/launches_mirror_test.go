@@ -0,0 +1,144 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/leveldorado/space-trouble/pkg/tools/logger"
+	"github.com/leveldorado/space-trouble/pkg/types"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLaunchesMirror_Sync(t *testing.T) {
+	launches := []types.Launch{{ID: uuid.New().String(), Launchpad: uuid.New().String(), DateUTC: time.Now().UTC()}}
+	source := &mockLaunchesSource{}
+	source.On("ListAllLaunches", mock.Anything, mock.Anything, mock.Anything).Return(launches, nil)
+	repo := &mockLaunchesMirrorRepo{}
+	repo.On("Replace", mock.Anything, mock.MatchedBy(func(sync types.LaunchesSync) bool {
+		return sync.Source == LaunchesSourceSpaceX && sync.Launches == 1 &&
+			sync.To.Sub(sync.From) == launchesSyncBehind+launchesSyncHorizon &&
+			time.Since(sync.SyncedAt) < time.Minute
+	}), launches).Return(nil)
+
+	m := NewLaunchesMirror(LaunchesSourceSpaceX, repo, source, logger.New())
+	require.NoError(t, m.Sync(context.TODO()))
+	source.AssertExpectations(t)
+	repo.AssertExpectations(t)
+}
+
+func TestLaunchesMirror_SyncNotifiesChangedLaunchpads(t *testing.T) {
+	date := time.Date(2053, 3, 6, 5, 0, 0, 0, time.UTC)
+	kept := types.Launch{ID: uuid.New().String(), Launchpad: "kept", DateUTC: date}
+	moved := types.Launch{ID: uuid.New().String(), Launchpad: "moved", DateUTC: date}
+	removed := types.Launch{ID: uuid.New().String(), Launchpad: "removed", DateUTC: date}
+	added := types.Launch{ID: uuid.New().String(), Launchpad: "added", DateUTC: date}
+	movedNow := moved
+	movedNow.DateUTC = date.AddDate(0, 0, 1)
+	launches := []types.Launch{kept, movedNow, added}
+
+	source := &mockLaunchesSource{}
+	source.On("ListAllLaunches", mock.Anything, mock.Anything, mock.Anything).Return(launches, nil)
+	repo := newMockLaunchesMirrorRepo(t)
+	repo.On("ListAllLaunches", mock.Anything, LaunchesSourceSpaceX, mock.Anything, mock.Anything).
+		Return([]types.Launch{kept, moved, removed}, nil)
+	repo.On("Replace", mock.Anything, mock.Anything, launches).Return(nil)
+	listener := newMockLaunchesListener(t)
+	listener.On("LaunchesChanged", mock.Anything, []string{"added", "moved", "removed"}).Return(nil)
+
+	m := NewLaunchesMirror(LaunchesSourceSpaceX, repo, source, logger.New()).WithListener(listener)
+	require.NoError(t, m.Sync(context.TODO()))
+
+	// nothing changed since last sync
+	repo.ExpectedCalls[0].Return(launches, nil)
+	require.NoError(t, m.Sync(context.TODO()))
+	listener.AssertNumberOfCalls(t, "LaunchesChanged", 1)
+}
+
+func TestLaunchesMirror_SyncVirtualClock(t *testing.T) {
+	now := time.Date(2053, 3, 6, 5, 0, 0, 0, time.UTC)
+	c := &mockTimeSource{}
+	c.On("Now").Return(now)
+	source := &mockLaunchesSource{}
+	source.On("ListAllLaunches", mock.Anything, now.Add(-launchesSyncBehind), now.Add(launchesSyncHorizon)).Return(nil, nil)
+	repo := &mockLaunchesMirrorRepo{}
+	repo.On("Replace", mock.Anything, mock.MatchedBy(func(sync types.LaunchesSync) bool {
+		return sync.SyncedAt.Equal(now)
+	}), mock.Anything).Return(nil)
+	// synced in virtual time is fresh, though it's far from actual time
+	repo.On("GetSync", mock.Anything, LaunchesSourceSpaceX).Return(types.LaunchesSync{SyncedAt: now.Add(-time.Minute)}, nil)
+
+	m := NewLaunchesMirror(LaunchesSourceSpaceX, repo, source, logger.New()).WithClock(c)
+	require.NoError(t, m.Sync(context.TODO()))
+	staleness, synced, err := m.Staleness(context.TODO())
+	require.NoError(t, err)
+	require.True(t, synced)
+	require.Equal(t, time.Minute, staleness)
+	source.AssertExpectations(t)
+	repo.AssertExpectations(t)
+}
+
+func TestLaunchesMirror_CheckLaunches(t *testing.T) {
+	launchpad := uuid.New().String()
+	now := time.Now().UTC()
+	day := time.Date(now.Year(), now.Month(), now.Day()+10, 12, 0, 0, 0, time.UTC)
+	dayStart := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC)
+	fresh := types.LaunchesSync{Source: LaunchesSourceSpaceX, From: now.Add(-launchesSyncBehind), To: now.Add(launchesSyncHorizon), SyncedAt: now}
+	stale := fresh
+	stale.SyncedAt = now.Add(-launchesMaxStaleness - time.Minute)
+
+	source := &mockLaunchesSource{}
+	source.On("CheckLaunches", mock.Anything, launchpad, day).Return(true, nil)
+	repo := &mockLaunchesMirrorRepo{}
+	repo.On("GetSync", mock.Anything, LaunchesSourceSpaceX).Return(fresh, nil).Once()
+	repo.On("ListLaunches", mock.Anything, LaunchesSourceSpaceX, launchpad, dayStart, dayStart.AddDate(0, 0, 1)).
+		Return(nil, nil).Once()
+	m := NewLaunchesMirror(LaunchesSourceSpaceX, repo, source, logger.New())
+
+	// fresh mirror is used
+	exists, err := m.CheckLaunches(context.TODO(), launchpad, day)
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	// stale mirror falls back to source
+	repo.On("GetSync", mock.Anything, LaunchesSourceSpaceX).Return(stale, nil).Once()
+	exists, err = m.CheckLaunches(context.TODO(), launchpad, day)
+	require.NoError(t, err)
+	require.True(t, exists)
+
+	// never synced
+	repo.On("GetSync", mock.Anything, LaunchesSourceSpaceX).Return(types.LaunchesSync{}, types.ErrNotFound{}).Once()
+	exists, err = m.CheckLaunches(context.TODO(), launchpad, day)
+	require.NoError(t, err)
+	require.True(t, exists)
+
+	// day beyond synced range
+	farDay := day.Add(launchesSyncHorizon)
+	source.On("CheckLaunches", mock.Anything, launchpad, farDay).Return(false, nil)
+	repo.On("GetSync", mock.Anything, LaunchesSourceSpaceX).Return(fresh, nil).Once()
+	exists, err = m.CheckLaunches(context.TODO(), launchpad, farDay)
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	source.AssertExpectations(t)
+	repo.AssertExpectations(t)
+}
+
+func TestLaunchesMirror_Staleness(t *testing.T) {
+	repo := &mockLaunchesMirrorRepo{}
+	repo.On("GetSync", mock.Anything, LaunchesSourceSpaceX).Return(types.LaunchesSync{}, types.ErrNotFound{}).Once()
+	repo.On("GetSync", mock.Anything, LaunchesSourceSpaceX).
+		Return(types.LaunchesSync{SyncedAt: time.Now().Add(-time.Hour)}, nil).Once()
+	m := NewLaunchesMirror(LaunchesSourceSpaceX, repo, &mockLaunchesSource{}, logger.New())
+
+	_, synced, err := m.Staleness(context.TODO())
+	require.NoError(t, err)
+	require.False(t, synced)
+
+	staleness, synced, err := m.Staleness(context.TODO())
+	require.NoError(t, err)
+	require.True(t, synced)
+	require.InDelta(t, time.Hour.Seconds(), staleness.Seconds(), 60)
+}
@@ -0,0 +1,148 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/leveldorado/space-trouble/pkg/tools/clock"
+	"github.com/leveldorado/space-trouble/pkg/types"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// how often launchpads are listed again while they are served from snapshot
+const launchpadsSnapshotRetryInterval = 30 * time.Second
+
+type launchpadsSnapshotRepo interface {
+	Save(ctx context.Context, launchpads []types.Launchpad, savedAt time.Time) error
+	Load(ctx context.Context) ([]types.Launchpad, time.Time, error)
+}
+
+/*
+LaunchpadsSnapshot serves launchpads of source and falls back to last saved list when source fails.
+
+	every successful list is saved as snapshot. While snapshot is served app is degraded
+	and list is retried in background until source is back
+*/
+type LaunchpadsSnapshot struct {
+	source launchpadRepo
+	repo   launchpadsSnapshotRepo
+	clock  timeSource
+	log    logrus.FieldLogger
+
+	mu       sync.Mutex
+	degraded bool
+	since    time.Time
+	reason   string
+}
+
+func NewLaunchpadsSnapshot(source launchpadRepo, repo launchpadsSnapshotRepo, log logrus.FieldLogger) *LaunchpadsSnapshot {
+	c := clock.Real{}
+	return &LaunchpadsSnapshot{source: source, repo: repo, clock: c, log: log, since: c.Now().UTC()}
+}
+
+/*
+WithClock replaces actual time used for snapshot save time and degraded mode start
+*/
+func (s *LaunchpadsSnapshot) WithClock(c timeSource) *LaunchpadsSnapshot {
+	s.clock = c
+	s.since = c.Now().UTC()
+	return s
+}
+
+/*
+List returns launchpads of source, snapshot when source fails. Error is returned only when there is no snapshot
+*/
+func (s *LaunchpadsSnapshot) List(ctx context.Context) ([]types.Launchpad, error) {
+	launchpads, err := s.source.List(ctx)
+	if err == nil {
+		s.setDegraded(false, "")
+		if err = s.repo.Save(ctx, launchpads, s.clock.Now().UTC()); err != nil {
+			s.log.WithField("err", err.Error()).Error("failed to save launchpads snapshot")
+		}
+		return launchpads, nil
+	}
+	snapshot, savedAt, snapshotErr := s.repo.Load(ctx)
+	if snapshotErr != nil {
+		return nil, errors.Wrapf(err, `failed to list launchpads and load snapshot: snapshot err - %s`, snapshotErr)
+	}
+	s.setDegraded(true, err.Error())
+	s.log.WithField("err", err.Error()).WithField("saved_at", savedAt).Warn("launchpads are served from snapshot")
+	return snapshot, nil
+}
+
+/*
+Get returns launchpad of source, snapshot one when source fails for other reason than not found
+*/
+func (s *LaunchpadsSnapshot) Get(ctx context.Context, id string) (types.Launchpad, error) {
+	launchpad, err := s.source.Get(ctx, id)
+	if err == nil || errors.As(err, &types.ErrNotFound{}) {
+		return launchpad, err
+	}
+	snapshot, _, snapshotErr := s.repo.Load(ctx)
+	if snapshotErr != nil {
+		return types.Launchpad{}, err
+	}
+	for _, l := range snapshot {
+		if l.ID == id {
+			s.setDegraded(true, err.Error())
+			return l, nil
+		}
+	}
+	return types.Launchpad{}, err
+}
+
+/*
+Health reports degraded status while launchpads are served from snapshot
+*/
+func (s *LaunchpadsSnapshot) Health() types.HealthCheck {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	since := s.since
+	if !s.degraded {
+		return types.HealthCheck{Status: types.HealthStatusOK, Since: &since}
+	}
+	return types.HealthCheck{Status: types.HealthStatusDegraded, Message: "launchpads are served from snapshot: " + s.reason, Since: &since}
+}
+
+/*
+Run retries listing launchpads while snapshot is served until context is cancelled
+*/
+func (s *LaunchpadsSnapshot) Run(ctx context.Context) {
+	ticker := time.NewTicker(launchpadsSnapshotRetryInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if !s.isDegraded() {
+			continue
+		}
+		if _, err := s.List(ctx); err != nil {
+			s.log.WithField("err", err.Error()).Error("failed to list launchpads")
+			continue
+		}
+		if !s.isDegraded() {
+			s.log.Info("launchpads source is back")
+		}
+	}
+}
+
+func (s *LaunchpadsSnapshot) isDegraded() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.degraded
+}
+
+func (s *LaunchpadsSnapshot) setDegraded(degraded bool, reason string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.degraded != degraded {
+		s.since = s.clock.Now().UTC()
+	}
+	s.degraded = degraded
+	s.reason = reason
+}
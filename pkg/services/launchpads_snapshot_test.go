@@ -0,0 +1,67 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/leveldorado/space-trouble/pkg/tools/logger"
+	"github.com/leveldorado/space-trouble/pkg/types"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLaunchpadsSnapshot(t *testing.T) {
+	launchpad := types.Launchpad{ID: uuid.New().String(), FullName: "pad", Location: time.UTC, Status: types.LaunchpadStatusActive}
+	unavailable := errors.Wrap(types.ErrUnavailable{Service: "spacex api"}, "failed to list")
+	lr := &mockLaunchpadRepo{}
+	repo := &mockLaunchpadsSnapshotRepo{}
+	s := NewLaunchpadsSnapshot(lr, repo, logger.New())
+
+	// source is down and there is no snapshot yet
+	lr.On("List", mock.Anything).Return(nil, unavailable).Once()
+	repo.On("Load", mock.Anything).Return(nil, time.Time{}, types.ErrNotFound{}).Once()
+	_, err := s.List(context.TODO())
+	require.ErrorAs(t, err, &types.ErrUnavailable{})
+
+	// successful list is saved
+	lr.On("List", mock.Anything).Return([]types.Launchpad{launchpad}, nil).Once()
+	repo.On("Save", mock.Anything, []types.Launchpad{launchpad}, mock.Anything).Return(nil).Once()
+	launchpads, err := s.List(context.TODO())
+	require.NoError(t, err)
+	require.Equal(t, []types.Launchpad{launchpad}, launchpads)
+	require.Equal(t, types.HealthStatusOK, s.Health().Status)
+
+	// source is down, snapshot is served
+	savedAt := time.Now().UTC()
+	lr.On("List", mock.Anything).Return(nil, unavailable).Once()
+	repo.On("Load", mock.Anything).Return([]types.Launchpad{launchpad}, savedAt, nil)
+	launchpads, err = s.List(context.TODO())
+	require.NoError(t, err)
+	require.Equal(t, []types.Launchpad{launchpad}, launchpads)
+	health := s.Health()
+	require.Equal(t, types.HealthStatusDegraded, health.Status)
+	require.Contains(t, health.Message, "spacex api is unavailable")
+
+	lr.On("Get", mock.Anything, launchpad.ID).Return(types.Launchpad{}, unavailable).Once()
+	got, err := s.Get(context.TODO(), launchpad.ID)
+	require.NoError(t, err)
+	require.Equal(t, launchpad, got)
+
+	// not found is not hidden by snapshot
+	missing := uuid.New().String()
+	lr.On("Get", mock.Anything, missing).Return(types.Launchpad{}, types.ErrNotFound{}).Once()
+	_, err = s.Get(context.TODO(), missing)
+	require.ErrorAs(t, err, &types.ErrNotFound{})
+
+	// source is back
+	lr.On("List", mock.Anything).Return([]types.Launchpad{launchpad}, nil).Once()
+	repo.On("Save", mock.Anything, []types.Launchpad{launchpad}, mock.Anything).Return(nil).Once()
+	_, err = s.List(context.TODO())
+	require.NoError(t, err)
+	require.Equal(t, types.HealthStatusOK, s.Health().Status)
+	lr.AssertExpectations(t)
+	repo.AssertExpectations(t)
+}
@@ -0,0 +1,129 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/leveldorado/space-trouble/pkg/migrations"
+	"github.com/leveldorado/space-trouble/pkg/tools/clock"
+	"github.com/leveldorado/space-trouble/pkg/types"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const launchpadsRefreshInterval = 10 * time.Minute
+
+type launchpadsLister interface {
+	List(ctx context.Context) ([]types.Launchpad, error)
+}
+
+type launchpadAnchorsRepo interface {
+	Get(ctx context.Context, launchpad string) (types.LaunchpadFirstDestination, error)
+	Set(ctx context.Context, doc types.LaunchpadFirstDestination) error
+}
+
+/*
+LaunchpadsWatcher periodically refreshes launchpads to react on status changes.
+
+	orders of launchpad which left active status are moved to conflict,
+	launchpads which became active get rotation anchor so they can be booked without restart
+*/
+type LaunchpadsWatcher struct {
+	lr       launchpadsLister
+	dr       destinationRepo
+	fr       launchpadAnchorsRepo
+	orders   conflictsDetector
+	statuses map[string]string
+	clock    timeSource
+	log      logrus.FieldLogger
+}
+
+func NewLaunchpadsWatcher(
+	lr launchpadsLister,
+	dr destinationRepo,
+	fr launchpadAnchorsRepo,
+	orders conflictsDetector,
+	log logrus.FieldLogger,
+) *LaunchpadsWatcher {
+	return &LaunchpadsWatcher{lr: lr, dr: dr, fr: fr, orders: orders, statuses: map[string]string{}, clock: clock.Real{}, log: log}
+}
+
+/*
+WithClock replaces actual time used as anchor date of launchpads which became active
+*/
+func (w *LaunchpadsWatcher) WithClock(c timeSource) *LaunchpadsWatcher {
+	w.clock = c
+	return w
+}
+
+/*
+Refresh lists launchpads and compares their statuses with previous refresh.
+
+	first refresh only remembers statuses, launchpads retired while server was down are caught by conflicts watcher
+*/
+func (w *LaunchpadsWatcher) Refresh(ctx context.Context) (types.LaunchpadsRefresh, error) {
+	launchpads, err := w.lr.List(ctx)
+	if err != nil {
+		return types.LaunchpadsRefresh{}, errors.Wrap(err, `failed to list launchpads`)
+	}
+	report := types.LaunchpadsRefresh{Launchpads: len(launchpads)}
+	var deactivated bool
+	for _, pad := range launchpads {
+		previous, known := w.statuses[pad.ID]
+		if !known || previous == pad.Status {
+			continue
+		}
+		report.Transitions = append(report.Transitions, types.LaunchpadTransition{LaunchpadID: pad.ID, From: previous, To: pad.Status})
+		if previous == types.LaunchpadStatusActive {
+			deactivated = true
+		}
+	}
+	if report.Anchored, err = migrations.AddLaunchpadFirstDestinations(ctx, launchpads, w.dr, w.fr, w.clock.Now()); err != nil {
+		return types.LaunchpadsRefresh{}, errors.Wrap(err, `failed to add launchpad first destinations`)
+	}
+	if deactivated {
+		conflicts, err := w.orders.DetectConflicts(ctx)
+		if err != nil {
+			return types.LaunchpadsRefresh{}, errors.Wrap(err, `failed to detect conflicts`)
+		}
+		report.Conflicts = conflicts.Conflicts
+	}
+	for _, pad := range launchpads {
+		w.statuses[pad.ID] = pad.Status
+	}
+	return report, nil
+}
+
+/*
+Run refreshes launchpads right away and then periodically until context is cancelled
+*/
+func (w *LaunchpadsWatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(launchpadsRefreshInterval)
+	defer ticker.Stop()
+	for {
+		w.refresh(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *LaunchpadsWatcher) refresh(ctx context.Context) {
+	report, err := w.Refresh(ctx)
+	if err != nil {
+		w.log.WithField("err", err.Error()).Error("failed to refresh launchpads")
+		return
+	}
+	for _, t := range report.Transitions {
+		w.log.WithField("launchpad", t.LaunchpadID).WithField("from", t.From).WithField("to", t.To).
+			Warn("launchpad status changed")
+	}
+	if report.Anchored > 0 {
+		w.log.WithField("anchored", report.Anchored).Info("rotation anchors created")
+	}
+	if report.Conflicts > 0 {
+		w.log.WithField("conflicts", report.Conflicts).Warn("orders of deactivated launchpads in conflict")
+	}
+}
@@ -0,0 +1,56 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/leveldorado/space-trouble/pkg/tools/logger"
+	"github.com/leveldorado/space-trouble/pkg/types"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLaunchpadsWatcher_Refresh(t *testing.T) {
+	active := types.Launchpad{ID: uuid.New().String(), Location: time.UTC, Status: types.LaunchpadStatusActive}
+	building := types.Launchpad{ID: uuid.New().String(), Location: time.UTC, Status: "under construction"}
+	_, dr := prepareDestinations()
+	lr := &mockLaunchpadsLister{}
+	lr.On("List", mock.Anything).Return([]types.Launchpad{active, building}, nil).Once()
+	fr := &mockLaunchpadAnchorsRepo{}
+	fr.On("Get", mock.Anything, active.ID).Return(types.LaunchpadFirstDestination{LaunchpadID: active.ID}, nil)
+	orders := &mockConflictsDetector{}
+	w := NewLaunchpadsWatcher(lr, dr, fr, orders, logger.New())
+
+	// first refresh only remembers statuses
+	report, err := w.Refresh(context.TODO())
+	require.NoError(t, err)
+	require.Equal(t, types.LaunchpadsRefresh{Launchpads: 2}, report)
+
+	// active launchpad retired, launchpad under construction activated
+	retired, activated := active, building
+	retired.Status = "retired"
+	activated.Status = types.LaunchpadStatusActive
+	lr.On("List", mock.Anything).Return([]types.Launchpad{retired, activated}, nil).Once()
+	fr.On("Get", mock.Anything, activated.ID).Return(types.LaunchpadFirstDestination{}, types.ErrNotFound{}).Once()
+	fr.On("Set", mock.Anything, mock.MatchedBy(func(doc types.LaunchpadFirstDestination) bool {
+		return doc.LaunchpadID == activated.ID
+	})).Return(nil).Once()
+	orders.On("DetectConflicts", mock.Anything).Return(types.ConflictsReport{Checked: 3, Conflicts: 2}, nil).Once()
+
+	report, err = w.Refresh(context.TODO())
+	require.NoError(t, err)
+	require.Equal(t, types.LaunchpadsRefresh{
+		Launchpads: 2,
+		Transitions: []types.LaunchpadTransition{
+			{LaunchpadID: active.ID, From: types.LaunchpadStatusActive, To: "retired"},
+			{LaunchpadID: building.ID, From: "under construction", To: types.LaunchpadStatusActive},
+		},
+		Anchored:  1,
+		Conflicts: 2,
+	}, report)
+	lr.AssertExpectations(t)
+	fr.AssertExpectations(t)
+	orders.AssertExpectations(t)
+}
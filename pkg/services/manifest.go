@@ -0,0 +1,70 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/leveldorado/space-trouble/pkg/types"
+	"github.com/pkg/errors"
+)
+
+/*
+Manifest returns active orders of launchpad with launch on provided local date of launchpad timezone.
+
+	launch dates of manifest orders are in launchpad timezone
+*/
+func (s *Orders) Manifest(ctx context.Context, launchpadID, localDate string) (types.Manifest, error) {
+	launchpad, err := s.launchpadRepo.Get(ctx, launchpadID)
+	if err != nil {
+		return types.Manifest{}, errors.Wrapf(err, `failed to get launchpad: id - %s`, launchpadID)
+	}
+	from, err := time.ParseInLocation(types.LocalDateLayout, localDate, launchpad.Location)
+	if err != nil {
+		return types.Manifest{}, types.NewErrInvalidData("date should be in " + types.LocalDateLayout + " format")
+	}
+	orders, err := s.orderRepo.ListByLaunchpad(ctx, launchpadID, from, from.AddDate(0, 0, 1))
+	if err != nil {
+		return types.Manifest{}, errors.Wrapf(err, `failed to list orders: launchpad - %s, date - %s`, launchpadID, localDate)
+	}
+	destinations, err := s.destinationRepo.ListSorted(ctx)
+	if err != nil {
+		return types.Manifest{}, errors.Wrap(err, `failed to get destinations`)
+	}
+	names := make(map[string]string, len(destinations))
+	for _, d := range destinations {
+		names[d.ID] = d.Name
+	}
+	m := types.Manifest{
+		LaunchpadID:   launchpadID,
+		LaunchpadName: launchpad.FullName,
+		LocalDate:     localDate,
+		Timezone:      launchpad.Location.String(),
+		Destinations:  []types.ManifestDestination{},
+		Orders:        make([]types.ManifestOrder, 0, len(orders)),
+	}
+	seats := map[string]int{}
+	for _, o := range orders {
+		passengers := o.PassengerList()
+		if _, ok := seats[o.DestinationID]; !ok {
+			m.Destinations = append(m.Destinations, types.ManifestDestination{
+				DestinationID:   o.DestinationID,
+				DestinationName: names[o.DestinationID],
+			})
+		}
+		seats[o.DestinationID] += len(passengers)
+		m.Seats += len(passengers)
+		m.Orders = append(m.Orders, types.ManifestOrder{
+			OrderID:         o.ID,
+			DestinationID:   o.DestinationID,
+			DestinationName: names[o.DestinationID],
+			LaunchDate:      o.LaunchDate.In(launchpad.Location),
+			Email:           o.Email,
+			Seats:           len(passengers),
+			Passengers:      passengers,
+		})
+	}
+	for i, d := range m.Destinations {
+		m.Destinations[i].Seats = seats[d.DestinationID]
+	}
+	return m, nil
+}
@@ -0,0 +1,61 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/brianvoe/gofakeit"
+	"github.com/google/uuid"
+	"github.com/leveldorado/space-trouble/pkg/types"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrders_Manifest(t *testing.T) {
+	launchpad, lr := prepareLaunchpad(t)
+	destinations, dr := prepareDestinations()
+	destinations[1].Name = "Mars"
+	group := types.Order{
+		ID:            uuid.New().String(),
+		DestinationID: destinations[1].ID,
+		LaunchpadID:   launchpad.ID,
+		LaunchDate:    time.Date(2053, 3, 6, 3, 0, 0, 0, time.UTC),
+		Passengers: []types.Passenger{
+			{FirstName: gofakeit.FirstName(), LastName: gofakeit.LastName()},
+			{FirstName: gofakeit.FirstName(), LastName: gofakeit.LastName()},
+		},
+	}
+	single := types.Order{
+		ID:            uuid.New().String(),
+		FirstName:     gofakeit.FirstName(),
+		LastName:      gofakeit.LastName(),
+		DestinationID: destinations[1].ID,
+		LaunchpadID:   launchpad.ID,
+		LaunchDate:    time.Date(2053, 3, 6, 15, 0, 0, 0, time.UTC),
+	}
+	or := &mockOrderRepo{}
+	// local day of America/New_York starts at 05:00 UTC in winter
+	or.On("ListByLaunchpad", mock.Anything, launchpad.ID,
+		time.Date(2053, 3, 5, 0, 0, 0, 0, launchpad.Location),
+		time.Date(2053, 3, 6, 0, 0, 0, 0, launchpad.Location)).
+		Return([]types.Order{group, single}, nil)
+
+	s := NewOrders(or, lr, dr, nil, nil)
+	m, err := s.Manifest(context.TODO(), launchpad.ID, "2053-03-05")
+	require.NoError(t, err)
+	require.Equal(t, "America/New_York", m.Timezone)
+	require.Equal(t, 3, m.Seats)
+	require.Equal(t, []types.ManifestDestination{{DestinationID: destinations[1].ID, DestinationName: "Mars", Seats: 3}}, m.Destinations)
+	require.Len(t, m.Orders, 2)
+	require.Equal(t, 2, m.Orders[0].Seats)
+	require.Equal(t, group.Passengers, m.Orders[0].Passengers)
+	require.Equal(t, 22, m.Orders[0].LaunchDate.Hour())
+	require.Equal(t, []types.Passenger{single.LeadPassenger()}, m.Orders[1].Passengers)
+
+	_, err = s.Manifest(context.TODO(), launchpad.ID, "05.03.2053")
+	require.True(t, errors.As(err, &types.ErrInvalidData{}))
+
+	or.AssertExpectations(t)
+}
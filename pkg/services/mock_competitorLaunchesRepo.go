@@ -6,6 +6,7 @@ import (
 	context "context"
 	time "time"
 
+	types "github.com/leveldorado/space-trouble/pkg/types"
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -35,6 +36,29 @@ func (_m *mockCompetitorLaunchesRepo) CheckLaunches(ctx context.Context, launchp
 	return r0, r1
 }
 
+// ListLaunches provides a mock function with given fields: ctx, launchpad, from, to
+func (_m *mockCompetitorLaunchesRepo) ListLaunches(ctx context.Context, launchpad string, from time.Time, to time.Time) ([]types.Launch, error) {
+	ret := _m.Called(ctx, launchpad, from, to)
+
+	var r0 []types.Launch
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time, time.Time) []types.Launch); ok {
+		r0 = rf(ctx, launchpad, from, to)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.Launch)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time, time.Time) error); ok {
+		r1 = rf(ctx, launchpad, from, to)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 type mockConstructorTestingTnewMockCompetitorLaunchesRepo interface {
 	mock.TestingT
 	Cleanup(func())
@@ -35,6 +35,29 @@ func (_m *mockLaunchpadRepo) Get(ctx context.Context, id string) (types.Launchpa
 	return r0, r1
 }
 
+// List provides a mock function with given fields: ctx
+func (_m *mockLaunchpadRepo) List(ctx context.Context) ([]types.Launchpad, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []types.Launchpad
+	if rf, ok := ret.Get(0).(func(context.Context) []types.Launchpad); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.Launchpad)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 type mockConstructorTestingTnewMockLaunchpadRepo interface {
 	mock.TestingT
 	Cleanup(func())
@@ -4,6 +4,7 @@ package services
 
 import (
 	context "context"
+	time "time"
 
 	types "github.com/leveldorado/space-trouble/pkg/types"
 	mock "github.com/stretchr/testify/mock"
@@ -14,13 +15,13 @@ type mockOrderRepo struct {
 	mock.Mock
 }
 
-// Delete provides a mock function with given fields: ctx, id
-func (_m *mockOrderRepo) Delete(ctx context.Context, id string) error {
-	ret := _m.Called(ctx, id)
+// Delete provides a mock function with given fields: ctx, now, id
+func (_m *mockOrderRepo) Delete(ctx context.Context, now time.Time, id string) error {
+	ret := _m.Called(ctx, now, id)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
-		r0 = rf(ctx, id)
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, string) error); ok {
+		r0 = rf(ctx, now, id)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -49,6 +50,29 @@ func (_m *mockOrderRepo) Get(ctx context.Context, id string) (types.Order, error
 	return r0, r1
 }
 
+// History provides a mock function with given fields: ctx, orderID
+func (_m *mockOrderRepo) History(ctx context.Context, orderID string) ([]types.OrderAuditEntry, error) {
+	ret := _m.Called(ctx, orderID)
+
+	var r0 []types.OrderAuditEntry
+	if rf, ok := ret.Get(0).(func(context.Context, string) []types.OrderAuditEntry); ok {
+		r0 = rf(ctx, orderID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.OrderAuditEntry)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, orderID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Insert provides a mock function with given fields: ctx, o
 func (_m *mockOrderRepo) Insert(ctx context.Context, o types.Order) error {
 	ret := _m.Called(ctx, o)
@@ -63,6 +87,41 @@ func (_m *mockOrderRepo) Insert(ctx context.Context, o types.Order) error {
 	return r0
 }
 
+// InsertMany provides a mock function with given fields: ctx, docs
+func (_m *mockOrderRepo) InsertMany(ctx context.Context, docs []types.Order) error {
+	ret := _m.Called(ctx, docs)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []types.Order) error); ok {
+		r0 = rf(ctx, docs)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// TakenSeats provides a mock function with given fields: ctx, launchpadID, launchLocalDate
+func (_m *mockOrderRepo) TakenSeats(ctx context.Context, launchpadID string, launchLocalDate string) (int, error) {
+	ret := _m.Called(ctx, launchpadID, launchLocalDate)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) int); ok {
+		r0 = rf(ctx, launchpadID, launchLocalDate)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, launchpadID, launchLocalDate)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // List provides a mock function with given fields: ctx, limit, offset
 func (_m *mockOrderRepo) List(ctx context.Context, limit int, offset int) ([]types.Order, error) {
 	ret := _m.Called(ctx, limit, offset)
@@ -86,6 +145,94 @@ func (_m *mockOrderRepo) List(ctx context.Context, limit int, offset int) ([]typ
 	return r0, r1
 }
 
+// ListByLaunchpad provides a mock function with given fields: ctx, launchpadID, from, to
+func (_m *mockOrderRepo) ListByLaunchpad(ctx context.Context, launchpadID string, from time.Time, to time.Time) ([]types.Order, error) {
+	ret := _m.Called(ctx, launchpadID, from, to)
+
+	var r0 []types.Order
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time, time.Time) []types.Order); ok {
+		r0 = rf(ctx, launchpadID, from, to)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.Order)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time, time.Time) error); ok {
+		r1 = rf(ctx, launchpadID, from, to)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListByStatus provides a mock function with given fields: ctx, status, from, limit, offset
+func (_m *mockOrderRepo) ListByStatus(ctx context.Context, status string, from time.Time, limit int, offset int) ([]types.Order, error) {
+	ret := _m.Called(ctx, status, from, limit, offset)
+
+	var r0 []types.Order
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time, int, int) []types.Order); ok {
+		r0 = rf(ctx, status, from, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.Order)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time, int, int) error); ok {
+		r1 = rf(ctx, status, from, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MarkConflict provides a mock function with given fields: ctx, now, checked, reason
+func (_m *mockOrderRepo) MarkConflict(ctx context.Context, now time.Time, checked types.Order, reason string) error {
+	ret := _m.Called(ctx, now, checked, reason)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, types.Order, string) error); ok {
+		r0 = rf(ctx, now, checked, reason)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Reschedule provides a mock function with given fields: ctx, now, doc
+func (_m *mockOrderRepo) Reschedule(ctx context.Context, now time.Time, doc types.Order) error {
+	ret := _m.Called(ctx, now, doc)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, types.Order) error); ok {
+		r0 = rf(ctx, now, doc)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Stream provides a mock function with given fields: ctx, limit, offset, fn
+func (_m *mockOrderRepo) Stream(ctx context.Context, limit int, offset int, fn func(types.Order) error) error {
+	ret := _m.Called(ctx, limit, offset, fn)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, func(types.Order) error) error); ok {
+		r0 = rf(ctx, limit, offset, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 type mockConstructorTestingTnewMockOrderRepo interface {
 	mock.TestingT
 	Cleanup(func())
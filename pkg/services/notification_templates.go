@@ -0,0 +1,98 @@
+package services
+
+import (
+	"bytes"
+	"text/template"
+
+	"github.com/leveldorado/space-trouble/pkg/types"
+	"github.com/pkg/errors"
+)
+
+type notificationData struct {
+	Order           types.Order
+	Passengers      []types.Passenger
+	DestinationName string
+	LaunchpadName   string
+	LaunchDate      string
+}
+
+type notificationTemplate struct {
+	subject *template.Template
+	body    *template.Template
+}
+
+const notificationDetailsTemplate = `Booking: {{.Order.ID}}
+Destination: {{.DestinationName}}
+Launchpad: {{.LaunchpadName}}
+Launch date: {{.LaunchDate}}
+Passengers:
+{{range .Passengers}}  - {{.FirstName}} {{.LastName}}
+{{end}}
+SpaceTrouble
+`
+
+var notificationTemplates = map[string]notificationTemplate{
+	types.NotificationKindBookingConfirmed: newNotificationTemplate(
+		`Your flight to {{.DestinationName}} is booked`,
+		`Hello {{.Order.FirstName}} {{.Order.LastName}},
+
+your booking is confirmed.
+
+`,
+	),
+	types.NotificationKindBookingCancelled: newNotificationTemplate(
+		`Your flight to {{.DestinationName}} is cancelled`,
+		`Hello {{.Order.FirstName}} {{.Order.LastName}},
+
+your booking is cancelled.
+
+`,
+	),
+	types.NotificationKindBookingRescheduled: newNotificationTemplate(
+		`Your flight to {{.DestinationName}} is rescheduled`,
+		`Hello {{.Order.FirstName}} {{.Order.LastName}},
+
+your flight is rescheduled, new details are below.
+
+`,
+	),
+	types.NotificationKindBookingConflicted: newNotificationTemplate(
+		`Your flight to {{.DestinationName}} can't launch as booked`,
+		`Hello {{.Order.FirstName}} {{.Order.LastName}},
+
+your flight can't launch as booked ({{.Order.ConflictReason}}), we will contact you about rebooking.
+
+`,
+	),
+	types.NotificationKindLaunchReminder: newNotificationTemplate(
+		`Your flight to {{.DestinationName}} launches soon`,
+		`Hello {{.Order.FirstName}} {{.Order.LastName}},
+
+this is a reminder about your upcoming launch.
+
+`,
+	),
+}
+
+func newNotificationTemplate(subject, intro string) notificationTemplate {
+	return notificationTemplate{
+		subject: template.Must(template.New("subject").Parse(subject)),
+		body:    template.Must(template.New("body").Parse(intro + notificationDetailsTemplate)),
+	}
+}
+
+func renderNotification(kind string, data notificationData) (string, string, error) {
+	t, ok := notificationTemplates[kind]
+	if !ok {
+		return "", "", errors.Errorf(`unknown notification kind: %s`, kind)
+	}
+	subject := &bytes.Buffer{}
+	if err := t.subject.Execute(subject, data); err != nil {
+		return "", "", errors.Wrapf(err, `failed to render subject: kind - %s`, kind)
+	}
+	body := &bytes.Buffer{}
+	if err := t.body.Execute(body, data); err != nil {
+		return "", "", errors.Wrapf(err, `failed to render body: kind - %s`, kind)
+	}
+	return subject.String(), body.String(), nil
+}
@@ -0,0 +1,276 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/leveldorado/space-trouble/pkg/tools/clock"
+	"github.com/leveldorado/space-trouble/pkg/types"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	notificationMaxAttempts = 6
+	notificationBaseBackoff = 30 * time.Second
+	notificationMaxBackoff  = time.Hour
+	notificationBatchSize   = 50
+	// matches default SMTP timeout, replaced by WithSendTimeout when sender is configured with other one
+	notificationSendTimeout  = 30 * time.Second
+	notificationPollInterval = time.Second
+	launchReminderBefore     = 24 * time.Hour
+	launchDateLayout         = "2006-01-02 15:04 MST"
+)
+
+type notificationsRepo interface {
+	Insert(ctx context.Context, docs []types.Notification) error
+	CancelPending(ctx context.Context, orderID, kind string) error
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]types.Notification, error)
+	Update(ctx context.Context, doc types.Notification) error
+	ListByOrder(ctx context.Context, orderID string) ([]types.Notification, error)
+}
+
+/*
+EmailSender delivers rendered notification to recipient
+*/
+type EmailSender interface {
+	Send(ctx context.Context, msg types.EmailMessage) error
+}
+
+/*
+Notifications turns order events into customer emails and sends them.
+
+	notifications are stored first and sent by Run loop with retries,
+	launch reminder is scheduled on booking and cancelled together with booking or when booking gets conflict
+*/
+type Notifications struct {
+	repo            notificationsRepo
+	sender          EmailSender
+	launchpadRepo   launchpadRepo
+	destinationRepo destinationRepo
+	clock           timeSource
+	sendTimeout     time.Duration
+	log             logrus.FieldLogger
+}
+
+func NewNotifications(
+	repo notificationsRepo,
+	sender EmailSender,
+	lr launchpadRepo,
+	dr destinationRepo,
+	log logrus.FieldLogger,
+) *Notifications {
+	return &Notifications{
+		repo:            repo,
+		sender:          sender,
+		launchpadRepo:   lr,
+		destinationRepo: dr,
+		clock:           clock.Real{},
+		sendTimeout:     notificationSendTimeout,
+		log:             log,
+	}
+}
+
+/*
+WithClock replaces actual time used for scheduling and sending notifications
+*/
+func (n *Notifications) WithClock(c timeSource) *Notifications {
+	n.clock = c
+	return n
+}
+
+/*
+WithSendTimeout replaces time one message is allowed to be sent, claim lease of batch is derived from it
+*/
+func (n *Notifications) WithSendTimeout(d time.Duration) *Notifications {
+	n.sendTimeout = d
+	return n
+}
+
+func (n *Notifications) OrderNotifications(ctx context.Context, orderID string) ([]types.Notification, error) {
+	return n.repo.ListByOrder(ctx, orderID)
+}
+
+/*
+Publish creates notifications for order event, orders without email are skipped.
+
+	publishing the same event again does not duplicate notifications
+*/
+func (n *Notifications) Publish(ctx context.Context, e types.OrderEvent) error {
+	if e.Order == nil || e.Order.Email == "" {
+		return nil
+	}
+	now := n.clock.Now().UTC()
+	var kinds []string
+	switch e.Type {
+	case types.OrderEventCreated:
+		kinds = []string{types.NotificationKindBookingConfirmed, types.NotificationKindLaunchReminder}
+	case types.OrderEventCancelled:
+		kinds = []string{types.NotificationKindBookingCancelled}
+	case types.OrderEventRescheduled:
+		kinds = []string{types.NotificationKindBookingRescheduled, types.NotificationKindLaunchReminder}
+	case types.OrderEventConflicted:
+		kinds = []string{types.NotificationKindBookingConflicted}
+	default:
+		return nil
+	}
+	if e.Type != types.OrderEventCreated {
+		if err := n.repo.CancelPending(ctx, e.OrderID, types.NotificationKindLaunchReminder); err != nil {
+			return errors.Wrapf(err, `failed to cancel reminders: order - %s`, e.OrderID)
+		}
+	}
+	data, err := n.notificationData(ctx, *e.Order)
+	if err != nil {
+		return err
+	}
+	docs := make([]types.Notification, 0, len(kinds))
+	for _, kind := range kinds {
+		sendAt := now
+		if kind == types.NotificationKindLaunchReminder {
+			sendAt = e.Order.LaunchDate.Add(-launchReminderBefore)
+			if sendAt.Before(now) {
+				continue
+			}
+		}
+		subject, body, err := renderNotification(kind, data)
+		if err != nil {
+			return err
+		}
+		docs = append(docs, types.Notification{
+			ID:            uuid.New().String(),
+			OrderID:       e.OrderID,
+			EventID:       e.ID,
+			Kind:          kind,
+			Recipient:     e.Order.Email,
+			Subject:       subject,
+			Body:          body,
+			Status:        types.NotificationStatusPending,
+			NextAttemptAt: sendAt,
+			CreatedAt:     now,
+		})
+	}
+	if len(docs) == 0 {
+		return nil
+	}
+	return errors.Wrapf(n.repo.Insert(ctx, docs), `failed to insert notifications: event - %s`, e.ID)
+}
+
+/*
+notificationData resolves names for templates, unknown launchpad or destination is shown by id
+*/
+func (n *Notifications) notificationData(ctx context.Context, o types.Order) (notificationData, error) {
+	data := notificationData{
+		Order:           o,
+		Passengers:      o.PassengerList(),
+		DestinationName: o.DestinationID,
+		LaunchpadName:   o.LaunchpadID,
+		LaunchDate:      o.LaunchDate.UTC().Format(launchDateLayout),
+	}
+	destinations, err := n.destinationRepo.ListSorted(ctx)
+	if err != nil {
+		return notificationData{}, errors.Wrap(err, `failed to list destinations`)
+	}
+	for _, d := range destinations {
+		if d.ID == o.DestinationID {
+			data.DestinationName = d.Name
+		}
+	}
+	launchpad, err := n.launchpadRepo.Get(ctx, o.LaunchpadID)
+	if errors.As(err, &types.ErrNotFound{}) {
+		return data, nil
+	}
+	if err != nil {
+		return notificationData{}, errors.Wrapf(err, `failed to get launchpad: id - %s`, o.LaunchpadID)
+	}
+	data.LaunchpadName = launchpad.FullName
+	if launchpad.Location != nil {
+		data.LaunchDate = o.LaunchDate.In(launchpad.Location).Format(launchDateLayout)
+	}
+	return data, nil
+}
+
+/*
+Run sends due notifications until context is cancelled
+*/
+func (n *Notifications) Run(ctx context.Context) {
+	ticker := time.NewTicker(notificationPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		for {
+			processed, err := n.ProcessDue(ctx)
+			if err != nil {
+				n.log.WithField("err", err.Error()).Error("failed to process notifications")
+			}
+			if err != nil || processed < notificationBatchSize {
+				break
+			}
+		}
+	}
+}
+
+/*
+ProcessDue sends one batch of due notifications and returns number of processed notifications
+*/
+func (n *Notifications) ProcessDue(ctx context.Context) (int, error) {
+	docs, err := n.repo.ClaimDue(ctx, n.clock.Now().UTC(), notificationClaimLease(n.sendTimeout), notificationBatchSize)
+	if err != nil {
+		return 0, errors.Wrap(err, `failed to claim notifications`)
+	}
+	for _, doc := range docs {
+		doc = n.attempt(ctx, doc, n.clock.Now().UTC())
+		if err := n.repo.Update(ctx, doc); err != nil {
+			return 0, errors.Wrapf(err, `failed to update notification: id - %s`, doc.ID)
+		}
+	}
+	return len(docs), nil
+}
+
+func (n *Notifications) attempt(ctx context.Context, doc types.Notification, now time.Time) types.Notification {
+	doc.Attempts++
+	sendCtx, cancel := context.WithTimeout(ctx, n.sendTimeout)
+	err := n.sender.Send(sendCtx, types.EmailMessage{To: doc.Recipient, Subject: doc.Subject, Body: doc.Body, Date: now})
+	cancel()
+	if err == nil {
+		doc.Status = types.NotificationStatusSent
+		doc.LastError = ""
+		doc.SentAt = &now
+		return doc
+	}
+	doc.LastError = err.Error()
+	if len(doc.LastError) > maxLastErrorLength {
+		doc.LastError = doc.LastError[:maxLastErrorLength]
+	}
+	if doc.Attempts >= notificationMaxAttempts {
+		doc.Status = types.NotificationStatusFailed
+		return doc
+	}
+	doc.NextAttemptAt = now.Add(notificationBackoff(doc.Attempts))
+	return doc
+}
+
+/*
+notificationClaimLease returns lease of claimed batch.
+
+	notifications of batch are sent one by one, lease outlasts batch even when every send times out,
+	so claimed notification is never claimed by other replica while it's still being sent
+*/
+func notificationClaimLease(sendTimeout time.Duration) time.Duration {
+	return notificationBatchSize*sendTimeout + time.Minute
+}
+
+func notificationBackoff(attempts int) time.Duration {
+	backoff := notificationBaseBackoff
+	for i := 1; i < attempts; i++ {
+		backoff *= 2
+		if backoff >= notificationMaxBackoff {
+			return notificationMaxBackoff
+		}
+	}
+	return backoff
+}
@@ -0,0 +1,213 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/brianvoe/gofakeit"
+	"github.com/google/uuid"
+	"github.com/leveldorado/space-trouble/pkg/tools/logger"
+	"github.com/leveldorado/space-trouble/pkg/types"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type emailSenderFunc func(ctx context.Context, msg types.EmailMessage) error
+
+func (f emailSenderFunc) Send(ctx context.Context, msg types.EmailMessage) error {
+	return f(ctx, msg)
+}
+
+func prepareNotificationEvent(t *testing.T, eventType string) (types.OrderEvent, types.Launchpad, *mockLaunchpadRepo, *mockDestinationRepo) {
+	launchpad, lr := prepareLaunchpad(t)
+	launchpad.FullName = "Kennedy Space Center Historic Launch Complex 39A"
+	lr.ExpectedCalls = nil
+	lr.On("Get", mock.Anything, launchpad.ID).Return(launchpad, nil)
+	destinations, dr := prepareDestinations()
+	destinations[2].Name = "Mars"
+	o := types.Order{
+		ID:            uuid.New().String(),
+		FirstName:     gofakeit.FirstName(),
+		LastName:      gofakeit.LastName(),
+		Email:         gofakeit.Email(),
+		LaunchpadID:   launchpad.ID,
+		DestinationID: destinations[2].ID,
+		LaunchDate:    time.Now().UTC().Add(10 * 24 * time.Hour).Truncate(time.Minute),
+	}
+	return types.OrderEvent{ID: uuid.New().String(), Type: eventType, OrderID: o.ID, Order: &o}, launchpad, lr, dr
+}
+
+func TestNotifications_PublishCreated(t *testing.T) {
+	e, launchpad, lr, dr := prepareNotificationEvent(t, types.OrderEventCreated)
+	repo := &mockNotificationsRepo{}
+	repo.On("Insert", mock.Anything, mock.Anything).
+		Return(func(_ context.Context, docs []types.Notification) error {
+			require.Len(t, docs, 2)
+			confirmed, reminder := docs[0], docs[1]
+			require.Equal(t, types.NotificationKindBookingConfirmed, confirmed.Kind)
+			require.Equal(t, e.Order.Email, confirmed.Recipient)
+			require.Equal(t, e.ID, confirmed.EventID)
+			require.Equal(t, types.NotificationStatusPending, confirmed.Status)
+			require.Equal(t, "Your flight to Mars is booked", confirmed.Subject)
+			require.Contains(t, confirmed.Body, e.Order.FirstName)
+			require.Contains(t, confirmed.Body, "Kennedy Space Center")
+			require.Contains(t, confirmed.Body, e.Order.LaunchDate.In(launchpad.Location).Format(launchDateLayout))
+			require.WithinDuration(t, time.Now(), confirmed.NextAttemptAt, time.Second)
+
+			require.Equal(t, types.NotificationKindLaunchReminder, reminder.Kind)
+			require.Equal(t, e.Order.LaunchDate.Add(-launchReminderBefore), reminder.NextAttemptAt)
+			return nil
+		})
+
+	require.NoError(t, NewNotifications(repo, nil, lr, dr, logger.New()).Publish(context.TODO(), e))
+	repo.AssertExpectations(t)
+}
+
+func TestNotifications_PublishVirtualClock(t *testing.T) {
+	e, _, lr, dr := prepareNotificationEvent(t, types.OrderEventCreated)
+	// reminder time has already come in virtual time
+	now := e.Order.LaunchDate.Add(-12 * time.Hour)
+	c := &mockTimeSource{}
+	c.On("Now").Return(now)
+	repo := &mockNotificationsRepo{}
+	repo.On("Insert", mock.Anything, mock.Anything).
+		Return(func(_ context.Context, docs []types.Notification) error {
+			require.Len(t, docs, 1)
+			require.Equal(t, types.NotificationKindBookingConfirmed, docs[0].Kind)
+			require.Equal(t, now, docs[0].NextAttemptAt)
+			return nil
+		})
+
+	require.NoError(t, NewNotifications(repo, nil, lr, dr, logger.New()).WithClock(c).Publish(context.TODO(), e))
+	repo.AssertExpectations(t)
+	c.AssertExpectations(t)
+}
+
+func TestNotifications_PublishCancelled(t *testing.T) {
+	e, _, lr, dr := prepareNotificationEvent(t, types.OrderEventCancelled)
+	repo := &mockNotificationsRepo{}
+	repo.On("CancelPending", mock.Anything, e.OrderID, types.NotificationKindLaunchReminder).Return(nil)
+	repo.On("Insert", mock.Anything, mock.Anything).
+		Return(func(_ context.Context, docs []types.Notification) error {
+			require.Len(t, docs, 1)
+			require.Equal(t, types.NotificationKindBookingCancelled, docs[0].Kind)
+			return nil
+		})
+
+	require.NoError(t, NewNotifications(repo, nil, lr, dr, logger.New()).Publish(context.TODO(), e))
+	repo.AssertExpectations(t)
+}
+
+func TestNotifications_PublishConflicted(t *testing.T) {
+	e, _, lr, dr := prepareNotificationEvent(t, types.OrderEventConflicted)
+	e.Order.Status = types.OrderStatusConflict
+	e.Order.ConflictReason = types.FlightImpossibleReasonCompetitorLaunch
+	repo := &mockNotificationsRepo{}
+	repo.On("CancelPending", mock.Anything, e.OrderID, types.NotificationKindLaunchReminder).Return(nil)
+	repo.On("Insert", mock.Anything, mock.Anything).
+		Return(func(_ context.Context, docs []types.Notification) error {
+			require.Len(t, docs, 1)
+			require.Equal(t, types.NotificationKindBookingConflicted, docs[0].Kind)
+			require.Contains(t, docs[0].Body, types.FlightImpossibleReasonCompetitorLaunch)
+			return nil
+		})
+
+	require.NoError(t, NewNotifications(repo, nil, lr, dr, logger.New()).Publish(context.TODO(), e))
+	repo.AssertExpectations(t)
+}
+
+func TestNotifications_PublishWithoutEmail(t *testing.T) {
+	e, _, lr, dr := prepareNotificationEvent(t, types.OrderEventCreated)
+	e.Order.Email = ""
+	require.NoError(t, NewNotifications(&mockNotificationsRepo{}, nil, lr, dr, logger.New()).Publish(context.TODO(), e))
+}
+
+func TestNotifications_ProcessDue(t *testing.T) {
+	doc := types.Notification{
+		ID:        uuid.New().String(),
+		Recipient: gofakeit.Email(),
+		Subject:   "subject",
+		Body:      "body",
+		Status:    types.NotificationStatusPending,
+	}
+	var sent []types.EmailMessage
+	fail := true
+	sender := emailSenderFunc(func(_ context.Context, msg types.EmailMessage) error {
+		if fail {
+			return errors.New("connection refused")
+		}
+		sent = append(sent, msg)
+		return nil
+	})
+	repo := &mockNotificationsRepo{}
+	repo.On("ClaimDue", mock.Anything, mock.Anything, notificationClaimLease(notificationSendTimeout), notificationBatchSize).
+		Return(func(context.Context, time.Time, time.Duration, int) []types.Notification {
+			return []types.Notification{doc}
+		}, nil)
+	repo.On("Update", mock.Anything, mock.Anything).
+		Return(func(_ context.Context, updated types.Notification) error {
+			doc = updated
+			return nil
+		})
+	n := NewNotifications(repo, sender, nil, nil, logger.New())
+
+	processed, err := n.ProcessDue(context.TODO())
+	require.NoError(t, err)
+	require.Equal(t, 1, processed)
+	require.Equal(t, types.NotificationStatusPending, doc.Status)
+	require.Equal(t, 1, doc.Attempts)
+	require.Equal(t, "connection refused", doc.LastError)
+	require.WithinDuration(t, time.Now().Add(notificationBaseBackoff), doc.NextAttemptAt, time.Second)
+
+	fail = false
+	_, err = n.ProcessDue(context.TODO())
+	require.NoError(t, err)
+	require.Equal(t, types.NotificationStatusSent, doc.Status)
+	require.NotNil(t, doc.SentAt)
+	require.Len(t, sent, 1)
+	require.WithinDuration(t, time.Now(), sent[0].Date, time.Second)
+	sent[0].Date = time.Time{}
+	require.Equal(t, []types.EmailMessage{{To: doc.Recipient, Subject: "subject", Body: "body"}}, sent)
+
+	fail = true
+	doc = n.attempt(context.TODO(), types.Notification{Attempts: notificationMaxAttempts - 1}, time.Now())
+	require.Equal(t, types.NotificationStatusFailed, doc.Status)
+}
+
+func TestNotifications_ClaimLeaseOutlastsBatch(t *testing.T) {
+	sendTimeout := 2 * time.Minute
+	repo := &mockNotificationsRepo{}
+	repo.On("ClaimDue", mock.Anything, mock.Anything, mock.Anything, notificationBatchSize).
+		Return(func(_ context.Context, _ time.Time, lease time.Duration, _ int) []types.Notification {
+			require.Greater(t, lease, notificationBatchSize*sendTimeout)
+			return nil
+		}, nil)
+
+	_, err := NewNotifications(repo, nil, nil, nil, logger.New()).WithSendTimeout(sendTimeout).ProcessDue(context.TODO())
+	require.NoError(t, err)
+	repo.AssertExpectations(t)
+}
+
+func TestRenderNotification(t *testing.T) {
+	for _, kind := range []string{
+		types.NotificationKindBookingConfirmed,
+		types.NotificationKindBookingCancelled,
+		types.NotificationKindBookingRescheduled,
+		types.NotificationKindBookingConflicted,
+		types.NotificationKindLaunchReminder,
+	} {
+		subject, body, err := renderNotification(kind, notificationData{
+			Order:           types.Order{ID: "1", FirstName: "Vasyl"},
+			Passengers:      []types.Passenger{{FirstName: "Vasyl", LastName: "Osypchuk"}, {FirstName: "Olena", LastName: "Osypchuk"}},
+			DestinationName: "Mars",
+		})
+		require.NoError(t, err)
+		require.Contains(t, subject, "Mars")
+		require.Contains(t, body, "Hello Vasyl")
+		require.Contains(t, body, "  - Olena Osypchuk\n")
+	}
+	_, _, err := renderNotification("unknown", notificationData{})
+	require.Error(t, err)
+}
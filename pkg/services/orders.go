@@ -2,23 +2,35 @@ package services
 
 import (
 	"context"
+	"fmt"
+	"math"
 	"time"
 
 	"github.com/google/uuid"
 
+	"github.com/leveldorado/space-trouble/pkg/tools/clock"
 	"github.com/leveldorado/space-trouble/pkg/types"
 	"github.com/pkg/errors"
 )
 
 type orderRepo interface {
 	Get(ctx context.Context, id string) (types.Order, error)
-	Delete(ctx context.Context, id string) error
+	Delete(ctx context.Context, now time.Time, id string) error
 	List(ctx context.Context, limit, offset int) ([]types.Order, error)
+	ListByLaunchpad(ctx context.Context, launchpadID string, from, to time.Time) ([]types.Order, error)
 	Insert(ctx context.Context, o types.Order) error
+	Stream(ctx context.Context, limit, offset int, fn func(types.Order) error) error
+	InsertMany(ctx context.Context, docs []types.Order) error
+	TakenSeats(ctx context.Context, launchpadID, launchLocalDate string) (int, error)
+	History(ctx context.Context, orderID string) ([]types.OrderAuditEntry, error)
+	ListByStatus(ctx context.Context, status string, from time.Time, limit, offset int) ([]types.Order, error)
+	MarkConflict(ctx context.Context, now time.Time, checked types.Order, reason string) error
+	Reschedule(ctx context.Context, now time.Time, doc types.Order) error
 }
 
 type launchpadRepo interface {
 	Get(ctx context.Context, id string) (types.Launchpad, error)
+	List(ctx context.Context) ([]types.Launchpad, error)
 }
 
 type destinationRepo interface {
@@ -31,6 +43,14 @@ type launchpadFirstDestinationRepo interface {
 
 type competitorLaunchesRepo interface {
 	CheckLaunches(ctx context.Context, launchpad string, localDate time.Time) (bool, error)
+	ListLaunches(ctx context.Context, launchpad string, from, to time.Time) ([]types.Launch, error)
+}
+
+/*
+timeSource tells current time, actual one or virtual in staging
+*/
+type timeSource interface {
+	Now() time.Time
 }
 
 type Orders struct {
@@ -39,6 +59,7 @@ type Orders struct {
 	destinationRepo               destinationRepo
 	launchpadFirstDestinationRepo launchpadFirstDestinationRepo
 	competitorLaunchesRepo        competitorLaunchesRepo
+	clock                         timeSource
 }
 
 func NewOrders(
@@ -54,9 +75,18 @@ func NewOrders(
 		destinationRepo:               dr,
 		launchpadFirstDestinationRepo: lfr,
 		competitorLaunchesRepo:        cr,
+		clock:                         clock.Real{},
 	}
 }
 
+/*
+WithClock replaces actual time used for booking cutoffs, creation time of orders and conflicts checks
+*/
+func (s *Orders) WithClock(c timeSource) *Orders {
+	s.clock = c
+	return s
+}
+
 /*
 Create
 validate launchpad id and destination id
@@ -69,34 +99,225 @@ launchpad destination on date calculates by logic
 	    and shift destinations by diff days in destination list (required destinations to be sorted)
 */
 func (s *Orders) Create(ctx context.Context, o types.Order) (string, error) {
+	o, err := s.prepare(ctx, o)
+	if err != nil {
+		return "", err
+	}
+	if err = s.orderRepo.Insert(ctx, o); err != nil {
+		return "", errors.Wrapf(err, `failed to insert order: o - %+v`, o)
+	}
+	return o.ID, nil
+}
+
+/*
+CreateWithID books order under provided id, so booking can be retried without booking order twice.
+
+	ErrDuplicatedOrder returned when order with id is already booked
+*/
+func (s *Orders) CreateWithID(ctx context.Context, id string, o types.Order) error {
+	o, err := s.prepare(ctx, o)
+	if err != nil {
+		return err
+	}
+	o.ID = id
+	return errors.Wrapf(s.orderRepo.Insert(ctx, o), `failed to insert order: o - %+v`, o)
+}
+
+/*
+prepare checks if flight is possible and fills generated fields of order
+*/
+func (s *Orders) prepare(ctx context.Context, o types.Order) (types.Order, error) {
 	launchpad, err := s.launchpadRepo.Get(ctx, o.LaunchpadID)
 	if errors.As(err, &types.ErrNotFound{}) {
-		return "", types.NewErrInvalidData("invalid launchpad id")
+		return types.Order{}, types.NewErrInvalidData("invalid launchpad id")
 	}
 	if err != nil {
-		return "", errors.Wrapf(err, `failed to get launchpad: id - %s`, o.LaunchpadID)
+		return types.Order{}, errors.Wrapf(err, `failed to get launchpad: id - %s`, o.LaunchpadID)
 	}
 	if launchpad.Status != types.LaunchpadStatusActive {
-		return "", types.NewErrInvalidData("launchpad status is not active")
+		return types.Order{}, types.NewErrInvalidData("launchpad status is not active")
+	}
+	if o, err = withLaunchLocalDate(o, launchpad.Location); err != nil {
+		return types.Order{}, err
 	}
+	if err = s.checkFlight(ctx, launchpad, o); err != nil {
+		return types.Order{}, err
+	}
+	o = o.WithPassengers(nil)
+	o.ID = uuid.New().String()
+	o.LaunchDate = o.LaunchDate.UTC()
+	o.Status = types.OrderStatusActive
+	o.ConflictReason = ""
+	o.CreatedAt = s.clock.Now().UTC()
+	return o, nil
+}
+
+/*
+checkFlight returns ErrFlightImpossible when launchpad flies to other destination on launch date or is used by competitor
+*/
+func (s *Orders) checkFlight(ctx context.Context, launchpad types.Launchpad, o types.Order) error {
 	if err := s.checkLaunchpadDestination(ctx, launchpad, o); err != nil {
-		return "", err
+		return err
 	}
 	exists, err := s.competitorLaunchesRepo.CheckLaunches(ctx, o.LaunchpadID, o.LaunchDate.In(launchpad.Location))
 	if err != nil {
-		return "", errors.Wrapf(err, `failed to list competitor launches by date: launchpad - %s, date - %s`, o.LaunchDate, o.LaunchDate)
+		return errors.Wrapf(err, `failed to list competitor launches by date: launchpad - %s, date - %s`, o.LaunchpadID, o.LaunchDate)
 	}
 	if exists {
-		return "", types.ErrFlightImpossible{}
+		return types.NewErrFlightImpossible(types.FlightImpossibleReasonCompetitorLaunch)
 	}
-	o.ID = uuid.New().String()
-	o.LaunchDate = o.LaunchDate.UTC()
-	o.CreatedAt = time.Now().UTC()
-	return o.ID, errors.Wrapf(s.orderRepo.Insert(ctx, o), `failed to insert order: o - %+v`, o)
+	return nil
+}
+
+/*
+Import checks each row by the same rules as Create and inserts feasible ones.
+
+	rows failed by validation, feasibility or seats left on flight are reported with error message,
+	other check errors stop import before any insert.
+	rows which failed to insert are reported with error as well, so report always lists ids of committed rows.
+	in dry run mode nothing is inserted.
+	in all or nothing mode rows are inserted in single transaction and only if every row is feasible
+*/
+func (s *Orders) Import(ctx context.Context, rows []types.ImportRow, opts types.ImportOptions) (types.ImportReport, error) {
+	report := types.ImportReport{
+		ImportOptions: opts,
+		Total:         len(rows),
+		Rows:          make([]types.ImportRowResult, len(rows)),
+	}
+	prepared := make([]types.Order, len(rows))
+	for i, row := range rows {
+		report.Rows[i].Line = row.Line
+		o, err := s.prepareImportRow(ctx, row)
+		if err != nil && !isRejection(err) {
+			return types.ImportReport{}, errors.Wrapf(err, `failed to check row: line - %d`, row.Line)
+		}
+		if err != nil {
+			report.Rows[i].Status = types.ImportRowStatusFailed
+			report.Rows[i].Error = err.Error()
+			report.Failed++
+			continue
+		}
+		report.Rows[i].Status = types.ImportRowStatusOK
+		prepared[i] = o
+	}
+	if err := s.checkImportSeats(ctx, &report, prepared); err != nil {
+		return types.ImportReport{}, err
+	}
+	switch {
+	case opts.DryRun:
+		report.Succeeded = report.Total - report.Failed
+	case opts.AllOrNothing:
+		return s.importAllOrNothing(ctx, report, prepared)
+	default:
+		for i := range report.Rows {
+			if report.Rows[i].Status != types.ImportRowStatusOK {
+				continue
+			}
+			// rows inserted before stay committed, so failed insert is reported on its row instead of failing whole import
+			if err := s.orderRepo.Insert(ctx, prepared[i]); err != nil {
+				report.Rows[i].Status = types.ImportRowStatusFailed
+				report.Rows[i].Error = errors.Wrapf(err, `failed to insert order: line - %d`, report.Rows[i].Line).Error()
+				report.Failed++
+				continue
+			}
+			report.Rows[i].Status = types.ImportRowStatusCreated
+			report.Rows[i].ID = prepared[i].ID
+			report.Succeeded++
+		}
+		report.Committed = report.Succeeded > 0
+	}
+	return report, nil
+}
+
+func (s *Orders) importAllOrNothing(ctx context.Context, report types.ImportReport, prepared []types.Order) (types.ImportReport, error) {
+	if report.Failed > 0 {
+		for i := range report.Rows {
+			if report.Rows[i].Status == types.ImportRowStatusOK {
+				report.Rows[i].Status = types.ImportRowStatusSkipped
+			}
+		}
+		return report, nil
+	}
+	if len(prepared) == 0 {
+		return report, nil
+	}
+	if err := s.orderRepo.InsertMany(ctx, prepared); err != nil {
+		if !isRejection(err) {
+			return types.ImportReport{}, errors.Wrapf(err, `failed to insert orders: count - %d`, len(prepared))
+		}
+		// flight may get booked after check, whole transaction is rolled back so every row is reported with rejection
+		for i := range report.Rows {
+			report.Rows[i].Status = types.ImportRowStatusFailed
+			report.Rows[i].Error = errors.Cause(err).Error()
+		}
+		report.Failed = report.Total
+		return report, nil
+	}
+	for i := range report.Rows {
+		report.Rows[i].Status = types.ImportRowStatusCreated
+		report.Rows[i].ID = prepared[i].ID
+	}
+	report.Succeeded = report.Total
+	report.Committed = true
+	return report, nil
+}
+
+/*
+checkImportSeats fails feasible rows which passengers do not fit into seats left on their flight.
+
+	seats are counted as on insert: taken by stored active orders and by feasible rows above in the same import
+*/
+func (s *Orders) checkImportSeats(ctx context.Context, report *types.ImportReport, prepared []types.Order) error {
+	taken := map[string]int{}
+	for i := range report.Rows {
+		if report.Rows[i].Status != types.ImportRowStatusOK {
+			continue
+		}
+		o := prepared[i]
+		flight := o.LaunchpadID + "/" + o.LaunchLocalDate
+		seats, ok := taken[flight]
+		if !ok {
+			var err error
+			if seats, err = s.orderRepo.TakenSeats(ctx, o.LaunchpadID, o.LaunchLocalDate); err != nil {
+				return errors.Wrapf(err, `failed to count taken seats: flight - %s`, flight)
+			}
+			taken[flight] = seats
+		}
+		passengers := len(o.PassengerList())
+		if seats+passengers > types.FlightSeats {
+			report.Rows[i].Status = types.ImportRowStatusFailed
+			report.Rows[i].Error = types.NewErrFlightImpossible(types.FlightImpossibleReasonNoSeats).Error()
+			report.Failed++
+			continue
+		}
+		taken[flight] = seats + passengers
+	}
+	return nil
+}
+
+func (s *Orders) prepareImportRow(ctx context.Context, row types.ImportRow) (types.Order, error) {
+	if row.Error != "" {
+		return types.Order{}, types.NewErrInvalidData(row.Error)
+	}
+	if err := row.Order.Validate(); err != nil {
+		return types.Order{}, types.NewErrInvalidData(err.Error())
+	}
+	return s.prepare(ctx, row.Order)
+}
+
+/*
+isRejection reports if error caused by order itself and not by failure of dependencies
+*/
+func isRejection(err error) bool {
+	switch errors.Cause(err).(type) {
+	case types.ErrInvalidData, types.ErrFlightImpossible, types.ErrDuplicatedOrder:
+		return true
+	}
+	return false
 }
 
 func (s *Orders) checkLaunchpadDestination(ctx context.Context, launchpad types.Launchpad, o types.Order) error {
-	if hasDatePassed(o.LaunchDate, launchpad.Location) {
+	if hasDatePassed(s.clock.Now(), o.LaunchDate, launchpad.Location) {
 		return types.NewErrInvalidData("launch date has passed")
 	}
 	firstDestination, err := s.launchpadFirstDestinationRepo.Get(ctx, o.LaunchpadID)
@@ -108,14 +329,14 @@ func (s *Orders) checkLaunchpadDestination(ctx context.Context, launchpad types.
 		return errors.Wrap(err, `failed to get destinations`)
 	}
 	if len(destinations) == 0 {
-		return types.ErrFlightImpossible{}
+		return types.NewErrFlightImpossible(types.FlightImpossibleReasonDestination)
 	}
 	destinationID, err := calculateDestinationForDate(o.LaunchDate, launchpad.Location, firstDestination, destinations)
 	if err != nil {
 		return err
 	}
 	if destinationID != o.DestinationID {
-		return types.ErrFlightImpossible{}
+		return types.NewErrFlightImpossible(types.FlightImpossibleReasonDestination)
 	}
 	return nil
 }
@@ -124,7 +345,8 @@ func calculateDestinationForDate(requestedDate time.Time, location *time.Locatio
 	year, month, day := requestedDate.In(location).Date()
 	requestedDateStartOfDay := time.Date(year, month, day, 0, 0, 0, 0, location)
 	firstLaunchStartOfDay := time.Date(first.LocalYear, time.Month(first.LocalMonth), first.LocalDay, 0, 0, 0, 0, location)
-	daysShift := int(requestedDateStartOfDay.Sub(firstLaunchStartOfDay).Hours() / 24)
+	// rounding keeps days with daylight saving transitions from being counted as 23 or 25 hours
+	daysShift := int(math.Round(requestedDateStartOfDay.Sub(firstLaunchStartOfDay).Hours() / 24))
 	destinationsN := len(destinations)
 	var firstDestinationOrder int
 	var destinationFound bool
@@ -138,7 +360,7 @@ func calculateDestinationForDate(requestedDate time.Time, location *time.Locatio
 	if !destinationFound {
 		return "", errors.Errorf(`first destination is not present in destinations: first - %+v, destinations: - %+v`, first, destinations)
 	}
-	destinationsShift := daysShift % destinationsN
+	destinationsShift := (daysShift%destinationsN + destinationsN) % destinationsN
 	destinationOrder := firstDestinationOrder + destinationsShift
 	if destinationOrder > destinationsN {
 		destinationOrder = destinationOrder - destinationsN
@@ -146,8 +368,8 @@ func calculateDestinationForDate(requestedDate time.Time, location *time.Locatio
 	return destinations[destinationOrder-1].ID, nil
 }
 
-func hasDatePassed(requestedLaunchDate time.Time, location *time.Location) bool {
-	nowYear, nowMonth, nowDay := time.Now().In(location).Date()
+func hasDatePassed(now, requestedLaunchDate time.Time, location *time.Location) bool {
+	nowYear, nowMonth, nowDay := now.In(location).Date()
 	requestedYear, requestedMonth, requestedDay := requestedLaunchDate.In(location).Date()
 	if requestedYear < nowYear {
 		return true
@@ -168,17 +390,198 @@ func hasDatePassed(requestedLaunchDate time.Time, location *time.Location) bool
 }
 
 func (s *Orders) Get(ctx context.Context, id string) (types.Order, error) {
-	return s.orderRepo.Get(ctx, id)
+	o, err := s.orderRepo.Get(ctx, id)
+	if err != nil {
+		return types.Order{}, err
+	}
+	if err = s.fillLaunchLocalDate(ctx, &o, map[string]*time.Location{}); err != nil {
+		return types.Order{}, err
+	}
+	return o, nil
+}
+
+/*
+History returns audit entries of order oldest first, history stays available after order is deleted
+*/
+func (s *Orders) History(ctx context.Context, id string) ([]types.OrderAuditEntry, error) {
+	entries, err := s.orderRepo.History(ctx, id)
+	if err != nil {
+		return nil, errors.Wrapf(err, `failed to get history: id - %s`, id)
+	}
+	if len(entries) == 0 {
+		return nil, types.ErrNotFound{}
+	}
+	return entries, nil
 }
 
 func (s *Orders) List(ctx context.Context, limit, offset int) ([]types.Order, error) {
-	return s.orderRepo.List(ctx, limit, offset)
+	orders, err := s.orderRepo.List(ctx, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	locations := map[string]*time.Location{}
+	for i := range orders {
+		if err = s.fillLaunchLocalDate(ctx, &orders[i], locations); err != nil {
+			return nil, err
+		}
+	}
+	return orders, nil
 }
 
 func (s *Orders) Delete(ctx context.Context, id string) error {
-	return s.orderRepo.Delete(ctx, id)
+	return s.orderRepo.Delete(ctx, s.clock.Now().UTC(), id)
+}
+
+/*
+Export streams orders with launch local date in launchpad timezone and destination name resolved.
+
+	launchpads are requested once per export
+*/
+func (s *Orders) Export(ctx context.Context, limit, offset int, fn func(types.OrderExport) error) error {
+	destinations, err := s.destinationRepo.ListSorted(ctx)
+	if err != nil {
+		return errors.Wrap(err, `failed to get destinations`)
+	}
+	names := make(map[string]string, len(destinations))
+	for _, d := range destinations {
+		names[d.ID] = d.Name
+	}
+	locations := map[string]*time.Location{}
+	return s.orderRepo.Stream(ctx, limit, offset, func(o types.Order) error {
+		if err := s.fillLaunchLocalDate(ctx, &o, locations); err != nil {
+			return err
+		}
+		return fn(types.OrderExport{
+			Order:           o,
+			DestinationName: names[o.DestinationID],
+		})
+	})
+}
+
+/*
+fillLaunchLocalDate sets launch local date and timezone of orders booked before they were stored,
+locations caches launchpad timezones between calls
+*/
+func (s *Orders) fillLaunchLocalDate(ctx context.Context, o *types.Order, locations map[string]*time.Location) error {
+	if o.LaunchpadTimezone != "" {
+		return nil
+	}
+	location, ok := locations[o.LaunchpadID]
+	if !ok {
+		var err error
+		location, err = s.launchpadLocation(ctx, o.LaunchpadID)
+		if err != nil {
+			return err
+		}
+		locations[o.LaunchpadID] = location
+	}
+	o.LaunchLocalDate = o.LaunchDate.In(location).Format(types.LocalDateLayout)
+	o.LaunchpadTimezone = location.String()
+	return nil
+}
+
+/*
+withLaunchLocalDate resolves launch date of order in launchpad timezone.
+
+	local date alone means start of local day, launch date sent together with local date should fall on that day
+*/
+func withLaunchLocalDate(o types.Order, location *time.Location) (types.Order, error) {
+	if o.LaunchLocalDate != "" {
+		localDate, err := time.ParseInLocation(types.LocalDateLayout, o.LaunchLocalDate, location)
+		if err != nil {
+			return types.Order{}, types.NewErrInvalidData("launch_local_date should be in " + types.LocalDateLayout + " format")
+		}
+		switch {
+		case o.LaunchDate.IsZero():
+			o.LaunchDate = localDate
+		case o.LaunchDate.In(location).Format(types.LocalDateLayout) != o.LaunchLocalDate:
+			return types.Order{}, types.NewErrInvalidData("launch_date is not on launch_local_date in launchpad timezone")
+		}
+	}
+	o.LaunchLocalDate = o.LaunchDate.In(location).Format(types.LocalDateLayout)
+	o.LaunchpadTimezone = location.String()
+	return o, nil
+}
+
+/*
+launchpadLocation returns UTC for unknown launchpads so orders of removed launchpads still can be reported
+*/
+func (s *Orders) launchpadLocation(ctx context.Context, id string) (*time.Location, error) {
+	launchpad, err := s.launchpadRepo.Get(ctx, id)
+	if errors.As(err, &types.ErrNotFound{}) {
+		return time.UTC, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, `failed to get launchpad: id - %s`, id)
+	}
+	return launchpad.Location, nil
 }
 
 func (s *Orders) Destinations(ctx context.Context) ([]types.Destination, error) {
 	return s.destinationRepo.ListSorted(ctx)
 }
+
+const (
+	maxScheduleDays = 366
+)
+
+/*
+LaunchpadSchedule returns destinations of launchpad for each local day starting from provided date.
+
+	days with competitor launches from the launchpad are marked as blocked
+*/
+func (s *Orders) LaunchpadSchedule(ctx context.Context, launchpadID string, from time.Time, days int) ([]types.LaunchpadScheduleDay, error) {
+	if days <= 0 || days > maxScheduleDays {
+		return nil, types.NewErrInvalidData(fmt.Sprintf("days should be between 1 and %d", maxScheduleDays))
+	}
+	launchpad, err := s.launchpadRepo.Get(ctx, launchpadID)
+	if errors.As(err, &types.ErrNotFound{}) {
+		return nil, types.NewErrInvalidData("invalid launchpad id")
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, `failed to get launchpad: id - %s`, launchpadID)
+	}
+	firstDestination, err := s.launchpadFirstDestinationRepo.Get(ctx, launchpadID)
+	if err != nil {
+		return nil, errors.Wrapf(err, `failed to get first destination for launchpad: id - %s`, launchpadID)
+	}
+	destinations, err := s.destinationRepo.ListSorted(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, `failed to get destinations`)
+	}
+	if len(destinations) == 0 {
+		return nil, nil
+	}
+	names := make(map[string]string, len(destinations))
+	for _, d := range destinations {
+		names[d.ID] = d.Name
+	}
+	year, month, day := from.In(launchpad.Location).Date()
+	start := time.Date(year, month, day, 0, 0, 0, 0, launchpad.Location)
+	end := time.Date(year, month, day+days, 0, 0, 0, 0, launchpad.Location)
+	launches, err := s.competitorLaunchesRepo.ListLaunches(ctx, launchpadID, start, end)
+	if err != nil {
+		return nil, errors.Wrapf(err, `failed to list competitor launches: launchpad - %s`, launchpadID)
+	}
+	blocked := make(map[string]bool, len(launches))
+	for _, l := range launches {
+		blocked[l.DateUTC.In(launchpad.Location).Format(types.LocalDateLayout)] = true
+	}
+	var schedule []types.LaunchpadScheduleDay
+	for i := 0; i < days; i++ {
+		date := time.Date(year, month, day+i, 0, 0, 0, 0, launchpad.Location)
+		destinationID, err := calculateDestinationForDate(date, launchpad.Location, firstDestination, destinations)
+		if err != nil {
+			return nil, err
+		}
+		localDate := date.Format(types.LocalDateLayout)
+		schedule = append(schedule, types.LaunchpadScheduleDay{
+			LaunchpadID:     launchpadID,
+			LocalDate:       localDate,
+			DestinationID:   destinationID,
+			DestinationName: names[destinationID],
+			Blocked:         blocked[localDate],
+		})
+	}
+	return schedule, nil
+}
@@ -66,8 +66,15 @@ func prepareOrder(t *testing.T, lID, dID string, launchDate time.Time) (types.Or
 	or.On("Insert", mock.Anything, mock.Anything).
 		Return(func(ctx context.Context, doc types.Order) error {
 			doc.ID = ""
+			o = o.WithPassengers(nil)
 			o.LaunchDate = o.LaunchDate.UTC()
 			o.CreatedAt = doc.CreatedAt
+			// prepareLaunchpad places launchpads in America/New_York
+			launchpadLocation, err := time.LoadLocation("America/New_York")
+			require.NoError(t, err)
+			o.LaunchLocalDate = o.LaunchDate.In(launchpadLocation).Format(types.LocalDateLayout)
+			o.LaunchpadTimezone = launchpadLocation.String()
+			o.Status = types.OrderStatusActive
 			require.Equal(t, o, doc)
 			return nil
 		})
@@ -104,6 +111,74 @@ func TestOrders_CreateSuccess(t *testing.T) {
 	clr.AssertExpectations(t)
 }
 
+func TestOrders_CreateVirtualClock(t *testing.T) {
+	launchpad, lr := prepareLaunchpad(t)
+	destinations, dr := prepareDestinations()
+	lfr := prepareFirstDestinationRepo(launchpad.ID, destinations[0].ID, 2053, 3, 3)
+	launchDate := time.Date(2053, 3, 5, 12, 0, 0, 0, launchpad.Location)
+	o, or := prepareOrder(t, launchpad.ID, destinations[2].ID, launchDate)
+	clr := prepareCompetitorsLaunchesRepo(launchpad.ID, launchDate, false)
+	// launch day has passed in launchpad timezone
+	passed := &mockTimeSource{}
+	passed.On("Now").Return(time.Date(2053, 3, 6, 5, 0, 0, 0, time.UTC))
+
+	_, err := NewOrders(or, lr, dr, lfr, clr).WithClock(passed).Create(context.TODO(), o)
+	require.True(t, errors.As(err, &types.ErrInvalidData{}))
+	or.AssertNotCalled(t, "Insert", mock.Anything, mock.Anything)
+
+	// still the same day in launchpad timezone
+	now := time.Date(2053, 3, 6, 4, 0, 0, 0, time.UTC)
+	sameDay := &mockTimeSource{}
+	sameDay.On("Now").Return(now)
+	or = &mockOrderRepo{}
+	or.On("Insert", mock.Anything, mock.Anything).Return(func(_ context.Context, doc types.Order) error {
+		require.Equal(t, now, doc.CreatedAt)
+		return nil
+	})
+	_, err = NewOrders(or, lr, dr, lfr, clr).WithClock(sameDay).Create(context.TODO(), o)
+	require.NoError(t, err)
+	or.AssertExpectations(t)
+	passed.AssertExpectations(t)
+	sameDay.AssertExpectations(t)
+}
+
+func TestOrders_CreateLaunchLocalDate(t *testing.T) {
+	launchpad, lr := prepareLaunchpad(t)
+	destinations, dr := prepareDestinations()
+	lfr := prepareFirstDestinationRepo(launchpad.ID, destinations[0].ID, 2053, 3, 3)
+	// midnight in Kyiv is previous day in launchpad timezone, local date keeps the day client meant
+	kyiv, err := time.LoadLocation("Europe/Kyiv")
+	require.NoError(t, err)
+	launchLocalDate := time.Date(2053, 3, 5, 0, 0, 0, 0, launchpad.Location)
+	clr := prepareCompetitorsLaunchesRepo(launchpad.ID, launchLocalDate, false)
+	or := &mockOrderRepo{}
+	or.On("Insert", mock.Anything, mock.Anything).
+		Return(func(_ context.Context, doc types.Order) error {
+			require.Equal(t, launchLocalDate.UTC(), doc.LaunchDate)
+			require.Equal(t, "2053-03-05", doc.LaunchLocalDate)
+			require.Equal(t, "America/New_York", doc.LaunchpadTimezone)
+			return nil
+		})
+	o := types.Order{
+		FirstName:       gofakeit.FirstName(),
+		LastName:        gofakeit.LastName(),
+		LaunchpadID:     launchpad.ID,
+		DestinationID:   destinations[2].ID,
+		LaunchLocalDate: "2053-03-05",
+	}
+
+	s := NewOrders(or, lr, dr, lfr, clr)
+	_, err = s.Create(context.TODO(), o)
+	require.NoError(t, err)
+
+	o.LaunchDate = time.Date(2053, 3, 5, 0, 0, 0, 0, kyiv)
+	_, err = s.Create(context.TODO(), o)
+	require.True(t, errors.As(err, &types.ErrInvalidData{}))
+
+	or.AssertNumberOfCalls(t, "Insert", 1)
+	clr.AssertExpectations(t)
+}
+
 func TestOrders_CreateWrongDestination(t *testing.T) {
 	launchpad, lr := prepareLaunchpad(t)
 	destinations, dr := prepareDestinations()
@@ -119,7 +194,7 @@ func TestOrders_CreateWrongDestination(t *testing.T) {
 
 	_, err = s.Create(context.TODO(), o)
 	require.Error(t, err)
-	require.True(t, errors.As(err, &types.ErrFlightImpossible{}))
+	require.Equal(t, types.NewErrFlightImpossible(types.FlightImpossibleReasonDestination), errors.Cause(err))
 
 	lr.AssertExpectations(t)
 	dr.AssertExpectations(t)
@@ -142,10 +217,367 @@ func TestOrders_CreateCompetitorLaunch(t *testing.T) {
 
 	_, err = s.Create(context.TODO(), o)
 	require.Error(t, err)
-	require.True(t, errors.As(err, &types.ErrFlightImpossible{}))
+	require.Equal(t, types.NewErrFlightImpossible(types.FlightImpossibleReasonCompetitorLaunch), errors.Cause(err))
 
 	lr.AssertExpectations(t)
 	dr.AssertExpectations(t)
 	lfr.AssertExpectations(t)
 	clr.AssertExpectations(t)
 }
+
+func TestOrders_LaunchpadSchedule(t *testing.T) {
+	launchpad, lr := prepareLaunchpad(t)
+	destinations, dr := prepareDestinations()
+	lfr := prepareFirstDestinationRepo(launchpad.ID, destinations[0].ID, 2053, 3, 3)
+	// launch at 2053-03-06 01:00 UTC is 2053-03-05 20:00 in America/New_York
+	clr := &mockCompetitorLaunchesRepo{}
+	clr.On("ListLaunches", mock.Anything, launchpad.ID,
+		time.Date(2053, 3, 1, 0, 0, 0, 0, launchpad.Location),
+		time.Date(2053, 3, 21, 0, 0, 0, 0, launchpad.Location)).
+		Return([]types.Launch{{ID: uuid.New().String(), DateUTC: time.Date(2053, 3, 6, 1, 0, 0, 0, time.UTC)}}, nil)
+
+	s := NewOrders(nil, lr, dr, lfr, clr)
+
+	// 2053-03-09 is daylight saving transition day in America/New_York
+	from := time.Date(2053, 3, 1, 12, 0, 0, 0, time.UTC)
+	schedule, err := s.LaunchpadSchedule(context.TODO(), launchpad.ID, from, 20)
+	require.NoError(t, err)
+	require.Len(t, schedule, 20)
+	for i, day := range schedule {
+		expectedDestination := destinations[((i-2)%len(destinations)+len(destinations))%len(destinations)]
+		require.Equal(t, time.Date(2053, 3, 1+i, 0, 0, 0, 0, time.UTC).Format(types.LocalDateLayout), day.LocalDate)
+		require.Equal(t, expectedDestination.ID, day.DestinationID, day.LocalDate)
+		require.Equal(t, launchpad.ID, day.LaunchpadID)
+		require.Equal(t, day.LocalDate == "2053-03-05", day.Blocked, day.LocalDate)
+	}
+
+	_, err = s.LaunchpadSchedule(context.TODO(), launchpad.ID, from, 0)
+	require.True(t, errors.As(err, &types.ErrInvalidData{}))
+
+	lr.AssertExpectations(t)
+	dr.AssertExpectations(t)
+	lfr.AssertExpectations(t)
+	clr.AssertExpectations(t)
+}
+
+func TestOrders_Export(t *testing.T) {
+	launchpad, lr := prepareLaunchpad(t)
+	destinations, dr := prepareDestinations()
+	destinations[1].Name = "Mars"
+	unknownLaunchpadID := uuid.New().String()
+	lr.On("Get", mock.Anything, unknownLaunchpadID).Return(types.Launchpad{}, types.ErrNotFound{})
+
+	orders := []types.Order{
+		{
+			ID:            uuid.New().String(),
+			LaunchpadID:   launchpad.ID,
+			DestinationID: destinations[1].ID,
+			LaunchDate:    time.Date(2053, 3, 5, 2, 0, 0, 0, time.UTC),
+		},
+		{
+			ID:            uuid.New().String(),
+			LaunchpadID:   launchpad.ID,
+			DestinationID: destinations[1].ID,
+			LaunchDate:    time.Date(2053, 3, 6, 2, 0, 0, 0, time.UTC),
+		},
+		{
+			ID:            uuid.New().String(),
+			LaunchpadID:   unknownLaunchpadID,
+			DestinationID: destinations[1].ID,
+			LaunchDate:    time.Date(2053, 3, 6, 2, 0, 0, 0, time.UTC),
+		},
+	}
+	or := &mockOrderRepo{}
+	or.On("Stream", mock.Anything, 0, 0, mock.Anything).
+		Return(func(_ context.Context, _, _ int, fn func(types.Order) error) error {
+			for _, o := range orders {
+				if err := fn(o); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+
+	s := NewOrders(or, lr, dr, nil, nil)
+	var exported []types.OrderExport
+	require.NoError(t, s.Export(context.TODO(), 0, 0, func(o types.OrderExport) error {
+		exported = append(exported, o)
+		return nil
+	}))
+	withLocalDate := func(o types.Order, localDate, timezone string) types.Order {
+		o.LaunchLocalDate = localDate
+		o.LaunchpadTimezone = timezone
+		return o
+	}
+	require.Equal(t, []types.OrderExport{
+		{Order: withLocalDate(orders[0], "2053-03-04", "America/New_York"), DestinationName: "Mars"},
+		{Order: withLocalDate(orders[1], "2053-03-05", "America/New_York"), DestinationName: "Mars"},
+		{Order: withLocalDate(orders[2], "2053-03-06", "UTC"), DestinationName: "Mars"},
+	}, exported)
+
+	lr.AssertNumberOfCalls(t, "Get", 2)
+	or.AssertExpectations(t)
+	dr.AssertExpectations(t)
+}
+
+func prepareImport(t *testing.T) (*Orders, []types.ImportRow, *mockOrderRepo) {
+	launchpad, lr := prepareLaunchpad(t)
+	destinations, dr := prepareDestinations()
+	lfr := prepareFirstDestinationRepo(launchpad.ID, destinations[0].ID, 2053, 3, 3)
+	launchDate := time.Date(2053, 3, 4, 12, 0, 0, 0, time.UTC)
+	clr := prepareCompetitorsLaunchesRepo(launchpad.ID, launchDate.In(launchpad.Location), false)
+	feasible := types.Order{
+		FirstName:     gofakeit.FirstName(),
+		LastName:      gofakeit.LastName(),
+		BirthdayYear:  2000,
+		BirthdayMonth: 1,
+		BirthdayDay:   1,
+		LaunchpadID:   launchpad.ID,
+		DestinationID: destinations[1].ID,
+		LaunchDate:    launchDate,
+	}
+	wrongDestination := feasible
+	wrongDestination.DestinationID = destinations[2].ID
+	rows := []types.ImportRow{
+		{Line: 2, Order: feasible},
+		{Line: 3, Order: wrongDestination},
+		{Line: 4, Order: types.Order{FirstName: gofakeit.FirstName()}},
+		{Line: 5, Error: "wrong number of fields"},
+	}
+	or := &mockOrderRepo{}
+	or.On("TakenSeats", mock.Anything, launchpad.ID, mock.Anything).Return(0, nil).Maybe()
+	return NewOrders(or, lr, dr, lfr, clr), rows, or
+}
+
+func TestOrders_ImportDryRun(t *testing.T) {
+	s, rows, or := prepareImport(t)
+
+	report, err := s.Import(context.TODO(), rows, types.ImportOptions{DryRun: true})
+	require.NoError(t, err)
+	require.Equal(t, 4, report.Total)
+	require.Equal(t, 1, report.Succeeded)
+	require.Equal(t, 3, report.Failed)
+	require.False(t, report.Committed)
+	require.Equal(t, types.ImportRowStatusOK, report.Rows[0].Status)
+	require.Empty(t, report.Rows[0].ID)
+	require.Equal(t, types.ImportRowResult{Line: 3, Status: types.ImportRowStatusFailed, Error: types.ErrFlightImpossible{}.Error()}, report.Rows[1])
+	require.Equal(t, types.ImportRowResult{Line: 4, Status: types.ImportRowStatusFailed, Error: "last_name is required"}, report.Rows[2])
+	require.Equal(t, types.ImportRowResult{Line: 5, Status: types.ImportRowStatusFailed, Error: "wrong number of fields"}, report.Rows[3])
+	or.AssertExpectations(t)
+}
+
+func TestOrders_ImportAllOrNothing(t *testing.T) {
+	s, rows, or := prepareImport(t)
+
+	report, err := s.Import(context.TODO(), rows, types.ImportOptions{AllOrNothing: true})
+	require.NoError(t, err)
+	require.False(t, report.Committed)
+	require.Equal(t, 0, report.Succeeded)
+	require.Equal(t, types.ImportRowStatusSkipped, report.Rows[0].Status)
+
+	or.On("InsertMany", mock.Anything, mock.Anything).
+		Return(func(_ context.Context, docs []types.Order) error {
+			require.Len(t, docs, 1)
+			require.Equal(t, rows[0].Order.FirstName, docs[0].FirstName)
+			require.NotEmpty(t, docs[0].ID)
+			return nil
+		})
+	report, err = s.Import(context.TODO(), rows[:1], types.ImportOptions{AllOrNothing: true})
+	require.NoError(t, err)
+	require.True(t, report.Committed)
+	require.Equal(t, 1, report.Succeeded)
+	require.Equal(t, types.ImportRowStatusCreated, report.Rows[0].Status)
+	require.NotEmpty(t, report.Rows[0].ID)
+	or.AssertExpectations(t)
+}
+
+func TestOrders_ImportSeats(t *testing.T) {
+	s, rows, or := prepareImport(t)
+	or.ExpectedCalls = nil
+	or.On("TakenSeats", mock.Anything, rows[0].Order.LaunchpadID, mock.Anything).Return(types.FlightSeats-1, nil).Once()
+	second := rows[0]
+	second.Line = 6
+	noSeats := types.NewErrFlightImpossible(types.FlightImpossibleReasonNoSeats).Error()
+
+	// seats are counted in dry run as well, row which does not fit is reported
+	report, err := s.Import(context.TODO(), []types.ImportRow{rows[0], second}, types.ImportOptions{DryRun: true})
+	require.NoError(t, err)
+	require.Equal(t, 1, report.Succeeded)
+	require.Equal(t, 1, report.Failed)
+	require.Equal(t, types.ImportRowStatusOK, report.Rows[0].Status)
+	require.Equal(t, types.ImportRowResult{Line: 6, Status: types.ImportRowStatusFailed, Error: noSeats}, report.Rows[1])
+
+	// flight booked after check rejects whole transaction, rows are reported
+	or.On("TakenSeats", mock.Anything, rows[0].Order.LaunchpadID, mock.Anything).Return(0, nil).Once()
+	or.On("InsertMany", mock.Anything, mock.Anything).
+		Return(errors.Wrap(types.NewErrFlightImpossible(types.FlightImpossibleReasonNoSeats), "failed to insert order")).Once()
+	report, err = s.Import(context.TODO(), []types.ImportRow{rows[0], second}, types.ImportOptions{AllOrNothing: true})
+	require.NoError(t, err)
+	require.False(t, report.Committed)
+	require.Equal(t, 2, report.Failed)
+	require.Equal(t, types.ImportRowResult{Line: 2, Status: types.ImportRowStatusFailed, Error: noSeats}, report.Rows[0])
+	require.Equal(t, types.ImportRowResult{Line: 6, Status: types.ImportRowStatusFailed, Error: noSeats}, report.Rows[1])
+
+	or.On("TakenSeats", mock.Anything, rows[0].Order.LaunchpadID, mock.Anything).Return(0, errors.New("connection reset")).Once()
+	_, err = s.Import(context.TODO(), rows[:1], types.ImportOptions{DryRun: true})
+	require.Error(t, err)
+	or.AssertExpectations(t)
+}
+
+func TestOrders_Import(t *testing.T) {
+	s, rows, or := prepareImport(t)
+	var insertedID string
+	or.On("Insert", mock.Anything, mock.Anything).
+		Return(func(_ context.Context, doc types.Order) error {
+			insertedID = doc.ID
+			return nil
+		}).Once()
+
+	report, err := s.Import(context.TODO(), rows, types.ImportOptions{})
+	require.NoError(t, err)
+	require.True(t, report.Committed)
+	require.Equal(t, 1, report.Succeeded)
+	require.Equal(t, 3, report.Failed)
+	require.Equal(t, types.ImportRowResult{Line: 2, Status: types.ImportRowStatusCreated, ID: insertedID}, report.Rows[0])
+
+	// insert failure of one row keeps rows committed before it in report
+	second := rows[0]
+	second.Line = 6
+	or.On("Insert", mock.Anything, mock.Anything).Return(nil).Once()
+	or.On("Insert", mock.Anything, mock.Anything).Return(errors.New("connection reset")).Once()
+	report, err = s.Import(context.TODO(), []types.ImportRow{rows[0], second}, types.ImportOptions{})
+	require.NoError(t, err)
+	require.True(t, report.Committed)
+	require.Equal(t, 1, report.Succeeded)
+	require.Equal(t, 1, report.Failed)
+	require.Equal(t, types.ImportRowStatusCreated, report.Rows[0].Status)
+	require.NotEmpty(t, report.Rows[0].ID)
+	require.Equal(t, types.ImportRowStatusFailed, report.Rows[1].Status)
+	require.Contains(t, report.Rows[1].Error, "connection reset")
+	or.AssertExpectations(t)
+}
+
+func TestOrders_CreateGroup(t *testing.T) {
+	launchpad, lr := prepareLaunchpad(t)
+	destinations, dr := prepareDestinations()
+	lfr := prepareFirstDestinationRepo(launchpad.ID, destinations[0].ID, 2053, 3, 3)
+	launchDate := time.Date(2053, 3, 4, 12, 0, 0, 0, time.UTC)
+	clr := prepareCompetitorsLaunchesRepo(launchpad.ID, launchDate.In(launchpad.Location), false)
+
+	passengers := []types.Passenger{
+		{FirstName: gofakeit.FirstName(), LastName: gofakeit.LastName()},
+		{FirstName: gofakeit.FirstName(), LastName: gofakeit.LastName()},
+	}
+	or := &mockOrderRepo{}
+	or.On("Insert", mock.Anything, mock.Anything).
+		Return(func(ctx context.Context, doc types.Order) error {
+			require.Equal(t, passengers, doc.Passengers)
+			require.Equal(t, passengers[0], doc.LeadPassenger())
+			return nil
+		})
+
+	s := NewOrders(or, lr, dr, lfr, clr)
+	_, err := s.Create(context.TODO(), types.Order{
+		Passengers:    passengers,
+		LaunchpadID:   launchpad.ID,
+		DestinationID: destinations[1].ID,
+		LaunchDate:    launchDate,
+	})
+	require.NoError(t, err)
+	or.AssertExpectations(t)
+}
+
+func TestOrders_History(t *testing.T) {
+	id := uuid.New().String()
+	or := &mockOrderRepo{}
+	or.On("History", mock.Anything, id).Return(nil, nil).Once()
+	s := NewOrders(or, nil, nil, nil, nil)
+
+	_, err := s.History(context.TODO(), id)
+	require.True(t, errors.As(err, &types.ErrNotFound{}))
+
+	entries := []types.OrderAuditEntry{
+		{OrderID: id, Action: types.OrderAuditActionCreate},
+		{OrderID: id, Action: types.OrderAuditActionDelete},
+	}
+	or.On("History", mock.Anything, id).Return(entries, nil).Once()
+	result, err := s.History(context.TODO(), id)
+	require.NoError(t, err)
+	require.Equal(t, entries, result)
+}
+
+func TestOrders_Alternatives(t *testing.T) {
+	launchpad, lr := prepareLaunchpad(t)
+	destinations, dr := prepareDestinations()
+	lfr := prepareFirstDestinationRepo(launchpad.ID, destinations[0].ID, 2053, 3, 3)
+	other := types.Launchpad{ID: uuid.New().String(), FullName: "Vandenberg", Location: launchpad.Location, Status: types.LaunchpadStatusActive}
+	busy := types.Launchpad{ID: uuid.New().String(), Location: launchpad.Location, Status: types.LaunchpadStatusActive}
+	retired := types.Launchpad{ID: uuid.New().String(), Location: launchpad.Location, Status: "retired"}
+	withoutAnchor := types.Launchpad{ID: uuid.New().String(), Location: launchpad.Location, Status: types.LaunchpadStatusActive}
+	lr.On("List", mock.Anything).Return([]types.Launchpad{launchpad, other, busy, retired, withoutAnchor}, nil)
+	for _, id := range []string{other.ID, busy.ID} {
+		lfr.On("Get", mock.Anything, id).Return(types.LaunchpadFirstDestination{
+			LaunchpadID: id, DestinationID: destinations[1].ID, LocalYear: 2053, LocalMonth: 3, LocalDay: 6,
+		}, nil)
+	}
+	lfr.On("Get", mock.Anything, withoutAnchor.ID).Return(types.LaunchpadFirstDestination{}, types.ErrNotFound{})
+
+	// requested 2053-03-06 flies to destinations[3], destinations[1] is reached every 9 days starting from 2053-03-04
+	launchDate := time.Date(2053, 3, 6, 10, 30, 0, 0, launchpad.Location)
+	clr := &mockCompetitorLaunchesRepo{}
+	clr.On("ListLaunches", mock.Anything, launchpad.ID,
+		time.Date(2053, 2, 4, 0, 0, 0, 0, launchpad.Location),
+		time.Date(2053, 4, 6, 0, 0, 0, 0, launchpad.Location)).
+		Return([]types.Launch{{DateUTC: time.Date(2053, 3, 13, 15, 0, 0, 0, time.UTC)}}, nil)
+	clr.On("CheckLaunches", mock.Anything, other.ID, launchDate).Return(false, nil)
+	clr.On("CheckLaunches", mock.Anything, busy.ID, launchDate).Return(true, nil)
+
+	s := NewOrders(nil, lr, dr, lfr, clr)
+	o := types.Order{LaunchpadID: launchpad.ID, DestinationID: destinations[1].ID, LaunchDate: launchDate.UTC()}
+	alternatives, err := s.Alternatives(context.TODO(), o, 4)
+	require.NoError(t, err)
+
+	var got []string
+	for _, a := range alternatives {
+		require.Equal(t, destinations[1].ID, a.DestinationID)
+		got = append(got, a.LaunchpadID+" "+a.LocalDate)
+	}
+	require.Equal(t, []string{
+		other.ID + " 2053-03-06",
+		launchpad.ID + " 2053-03-04",
+		launchpad.ID + " 2053-02-23",
+		launchpad.ID + " 2053-03-22",
+	}, got)
+	require.Equal(t, "Vandenberg", alternatives[0].LaunchpadName)
+	require.Equal(t, time.Date(2053, 3, 4, 10, 30, 0, 0, launchpad.Location).UTC(), alternatives[1].LaunchDate)
+
+	lr.AssertExpectations(t)
+	lfr.AssertExpectations(t)
+	clr.AssertExpectations(t)
+}
+
+func TestOrders_AlternativesOtherLaunchpadsLimit(t *testing.T) {
+	launchpad, lr := prepareLaunchpad(t)
+	destinations, dr := prepareDestinations()
+	lfr := prepareFirstDestinationRepo(launchpad.ID, destinations[0].ID, 2053, 3, 3)
+	first := types.Launchpad{ID: "a-" + uuid.New().String(), Location: launchpad.Location, Status: types.LaunchpadStatusActive}
+	second := types.Launchpad{ID: "b-" + uuid.New().String(), Location: launchpad.Location, Status: types.LaunchpadStatusActive}
+	lr.On("List", mock.Anything).Return([]types.Launchpad{second, launchpad, first}, nil)
+	lfr.On("Get", mock.Anything, first.ID).Return(types.LaunchpadFirstDestination{
+		LaunchpadID: first.ID, DestinationID: destinations[1].ID, LocalYear: 2053, LocalMonth: 3, LocalDay: 6,
+	}, nil)
+	launchDate := time.Date(2053, 3, 6, 10, 30, 0, 0, launchpad.Location)
+	clr := &mockCompetitorLaunchesRepo{}
+	clr.On("ListLaunches", mock.Anything, launchpad.ID, mock.Anything, mock.Anything).Return(nil, nil)
+	// second launchpad is not checked, limit is reached by first one
+	clr.On("CheckLaunches", mock.Anything, first.ID, launchDate).Return(false, nil).Once()
+
+	s := NewOrders(nil, lr, dr, lfr, clr)
+	o := types.Order{LaunchpadID: launchpad.ID, DestinationID: destinations[1].ID, LaunchDate: launchDate.UTC()}
+	alternatives, err := s.Alternatives(context.TODO(), o, 1)
+	require.NoError(t, err)
+	require.Len(t, alternatives, 1)
+	require.Equal(t, first.ID, alternatives[0].LaunchpadID)
+	lfr.AssertExpectations(t)
+	clr.AssertExpectations(t)
+}
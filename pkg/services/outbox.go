@@ -0,0 +1,141 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/leveldorado/space-trouble/pkg/tools/clock"
+	"github.com/leveldorado/space-trouble/pkg/types"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	outboxBatchSize    = 100
+	outboxPollInterval = time.Second
+	outboxBaseBackoff  = time.Second
+	outboxMaxBackoff   = 5 * time.Minute
+	// about an hour of retries, then event is dead and stops holding its order
+	outboxMaxAttempts = 20
+)
+
+type outboxRepo interface {
+	ProcessOutbox(ctx context.Context, now time.Time, limit int, fn func(e *types.OutboxEvent)) (int, error)
+}
+
+/*
+EventSink receives relayed order events, implementations should be idempotent by event id
+*/
+type EventSink interface {
+	Publish(ctx context.Context, e types.OrderEvent) error
+}
+
+/*
+OutboxRelay publishes order events stored in outbox to sinks.
+
+	event is marked published only after every sink accepted it, so sinks get events at least once
+	and have to tolerate duplicates. failed event is retried with backoff and holds following events of the same order,
+	after outboxMaxAttempts attempts event gets dead and is left in outbox for manual inspection
+*/
+type OutboxRelay struct {
+	repo  outboxRepo
+	sinks []EventSink
+	clock timeSource
+	log   logrus.FieldLogger
+}
+
+func NewOutboxRelay(repo outboxRepo, log logrus.FieldLogger, sinks ...EventSink) *OutboxRelay {
+	return &OutboxRelay{repo: repo, sinks: sinks, clock: clock.Real{}, log: log}
+}
+
+/*
+WithClock replaces actual time used for scheduling retries of failed events
+*/
+func (r *OutboxRelay) WithClock(c timeSource) *OutboxRelay {
+	r.clock = c
+	return r
+}
+
+/*
+Run relays events until context is cancelled
+*/
+func (r *OutboxRelay) Run(ctx context.Context) {
+	ticker := time.NewTicker(outboxPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		for {
+			n, err := r.ProcessBatch(ctx)
+			if err != nil {
+				r.log.WithField("err", err.Error()).Error("failed to relay order events")
+			}
+			if err != nil || n < outboxBatchSize {
+				break
+			}
+		}
+	}
+}
+
+/*
+ProcessBatch relays one batch of events and returns number of events attempted
+*/
+func (r *OutboxRelay) ProcessBatch(ctx context.Context) (int, error) {
+	return r.repo.ProcessOutbox(ctx, r.clock.Now().UTC(), outboxBatchSize, func(e *types.OutboxEvent) {
+		r.relay(ctx, e, r.clock.Now().UTC())
+	})
+}
+
+func (r *OutboxRelay) relay(ctx context.Context, e *types.OutboxEvent, now time.Time) {
+	e.Attempts++
+	for _, sink := range r.sinks {
+		if err := sink.Publish(ctx, e.OrderEvent); err != nil {
+			e.LastError = fmt.Sprintf("%T: %s", sink, err.Error())
+			if len(e.LastError) > maxLastErrorLength {
+				e.LastError = e.LastError[:maxLastErrorLength]
+			}
+			e.NextAttemptAt = now.Add(outboxBackoff(e.Attempts))
+			log := r.log.WithField("err", e.LastError).WithField("event_id", e.ID).WithField("attempts", e.Attempts)
+			if e.Attempts >= outboxMaxAttempts {
+				e.DeadAt = &now
+				log.Error("order event is dead, giving up")
+				return
+			}
+			log.Warn("failed to publish order event")
+			return
+		}
+	}
+	e.LastError = ""
+	e.PublishedAt = &now
+}
+
+func outboxBackoff(attempts int) time.Duration {
+	backoff := outboxBaseBackoff
+	for i := 1; i < attempts; i++ {
+		backoff *= 2
+		if backoff >= outboxMaxBackoff {
+			return outboxMaxBackoff
+		}
+	}
+	return backoff
+}
+
+/*
+LogEventSink writes order events to log
+*/
+type LogEventSink struct {
+	log logrus.FieldLogger
+}
+
+func NewLogEventSink(log logrus.FieldLogger) *LogEventSink {
+	return &LogEventSink{log: log}
+}
+
+func (s *LogEventSink) Publish(_ context.Context, e types.OrderEvent) error {
+	s.log.WithField("event_id", e.ID).WithField("event_type", e.Type).WithField("order_id", e.OrderID).
+		Info("order event")
+	return nil
+}
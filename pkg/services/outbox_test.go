@@ -0,0 +1,74 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/leveldorado/space-trouble/pkg/tools/logger"
+	"github.com/leveldorado/space-trouble/pkg/types"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type eventSinkFunc func(ctx context.Context, e types.OrderEvent) error
+
+func (f eventSinkFunc) Publish(ctx context.Context, e types.OrderEvent) error {
+	return f(ctx, e)
+}
+
+func TestOutboxRelay_ProcessBatch(t *testing.T) {
+	events := []types.OutboxEvent{
+		{OrderEvent: types.OrderEvent{ID: uuid.New().String(), Type: types.OrderEventCreated, OrderID: uuid.New().String()}},
+		{OrderEvent: types.OrderEvent{ID: uuid.New().String(), Type: types.OrderEventCancelled, OrderID: uuid.New().String()}},
+	}
+	var published []string
+	logSink := eventSinkFunc(func(_ context.Context, e types.OrderEvent) error {
+		published = append(published, e.ID)
+		return nil
+	})
+	failingSink := eventSinkFunc(func(_ context.Context, e types.OrderEvent) error {
+		if e.Type == types.OrderEventCancelled {
+			return errors.New("unavailable")
+		}
+		return nil
+	})
+	repo := &mockOutboxRepo{}
+	repo.On("ProcessOutbox", mock.Anything, mock.Anything, outboxBatchSize, mock.Anything).
+		Return(func(_ context.Context, _ time.Time, _ int, fn func(*types.OutboxEvent)) int {
+			for i := range events {
+				fn(&events[i])
+			}
+			return len(events)
+		}, nil)
+
+	n, err := NewOutboxRelay(repo, logger.New(), logSink, failingSink).ProcessBatch(context.TODO())
+	require.NoError(t, err)
+	require.Equal(t, 2, n)
+	require.Equal(t, []string{events[0].ID, events[1].ID}, published)
+
+	require.NotNil(t, events[0].PublishedAt)
+	require.Equal(t, 1, events[0].Attempts)
+	require.Empty(t, events[0].LastError)
+
+	require.Nil(t, events[1].PublishedAt)
+	require.Equal(t, 1, events[1].Attempts)
+	require.Contains(t, events[1].LastError, "unavailable")
+	require.WithinDuration(t, time.Now().Add(outboxBaseBackoff), events[1].NextAttemptAt, time.Second)
+	require.Nil(t, events[1].DeadAt)
+
+	events[1].Attempts = outboxMaxAttempts - 1
+	n, err = NewOutboxRelay(repo, logger.New(), logSink, failingSink).ProcessBatch(context.TODO())
+	require.NoError(t, err)
+	require.Equal(t, 2, n)
+	require.Nil(t, events[1].PublishedAt)
+	require.NotNil(t, events[1].DeadAt)
+}
+
+func TestOutboxBackoff(t *testing.T) {
+	require.Equal(t, outboxBaseBackoff, outboxBackoff(1))
+	require.Equal(t, 4*outboxBaseBackoff, outboxBackoff(3))
+	require.Equal(t, outboxMaxBackoff, outboxBackoff(50))
+}
@@ -0,0 +1,249 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/leveldorado/space-trouble/pkg/tools/actor"
+	"github.com/leveldorado/space-trouble/pkg/tools/clock"
+	"github.com/leveldorado/space-trouble/pkg/types"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	waitlistRecheckInterval = 10 * time.Minute
+	waitlistRetryInterval   = time.Minute
+	waitlistClaimLease      = time.Minute
+	waitlistBatchSize       = 50
+	waitlistPollInterval    = 5 * time.Second
+	// recorded in order audit log for orders booked from waitlist
+	waitlistActor = "waitlist"
+)
+
+type waitlistRepo interface {
+	Insert(ctx context.Context, doc types.WaitlistEntry) error
+	Get(ctx context.Context, id string) (types.WaitlistEntry, error)
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]types.WaitlistEntry, error)
+	Update(ctx context.Context, doc types.WaitlistEntry) error
+	Cancel(ctx context.Context, id string) error
+	RecheckLaunchpad(ctx context.Context, launchpadID string, at time.Time) error
+	HasWaitingAhead(ctx context.Context, id string) (bool, error)
+}
+
+type orderCreator interface {
+	CreateWithID(ctx context.Context, id string, o types.Order) error
+}
+
+/*
+Waitlist keeps orders blocked by competitor launches and books them once flight becomes possible.
+
+	entries wait for flight - launchpad and launch local date, only first waiting entry of flight is checked,
+	so freed seats go to entries in order of joining and next entry is checked once first one leaves waitlist.
+	entries are rechecked periodically, right after order of the same launchpad is cancelled, rescheduled or conflicted
+	and right after competitor launches of the launchpad change. booked order triggers usual booking notifications.
+	order is booked under id of entry, so entry which failed to be updated after booking is never booked twice
+*/
+type Waitlist struct {
+	repo       waitlistRepo
+	orders     orderCreator
+	launchpads launchpadRepo
+	clock      timeSource
+	log        logrus.FieldLogger
+}
+
+func NewWaitlist(repo waitlistRepo, orders orderCreator, launchpads launchpadRepo, log logrus.FieldLogger) *Waitlist {
+	return &Waitlist{repo: repo, orders: orders, launchpads: launchpads, clock: clock.Real{}, log: log}
+}
+
+/*
+WithClock replaces actual time used for scheduling rechecks of entries
+*/
+func (w *Waitlist) WithClock(c timeSource) *Waitlist {
+	w.clock = c
+	return w
+}
+
+/*
+Join books order when it is possible and nobody waits for the same flight, otherwise puts it on waitlist.
+
+	only orders blocked by competitor launch or lack of seats can wait, other rejections are returned as is.
+	entry is stored before booking, so failure after booking leaves waiting entry which finds its order on recheck,
+	and concurrent join of the same flight sees it waiting ahead.
+	when booking fails for other reason entry is waiting and booking is retried later
+*/
+func (w *Waitlist) Join(ctx context.Context, o types.Order) (types.WaitlistEntry, error) {
+	now := w.clock.Now().UTC()
+	o, err := w.withFlight(ctx, o)
+	if err != nil {
+		return types.WaitlistEntry{}, err
+	}
+	doc := types.WaitlistEntry{
+		ID:     uuid.New().String(),
+		Order:  o,
+		Status: types.WaitlistStatusWaiting,
+		// entry is held by join like claimed one until booking result is stored
+		NextCheckAt: now.Add(waitlistClaimLease),
+		CreatedAt:   now,
+	}
+	if err = w.repo.Insert(ctx, doc); err != nil {
+		return types.WaitlistEntry{}, errors.Wrapf(err, `failed to insert waitlist entry: order - %+v`, o)
+	}
+	ahead, err := w.repo.HasWaitingAhead(ctx, doc.ID)
+	if err != nil {
+		return types.WaitlistEntry{}, errors.Wrapf(err, `failed to check waitlist of flight: id - %s`, doc.ID)
+	}
+	if ahead {
+		// checked as soon as entries ahead leave waitlist
+		doc.NextCheckAt = now
+		if err = w.repo.Update(ctx, doc); err != nil {
+			return types.WaitlistEntry{}, errors.Wrapf(err, `failed to update waitlist entry: id - %s`, doc.ID)
+		}
+		return doc, nil
+	}
+	doc, err = w.book(ctx, doc, now)
+	if updateErr := w.repo.Update(ctx, doc); updateErr != nil {
+		return types.WaitlistEntry{}, errors.Wrapf(updateErr, `failed to update waitlist entry: id - %s`, doc.ID)
+	}
+	if doc.Status == types.WaitlistStatusExpired {
+		return types.WaitlistEntry{}, err
+	}
+	return doc, nil
+}
+
+/*
+withFlight fills launch local date of order, entries of the same launchpad and launch local date wait for the same flight
+*/
+func (w *Waitlist) withFlight(ctx context.Context, o types.Order) (types.Order, error) {
+	launchpad, err := w.launchpads.Get(ctx, o.LaunchpadID)
+	if errors.As(err, &types.ErrNotFound{}) {
+		return types.Order{}, types.NewErrInvalidData("invalid launchpad id")
+	}
+	if err != nil {
+		return types.Order{}, errors.Wrapf(err, `failed to get launchpad: id - %s`, o.LaunchpadID)
+	}
+	return withLaunchLocalDate(o, launchpad.Location)
+}
+
+func isWaitable(err error) bool {
+	impossible := types.ErrFlightImpossible{}
+	return errors.As(err, &impossible) &&
+		(impossible.Reason == types.FlightImpossibleReasonCompetitorLaunch || impossible.Reason == types.FlightImpossibleReasonNoSeats)
+}
+
+func (w *Waitlist) Get(ctx context.Context, id string) (types.WaitlistEntry, error) {
+	return w.repo.Get(ctx, id)
+}
+
+func (w *Waitlist) Leave(ctx context.Context, id string) error {
+	return w.repo.Cancel(ctx, id)
+}
+
+/*
+Publish schedules immediate recheck of launchpad waitlist when order of the launchpad frees its seats
+*/
+func (w *Waitlist) Publish(ctx context.Context, e types.OrderEvent) error {
+	if e.Order == nil || e.Type == types.OrderEventCreated {
+		return nil
+	}
+	return errors.Wrapf(w.repo.RecheckLaunchpad(ctx, e.Order.LaunchpadID, w.clock.Now().UTC()),
+		`failed to schedule waitlist recheck: launchpad - %s`, e.Order.LaunchpadID)
+}
+
+/*
+LaunchesChanged schedules immediate recheck of waitlists of launchpads which competitor launches changed
+*/
+func (w *Waitlist) LaunchesChanged(ctx context.Context, launchpadIDs []string) error {
+	now := w.clock.Now().UTC()
+	for _, id := range launchpadIDs {
+		if err := w.repo.RecheckLaunchpad(ctx, id, now); err != nil {
+			return errors.Wrapf(err, `failed to schedule waitlist recheck: launchpad - %s`, id)
+		}
+	}
+	return nil
+}
+
+/*
+Run rechecks due entries until context is cancelled
+*/
+func (w *Waitlist) Run(ctx context.Context) {
+	ticker := time.NewTicker(waitlistPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		for {
+			processed, err := w.ProcessDue(ctx)
+			if err != nil {
+				w.log.WithField("err", err.Error()).Error("failed to process waitlist")
+			}
+			if err != nil || processed < waitlistBatchSize {
+				break
+			}
+		}
+	}
+}
+
+/*
+ProcessDue rechecks one batch of due entries oldest first and returns number of processed entries.
+
+	batch has at most one entry of each flight, entries behind it are claimed once it leaves waitlist
+*/
+func (w *Waitlist) ProcessDue(ctx context.Context) (int, error) {
+	docs, err := w.repo.ClaimDue(ctx, w.clock.Now().UTC(), waitlistClaimLease, waitlistBatchSize)
+	if err != nil {
+		return 0, errors.Wrap(err, `failed to claim waitlist entries`)
+	}
+	ctx = actor.NewContext(ctx, waitlistActor)
+	for _, doc := range docs {
+		doc = w.check(ctx, doc, w.clock.Now().UTC())
+		if err := w.repo.Update(ctx, doc); err != nil {
+			return 0, errors.Wrapf(err, `failed to update waitlist entry: id - %s, order - %s`, doc.ID, doc.OrderID)
+		}
+	}
+	return len(docs), nil
+}
+
+/*
+check tries to book order of entry, see book
+*/
+func (w *Waitlist) check(ctx context.Context, doc types.WaitlistEntry, now time.Time) types.WaitlistEntry {
+	doc, _ = w.book(ctx, doc, now)
+	return doc
+}
+
+/*
+book tries to book order of entry under entry id and returns entry updated by result together with booking error.
+
+	order already booked by earlier attempt promotes entry.
+	entry expires when order is rejected for other reason than competitor launch or lack of seats (e.g. launch date passed)
+*/
+func (w *Waitlist) book(ctx context.Context, doc types.WaitlistEntry, now time.Time) (types.WaitlistEntry, error) {
+	doc.Checks++
+	err := w.orders.CreateWithID(ctx, doc.ID, doc.Order)
+	switch {
+	case err == nil || errors.As(err, &types.ErrDuplicatedOrder{}):
+		doc.Status = types.WaitlistStatusPromoted
+		doc.OrderID = doc.ID
+		doc.LastError = ""
+		doc.PromotedAt = &now
+		return doc, nil
+	case isWaitable(err):
+		doc.LastError = ""
+		doc.NextCheckAt = now.Add(waitlistRecheckInterval)
+	case isRejection(err):
+		doc.Status = types.WaitlistStatusExpired
+		doc.LastError = err.Error()
+	default:
+		doc.LastError = err.Error()
+		if len(doc.LastError) > maxLastErrorLength {
+			doc.LastError = doc.LastError[:maxLastErrorLength]
+		}
+		doc.NextCheckAt = now.Add(waitlistRetryInterval)
+	}
+	return doc, err
+}
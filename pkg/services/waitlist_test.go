@@ -0,0 +1,213 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/brianvoe/gofakeit"
+	"github.com/google/uuid"
+	"github.com/leveldorado/space-trouble/pkg/tools/actor"
+	"github.com/leveldorado/space-trouble/pkg/tools/logger"
+	"github.com/leveldorado/space-trouble/pkg/types"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func prepareWaitlistOrder() types.Order {
+	return types.Order{
+		FirstName:     gofakeit.FirstName(),
+		LastName:      gofakeit.LastName(),
+		Gender:        gofakeit.Gender(),
+		LaunchpadID:   uuid.New().String(),
+		DestinationID: uuid.New().String(),
+		LaunchDate:    time.Now().UTC().Add(48 * time.Hour),
+	}
+}
+
+/*
+withWaitlistFlight returns order with launch local date filled as Waitlist.Join does for launchpad in UTC
+*/
+func withWaitlistFlight(t *testing.T, o types.Order) types.Order {
+	o, err := withLaunchLocalDate(o, time.UTC)
+	require.NoError(t, err)
+	return o
+}
+
+func prepareWaitlistLaunchpads() *mockLaunchpadRepo {
+	lr := &mockLaunchpadRepo{}
+	lr.On("Get", mock.Anything, mock.Anything).Return(types.Launchpad{Status: types.LaunchpadStatusActive, Location: time.UTC}, nil)
+	return lr
+}
+
+func TestWaitlist_Join(t *testing.T) {
+	blocked := withWaitlistFlight(t, prepareWaitlistOrder())
+	full := withWaitlistFlight(t, prepareWaitlistOrder())
+	wrongDestination := withWaitlistFlight(t, prepareWaitlistOrder())
+	possible := withWaitlistFlight(t, prepareWaitlistOrder())
+	unavailable := withWaitlistFlight(t, prepareWaitlistOrder())
+	oc := &mockOrderCreator{}
+	oc.On("CreateWithID", mock.Anything, mock.Anything, blocked).
+		Return(errors.WithStack(types.NewErrFlightImpossible(types.FlightImpossibleReasonCompetitorLaunch)))
+	oc.On("CreateWithID", mock.Anything, mock.Anything, full).
+		Return(errors.WithStack(types.NewErrFlightImpossible(types.FlightImpossibleReasonNoSeats)))
+	oc.On("CreateWithID", mock.Anything, mock.Anything, wrongDestination).
+		Return(types.NewErrFlightImpossible(types.FlightImpossibleReasonDestination))
+	oc.On("CreateWithID", mock.Anything, mock.Anything, possible).Return(nil)
+	oc.On("CreateWithID", mock.Anything, mock.Anything, unavailable).Return(types.ErrUnavailable{Service: "spacex"})
+	inserted := map[string]types.WaitlistEntry{}
+	updated := map[string]types.WaitlistEntry{}
+	repo := &mockWaitlistRepo{}
+	// entry is stored before booking and is not due while join books it
+	repo.On("Insert", mock.Anything, mock.Anything).Return(func(_ context.Context, doc types.WaitlistEntry) error {
+		require.Equal(t, types.WaitlistStatusWaiting, doc.Status)
+		require.WithinDuration(t, time.Now().Add(waitlistClaimLease), doc.NextCheckAt, time.Second)
+		inserted[doc.ID] = doc
+		return nil
+	})
+	repo.On("Update", mock.Anything, mock.Anything).Return(func(_ context.Context, doc types.WaitlistEntry) error {
+		require.Contains(t, inserted, doc.ID)
+		updated[doc.ID] = doc
+		return nil
+	})
+	repo.On("HasWaitingAhead", mock.Anything, mock.Anything).Return(false, nil)
+
+	w := NewWaitlist(repo, oc, prepareWaitlistLaunchpads(), logger.New())
+
+	for _, o := range []types.Order{blocked, full} {
+		o.LaunchLocalDate, o.LaunchpadTimezone = "", ""
+		doc, err := w.Join(context.TODO(), o)
+		require.NoError(t, err)
+		require.Equal(t, types.WaitlistStatusWaiting, doc.Status)
+		require.Equal(t, withWaitlistFlight(t, o), doc.Order)
+		require.Empty(t, doc.OrderID)
+		require.WithinDuration(t, time.Now().Add(waitlistRecheckInterval), updated[doc.ID].NextCheckAt, time.Second)
+	}
+
+	doc, err := w.Join(context.TODO(), possible)
+	require.NoError(t, err)
+	require.Equal(t, types.WaitlistStatusPromoted, doc.Status)
+	require.Equal(t, doc.ID, doc.OrderID)
+	require.NotNil(t, doc.PromotedAt)
+	require.Equal(t, doc, updated[doc.ID])
+
+	doc, err = w.Join(context.TODO(), unavailable)
+	require.NoError(t, err)
+	require.Equal(t, types.WaitlistStatusWaiting, doc.Status)
+	require.Equal(t, "spacex is unavailable", doc.LastError)
+	require.WithinDuration(t, time.Now().Add(waitlistRetryInterval), doc.NextCheckAt, time.Second)
+
+	_, err = w.Join(context.TODO(), wrongDestination)
+	require.True(t, errors.As(err, &types.ErrFlightImpossible{}))
+
+	require.Len(t, inserted, 5)
+	require.Len(t, updated, 5)
+	oc.AssertExpectations(t)
+}
+
+func TestWaitlist_JoinBehindWaiting(t *testing.T) {
+	o := prepareWaitlistOrder()
+	var updated types.WaitlistEntry
+	repo := newMockWaitlistRepo(t)
+	repo.On("Insert", mock.Anything, mock.Anything).Return(nil)
+	repo.On("HasWaitingAhead", mock.Anything, mock.Anything).Return(true, nil)
+	repo.On("Update", mock.Anything, mock.Anything).Return(func(_ context.Context, doc types.WaitlistEntry) error {
+		updated = doc
+		return nil
+	})
+	// flight is not booked while entry which joined earlier waits for it
+	oc := newMockOrderCreator(t)
+
+	doc, err := NewWaitlist(repo, oc, prepareWaitlistLaunchpads(), logger.New()).Join(context.TODO(), o)
+	require.NoError(t, err)
+	require.Equal(t, types.WaitlistStatusWaiting, doc.Status)
+	require.Equal(t, o.LaunchDate.Format(types.LocalDateLayout), doc.Order.LaunchLocalDate)
+	require.Zero(t, doc.Checks)
+	require.WithinDuration(t, time.Now(), updated.NextCheckAt, time.Second)
+}
+
+func TestWaitlist_JoinUnknownLaunchpad(t *testing.T) {
+	lr := &mockLaunchpadRepo{}
+	lr.On("Get", mock.Anything, mock.Anything).Return(types.Launchpad{}, types.ErrNotFound{})
+
+	_, err := NewWaitlist(newMockWaitlistRepo(t), newMockOrderCreator(t), lr, logger.New()).Join(context.TODO(), prepareWaitlistOrder())
+	require.True(t, errors.As(err, &types.ErrInvalidData{}))
+}
+
+func TestWaitlist_ProcessDue(t *testing.T) {
+	now := time.Now().UTC()
+	first := types.WaitlistEntry{ID: uuid.New().String(), Order: prepareWaitlistOrder(), Status: types.WaitlistStatusWaiting, CreatedAt: now.Add(-3 * time.Hour)}
+	second := types.WaitlistEntry{ID: uuid.New().String(), Order: prepareWaitlistOrder(), Status: types.WaitlistStatusWaiting, CreatedAt: now.Add(-2 * time.Hour)}
+	third := types.WaitlistEntry{ID: uuid.New().String(), Order: prepareWaitlistOrder(), Status: types.WaitlistStatusWaiting, CreatedAt: now.Add(-time.Hour)}
+	fourth := types.WaitlistEntry{ID: uuid.New().String(), Order: prepareWaitlistOrder(), Status: types.WaitlistStatusWaiting, CreatedAt: now}
+	fifth := types.WaitlistEntry{ID: uuid.New().String(), Order: prepareWaitlistOrder(), Status: types.WaitlistStatusWaiting, CreatedAt: now}
+
+	var created []string
+	oc := &mockOrderCreator{}
+	create := func(err error) func(context.Context, string, types.Order) error {
+		return func(ctx context.Context, _ string, o types.Order) error {
+			require.Equal(t, waitlistActor, actor.FromContext(ctx))
+			created = append(created, o.FirstName)
+			return err
+		}
+	}
+	oc.On("CreateWithID", mock.Anything, first.ID, first.Order).Return(create(nil))
+	oc.On("CreateWithID", mock.Anything, second.ID, second.Order).
+		Return(create(types.NewErrFlightImpossible(types.FlightImpossibleReasonCompetitorLaunch)))
+	oc.On("CreateWithID", mock.Anything, third.ID, third.Order).Return(create(types.NewErrInvalidData("launch date has passed")))
+	oc.On("CreateWithID", mock.Anything, fourth.ID, fourth.Order).Return(create(errors.New("spacex is down")))
+	// order of fifth entry was booked but entry update failed
+	oc.On("CreateWithID", mock.Anything, fifth.ID, fifth.Order).Return(create(errors.WithStack(types.ErrDuplicatedOrder{})))
+
+	updated := map[string]types.WaitlistEntry{}
+	repo := &mockWaitlistRepo{}
+	repo.On("ClaimDue", mock.Anything, mock.Anything, waitlistClaimLease, waitlistBatchSize).
+		Return([]types.WaitlistEntry{first, second, third, fourth, fifth}, nil)
+	repo.On("Update", mock.Anything, mock.Anything).
+		Return(func(_ context.Context, doc types.WaitlistEntry) error {
+			updated[doc.ID] = doc
+			return nil
+		})
+
+	processed, err := NewWaitlist(repo, oc, nil, logger.New()).ProcessDue(context.TODO())
+	require.NoError(t, err)
+	require.Equal(t, 5, processed)
+	require.Equal(t, []string{first.Order.FirstName, second.Order.FirstName, third.Order.FirstName, fourth.Order.FirstName,
+		fifth.Order.FirstName}, created)
+
+	require.Equal(t, types.WaitlistStatusPromoted, updated[first.ID].Status)
+	require.Equal(t, first.ID, updated[first.ID].OrderID)
+	require.Equal(t, types.WaitlistStatusWaiting, updated[second.ID].Status)
+	require.WithinDuration(t, time.Now().Add(waitlistRecheckInterval), updated[second.ID].NextCheckAt, time.Second)
+	require.Equal(t, types.WaitlistStatusExpired, updated[third.ID].Status)
+	require.Equal(t, "launch date has passed", updated[third.ID].LastError)
+	require.Equal(t, types.WaitlistStatusWaiting, updated[fourth.ID].Status)
+	require.Equal(t, "spacex is down", updated[fourth.ID].LastError)
+	require.WithinDuration(t, time.Now().Add(waitlistRetryInterval), updated[fourth.ID].NextCheckAt, time.Second)
+	require.Equal(t, types.WaitlistStatusPromoted, updated[fifth.ID].Status)
+	require.Equal(t, fifth.ID, updated[fifth.ID].OrderID)
+	for _, doc := range updated {
+		require.Equal(t, 1, doc.Checks)
+	}
+}
+
+func TestWaitlist_Publish(t *testing.T) {
+	o := prepareWaitlistOrder()
+	repo := &mockWaitlistRepo{}
+	repo.On("RecheckLaunchpad", mock.Anything, o.LaunchpadID, mock.Anything).Return(nil).Once()
+	w := NewWaitlist(repo, nil, nil, logger.New())
+
+	require.NoError(t, w.Publish(context.TODO(), types.OrderEvent{Type: types.OrderEventCreated, Order: &o}))
+	require.NoError(t, w.Publish(context.TODO(), types.OrderEvent{Type: types.OrderEventCancelled, Order: &o}))
+
+	repo.AssertExpectations(t)
+}
+
+func TestWaitlist_LaunchesChanged(t *testing.T) {
+	repo := newMockWaitlistRepo(t)
+	repo.On("RecheckLaunchpad", mock.Anything, "first", mock.Anything).Return(nil).Once()
+	repo.On("RecheckLaunchpad", mock.Anything, "second", mock.Anything).Return(nil).Once()
+
+	require.NoError(t, NewWaitlist(repo, nil, nil, logger.New()).LaunchesChanged(context.TODO(), []string{"first", "second"}))
+}
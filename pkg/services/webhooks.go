@@ -0,0 +1,295 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/leveldorado/space-trouble/pkg/tools/clock"
+	"github.com/leveldorado/space-trouble/pkg/types"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	WebhookEventHeader     = "X-Space-Trouble-Event"
+	WebhookDeliveryHeader  = "X-Space-Trouble-Delivery"
+	WebhookSignatureHeader = "X-Space-Trouble-Signature"
+
+	webhookMaxAttempts = 8
+	webhookBaseBackoff = 10 * time.Second
+	webhookMaxBackoff  = time.Hour
+	webhookBatchSize   = 50
+	webhookSendTimeout = 5 * time.Second
+	// deliveries of batch are sent one by one, lease outlasts batch even when every send times out,
+	// so claimed delivery is never claimed by other replica while it's still being sent
+	webhookClaimLease   = webhookBatchSize*webhookSendTimeout + time.Minute
+	webhookPollInterval = time.Second
+	webhookSecretSize   = 32
+	maxLastErrorLength  = 512
+)
+
+type webhooksRepo interface {
+	InsertSubscription(ctx context.Context, doc types.WebhookSubscription) error
+	GetSubscription(ctx context.Context, id string) (types.WebhookSubscription, error)
+	ListSubscriptions(ctx context.Context) ([]types.WebhookSubscription, error)
+	DeleteSubscription(ctx context.Context, id string) error
+	InsertDeliveries(ctx context.Context, docs []types.WebhookDelivery) error
+	ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]types.WebhookDelivery, error)
+	UpdateDelivery(ctx context.Context, doc types.WebhookDelivery) error
+	GetDelivery(ctx context.Context, id string) (types.WebhookDelivery, error)
+	ListDeliveries(ctx context.Context, status string, limit, offset int) ([]types.WebhookDelivery, error)
+}
+
+/*
+Webhooks manages subscriptions and delivers order events to them.
+
+	every event is stored as delivery per subscription first and sent by Run loop,
+	failed deliveries are retried with exponential backoff and moved to dead letter list after max attempts
+*/
+type Webhooks struct {
+	repo  webhooksRepo
+	cl    *http.Client
+	clock timeSource
+	log   logrus.FieldLogger
+}
+
+func NewWebhooks(repo webhooksRepo, cl *http.Client, log logrus.FieldLogger) *Webhooks {
+	return &Webhooks{repo: repo, cl: cl, clock: clock.Real{}, log: log}
+}
+
+/*
+WithClock replaces actual time used for scheduling deliveries and their retries
+*/
+func (w *Webhooks) WithClock(c timeSource) *Webhooks {
+	w.clock = c
+	return w
+}
+
+/*
+Subscribe creates subscription, secret is generated when not provided.
+
+	returned subscription is the only place where secret is exposed
+*/
+func (w *Webhooks) Subscribe(ctx context.Context, sub types.WebhookSubscription) (types.WebhookSubscription, error) {
+	if err := sub.Validate(); err != nil {
+		return types.WebhookSubscription{}, types.NewErrInvalidData(err.Error())
+	}
+	if sub.Secret == "" {
+		secret := make([]byte, webhookSecretSize)
+		if _, err := rand.Read(secret); err != nil {
+			return types.WebhookSubscription{}, errors.Wrap(err, `failed to generate secret`)
+		}
+		sub.Secret = hex.EncodeToString(secret)
+	}
+	sub.ID = uuid.New().String()
+	sub.CreatedAt = w.clock.Now().UTC()
+	return sub, errors.Wrapf(w.repo.InsertSubscription(ctx, sub), `failed to insert subscription: url - %s`, sub.URL)
+}
+
+func (w *Webhooks) Subscriptions(ctx context.Context) ([]types.WebhookSubscription, error) {
+	subs, err := w.repo.ListSubscriptions(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, `failed to list subscriptions`)
+	}
+	for i := range subs {
+		subs[i].Secret = ""
+	}
+	return subs, nil
+}
+
+func (w *Webhooks) Unsubscribe(ctx context.Context, id string) error {
+	return w.repo.DeleteSubscription(ctx, id)
+}
+
+func (w *Webhooks) Deliveries(ctx context.Context, status string, limit, offset int) ([]types.WebhookDelivery, error) {
+	return w.repo.ListDeliveries(ctx, status, limit, offset)
+}
+
+/*
+Replay schedules delivery to be sent again right away with fresh attempts counter
+*/
+func (w *Webhooks) Replay(ctx context.Context, id string) error {
+	d, err := w.repo.GetDelivery(ctx, id)
+	if err != nil {
+		return err
+	}
+	d.Status = types.WebhookDeliveryStatusPending
+	d.Attempts = 0
+	d.LastError = ""
+	d.NextAttemptAt = w.clock.Now().UTC()
+	d.DeliveredAt = nil
+	return errors.Wrapf(w.repo.UpdateDelivery(ctx, d), `failed to update delivery: id - %s`, id)
+}
+
+/*
+Publish stores deliveries of event for every subscription interested in it.
+
+	publishing the same event again does not duplicate deliveries
+*/
+func (w *Webhooks) Publish(ctx context.Context, e types.OrderEvent) error {
+	subs, err := w.repo.ListSubscriptions(ctx)
+	if err != nil {
+		return errors.Wrap(err, `failed to list subscriptions`)
+	}
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return errors.Wrapf(err, `failed to marshal event: id - %s`, e.ID)
+	}
+	now := w.clock.Now().UTC()
+	var deliveries []types.WebhookDelivery
+	for _, sub := range subs {
+		if !sub.Subscribed(e.Type) {
+			continue
+		}
+		deliveries = append(deliveries, types.WebhookDelivery{
+			ID:             uuid.New().String(),
+			SubscriptionID: sub.ID,
+			EventID:        e.ID,
+			EventType:      e.Type,
+			Payload:        payload,
+			Status:         types.WebhookDeliveryStatusPending,
+			NextAttemptAt:  now,
+			CreatedAt:      now,
+		})
+	}
+	if len(deliveries) == 0 {
+		return nil
+	}
+	return errors.Wrapf(w.repo.InsertDeliveries(ctx, deliveries), `failed to insert deliveries: event - %s`, e.ID)
+}
+
+/*
+Run sends due deliveries until context is cancelled
+*/
+func (w *Webhooks) Run(ctx context.Context) {
+	ticker := time.NewTicker(webhookPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		for {
+			n, err := w.ProcessDue(ctx)
+			if err != nil {
+				w.log.WithField("err", err.Error()).Error("failed to process webhook deliveries")
+			}
+			if err != nil || n < webhookBatchSize {
+				break
+			}
+		}
+	}
+}
+
+/*
+ProcessDue sends one batch of due deliveries and returns number of processed deliveries
+*/
+func (w *Webhooks) ProcessDue(ctx context.Context) (int, error) {
+	deliveries, err := w.repo.ClaimDueDeliveries(ctx, w.clock.Now().UTC(), webhookClaimLease, webhookBatchSize)
+	if err != nil {
+		return 0, errors.Wrap(err, `failed to claim deliveries`)
+	}
+	subs := map[string]types.WebhookSubscription{}
+	for _, d := range deliveries {
+		sub, ok := subs[d.SubscriptionID]
+		if !ok {
+			sub, err = w.repo.GetSubscription(ctx, d.SubscriptionID)
+			if err != nil && !errors.As(err, &types.ErrNotFound{}) {
+				return 0, errors.Wrapf(err, `failed to get subscription: id - %s`, d.SubscriptionID)
+			}
+			subs[d.SubscriptionID] = sub
+		}
+		d = w.attempt(ctx, sub, d, w.clock.Now().UTC())
+		if err := w.repo.UpdateDelivery(ctx, d); err != nil {
+			return 0, errors.Wrapf(err, `failed to update delivery: id - %s`, d.ID)
+		}
+	}
+	return len(deliveries), nil
+}
+
+/*
+attempt sends delivery and returns it with status updated according to result
+*/
+func (w *Webhooks) attempt(ctx context.Context, sub types.WebhookSubscription, d types.WebhookDelivery, now time.Time) types.WebhookDelivery {
+	d.Attempts++
+	var err error
+	if sub.ID == "" {
+		err = errors.New("subscription deleted")
+		d.Attempts = webhookMaxAttempts
+	} else {
+		err = w.send(ctx, sub, d)
+	}
+	if err == nil {
+		d.Status = types.WebhookDeliveryStatusDelivered
+		d.LastError = ""
+		d.DeliveredAt = &now
+		return d
+	}
+	d.LastError = err.Error()
+	if len(d.LastError) > maxLastErrorLength {
+		d.LastError = d.LastError[:maxLastErrorLength]
+	}
+	if d.Attempts >= webhookMaxAttempts {
+		d.Status = types.WebhookDeliveryStatusDead
+		return d
+	}
+	d.NextAttemptAt = now.Add(webhookBackoff(d.Attempts))
+	return d
+}
+
+func webhookBackoff(attempts int) time.Duration {
+	backoff := webhookBaseBackoff
+	for i := 1; i < attempts; i++ {
+		backoff *= 2
+		if backoff >= webhookMaxBackoff {
+			return webhookMaxBackoff
+		}
+	}
+	return backoff
+}
+
+/*
+send posts delivery to subscription url, request is limited by webhookSendTimeout whatever timeout of client is
+*/
+func (w *Webhooks) send(ctx context.Context, sub types.WebhookSubscription, d types.WebhookDelivery) error {
+	ctx, cancel := context.WithTimeout(ctx, webhookSendTimeout)
+	defer cancel()
+	req, err := http.NewRequest(http.MethodPost, sub.URL, bytes.NewReader(d.Payload))
+	if err != nil {
+		return errors.Wrapf(err, `failed to create request: url - %s`, sub.URL)
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookEventHeader, d.EventType)
+	req.Header.Set(WebhookDeliveryHeader, d.ID)
+	req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(sub.Secret, d.Payload))
+	resp, err := w.cl.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, `failed to do request: url - %s`, sub.URL)
+	}
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+	_ = resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return errors.Errorf(`non success response code: code - %s`, resp.Status)
+	}
+	return nil
+}
+
+/*
+SignWebhookPayload returns value of signature header: hex encoded HMAC-SHA256 of payload with subscription secret
+*/
+func SignWebhookPayload(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,169 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/leveldorado/space-trouble/pkg/tools/logger"
+	"github.com/leveldorado/space-trouble/pkg/types"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhooks_Subscribe(t *testing.T) {
+	repo := &mockWebhooksRepo{}
+	repo.On("InsertSubscription", mock.Anything, mock.Anything).Return(nil)
+	w := NewWebhooks(repo, http.DefaultClient, logger.New())
+
+	_, err := w.Subscribe(context.TODO(), types.WebhookSubscription{URL: "ftp://example.com", EventTypes: []string{types.OrderEventCreated}})
+	require.True(t, errors.As(err, &types.ErrInvalidData{}))
+	_, err = w.Subscribe(context.TODO(), types.WebhookSubscription{URL: "https://example.com", EventTypes: []string{"order.unknown"}})
+	require.True(t, errors.As(err, &types.ErrInvalidData{}))
+
+	sub, err := w.Subscribe(context.TODO(), types.WebhookSubscription{URL: "https://example.com/hook", EventTypes: []string{types.OrderEventCreated}})
+	require.NoError(t, err)
+	require.NotEmpty(t, sub.ID)
+	require.Len(t, sub.Secret, webhookSecretSize*2)
+	repo.AssertNumberOfCalls(t, "InsertSubscription", 1)
+}
+
+func TestWebhooks_Publish(t *testing.T) {
+	subs := []types.WebhookSubscription{
+		{ID: uuid.New().String(), EventTypes: []string{types.OrderEventCreated, types.OrderEventCancelled}},
+		{ID: uuid.New().String(), EventTypes: []string{types.OrderEventCancelled}},
+	}
+	event := types.OrderEvent{
+		ID:      uuid.New().String(),
+		Type:    types.OrderEventCreated,
+		OrderID: uuid.New().String(),
+	}
+	repo := &mockWebhooksRepo{}
+	repo.On("ListSubscriptions", mock.Anything).Return(subs, nil)
+	repo.On("InsertDeliveries", mock.Anything, mock.Anything).
+		Return(func(_ context.Context, docs []types.WebhookDelivery) error {
+			require.Len(t, docs, 1)
+			require.Equal(t, subs[0].ID, docs[0].SubscriptionID)
+			require.Equal(t, event.ID, docs[0].EventID)
+			require.Equal(t, types.WebhookDeliveryStatusPending, docs[0].Status)
+			var payload types.OrderEvent
+			require.NoError(t, json.Unmarshal(docs[0].Payload, &payload))
+			require.Equal(t, event.OrderID, payload.OrderID)
+			return nil
+		})
+
+	require.NoError(t, NewWebhooks(repo, http.DefaultClient, logger.New()).Publish(context.TODO(), event))
+	repo.AssertExpectations(t)
+}
+
+func TestWebhooks_ProcessDueRetriesThenDelivers(t *testing.T) {
+	var calls int32
+	secret := "secret"
+	payload := []byte(`{"id":"1","type":"order.created"}`)
+	receiver := httptest.NewServer(http.HandlerFunc(func(wr http.ResponseWriter, req *http.Request) {
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		require.Equal(t, payload, body)
+		require.Equal(t, SignWebhookPayload(secret, body), req.Header.Get(WebhookSignatureHeader))
+		require.Equal(t, types.OrderEventCreated, req.Header.Get(WebhookEventHeader))
+		if atomic.AddInt32(&calls, 1) == 1 {
+			wr.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		wr.WriteHeader(http.StatusOK)
+	}))
+	defer receiver.Close()
+
+	sub := types.WebhookSubscription{ID: uuid.New().String(), URL: receiver.URL, Secret: secret}
+	delivery := types.WebhookDelivery{
+		ID:             uuid.New().String(),
+		SubscriptionID: sub.ID,
+		EventType:      types.OrderEventCreated,
+		Payload:        payload,
+		Status:         types.WebhookDeliveryStatusPending,
+	}
+	repo := &mockWebhooksRepo{}
+	repo.On("ClaimDueDeliveries", mock.Anything, mock.Anything, webhookClaimLease, webhookBatchSize).
+		Return(func(context.Context, time.Time, time.Duration, int) []types.WebhookDelivery {
+			return []types.WebhookDelivery{delivery}
+		}, nil)
+	repo.On("GetSubscription", mock.Anything, sub.ID).Return(sub, nil)
+	repo.On("UpdateDelivery", mock.Anything, mock.Anything).
+		Return(func(_ context.Context, doc types.WebhookDelivery) error {
+			delivery = doc
+			return nil
+		})
+	w := NewWebhooks(repo, receiver.Client(), logger.New())
+
+	n, err := w.ProcessDue(context.TODO())
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	require.Equal(t, types.WebhookDeliveryStatusPending, delivery.Status)
+	require.Equal(t, 1, delivery.Attempts)
+	require.Contains(t, delivery.LastError, "502")
+	require.WithinDuration(t, time.Now().Add(webhookBaseBackoff), delivery.NextAttemptAt, time.Second)
+
+	_, err = w.ProcessDue(context.TODO())
+	require.NoError(t, err)
+	require.Equal(t, types.WebhookDeliveryStatusDelivered, delivery.Status)
+	require.Equal(t, 2, delivery.Attempts)
+	require.NotNil(t, delivery.DeliveredAt)
+	require.Empty(t, delivery.LastError)
+}
+
+func TestWebhooks_AttemptMovesToDeadLetter(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(wr http.ResponseWriter, _ *http.Request) {
+		wr.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer receiver.Close()
+	w := NewWebhooks(nil, receiver.Client(), logger.New())
+	sub := types.WebhookSubscription{ID: uuid.New().String(), URL: receiver.URL}
+	d := types.WebhookDelivery{Attempts: webhookMaxAttempts - 1, Status: types.WebhookDeliveryStatusPending}
+
+	d = w.attempt(context.TODO(), sub, d, time.Now())
+	require.Equal(t, types.WebhookDeliveryStatusDead, d.Status)
+	require.Equal(t, webhookMaxAttempts, d.Attempts)
+
+	d = w.attempt(context.TODO(), types.WebhookSubscription{}, types.WebhookDelivery{}, time.Now())
+	require.Equal(t, types.WebhookDeliveryStatusDead, d.Status)
+	require.Equal(t, "subscription deleted", d.LastError)
+}
+
+func TestWebhookClaimLeaseOutlastsBatch(t *testing.T) {
+	require.Greater(t, webhookClaimLease, webhookBatchSize*webhookSendTimeout)
+}
+
+func TestWebhookBackoff(t *testing.T) {
+	require.Equal(t, webhookBaseBackoff, webhookBackoff(1))
+	require.Equal(t, 2*webhookBaseBackoff, webhookBackoff(2))
+	require.Equal(t, 8*webhookBaseBackoff, webhookBackoff(4))
+	require.Equal(t, webhookMaxBackoff, webhookBackoff(100))
+}
+
+func TestWebhooks_Replay(t *testing.T) {
+	delivery := types.WebhookDelivery{
+		ID:        uuid.New().String(),
+		Status:    types.WebhookDeliveryStatusDead,
+		Attempts:  webhookMaxAttempts,
+		LastError: "fail",
+	}
+	repo := &mockWebhooksRepo{}
+	repo.On("GetDelivery", mock.Anything, delivery.ID).Return(delivery, nil)
+	repo.On("UpdateDelivery", mock.Anything, mock.Anything).
+		Return(func(_ context.Context, doc types.WebhookDelivery) error {
+			require.Equal(t, types.WebhookDeliveryStatusPending, doc.Status)
+			require.Equal(t, 0, doc.Attempts)
+			require.Empty(t, doc.LastError)
+			return nil
+		})
+
+	require.NoError(t, NewWebhooks(repo, http.DefaultClient, logger.New()).Replay(context.TODO(), delivery.ID))
+	repo.AssertExpectations(t)
+}
@@ -0,0 +1,47 @@
+package actor
+
+import (
+	"context"
+	"net/http"
+)
+
+const (
+	// Header is request header with name of user or system doing the request
+	Header = "X-Actor"
+	// Unknown is returned for context without actor
+	Unknown = "unknown"
+
+	maxLength = 256
+)
+
+type ctxKey struct{}
+
+func NewContext(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, actor)
+}
+
+func FromContext(ctx context.Context) string {
+	if ctx == nil {
+		return Unknown
+	}
+	if actor, ok := ctx.Value(ctxKey{}).(string); ok && actor != "" {
+		return actor
+	}
+	return Unknown
+}
+
+/*
+Middleware puts actor from Header to request context
+*/
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(wr http.ResponseWriter, req *http.Request) {
+		actor := req.Header.Get(Header)
+		if len(actor) > maxLength {
+			actor = actor[:maxLength]
+		}
+		if actor != "" {
+			req = req.WithContext(NewContext(req.Context(), actor))
+		}
+		next.ServeHTTP(wr, req)
+	})
+}
@@ -0,0 +1,35 @@
+package actor
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMiddleware(t *testing.T) {
+	var got string
+	h := Middleware(http.HandlerFunc(func(_ http.ResponseWriter, req *http.Request) {
+		got = FromContext(req.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	h.ServeHTTP(httptest.NewRecorder(), req)
+	require.Equal(t, Unknown, got)
+
+	req.Header.Set(Header, "support@example.com")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+	require.Equal(t, "support@example.com", got)
+
+	req.Header.Set(Header, strings.Repeat("a", maxLength+10))
+	h.ServeHTTP(httptest.NewRecorder(), req)
+	require.Len(t, got, maxLength)
+}
+
+func TestFromContext(t *testing.T) {
+	require.Equal(t, Unknown, FromContext(context.Background()))
+	require.Equal(t, "cli", FromContext(NewContext(context.Background(), "cli")))
+}
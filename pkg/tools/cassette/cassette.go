@@ -0,0 +1,303 @@
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	ModeReplay = "replay"
+	ModeRecord = "record"
+)
+
+type Request struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+	Body   string `json:"body,omitempty"`
+}
+
+type Response struct {
+	Code    int         `json:"code"`
+	Headers http.Header `json:"headers,omitempty"`
+	Body    string      `json:"body"`
+}
+
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+/*
+Transport records request/response pairs into cassette file or replays them without network.
+
+	requests match on method, url and body, JSON bodies are compared normalized so key order and spacing don't matter.
+	in replay mode matching interactions are returned in recorded order, the last one is repeated when they are used up.
+	in record mode cassette is written after every interaction, so it's complete even when test fails
+*/
+type Transport struct {
+	path          string
+	mode          string
+	next          http.RoundTripper
+	ignoredFields [][]string
+
+	mu       sync.Mutex
+	cassette Cassette
+	used     []bool
+}
+
+/*
+NewTransport creates transport for cassette at path, next is used to do real requests in record mode,
+http.DefaultTransport when nil. Recording starts new cassette
+*/
+func NewTransport(path, mode string, next http.RoundTripper) (*Transport, error) {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	t := &Transport{path: path, mode: mode, next: next}
+	switch mode {
+	case ModeRecord:
+		return t, nil
+	case ModeReplay:
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, errors.Wrapf(err, `failed to read cassette: path - %s`, path)
+		}
+		if err = json.Unmarshal(data, &t.cassette); err != nil {
+			return nil, errors.Wrapf(err, `failed to unmarshal cassette: path - %s`, path)
+		}
+		t.used = make([]bool, len(t.cassette.Interactions))
+		return t, nil
+	default:
+		return nil, errors.Errorf(`unknown cassette mode: mode - %s`, mode)
+	}
+}
+
+/*
+WithIgnoredBodyFields makes replay match JSON bodies regardless of values at dot separated paths like query.date_utc.
+
+	it's for requests which body depends on current time, like launches mirror sync range, so cassette recorded
+	yesterday is replayed today. interactions matching exactly are preferred over ones matching without ignored fields
+*/
+func (t *Transport) WithIgnoredBodyFields(paths ...string) *Transport {
+	for _, p := range paths {
+		t.ignoredFields = append(t.ignoredFields, strings.Split(p, "."))
+	}
+	return t
+}
+
+/*
+Unused returns recorded requests which were not replayed, tests check it's empty so cassette has no interactions
+which test doesn't do, like edited by hand ones
+*/
+func (t *Transport) Unused() []Request {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var unused []Request
+	for i, used := range t.used {
+		if !used {
+			unused = append(unused, t.cassette.Interactions[i].Request)
+		}
+	}
+	return unused
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+	recorded := Request{Method: req.Method, URL: req.URL.String(), Body: body}
+	if t.mode == ModeReplay {
+		resp, err := t.replay(recorded)
+		if err != nil {
+			return nil, err
+		}
+		return newResponse(req, resp), nil
+	}
+	return t.record(req, recorded)
+}
+
+func (t *Transport) replay(req Request) (Response, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if resp, ok := t.find(req, nil); ok {
+		return resp, nil
+	}
+	if len(t.ignoredFields) > 0 {
+		if resp, ok := t.find(req, t.ignoredFields); ok {
+			return resp, nil
+		}
+	}
+	return Response{}, errors.Errorf(`no recorded interaction: method - %s, url - %s, body - %s`, req.Method, req.URL, req.Body)
+}
+
+/*
+find returns the first not used interaction matching request, the last matching one when all of them are used
+*/
+func (t *Transport) find(req Request, ignoredFields [][]string) (Response, bool) {
+	last := -1
+	for i, interaction := range t.cassette.Interactions {
+		if !matches(interaction.Request, req, ignoredFields) {
+			continue
+		}
+		last = i
+		if !t.used[i] {
+			t.used[i] = true
+			return interaction.Response, true
+		}
+	}
+	if last < 0 {
+		return Response{}, false
+	}
+	return t.cassette.Interactions[last].Response, true
+}
+
+func (t *Transport) record(req *http.Request, recorded Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, errors.Wrap(err, `failed to read response body`)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(data))
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.cassette.Interactions = append(t.cassette.Interactions, Interaction{
+		Request:  recorded,
+		Response: Response{Code: resp.StatusCode, Headers: recordedHeaders(resp.Header), Body: string(data)},
+	})
+	if err = t.save(); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (t *Transport) save() error {
+	data, err := json.MarshalIndent(t.cassette, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, `failed to marshal cassette`)
+	}
+	if err = os.MkdirAll(filepath.Dir(t.path), 0o755); err != nil {
+		return errors.Wrapf(err, `failed to create cassette dir: path - %s`, t.path)
+	}
+	return errors.Wrapf(os.WriteFile(t.path, append(data, '\n'), 0o600), `failed to write cassette: path - %s`, t.path)
+}
+
+func readBody(req *http.Request) (string, error) {
+	if req.Body == nil {
+		return "", nil
+	}
+	data, err := io.ReadAll(req.Body)
+	_ = req.Body.Close()
+	if err != nil {
+		return "", errors.Wrap(err, `failed to read request body`)
+	}
+	req.Body = io.NopCloser(bytes.NewReader(data))
+	return normalizeBody(data), nil
+}
+
+/*
+normalizeBody re-encodes JSON body with sorted keys and without spacing, other bodies are kept as they are
+*/
+func normalizeBody(data []byte) string {
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return string(data)
+	}
+	normalized, err := json.Marshal(v)
+	if err != nil {
+		return string(data)
+	}
+	return string(normalized)
+}
+
+func matches(recorded, req Request, ignoredFields [][]string) bool {
+	if !strings.EqualFold(recorded.Method, req.Method) || recorded.URL != req.URL {
+		return false
+	}
+	if len(ignoredFields) == 0 {
+		return normalizeBody([]byte(recorded.Body)) == req.Body
+	}
+	return withoutFields(recorded.Body, ignoredFields) == withoutFields(req.Body, ignoredFields)
+}
+
+/*
+withoutFields returns normalized JSON body without fields at paths, other bodies are kept as they are
+*/
+func withoutFields(body string, paths [][]string) string {
+	var v interface{}
+	if err := json.Unmarshal([]byte(body), &v); err != nil {
+		return body
+	}
+	for _, p := range paths {
+		deleteField(v, p)
+	}
+	normalized, err := json.Marshal(v)
+	if err != nil {
+		return body
+	}
+	return string(normalized)
+}
+
+func deleteField(v interface{}, path []string) {
+	obj, ok := v.(map[string]interface{})
+	if !ok || len(path) == 0 {
+		return
+	}
+	if len(path) == 1 {
+		delete(obj, path[0])
+		return
+	}
+	deleteField(obj[path[0]], path[1:])
+}
+
+/*
+recordedHeaders keeps headers which affect response handling, so cassettes don't carry cookies and request ids
+*/
+func recordedHeaders(h http.Header) http.Header {
+	kept := http.Header{}
+	for _, key := range []string{"Content-Type", "Retry-After"} {
+		if values := h.Values(key); len(values) > 0 {
+			kept[key] = values
+		}
+	}
+	if len(kept) == 0 {
+		return nil
+	}
+	return kept
+}
+
+func newResponse(req *http.Request, r Response) *http.Response {
+	header := r.Headers.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	return &http.Response{
+		Status:        strconv.Itoa(r.Code) + " " + http.StatusText(r.Code),
+		StatusCode:    r.Code,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(r.Body)),
+		ContentLength: int64(len(r.Body)),
+		Request:       req,
+	}
+}
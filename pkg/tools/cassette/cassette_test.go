@@ -0,0 +1,103 @@
+package cassette
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func post(t *testing.T, cl *http.Client, url, body string) (int, string) {
+	resp, err := cl.Post(url, "application/json", bytes.NewBufferString(body))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp.StatusCode, string(data)
+}
+
+func TestTransport(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		data, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Request-Id", "secret")
+		_, _ = w.Write([]byte(`{"call": ` + strconv.Itoa(calls) + `, "echo": ` + string(data) + `}`))
+	}))
+	path := filepath.Join(t.TempDir(), "cassettes", "query.json")
+
+	recorder, err := NewTransport(path, ModeRecord, nil)
+	require.NoError(t, err)
+	cl := &http.Client{Transport: recorder}
+	_, first := post(t, cl, srv.URL+"/query", `{"query": {"a": 1}, "options": {}}`)
+	_, second := post(t, cl, srv.URL+"/query", `{"query": {"a": 1}, "options": {}}`)
+	_, other := post(t, cl, srv.URL+"/query", `{"query": {"a": 2}}`)
+	srv.Close()
+
+	player, err := NewTransport(path, ModeReplay, nil)
+	require.NoError(t, err)
+	cl = &http.Client{Transport: player}
+	// key order and spacing of JSON body don't matter
+	code, body := post(t, cl, srv.URL+"/query", `{"options":{},"query":{"a":1}}`)
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, first, body)
+	_, body = post(t, cl, srv.URL+"/query", `{"options":{},"query":{"a":1}}`)
+	require.Equal(t, second, body)
+	// used up interactions repeat the last one
+	_, body = post(t, cl, srv.URL+"/query", `{"options":{},"query":{"a":1}}`)
+	require.Equal(t, second, body)
+	_, body = post(t, cl, srv.URL+"/query", `{"query":{"a":2}}`)
+	require.Equal(t, other, body)
+
+	resp, err := cl.Get(srv.URL + "/query")
+	require.Error(t, err)
+	require.Nil(t, resp)
+
+	require.Nil(t, player.cassette.Interactions[0].Response.Headers.Values("X-Request-Id"))
+	require.Equal(t, "application/json", player.cassette.Interactions[0].Response.Headers.Get("Content-Type"))
+
+	_, err = NewTransport(filepath.Join(t.TempDir(), "missing.json"), ModeReplay, nil)
+	require.Error(t, err)
+}
+
+func TestTransport_IgnoredBodyFields(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		_, _ = w.Write(data)
+	}))
+	path := filepath.Join(t.TempDir(), "sync.json")
+
+	recorder, err := NewTransport(path, ModeRecord, nil)
+	require.NoError(t, err)
+	cl := &http.Client{Transport: recorder}
+	_, synced := post(t, cl, srv.URL+"/query", `{"query": {"date_utc": {"$gte": "2022-08-20T00:00:00Z"}}, "options": {"limit": 1}}`)
+	_, exact := post(t, cl, srv.URL+"/query", `{"query": {"date_utc": {"$gte": "2022-08-21T00:00:00Z"}}, "options": {"limit": 1}}`)
+	srv.Close()
+
+	player, err := NewTransport(path, ModeReplay, nil)
+	require.NoError(t, err)
+	cl = &http.Client{Transport: player}
+	_, err = cl.Post(srv.URL+"/query", "application/json", bytes.NewBufferString(`{"query": {"date_utc": {"$gte": "2023-01-01T00:00:00Z"}}, "options": {"limit": 1}}`))
+	require.Error(t, err)
+
+	player, err = NewTransport(path, ModeReplay, nil)
+	require.NoError(t, err)
+	player.WithIgnoredBodyFields("query.date_utc")
+	cl = &http.Client{Transport: player}
+	// exact match is preferred
+	_, body := post(t, cl, srv.URL+"/query", `{"query": {"date_utc": {"$gte": "2022-08-21T00:00:00Z"}}, "options": {"limit": 1}}`)
+	require.Equal(t, exact, body)
+	require.Len(t, player.Unused(), 1)
+	_, body = post(t, cl, srv.URL+"/query", `{"query": {"date_utc": {"$gte": "2023-01-01T00:00:00Z"}}, "options": {"limit": 1}}`)
+	require.Equal(t, synced, body)
+	require.Empty(t, player.Unused())
+	// not ignored fields still have to match
+	_, err = cl.Post(srv.URL+"/query", "application/json", bytes.NewBufferString(`{"query": {"date_utc": {"$gte": "2023-01-01T00:00:00Z"}}, "options": {"limit": 2}}`))
+	require.Error(t, err)
+}
@@ -0,0 +1,75 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+/*
+Real tells actual time
+*/
+type Real struct{}
+
+func (Real) Now() time.Time {
+	return time.Now()
+}
+
+/*
+Virtual tells time shifted from actual one, it's meant for staging to exercise schedules and cutoffs ahead of time.
+
+	shifted time keeps running, so scheduled work becomes due the same way as with actual time
+*/
+type Virtual struct {
+	mu     sync.RWMutex
+	offset time.Duration
+	now    func() time.Time
+}
+
+func NewVirtual() *Virtual {
+	return &Virtual{now: time.Now}
+}
+
+func (c *Virtual) Now() time.Time {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.now().Add(c.offset)
+}
+
+/*
+Offset returns how far virtual time is from actual one, negative when it's in the past
+*/
+func (c *Virtual) Offset() time.Duration {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.offset
+}
+
+/*
+Set moves virtual time to t
+*/
+func (c *Virtual) Set(t time.Time) time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.offset = t.Sub(c.now())
+	return t
+}
+
+/*
+Advance moves virtual time forward by d, negative d moves it back
+*/
+func (c *Virtual) Advance(d time.Duration) time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.offset += d
+	return c.now().Add(c.offset)
+}
+
+/*
+Reset returns virtual time to actual one
+*/
+func (c *Virtual) Reset() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.offset = 0
+	return c.now()
+}
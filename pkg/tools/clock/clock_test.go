@@ -0,0 +1,31 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVirtual(t *testing.T) {
+	actual := time.Date(2022, 8, 21, 10, 0, 0, 0, time.UTC)
+	c := NewVirtual()
+	c.now = func() time.Time { return actual }
+	require.Equal(t, actual, c.Now())
+
+	future := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	require.Equal(t, future, c.Set(future))
+	require.Equal(t, future, c.Now())
+
+	// virtual time keeps running after it's set
+	actual = actual.Add(time.Hour)
+	require.Equal(t, future.Add(time.Hour), c.Now())
+
+	require.Equal(t, future.Add(25*time.Hour), c.Advance(24*time.Hour))
+	require.Equal(t, future.Add(25*time.Hour).Sub(actual), c.Offset())
+	require.Equal(t, future.Add(time.Hour), c.Advance(-24*time.Hour))
+
+	require.Equal(t, actual, c.Reset())
+	require.Equal(t, actual, c.Now())
+	require.Zero(t, c.Offset())
+}
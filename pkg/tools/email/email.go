@@ -0,0 +1,158 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/leveldorado/space-trouble/pkg/types"
+	"github.com/pkg/errors"
+)
+
+/*
+Format returns message in RFC 5322 format with plain text utf-8 body, Date header is taken from message
+*/
+func Format(from string, msg types.EmailMessage) []byte {
+	b := &bytes.Buffer{}
+	fmt.Fprintf(b, "From: %s\r\n", from)
+	fmt.Fprintf(b, "To: %s\r\n", msg.To)
+	fmt.Fprintf(b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(b, "Date: %s\r\n", msg.Date.Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(strings.ReplaceAll(msg.Body, "\r\n", "\n"), "\n", "\r\n"))
+	return b.Bytes()
+}
+
+// DefaultSMTPTimeout bounds dial and whole exchange with SMTP server of one message
+const DefaultSMTPTimeout = 30 * time.Second
+
+/*
+SMTPSender sends messages with SMTP server, authentication is used only when username provided.
+
+	connection is dialed with context of send and gets deadline of context or timeout whichever is earlier,
+	cancelled context closes connection, so stuck server doesn't block sending forever.
+	STARTTLS is used when server supports it, like smtp.SendMail does
+*/
+type SMTPSender struct {
+	addr    string
+	host    string
+	from    string
+	auth    smtp.Auth
+	timeout time.Duration
+}
+
+func NewSMTPSender(addr, from, username, password string) (*SMTPSender, error) {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, errors.Wrapf(err, `failed to parse smtp address: addr - %s`, addr)
+	}
+	s := &SMTPSender{addr: addr, host: host, from: from, timeout: DefaultSMTPTimeout}
+	if username != "" {
+		s.auth = smtp.PlainAuth("", username, password, host)
+	}
+	return s, nil
+}
+
+/*
+WithTimeout replaces DefaultSMTPTimeout
+*/
+func (s *SMTPSender) WithTimeout(d time.Duration) *SMTPSender {
+	s.timeout = d
+	return s
+}
+
+func (s *SMTPSender) Send(ctx context.Context, msg types.EmailMessage) error {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	return errors.Wrapf(s.send(ctx, msg), `failed to send mail: addr - %s, to - %s`, s.addr, msg.To)
+}
+
+func (s *SMTPSender) send(ctx context.Context, msg types.EmailMessage) error {
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", s.addr)
+	if err != nil {
+		return errors.Wrap(err, `failed to dial`)
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		if err = conn.SetDeadline(deadline); err != nil {
+			return errors.Wrap(err, `failed to set deadline`)
+		}
+	}
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			// unblocks reads and writes of cancelled send
+			_ = conn.Close()
+		case <-done:
+		}
+	}()
+
+	c, err := smtp.NewClient(conn, s.host)
+	if err != nil {
+		return errors.Wrap(err, `failed to create client`)
+	}
+	defer c.Close()
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err = c.StartTLS(&tls.Config{ServerName: s.host}); err != nil {
+			return errors.Wrap(err, `failed to start tls`)
+		}
+	}
+	if s.auth != nil {
+		if ok, _ := c.Extension("AUTH"); !ok {
+			return errors.New(`server doesn't support AUTH`)
+		}
+		if err = c.Auth(s.auth); err != nil {
+			return errors.Wrap(err, `failed to authenticate`)
+		}
+	}
+	if err = c.Mail(s.from); err != nil {
+		return errors.Wrap(err, `failed to set sender`)
+	}
+	if err = c.Rcpt(msg.To); err != nil {
+		return errors.Wrap(err, `failed to set recipient`)
+	}
+	w, err := c.Data()
+	if err != nil {
+		return errors.Wrap(err, `failed to start data`)
+	}
+	if _, err = w.Write(Format(s.from, msg)); err != nil {
+		return errors.Wrap(err, `failed to write message`)
+	}
+	if err = w.Close(); err != nil {
+		return errors.Wrap(err, `failed to finish data`)
+	}
+	return errors.Wrap(c.Quit(), `failed to quit`)
+}
+
+/*
+WriterSender writes messages to writer, used for local development with stdout or file
+*/
+type WriterSender struct {
+	mu   sync.Mutex
+	w    io.Writer
+	from string
+}
+
+func NewWriterSender(w io.Writer, from string) *WriterSender {
+	return &WriterSender{w: w, from: from}
+}
+
+func (s *WriterSender) Send(_ context.Context, msg types.EmailMessage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b := append(Format(s.from, msg), "\r\n.\r\n"...)
+	_, err := s.w.Write(b)
+	return errors.Wrapf(err, `failed to write message: to - %s`, msg.To)
+}
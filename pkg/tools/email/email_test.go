@@ -0,0 +1,127 @@
+package email
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/leveldorado/space-trouble/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormat(t *testing.T) {
+	msg := types.EmailMessage{
+		To:      "vasyl@example.com",
+		Subject: "Політ на Марс",
+		Body:    "line 1\nline 2",
+		Date:    time.Date(2022, 8, 21, 12, 0, 0, 0, time.UTC),
+	}
+	b := string(Format("bookings@example.com", msg))
+	require.Contains(t, b, "From: bookings@example.com\r\n")
+	require.Contains(t, b, "To: vasyl@example.com\r\n")
+	require.Contains(t, b, "Subject: =?utf-8?q?")
+	require.Contains(t, b, "Date: Sun, 21 Aug 2022 12:00:00 +0000\r\n")
+	require.True(t, strings.HasSuffix(b, "\r\n\r\nline 1\r\nline 2"))
+}
+
+func TestWriterSender(t *testing.T) {
+	b := &bytes.Buffer{}
+	s := NewWriterSender(b, "bookings@example.com")
+	require.NoError(t, s.Send(context.TODO(), types.EmailMessage{To: "a@example.com", Subject: "a", Body: "a"}))
+	require.NoError(t, s.Send(context.TODO(), types.EmailMessage{To: "b@example.com", Subject: "b", Body: "b"}))
+	require.Equal(t, 2, strings.Count(b.String(), "\r\n.\r\n"))
+	require.Contains(t, b.String(), "To: b@example.com")
+}
+
+func TestNewSMTPSender(t *testing.T) {
+	_, err := NewSMTPSender("localhost", "bookings@example.com", "", "")
+	require.Error(t, err)
+	s, err := NewSMTPSender("localhost:25", "bookings@example.com", "user", "password")
+	require.NoError(t, err)
+	require.NotNil(t, s.auth)
+}
+
+/*
+serveSMTP accepts one connection and answers commands like SMTP server without extensions, silent server never answers
+*/
+func serveSMTP(t *testing.T, silent bool) (string, <-chan string) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = l.Close() })
+	received := make(chan string, 1)
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		if silent {
+			_, _ = io.Copy(io.Discard, conn)
+			return
+		}
+		r := bufio.NewReader(conn)
+		_, _ = conn.Write([]byte("220 localhost ready\r\n"))
+		var data strings.Builder
+		inData := false
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			if inData {
+				if line == ".\r\n" {
+					inData = false
+					received <- data.String()
+					_, _ = conn.Write([]byte("250 queued\r\n"))
+					continue
+				}
+				data.WriteString(line)
+				continue
+			}
+			switch strings.ToUpper(strings.Fields(line)[0]) {
+			case "DATA":
+				inData = true
+				_, _ = conn.Write([]byte("354 go ahead\r\n"))
+			case "QUIT":
+				_, _ = conn.Write([]byte("221 bye\r\n"))
+				return
+			default:
+				_, _ = conn.Write([]byte("250 ok\r\n"))
+			}
+		}
+	}()
+	return l.Addr().String(), received
+}
+
+func TestSMTPSender_Send(t *testing.T) {
+	addr, received := serveSMTP(t, false)
+	s, err := NewSMTPSender(addr, "bookings@example.com", "", "")
+	require.NoError(t, err)
+	require.NoError(t, s.Send(context.TODO(), types.EmailMessage{To: "a@example.com", Subject: "a", Body: "hello"}))
+	require.Contains(t, <-received, "hello")
+}
+
+func TestSMTPSender_SendStuckServer(t *testing.T) {
+	addr, _ := serveSMTP(t, true)
+	s, err := NewSMTPSender(addr, "bookings@example.com", "", "")
+	require.NoError(t, err)
+
+	started := time.Now()
+	err = s.WithTimeout(100*time.Millisecond).Send(context.TODO(), types.EmailMessage{To: "a@example.com"})
+	require.Error(t, err)
+	require.Less(t, time.Since(started), 5*time.Second)
+
+	addr, _ = serveSMTP(t, true)
+	s, err = NewSMTPSender(addr, "bookings@example.com", "", "")
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+	started = time.Now()
+	require.Error(t, s.Send(ctx, types.EmailMessage{To: "a@example.com"}))
+	require.Less(t, time.Since(started), 5*time.Second)
+}
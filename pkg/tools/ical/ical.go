@@ -0,0 +1,225 @@
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	ContentType = "text/calendar; charset=utf-8"
+
+	productID     = "-//space-trouble//space-trouble//EN"
+	maxLineLength = 75
+	dateLayout    = "20060102"
+	localLayout   = "20060102T150405"
+	utcLayout     = "20060102T150405Z"
+	// how far back transition of time zone offset is searched for VTIMEZONE component
+	zoneSearchLimit = 366 * 24 * time.Hour
+)
+
+type Calendar struct {
+	Name   string
+	Events []Event
+}
+
+/*
+Event is calendar entry.
+
+	all day event takes dates of Start and End (exclusive), timed event is written in time zone of Start
+*/
+type Event struct {
+	UID         string
+	Summary     string
+	Description string
+	Location    string
+	Start       time.Time
+	End         time.Time
+	AllDay      bool
+}
+
+/*
+Encode writes calendar in RFC 5545 format, now is used as DTSTAMP of events
+*/
+func Encode(w io.Writer, c Calendar, now time.Time) error {
+	bw := bufio.NewWriter(w)
+	lw := &lineWriter{w: bw}
+	lw.line("BEGIN:VCALENDAR")
+	lw.line("VERSION:2.0")
+	lw.line("PRODID:" + productID)
+	lw.line("CALSCALE:GREGORIAN")
+	lw.line("METHOD:PUBLISH")
+	if c.Name != "" {
+		lw.line("X-WR-CALNAME:" + escapeText(c.Name))
+	}
+	writeTimezones(lw, c.Events)
+	for _, e := range c.Events {
+		lw.line("BEGIN:VEVENT")
+		lw.line("UID:" + escapeText(e.UID))
+		lw.line("DTSTAMP:" + now.UTC().Format(utcLayout))
+		if e.AllDay {
+			lw.line("DTSTART;VALUE=DATE:" + e.Start.Format(dateLayout))
+			lw.line("DTEND;VALUE=DATE:" + e.End.Format(dateLayout))
+		} else {
+			lw.line("DTSTART" + formatDateTime(e.Start))
+			lw.line("DTEND" + formatDateTime(e.End.In(e.Start.Location())))
+		}
+		lw.line("SUMMARY:" + escapeText(e.Summary))
+		if e.Description != "" {
+			lw.line("DESCRIPTION:" + escapeText(e.Description))
+		}
+		if e.Location != "" {
+			lw.line("LOCATION:" + escapeText(e.Location))
+		}
+		lw.line("END:VEVENT")
+	}
+	lw.line("END:VCALENDAR")
+	if lw.err != nil {
+		return errors.Wrap(lw.err, `failed to write calendar`)
+	}
+	return errors.Wrap(bw.Flush(), `failed to flush calendar`)
+}
+
+func formatDateTime(t time.Time) string {
+	if t.Location() == time.UTC {
+		return ":" + t.Format(utcLayout)
+	}
+	return ";TZID=" + t.Location().String() + ":" + t.Format(localLayout)
+}
+
+/*
+writeTimezones writes VTIMEZONE for every zone used by timed events.
+
+	component describes only offset periods events fall into, which is enough for clients to place events
+*/
+func writeTimezones(lw *lineWriter, events []Event) {
+	periods := map[string][]zonePeriod{}
+	var names []string
+	for _, e := range events {
+		if e.AllDay || e.Start.Location() == time.UTC {
+			continue
+		}
+		name := e.Start.Location().String()
+		if _, ok := periods[name]; !ok {
+			names = append(names, name)
+		}
+		p := findZonePeriod(e.Start)
+		if !containsPeriod(periods[name], p) {
+			periods[name] = append(periods[name], p)
+		}
+	}
+	for _, name := range names {
+		lw.line("BEGIN:VTIMEZONE")
+		lw.line("TZID:" + name)
+		for _, p := range periods[name] {
+			kind := "STANDARD"
+			if p.dst {
+				kind = "DAYLIGHT"
+			}
+			lw.line("BEGIN:" + kind)
+			lw.line("DTSTART:" + p.start.Format(localLayout))
+			lw.line("TZOFFSETFROM:" + formatOffset(p.offsetFrom))
+			lw.line("TZOFFSETTO:" + formatOffset(p.offsetTo))
+			lw.line("TZNAME:" + p.name)
+			lw.line("END:" + kind)
+		}
+		lw.line("END:VTIMEZONE")
+	}
+}
+
+type zonePeriod struct {
+	// local time of period start in offset before transition
+	start      time.Time
+	offsetFrom int
+	offsetTo   int
+	name       string
+	dst        bool
+}
+
+func containsPeriod(periods []zonePeriod, p zonePeriod) bool {
+	for _, existing := range periods {
+		if existing == p {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+findZonePeriod finds the last offset transition before t, zones without transitions get period starting in 1970
+*/
+func findZonePeriod(t time.Time) zonePeriod {
+	name, offset := t.Zone()
+	p := zonePeriod{
+		start:      time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC),
+		offsetFrom: offset,
+		offsetTo:   offset,
+		name:       name,
+		dst:        t.IsDST(),
+	}
+	after := t
+	for after.Sub(t) > -zoneSearchLimit {
+		before := after.Add(-time.Hour)
+		if _, beforeOffset := before.Zone(); beforeOffset != offset {
+			for after.Sub(before) > time.Minute {
+				middle := before.Add(after.Sub(before) / 2).Truncate(time.Minute)
+				if _, o := middle.Zone(); o == offset {
+					after = middle
+				} else {
+					before = middle
+				}
+			}
+			p.offsetFrom = beforeOffset
+			p.start = after.UTC().Add(time.Duration(beforeOffset) * time.Second)
+			return p
+		}
+		after = before
+	}
+	return p
+}
+
+func formatOffset(offset int) string {
+	sign := "+"
+	if offset < 0 {
+		sign = "-"
+		offset = -offset
+	}
+	return fmt.Sprintf("%s%02d%02d", sign, offset/3600, offset%3600/60)
+}
+
+var textEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+func escapeText(s string) string {
+	return textEscaper.Replace(s)
+}
+
+/*
+lineWriter writes content lines with CRLF and folds them at 75 octets without splitting utf-8 characters
+*/
+type lineWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (lw *lineWriter) line(s string) {
+	if lw.err != nil {
+		return
+	}
+	var b strings.Builder
+	lineLength := 0
+	for _, r := range s {
+		size := len(string(r))
+		if lineLength+size > maxLineLength {
+			b.WriteString("\r\n ")
+			lineLength = 1
+		}
+		b.WriteRune(r)
+		lineLength += size
+	}
+	b.WriteString("\r\n")
+	_, lw.err = io.WriteString(lw.w, b.String())
+}
@@ -0,0 +1,75 @@
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncode(t *testing.T) {
+	location, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	start := time.Date(2022, 9, 4, 10, 30, 0, 0, location)
+	c := Calendar{
+		Name: "Flights",
+		Events: []Event{
+			{
+				UID:         "1@space-trouble",
+				Summary:     "Flight to Mars",
+				Description: "Passengers: Vasyl Osypchuk, Olena Osypchuk\nSee you; soon",
+				Location:    "Kennedy Space Center",
+				Start:       start,
+				End:         start.Add(time.Hour),
+			},
+			{
+				UID:     "2@space-trouble",
+				Summary: "Moon",
+				Start:   time.Date(2022, 9, 5, 0, 0, 0, 0, location),
+				End:     time.Date(2022, 9, 6, 0, 0, 0, 0, location),
+				AllDay:  true,
+			},
+		},
+	}
+	b := &bytes.Buffer{}
+	require.NoError(t, Encode(b, c, time.Date(2022, 8, 21, 12, 0, 0, 0, time.UTC)))
+	out := b.String()
+
+	require.True(t, strings.HasPrefix(out, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"))
+	require.True(t, strings.HasSuffix(out, "END:VCALENDAR\r\n"))
+	require.Contains(t, out, "X-WR-CALNAME:Flights\r\n")
+	require.Contains(t, out, "DTSTAMP:20220821T120000Z\r\n")
+	require.Contains(t, out, "DTSTART;TZID=America/New_York:20220904T103000\r\n")
+	require.Contains(t, out, "DTEND;TZID=America/New_York:20220904T113000\r\n")
+	require.Contains(t, out, "DTSTART;VALUE=DATE:20220905\r\nDTEND;VALUE=DATE:20220906\r\n")
+	require.Contains(t, out, `Olena Osypchuk\nSee you\; soon`)
+	require.Contains(t, out, "LOCATION:Kennedy Space Center\r\n")
+
+	require.Contains(t, out, "BEGIN:VTIMEZONE\r\nTZID:America/New_York\r\nBEGIN:DAYLIGHT\r\n"+
+		"DTSTART:20220313T020000\r\nTZOFFSETFROM:-0500\r\nTZOFFSETTO:-0400\r\nTZNAME:EDT\r\nEND:DAYLIGHT\r\nEND:VTIMEZONE\r\n")
+	for _, line := range strings.Split(out, "\r\n") {
+		require.LessOrEqual(t, len(line), maxLineLength)
+	}
+}
+
+func TestFindZonePeriodWithoutTransitions(t *testing.T) {
+	p := findZonePeriod(time.Date(2022, 9, 4, 10, 30, 0, 0, time.FixedZone("X", 3*3600)))
+	require.Equal(t, 3*3600, p.offsetFrom)
+	require.Equal(t, 3*3600, p.offsetTo)
+	require.Equal(t, 1970, p.start.Year())
+}
+
+func TestLineFolding(t *testing.T) {
+	b := &bytes.Buffer{}
+	lw := &lineWriter{w: b}
+	lw.line("DESCRIPTION:" + strings.Repeat("ї", 60))
+	lines := strings.Split(strings.TrimSuffix(b.String(), "\r\n"), "\r\n")
+	require.Len(t, lines, 2)
+	require.True(t, strings.HasPrefix(lines[1], " "))
+	for _, line := range lines {
+		require.LessOrEqual(t, len(line), maxLineLength)
+	}
+	require.Equal(t, "DESCRIPTION:"+strings.Repeat("ї", 60), lines[0]+strings.TrimPrefix(lines[1], " "))
+}
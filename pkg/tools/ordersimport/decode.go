@@ -0,0 +1,178 @@
+package ordersimport
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/leveldorado/space-trouble/pkg/types"
+	"github.com/pkg/errors"
+)
+
+const (
+	FormatCSV    = "csv"
+	FormatNDJSON = "ndjson"
+
+	MaxRows = 10000
+
+	maxNDJSONLineSize = 1 << 20
+)
+
+/*
+Decode reads orders from csv or ndjson.
+
+	csv requires header row, columns are matched by names used in export, unknown columns are ignored.
+	rows which could not be decoded are returned with error so they can be reported together with other rows
+*/
+func Decode(format string, r io.Reader) ([]types.ImportRow, error) {
+	switch format {
+	case FormatCSV:
+		return decodeCSV(r)
+	case FormatNDJSON:
+		return decodeNDJSON(r)
+	default:
+		return nil, types.NewErrInvalidData("unsupported import format " + format)
+	}
+}
+
+func decodeNDJSON(r io.Reader) ([]types.ImportRow, error) {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), maxNDJSONLineSize)
+	var rows []types.ImportRow
+	var line int
+	for sc.Scan() {
+		line++
+		data := bytes.TrimSpace(sc.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+		if len(rows) == MaxRows {
+			return nil, types.NewErrInvalidData("import exceeds max rows " + strconv.Itoa(MaxRows))
+		}
+		row := types.ImportRow{Line: line}
+		if err := json.Unmarshal(data, &row.Order); err != nil {
+			row.Error = err.Error()
+		}
+		rows = append(rows, row)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, types.NewErrInvalidData("failed to read ndjson: " + err.Error())
+	}
+	return rows, nil
+}
+
+type csvField func(o *types.Order, value string) error
+
+var csvFields = map[string]csvField{
+	"first_name": func(o *types.Order, value string) error {
+		o.FirstName = value
+		return nil
+	},
+	"last_name": func(o *types.Order, value string) error {
+		o.LastName = value
+		return nil
+	},
+	"gender": func(o *types.Order, value string) error {
+		o.Gender = value
+		return nil
+	},
+	"email": func(o *types.Order, value string) error {
+		o.Email = value
+		return nil
+	},
+	"birthday_year":  intCSVField("birthday_year", func(o *types.Order) *int { return &o.BirthdayYear }),
+	"birthday_month": intCSVField("birthday_month", func(o *types.Order) *int { return &o.BirthdayMonth }),
+	"birthday_day":   intCSVField("birthday_day", func(o *types.Order) *int { return &o.BirthdayDay }),
+	"launchpad_id": func(o *types.Order, value string) error {
+		o.LaunchpadID = value
+		return nil
+	},
+	"destination_id": func(o *types.Order, value string) error {
+		o.DestinationID = value
+		return nil
+	},
+	"launch_date": func(o *types.Order, value string) error {
+		if value == "" {
+			return nil
+		}
+		date, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return errors.Errorf("invalid launch_date %s, RFC3339 expected", value)
+		}
+		o.LaunchDate = date
+		return nil
+	},
+	"launch_local_date": func(o *types.Order, value string) error {
+		o.LaunchLocalDate = value
+		return nil
+	},
+}
+
+func intCSVField(name string, field func(o *types.Order) *int) csvField {
+	return func(o *types.Order, value string) error {
+		if value == "" {
+			return nil
+		}
+		v, err := strconv.Atoi(value)
+		if err != nil {
+			return errors.Errorf("invalid %s %s", name, value)
+		}
+		*field(o) = v
+		return nil
+	}
+}
+
+func decodeCSV(r io.Reader) ([]types.ImportRow, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, types.NewErrInvalidData("csv header is required")
+	}
+	if err != nil {
+		return nil, types.NewErrInvalidData("failed to read csv header: " + err.Error())
+	}
+	fields := make([]csvField, len(header))
+	for i, name := range header {
+		fields[i] = csvFields[strings.TrimSpace(strings.ToLower(name))]
+	}
+	var rows []types.ImportRow
+	for {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, types.NewErrInvalidData("failed to read csv: " + err.Error())
+		}
+		if len(rows) == MaxRows {
+			return nil, types.NewErrInvalidData("import exceeds max rows " + strconv.Itoa(MaxRows))
+		}
+		line, _ := cr.FieldPos(0)
+		rows = append(rows, decodeCSVRecord(line, fields, record))
+	}
+}
+
+func decodeCSVRecord(line int, fields []csvField, record []string) types.ImportRow {
+	row := types.ImportRow{Line: line}
+	if len(record) != len(fields) {
+		row.Error = "wrong number of fields: expected " + strconv.Itoa(len(fields)) + ", got " + strconv.Itoa(len(record))
+		return row
+	}
+	for i, value := range record {
+		if fields[i] == nil {
+			continue
+		}
+		if err := fields[i](&row.Order, strings.TrimSpace(value)); err != nil {
+			row.Error = err.Error()
+			return row
+		}
+	}
+	return row
+}
@@ -0,0 +1,94 @@
+package ordersimport
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/leveldorado/space-trouble/pkg/types"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeCSV(t *testing.T) {
+	data := `id,first_name,last_name,gender,birthday_year,birthday_month,birthday_day,launchpad_id,destination_id,launch_date,destination_name
+,Vasyl,Osypchuk,male,2000,3,1,5e9e4501f509094ba4566f84,1,2053-09-04T00:00:00-07:00,Mars
+,Ivan,Franko,male,year,3,1,5e9e4501f509094ba4566f84,1,2053-09-04T00:00:00-07:00,Mars
+,Lesya,Ukrainka
+`
+	rows, err := Decode(FormatCSV, strings.NewReader(data))
+	require.NoError(t, err)
+	require.Equal(t, []types.ImportRow{
+		{
+			Line: 2,
+			Order: types.Order{
+				FirstName:     "Vasyl",
+				LastName:      "Osypchuk",
+				Gender:        "male",
+				BirthdayYear:  2000,
+				BirthdayMonth: 3,
+				BirthdayDay:   1,
+				LaunchpadID:   "5e9e4501f509094ba4566f84",
+				DestinationID: "1",
+				LaunchDate:    time.Date(2053, 9, 4, 0, 0, 0, 0, time.FixedZone("", -7*60*60)),
+			},
+		},
+		{
+			Line:  3,
+			Order: types.Order{FirstName: "Ivan", LastName: "Franko", Gender: "male"},
+			Error: "invalid birthday_year year",
+		},
+		{
+			Line:  4,
+			Error: "wrong number of fields: expected 11, got 3",
+		},
+	}, rows)
+}
+
+func TestDecodeCSVLaunchLocalDate(t *testing.T) {
+	data := `first_name,last_name,launchpad_id,destination_id,launch_date,launch_local_date
+Vasyl,Osypchuk,5e9e4501f509094ba4566f84,1,,2053-09-04
+`
+	rows, err := Decode(FormatCSV, strings.NewReader(data))
+	require.NoError(t, err)
+	require.Equal(t, []types.ImportRow{
+		{
+			Line: 2,
+			Order: types.Order{
+				FirstName:       "Vasyl",
+				LastName:        "Osypchuk",
+				LaunchpadID:     "5e9e4501f509094ba4566f84",
+				DestinationID:   "1",
+				LaunchLocalDate: "2053-09-04",
+			},
+		},
+	}, rows)
+}
+
+func TestDecodeNDJSON(t *testing.T) {
+	data := `{"first_name":"Vasyl","last_name":"Osypchuk","launchpad_id":"5e9e4501f509094ba4566f84","destination_id":"1"}
+
+{"first_name":
+`
+	rows, err := Decode(FormatNDJSON, strings.NewReader(data))
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	require.Equal(t, types.ImportRow{
+		Line: 1,
+		Order: types.Order{
+			FirstName:     "Vasyl",
+			LastName:      "Osypchuk",
+			LaunchpadID:   "5e9e4501f509094ba4566f84",
+			DestinationID: "1",
+		},
+	}, rows[0])
+	require.Equal(t, 3, rows[1].Line)
+	require.NotEmpty(t, rows[1].Error)
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	_, err := Decode("xml", strings.NewReader(""))
+	require.True(t, errors.As(err, &types.ErrInvalidData{}))
+	_, err = Decode(FormatCSV, strings.NewReader(""))
+	require.True(t, errors.As(err, &types.ErrInvalidData{}))
+}
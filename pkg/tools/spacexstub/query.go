@@ -0,0 +1,270 @@
+package spacexstub
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const defaultLimit = 10
+
+type queryRequest struct {
+	Query   map[string]interface{} `json:"query"`
+	Options queryOptions           `json:"options"`
+}
+
+type queryOptions struct {
+	Select     map[string]interface{} `json:"select"`
+	Sort       map[string]interface{} `json:"sort"`
+	Limit      *int                   `json:"limit"`
+	Offset     *int                   `json:"offset"`
+	Page       *int                   `json:"page"`
+	Pagination *bool                  `json:"pagination"`
+}
+
+/*
+queryResult is page of mongoose-paginate response
+*/
+type queryResult struct {
+	Docs          []map[string]interface{} `json:"docs"`
+	TotalDocs     int                      `json:"totalDocs"`
+	Offset        int                      `json:"offset"`
+	Limit         int                      `json:"limit"`
+	TotalPages    int                      `json:"totalPages"`
+	Page          int                      `json:"page"`
+	PagingCounter int                      `json:"pagingCounter"`
+	HasPrevPage   bool                     `json:"hasPrevPage"`
+	HasNextPage   bool                     `json:"hasNextPage"`
+	PrevPage      *int                     `json:"prevPage"`
+	NextPage      *int                     `json:"nextPage"`
+}
+
+func (q queryRequest) run(docs []map[string]interface{}) (queryResult, error) {
+	var matched []map[string]interface{}
+	for _, doc := range docs {
+		ok, err := matches(doc, q.Query)
+		if err != nil {
+			return queryResult{}, err
+		}
+		if ok {
+			matched = append(matched, doc)
+		}
+	}
+	if err := sortDocs(matched, q.Options.Sort); err != nil {
+		return queryResult{}, err
+	}
+	result := queryResult{TotalDocs: len(matched), Limit: len(matched), Page: 1, TotalPages: 1, PagingCounter: 1}
+	if q.Options.Pagination == nil || *q.Options.Pagination {
+		result.Limit = defaultLimit
+		if q.Options.Limit != nil {
+			result.Limit = *q.Options.Limit
+		}
+		switch {
+		case q.Options.Offset != nil:
+			result.Offset = *q.Options.Offset
+		case q.Options.Page != nil && *q.Options.Page > 0:
+			result.Offset = (*q.Options.Page - 1) * result.Limit
+		}
+		matched = paginate(matched, result.Offset, result.Limit)
+		if result.Limit > 0 {
+			result.Page = result.Offset/result.Limit + 1
+			result.TotalPages = (result.TotalDocs + result.Limit - 1) / result.Limit
+		}
+		result.PagingCounter = result.Offset + 1
+		result.HasPrevPage = result.Offset > 0
+		result.HasNextPage = result.Offset+len(matched) < result.TotalDocs
+		if result.HasPrevPage {
+			prev := result.Page - 1
+			result.PrevPage = &prev
+		}
+		if result.HasNextPage {
+			next := result.Page + 1
+			result.NextPage = &next
+		}
+	}
+	result.Docs = make([]map[string]interface{}, 0, len(matched))
+	for _, doc := range matched {
+		result.Docs = append(result.Docs, project(doc, q.Options.Select))
+	}
+	return result, nil
+}
+
+func paginate(docs []map[string]interface{}, offset, limit int) []map[string]interface{} {
+	if offset >= len(docs) {
+		return nil
+	}
+	docs = docs[offset:]
+	if limit > 0 && limit < len(docs) {
+		docs = docs[:limit]
+	}
+	return docs
+}
+
+/*
+project keeps selected fields of document, id is always kept
+*/
+func project(doc map[string]interface{}, fields map[string]interface{}) map[string]interface{} {
+	if len(fields) == 0 {
+		return doc
+	}
+	projected := map[string]interface{}{"id": doc["id"]}
+	for field, include := range fields {
+		if v, ok := include.(float64); ok && v == 0 {
+			continue
+		}
+		if value, ok := doc[field]; ok {
+			projected[field] = value
+		}
+	}
+	return projected
+}
+
+/*
+sortDocs sorts by fields of sort option, direction is 1, -1, asc or desc. Several fields are applied in name order
+as order of JSON object keys is not kept
+*/
+func sortDocs(docs []map[string]interface{}, fields map[string]interface{}) error {
+	names := make([]string, 0, len(fields))
+	directions := make(map[string]int, len(fields))
+	for name, direction := range fields {
+		switch direction {
+		case 1.0, "asc", "ascending":
+			directions[name] = 1
+		case -1.0, "desc", "descending":
+			directions[name] = -1
+		default:
+			return errors.Errorf(`invalid sort direction: field - %s, direction - %v`, name, direction)
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	sort.SliceStable(docs, func(i, j int) bool {
+		for _, name := range names {
+			c, _ := compare(docs[i][name], docs[j][name])
+			if c != 0 {
+				return c*directions[name] < 0
+			}
+		}
+		return false
+	})
+	return nil
+}
+
+/*
+matches checks document against query of field conditions, condition is value or object of
+$eq, $ne, $gt, $gte, $lt, $lte, $in and $nin operators
+*/
+func matches(doc map[string]interface{}, query map[string]interface{}) (bool, error) {
+	for field, condition := range query {
+		operators, ok := condition.(map[string]interface{})
+		if !ok || !isOperators(operators) {
+			if !equal(doc[field], condition) {
+				return false, nil
+			}
+			continue
+		}
+		for op, arg := range operators {
+			ok, err := matchOperator(op, doc[field], arg)
+			if err != nil {
+				return false, errors.Wrapf(err, `invalid condition: field - %s`, field)
+			}
+			if !ok {
+				return false, nil
+			}
+		}
+	}
+	return true, nil
+}
+
+func isOperators(condition map[string]interface{}) bool {
+	for key := range condition {
+		if !strings.HasPrefix(key, "$") {
+			return false
+		}
+	}
+	return len(condition) > 0
+}
+
+func matchOperator(op string, value, arg interface{}) (bool, error) {
+	switch op {
+	case "$eq":
+		return equal(value, arg), nil
+	case "$ne":
+		return !equal(value, arg), nil
+	case "$in", "$nin":
+		list, ok := arg.([]interface{})
+		if !ok {
+			return false, errors.Errorf(`%s requires array`, op)
+		}
+		var found bool
+		for _, el := range list {
+			found = found || equal(value, el)
+		}
+		return found == (op == "$in"), nil
+	case "$gt", "$gte", "$lt", "$lte":
+	default:
+		return false, errors.Errorf(`unknown operator: op - %s`, op)
+	}
+	c, ok := compare(value, arg)
+	if !ok {
+		return false, nil
+	}
+	switch op {
+	case "$gt":
+		return c > 0, nil
+	case "$gte":
+		return c >= 0, nil
+	case "$lt":
+		return c < 0, nil
+	}
+	return c <= 0, nil
+}
+
+func equal(a, b interface{}) bool {
+	c, ok := compare(a, b)
+	if ok {
+		return c == 0
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+/*
+compare orders numbers, dates and strings, date strings are compared as instants so dates in different zones match
+*/
+func compare(a, b interface{}) (int, bool) {
+	switch av := a.(type) {
+	case float64:
+		bv, ok := b.(float64)
+		if !ok {
+			return 0, false
+		}
+		switch {
+		case av < bv:
+			return -1, true
+		case av > bv:
+			return 1, true
+		}
+		return 0, true
+	case string:
+		bv, ok := b.(string)
+		if !ok {
+			return 0, false
+		}
+		at, aErr := time.Parse(time.RFC3339Nano, av)
+		bt, bErr := time.Parse(time.RFC3339Nano, bv)
+		if aErr == nil && bErr == nil {
+			switch {
+			case at.Before(bt):
+				return -1, true
+			case at.After(bt):
+				return 1, true
+			}
+			return 0, true
+		}
+		return strings.Compare(av, bv), true
+	}
+	return 0, false
+}
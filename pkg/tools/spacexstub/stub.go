@@ -0,0 +1,94 @@
+package spacexstub
+
+import (
+	_ "embed"
+	"encoding/json"
+	"net/http"
+	"os"
+
+	"github.com/go-chi/chi"
+	"github.com/pkg/errors"
+)
+
+//go:embed fixtures.json
+var defaultFixtures []byte
+
+/*
+Fixtures are documents served by stub in SpaceX API format, fields are kept as they are in fixtures file
+*/
+type Fixtures struct {
+	Launchpads []map[string]interface{} `json:"launchpads"`
+	Launches   []map[string]interface{} `json:"launches"`
+}
+
+/*
+ReadFixtures reads fixtures from JSON file with launchpads and launches lists, bundled fixtures are used when path is empty
+*/
+func ReadFixtures(path string) (Fixtures, error) {
+	data := defaultFixtures
+	if path != "" {
+		var err error
+		if data, err = os.ReadFile(path); err != nil {
+			return Fixtures{}, errors.Wrapf(err, `failed to read file: path - %s`, path)
+		}
+	}
+	f := Fixtures{}
+	if err := json.Unmarshal(data, &f); err != nil {
+		return Fixtures{}, errors.Wrapf(err, `failed to unmarshal fixtures: path - %s`, path)
+	}
+	return f, nil
+}
+
+/*
+NewHandler serves SpaceX API endpoints used by repositories from fixtures:
+
+	GET  /v4/launchpads/{id}
+	POST /v4/launchpads/query
+	POST /v5/launches/query
+
+	queries support mongoose style query and select, sort, limit, offset, page and pagination options.
+	handler can be started with httptest.NewServer
+*/
+func NewHandler(f Fixtures) http.Handler {
+	r := chi.NewRouter()
+	r.Get("/v4/launchpads/{id}", func(wr http.ResponseWriter, req *http.Request) {
+		id := chi.URLParam(req, "id")
+		for _, doc := range f.Launchpads {
+			if doc["id"] == id {
+				respond(wr, http.StatusOK, doc)
+				return
+			}
+		}
+		http.Error(wr, "Not Found", http.StatusNotFound)
+	})
+	r.Post("/v4/launchpads/query", queryHandler(f.Launchpads))
+	r.Post("/v5/launches/query", queryHandler(f.Launches))
+	return r
+}
+
+func queryHandler(docs []map[string]interface{}) http.HandlerFunc {
+	return func(wr http.ResponseWriter, req *http.Request) {
+		q := queryRequest{}
+		if err := json.NewDecoder(req.Body).Decode(&q); err != nil {
+			http.Error(wr, err.Error(), http.StatusBadRequest)
+			return
+		}
+		result, err := q.run(docs)
+		if err != nil {
+			http.Error(wr, err.Error(), http.StatusBadRequest)
+			return
+		}
+		respond(wr, http.StatusOK, result)
+	}
+}
+
+func respond(wr http.ResponseWriter, code int, body interface{}) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		http.Error(wr, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	wr.Header().Set("Content-Type", "application/json")
+	wr.WriteHeader(code)
+	_, _ = wr.Write(data)
+}
@@ -0,0 +1,60 @@
+package spacexstub
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+)
+
+func query(h http.Handler, path, body string) (int, string) {
+	resp := httptest.NewRecorder()
+	h.ServeHTTP(resp, httptest.NewRequest(http.MethodPost, path, bytes.NewBufferString(body)))
+	return resp.Code, resp.Body.String()
+}
+
+func TestHandler_Query(t *testing.T) {
+	f, err := ReadFixtures("")
+	require.NoError(t, err)
+	h := NewHandler(f)
+
+	code, body := query(h, "/v4/launchpads/query", `{
+		"query": {"status": {"$in": ["active", "retired"]}, "timezone": {"$ne": "America/New_York"}},
+		"options": {"select": {"full_name": 1}, "sort": {"full_name": -1}, "limit": 2, "offset": 1}
+	}`)
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, int64(3), gjson.Get(body, "totalDocs").Int())
+	require.True(t, gjson.Get(body, "hasPrevPage").Bool())
+	require.False(t, gjson.Get(body, "hasNextPage").Bool())
+	require.Equal(t, `[{"full_name":"Vandenberg Space Force Base Space Launch Complex 3W","id":"5e9e4501f5090910d4566f83"},`+
+		`{"full_name":"Kwajalein Atoll Omelek Island","id":"5e9e4502f5090995de566f86"}]`, gjson.Get(body, "docs").Raw)
+
+	// dates are compared as instants, so launch at 2022-08-27T23:41:00-04:00 is left out
+	code, body = query(h, "/v5/launches/query", `{
+		"query": {"date_local": {"$gte": "2022-08-28T00:00:00-04:00", "$lt": "2022-09-01T00:00:00Z"}},
+		"options": {"sort": {"date_utc": "asc"}, "pagination": false}
+	}`)
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, []interface{}{"Starlink 3-4"}, gjson.Get(body, "docs.#.name").Value())
+
+	code, _ = query(h, "/v5/launches/query", `{"query": {"date_utc": {"$regex": "2022"}}}`)
+	require.Equal(t, http.StatusBadRequest, code)
+}
+
+func TestHandler_Launchpad(t *testing.T) {
+	f, err := ReadFixtures("")
+	require.NoError(t, err)
+	h := NewHandler(f)
+
+	resp := httptest.NewRecorder()
+	h.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/v4/launchpads/5e9e4502f509092b78566f87", nil))
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.Equal(t, "America/Los_Angeles", gjson.Get(resp.Body.String(), "timezone").String())
+
+	resp = httptest.NewRecorder()
+	h.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/v4/launchpads/unknown", nil))
+	require.Equal(t, http.StatusNotFound, resp.Code)
+}
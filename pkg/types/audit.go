@@ -0,0 +1,25 @@
+package types
+
+import "time"
+
+const (
+	OrderAuditActionCreate = "create"
+	OrderAuditActionUpdate = "update"
+	OrderAuditActionDelete = "delete"
+)
+
+/*
+OrderAuditEntry is immutable record of order change.
+
+	before is empty for created order, after is empty for deleted one
+*/
+type OrderAuditEntry struct {
+	ID        string    `json:"id"`
+	OrderID   string    `json:"order_id"`
+	Action    string    `json:"action"`
+	Actor     string    `json:"actor"`
+	RequestID string    `json:"request_id,omitempty"`
+	Before    *Order    `json:"before,omitempty"`
+	After     *Order    `json:"after,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -18,14 +18,55 @@ func (ErrDuplicatedOrder) Error() string {
 	return "duplicated order"
 }
 
-type ErrFlightImpossible struct{}
+const (
+	FlightImpossibleReasonDestination      = "destination"
+	FlightImpossibleReasonCompetitorLaunch = "competitor_launch"
+	FlightImpossibleReasonLaunchpad        = "launchpad_inactive"
+	// all seats of flight are taken by other active orders
+	FlightImpossibleReasonNoSeats = "no_seats"
+)
+
+/*
+ErrFlightImpossible is returned when order can not be booked.
+
+	flights blocked by competitor launch may become possible later, destination of launchpad on date never changes.
+	Alternatives are nearest feasible flights to the same destination when they could be found
+*/
+type ErrFlightImpossible struct {
+	Reason       string
+	Alternatives []FlightAlternative
+}
+
+func NewErrFlightImpossible(reason string) ErrFlightImpossible {
+	return ErrFlightImpossible{Reason: reason}
+}
 
 func (ErrFlightImpossible) Error() string {
 	return "flight impossible for provided date and launchpad"
 }
 
+/*
+ErrOrderChanged is returned when order was changed concurrently and the change based on its previous state is not applied
+*/
+type ErrOrderChanged struct{}
+
+func (ErrOrderChanged) Error() string {
+	return "order was changed concurrently"
+}
+
 type ErrNotFound struct{}
 
 func (ErrNotFound) Error() string {
 	return "not found"
 }
+
+/*
+ErrUnavailable is returned when external service can not serve request right now, request can be retried later
+*/
+type ErrUnavailable struct {
+	Service string
+}
+
+func (e ErrUnavailable) Error() string {
+	return e.Service + " is unavailable"
+}
@@ -0,0 +1,41 @@
+package types
+
+import "time"
+
+const (
+	OrderEventCreated   = "order.created"
+	OrderEventCancelled = "order.cancelled"
+	// emitted when launch date or destination of existing order changes
+	OrderEventRescheduled = "order.rescheduled"
+	// emitted when flight of existing order becomes impossible
+	OrderEventConflicted = "order.conflicted"
+)
+
+var OrderEventTypes = []string{
+	OrderEventCreated,
+	OrderEventCancelled,
+	OrderEventRescheduled,
+	OrderEventConflicted,
+}
+
+type OrderEvent struct {
+	ID         string    `json:"id"`
+	Type       string    `json:"type"`
+	OrderID    string    `json:"order_id"`
+	Order      *Order    `json:"order,omitempty"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+/*
+OutboxEvent is order event stored in outbox together with order change and relayed to sinks afterwards
+*/
+type OutboxEvent struct {
+	OrderEvent
+	Seq           int64
+	Attempts      int
+	LastError     string
+	NextAttemptAt time.Time
+	PublishedAt   *time.Time
+	// set when relay gave up on event, dead event is kept for inspection and no longer holds its order
+	DeadAt *time.Time
+}
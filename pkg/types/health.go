@@ -0,0 +1,25 @@
+package types
+
+import "time"
+
+const (
+	HealthStatusOK       = "ok"
+	HealthStatusDegraded = "degraded"
+)
+
+/*
+HealthCheck state of app dependency, since is when dependency got into current status
+*/
+type HealthCheck struct {
+	Status  string     `json:"status"`
+	Message string     `json:"message,omitempty"`
+	Since   *time.Time `json:"since,omitempty"`
+}
+
+/*
+Health of app is degraded when any of checks is degraded
+*/
+type Health struct {
+	Status string                 `json:"status"`
+	Checks map[string]HealthCheck `json:"checks,omitempty"`
+}
@@ -0,0 +1,40 @@
+package types
+
+const (
+	ImportRowStatusOK      = "ok"
+	ImportRowStatusCreated = "created"
+	ImportRowStatusFailed  = "failed"
+	ImportRowStatusSkipped = "skipped"
+)
+
+/*
+ImportRow order decoded from import file.
+
+	Error is set when row could not be decoded
+*/
+type ImportRow struct {
+	Line  int    `json:"line"`
+	Order Order  `json:"order"`
+	Error string `json:"error,omitempty"`
+}
+
+type ImportOptions struct {
+	DryRun       bool `json:"dry_run"`
+	AllOrNothing bool `json:"all_or_nothing"`
+}
+
+type ImportRowResult struct {
+	Line   int    `json:"line"`
+	Status string `json:"status"`
+	ID     string `json:"id,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+type ImportReport struct {
+	ImportOptions
+	Total     int               `json:"total"`
+	Succeeded int               `json:"succeeded"`
+	Failed    int               `json:"failed"`
+	Committed bool              `json:"committed"`
+	Rows      []ImportRowResult `json:"rows"`
+}
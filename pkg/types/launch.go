@@ -6,11 +6,21 @@ const (
 	LaunchpadStatusActive = "active"
 )
 
+const (
+	LocalDateLayout = "2006-01-02"
+)
+
+/*
+Launch of competitor occupying launchpad.
+
+	provider is name of configured launches source, empty when source is queried directly
+*/
 type Launch struct {
 	ID        string    `json:"id"`
 	DateUTC   time.Time `json:"date_utc"`
 	DateLocal time.Time `json:"date_local"`
 	Launchpad string    `json:"launchpad"`
+	Provider  string    `json:"provider,omitempty"`
 }
 
 type Launchpad struct {
@@ -20,6 +30,27 @@ type Launchpad struct {
 	Status   string         `json:"status"`
 }
 
+/*
+LaunchpadTransition is change of launchpad status noticed between two refreshes
+*/
+type LaunchpadTransition struct {
+	LaunchpadID string `json:"launchpad_id"`
+	From        string `json:"from"`
+	To          string `json:"to"`
+}
+
+/*
+LaunchpadsRefresh summary of launchpads refresh.
+
+	anchored is number of launchpads which got rotation anchor, conflicts is number of orders moved to conflict
+*/
+type LaunchpadsRefresh struct {
+	Launchpads  int                   `json:"launchpads"`
+	Transitions []LaunchpadTransition `json:"transitions"`
+	Anchored    int                   `json:"anchored"`
+	Conflicts   int                   `json:"conflicts"`
+}
+
 type LaunchpadFirstDestination struct {
 	LaunchpadID   string     `json:"launchpad_id"`
 	DestinationID string     `json:"destination_id"`
@@ -27,3 +58,33 @@ type LaunchpadFirstDestination struct {
 	LocalMonth    time.Month `json:"local_month"`
 	LocalDay      int        `json:"local_day"`
 }
+
+/*
+FlightAlternative is feasible flight to the same destination offered instead of impossible one
+*/
+type FlightAlternative struct {
+	LaunchpadID   string    `json:"launchpad_id"`
+	LaunchpadName string    `json:"launchpad_name"`
+	DestinationID string    `json:"destination_id"`
+	LocalDate     string    `json:"local_date"`
+	LaunchDate    time.Time `json:"launch_date"`
+}
+
+type LaunchpadScheduleDay struct {
+	LaunchpadID     string `json:"launchpad_id"`
+	LocalDate       string `json:"local_date"`
+	DestinationID   string `json:"destination_id"`
+	DestinationName string `json:"destination_name"`
+	Blocked         bool   `json:"blocked"`
+}
+
+/*
+LaunchesSync state of launches mirrored from source, launches with UTC date in [from, to) are up to date as of synced at
+*/
+type LaunchesSync struct {
+	Source   string    `json:"source"`
+	From     time.Time `json:"from"`
+	To       time.Time `json:"to"`
+	SyncedAt time.Time `json:"synced_at"`
+	Launches int       `json:"launches"`
+}
@@ -0,0 +1,32 @@
+package types
+
+import "time"
+
+/*
+Manifest lists passengers of launchpad flights on local day of launchpad
+*/
+type Manifest struct {
+	LaunchpadID   string                `json:"launchpad_id"`
+	LaunchpadName string                `json:"launchpad_name"`
+	LocalDate     string                `json:"local_date"`
+	Timezone      string                `json:"timezone"`
+	Seats         int                   `json:"seats"`
+	Destinations  []ManifestDestination `json:"destinations"`
+	Orders        []ManifestOrder       `json:"orders"`
+}
+
+type ManifestDestination struct {
+	DestinationID   string `json:"destination_id"`
+	DestinationName string `json:"destination_name"`
+	Seats           int    `json:"seats"`
+}
+
+type ManifestOrder struct {
+	OrderID         string      `json:"order_id"`
+	DestinationID   string      `json:"destination_id"`
+	DestinationName string      `json:"destination_name"`
+	LaunchDate      time.Time   `json:"launch_date"`
+	Email           string      `json:"email,omitempty"`
+	Seats           int         `json:"seats"`
+	Passengers      []Passenger `json:"passengers"`
+}
@@ -0,0 +1,47 @@
+package types
+
+import "time"
+
+const (
+	NotificationKindBookingConfirmed   = "booking_confirmed"
+	NotificationKindBookingCancelled   = "booking_cancelled"
+	NotificationKindBookingRescheduled = "booking_rescheduled"
+	NotificationKindBookingConflicted  = "booking_conflicted"
+	NotificationKindLaunchReminder     = "launch_reminder"
+)
+
+const (
+	NotificationStatusPending   = "pending"
+	NotificationStatusSent      = "sent"
+	NotificationStatusFailed    = "failed"
+	NotificationStatusCancelled = "cancelled"
+)
+
+/*
+Notification message to customer rendered from order event.
+
+	message is rendered when notification is created so it shows order state at the moment of event
+*/
+type Notification struct {
+	ID            string     `json:"id"`
+	OrderID       string     `json:"order_id"`
+	EventID       string     `json:"event_id"`
+	Kind          string     `json:"kind"`
+	Recipient     string     `json:"recipient"`
+	Subject       string     `json:"subject"`
+	Body          string     `json:"-"`
+	Status        string     `json:"status"`
+	Attempts      int        `json:"attempts"`
+	LastError     string     `json:"last_error,omitempty"`
+	NextAttemptAt time.Time  `json:"next_attempt_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+	SentAt        *time.Time `json:"sent_at,omitempty"`
+}
+
+type EmailMessage struct {
+	To      string
+	Subject string
+	Body    string
+	// shown in Date header, taken from clock of sender caller so it follows virtual time in staging
+	Date time.Time
+}
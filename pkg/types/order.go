@@ -1,47 +1,155 @@
 package types
 
 import (
+	"net/mail"
 	"time"
 
 	"github.com/pkg/errors"
 )
 
+const (
+	MaxOrderPassengers = 10
+	// seats of one flight, flight is launchpad on launch local date
+	FlightSeats = 50
+)
+
+const (
+	OrderStatusActive = "active"
+	// flight of booked order became impossible, conflict reason tells why
+	OrderStatusConflict = "conflict"
+)
+
+/*
+Order booking of one or more seats on a flight.
+
+	first passenger is duplicated in flat passenger fields so single passenger clients keep working.
+	when passengers list is empty flat fields are treated as the only passenger.
+	email is optional contact for booking notifications.
+	launch day can be sent as launch_local_date interpreted in launchpad timezone,
+	booked order keeps local date and timezone of launchpad explicitly.
+	conflict reason is one of flight impossible reasons and is set only for orders in conflict status
+*/
 type Order struct {
-	ID            string    `json:"id"`
-	FirstName     string    `json:"first_name"`
-	LastName      string    `json:"last_name"`
-	Gender        string    `json:"gender"`
-	BirthdayYear  int       `json:"birthday_year"`
-	BirthdayMonth int       `json:"birthday_month"`
-	BirthdayDay   int       `json:"birthday_day"`
-	LaunchpadID   string    `json:"launchpad_id"`
-	DestinationID string    `json:"destination_id"`
-	LaunchDate    time.Time `json:"launch_date"`
-	CreatedAt     time.Time `json:"created_at"`
+	ID                string      `json:"id"`
+	FirstName         string      `json:"first_name"`
+	LastName          string      `json:"last_name"`
+	Gender            string      `json:"gender"`
+	BirthdayYear      int         `json:"birthday_year"`
+	BirthdayMonth     int         `json:"birthday_month"`
+	BirthdayDay       int         `json:"birthday_day"`
+	Passengers        []Passenger `json:"passengers,omitempty"`
+	Email             string      `json:"email,omitempty"`
+	LaunchpadID       string      `json:"launchpad_id"`
+	DestinationID     string      `json:"destination_id"`
+	LaunchDate        time.Time   `json:"launch_date"`
+	LaunchLocalDate   string      `json:"launch_local_date,omitempty"`
+	LaunchpadTimezone string      `json:"launchpad_timezone,omitempty"`
+	Status            string      `json:"status,omitempty"`
+	ConflictReason    string      `json:"conflict_reason,omitempty"`
+	CreatedAt         time.Time   `json:"created_at"`
 }
 
 func (o Order) Validate() error {
-	if o.FirstName == "" {
+	passengers := o.PassengerList()
+	if len(passengers) > MaxOrderPassengers {
+		return errors.Errorf("order can not have more than %d passengers", MaxOrderPassengers)
+	}
+	for i, p := range passengers {
+		if err := p.Validate(); err != nil {
+			if len(o.Passengers) == 0 {
+				return err
+			}
+			return errors.Wrapf(err, "passengers[%d]", i)
+		}
+	}
+	if o.LaunchpadID == "" {
+		return errors.New("launchpad_id is required")
+	}
+	if o.DestinationID == "" {
+		return errors.New("destination_id is required")
+	}
+	if o.LaunchDate.IsZero() && o.LaunchLocalDate == "" {
+		return errors.New("launch_date or launch_local_date is required")
+	}
+	if o.LaunchLocalDate != "" {
+		if _, err := time.Parse(LocalDateLayout, o.LaunchLocalDate); err != nil {
+			return errors.Errorf("launch_local_date should be in %s format", LocalDateLayout)
+		}
+	}
+	if o.Email != "" {
+		if addr, err := mail.ParseAddress(o.Email); err != nil || addr.Address != o.Email {
+			return errors.New("email is invalid")
+		}
+	}
+	return nil
+}
+
+/*
+PassengerList returns all passengers of order
+*/
+func (o Order) PassengerList() []Passenger {
+	if len(o.Passengers) > 0 {
+		return o.Passengers
+	}
+	return []Passenger{o.LeadPassenger()}
+}
+
+func (o Order) LeadPassenger() Passenger {
+	return Passenger{
+		FirstName:     o.FirstName,
+		LastName:      o.LastName,
+		Gender:        o.Gender,
+		BirthdayYear:  o.BirthdayYear,
+		BirthdayMonth: o.BirthdayMonth,
+		BirthdayDay:   o.BirthdayDay,
+	}
+}
+
+/*
+WithPassengers returns order with passengers list filled and flat fields set from first passenger.
+
+	when provided list is empty current passengers of order are used
+*/
+func (o Order) WithPassengers(passengers []Passenger) Order {
+	if len(passengers) == 0 {
+		passengers = o.PassengerList()
+	}
+	lead := passengers[0]
+	o.FirstName = lead.FirstName
+	o.LastName = lead.LastName
+	o.Gender = lead.Gender
+	o.BirthdayYear = lead.BirthdayYear
+	o.BirthdayMonth = lead.BirthdayMonth
+	o.BirthdayDay = lead.BirthdayDay
+	o.Passengers = passengers
+	return o
+}
+
+type Passenger struct {
+	FirstName     string `json:"first_name"`
+	LastName      string `json:"last_name"`
+	Gender        string `json:"gender"`
+	BirthdayYear  int    `json:"birthday_year"`
+	BirthdayMonth int    `json:"birthday_month"`
+	BirthdayDay   int    `json:"birthday_day"`
+}
+
+func (p Passenger) Validate() error {
+	if p.FirstName == "" {
 		return errors.New("first_name is required")
 	}
-	if o.LastName == "" {
+	if p.LastName == "" {
 		return errors.New("last_name is required")
 	}
-	if o.BirthdayYear == 0 {
+	if p.BirthdayYear == 0 {
 		return errors.New("birthday year is required")
 	}
-	if o.BirthdayMonth == 0 {
+	if p.BirthdayMonth == 0 {
 		return errors.New("birthday month is required")
 	}
-	if o.BirthdayDay == 0 {
+	if p.BirthdayDay == 0 {
 		return errors.New("birthday day is required")
 	}
-	if o.LaunchpadID == "" {
-		return errors.New("launchpad_id is required")
-	}
-	if o.DestinationID == "" {
-		return errors.New("destination_id is required")
-	}
 	return nil
 }
 
@@ -49,3 +157,28 @@ type Destination struct {
 	ID   string `json:"id"`
 	Name string `json:"name"`
 }
+
+/*
+OrderExport order with data resolved for reports
+*/
+type OrderExport struct {
+	Order
+	DestinationName string `json:"destination_name"`
+}
+
+/*
+ConflictsReport result of checking booked orders against current launch schedule and rotation
+*/
+type ConflictsReport struct {
+	Checked   int `json:"checked"`
+	Conflicts int `json:"conflicts"`
+}
+
+/*
+RebookResult outcome of moving order in conflict to nearest feasible flight, error is set when no flight was found
+*/
+type RebookResult struct {
+	OrderID string `json:"order_id"`
+	Order   *Order `json:"order,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
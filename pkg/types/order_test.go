@@ -2,6 +2,7 @@ package types
 
 import (
 	"testing"
+	"time"
 
 	"github.com/brianvoe/gofakeit"
 	"github.com/google/uuid"
@@ -22,5 +23,55 @@ func TestOrder_Validate(t *testing.T) {
 	o.LaunchpadID = uuid.New().String()
 	require.Error(t, o.Validate())
 	o.DestinationID = uuid.New().String()
+	require.Error(t, o.Validate())
+	o.LaunchLocalDate = "2053-13-01"
+	require.Error(t, o.Validate())
+	o.LaunchLocalDate = "2053-03-05"
+	require.NoError(t, o.Validate())
+	o.Email = "Vasyl <vasyl@example.com>"
+	require.Error(t, o.Validate())
+	o.Email = gofakeit.Email()
+	require.NoError(t, o.Validate())
+}
+
+func TestOrder_ValidatePassengers(t *testing.T) {
+	lead := Passenger{
+		FirstName:     gofakeit.FirstName(),
+		LastName:      gofakeit.LastName(),
+		BirthdayYear:  1980,
+		BirthdayMonth: 1,
+		BirthdayDay:   2,
+	}
+	o := Order{
+		LaunchpadID:   uuid.New().String(),
+		DestinationID: uuid.New().String(),
+		LaunchDate:    time.Date(2053, 3, 5, 10, 0, 0, 0, time.UTC),
+		Passengers:    []Passenger{lead, {FirstName: gofakeit.FirstName()}},
+	}
+	require.EqualError(t, o.Validate(), "passengers[1]: last_name is required")
+	o.Passengers[1] = lead
+	o.Passengers[1].BirthdayYear = 2010
 	require.NoError(t, o.Validate())
+
+	for len(o.Passengers) <= MaxOrderPassengers {
+		o.Passengers = append(o.Passengers, lead)
+	}
+	require.Error(t, o.Validate())
+}
+
+func TestOrder_WithPassengers(t *testing.T) {
+	o := Order{
+		FirstName:     gofakeit.FirstName(),
+		LastName:      gofakeit.LastName(),
+		BirthdayYear:  1980,
+		BirthdayMonth: 1,
+		BirthdayDay:   2,
+	}
+	single := o.WithPassengers(nil)
+	require.Equal(t, []Passenger{o.LeadPassenger()}, single.Passengers)
+
+	companion := Passenger{FirstName: gofakeit.FirstName(), LastName: gofakeit.LastName()}
+	group := o.WithPassengers([]Passenger{companion, o.LeadPassenger()})
+	require.Equal(t, companion, group.LeadPassenger())
+	require.Len(t, group.PassengerList(), 2)
 }
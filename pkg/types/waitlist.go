@@ -0,0 +1,27 @@
+package types
+
+import "time"
+
+const (
+	WaitlistStatusWaiting   = "waiting"
+	WaitlistStatusPromoted  = "promoted"
+	WaitlistStatusExpired   = "expired"
+	WaitlistStatusCancelled = "cancelled"
+)
+
+/*
+WaitlistEntry is order waiting for flight blocked by competitor launch.
+
+	entry is promoted when order becomes possible and is booked, OrderID refers to booked order
+*/
+type WaitlistEntry struct {
+	ID          string     `json:"id"`
+	Order       Order      `json:"order"`
+	Status      string     `json:"status"`
+	OrderID     string     `json:"order_id,omitempty"`
+	Checks      int        `json:"checks"`
+	LastError   string     `json:"last_error,omitempty"`
+	NextCheckAt time.Time  `json:"next_check_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	PromotedAt  *time.Time `json:"promoted_at,omitempty"`
+}
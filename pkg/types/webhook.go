@@ -0,0 +1,75 @@
+package types
+
+import (
+	"net/url"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	WebhookDeliveryStatusPending   = "pending"
+	WebhookDeliveryStatusDelivered = "delivered"
+	WebhookDeliveryStatusDead      = "dead"
+)
+
+type WebhookSubscription struct {
+	ID         string    `json:"id"`
+	URL        string    `json:"url"`
+	EventTypes []string  `json:"event_types"`
+	Secret     string    `json:"secret,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func (s WebhookSubscription) Validate() error {
+	u, err := url.Parse(s.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("url should be absolute http or https url")
+	}
+	if len(s.EventTypes) == 0 {
+		return errors.New("event_types is required")
+	}
+	for _, t := range s.EventTypes {
+		if !isOrderEventType(t) {
+			return errors.Errorf("unknown event type %s", t)
+		}
+	}
+	return nil
+}
+
+func (s WebhookSubscription) Subscribed(eventType string) bool {
+	for _, t := range s.EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+func isOrderEventType(t string) bool {
+	for _, known := range OrderEventTypes {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+WebhookDelivery single event sent to single subscription.
+
+	deliveries which failed all attempts get dead status and stay in dead letter list until replayed
+*/
+type WebhookDelivery struct {
+	ID             string     `json:"id"`
+	SubscriptionID string     `json:"subscription_id"`
+	EventID        string     `json:"event_id"`
+	EventType      string     `json:"event_type"`
+	Payload        []byte     `json:"-"`
+	Status         string     `json:"status"`
+	Attempts       int        `json:"attempts"`
+	LastError      string     `json:"last_error,omitempty"`
+	NextAttemptAt  time.Time  `json:"next_attempt_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty"`
+}